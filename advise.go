@@ -0,0 +1,85 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+)
+
+// Advice describes how a file is going to be read, so the operating system can tune
+// read-ahead and caching for it.
+type Advice int
+
+const (
+	AdviceNormal     Advice = iota // No particular access pattern, the default
+	AdviceSequential               // Read from start to end, so read-ahead can be more aggressive
+	AdviceRandom                   // Read at random offsets, so read-ahead is wasted
+	AdviceWillNeed                 // Read soon, so it can be loaded into the cache ahead of time
+	AdviceDontNeed                 // Not read again soon, so its pages can be dropped from the cache
+)
+
+// String returns the name of the advice, like "sequential".
+func (a Advice) String() string {
+	switch a {
+	case AdviceNormal:
+		return "normal"
+	case AdviceSequential:
+		return "sequential"
+	case AdviceRandom:
+		return "random"
+	case AdviceWillNeed:
+		return "willneed"
+	case AdviceDontNeed:
+		return "dontneed"
+	default:
+		return fmt.Sprintf("Advice(%d)", int(a))
+	}
+}
+
+// Advise tells the operating system how the whole of an open file is going to be read.
+// It uses posix_fadvise on Linux, FreeBSD and NetBSD, and read-ahead control on macOS,
+// where only AdviceSequential and AdviceRandom have an effect. Elsewhere it does nothing.
+//
+// Example:
+//
+//	file, err := OpenRead("huge.log")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	err = Advise(file, AdviceSequential)
+func Advise(file *os.File, advice Advice) error {
+	err := advise(file, advice)
+	if err != nil {
+		return pathError("Advise", file.Name(), fmt.Errorf("Advise failed to give %s advice: %w", advice, err))
+	}
+
+	return nil
+}
+
+// adviseRead gives advice for a file that is about to be read. AdviceDontNeed is given by
+// adviseDone once the file was read instead, when its pages can actually be dropped.
+// Errors are ignored, since advice is only a hint.
+func adviseRead(file *os.File, advice Advice) {
+	if advice != AdviceNormal && advice != AdviceDontNeed {
+		advise(file, advice)
+	}
+}
+
+// adviseDone gives AdviceDontNeed for a file that was read, if that is the advice.
+func adviseDone(file *os.File, advice Advice) {
+	if advice == AdviceDontNeed {
+		advise(file, advice)
+	}
+}
+
+// advisedFile is a file that gets adviseDone when it is closed.
+type advisedFile struct {
+	*os.File
+	advice Advice
+}
+
+func (f advisedFile) Close() error {
+	adviseDone(f.File, f.advice)
+	return f.File.Close()
+}
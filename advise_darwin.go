@@ -0,0 +1,23 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func advise(file *os.File, advice Advice) error {
+	switch advice {
+	case AdviceNormal, AdviceSequential:
+		_, err := unix.FcntlInt(file.Fd(), unix.F_RDAHEAD, 1)
+		return err
+	case AdviceRandom:
+		_, err := unix.FcntlInt(file.Fd(), unix.F_RDAHEAD, 0)
+		return err
+	case AdviceWillNeed, AdviceDontNeed:
+		return nil
+	default:
+		return fmt.Errorf("unknown advice %s", advice)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build linux || freebsd || netbsd
+
+package fs_go
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func advise(file *os.File, advice Advice) error {
+	var flag int
+	switch advice {
+	case AdviceNormal:
+		flag = unix.FADV_NORMAL
+	case AdviceSequential:
+		flag = unix.FADV_SEQUENTIAL
+	case AdviceRandom:
+		flag = unix.FADV_RANDOM
+	case AdviceWillNeed:
+		flag = unix.FADV_WILLNEED
+	case AdviceDontNeed:
+		flag = unix.FADV_DONTNEED
+	default:
+		return fmt.Errorf("unknown advice %s", advice)
+	}
+
+	return unix.Fadvise(int(file.Fd()), 0, 0, flag)
+}
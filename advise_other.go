@@ -0,0 +1,9 @@
+//go:build !linux && !freebsd && !netbsd && !darwin
+
+package fs_go
+
+import "os"
+
+func advise(file *os.File, advice Advice) error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAdvise(t *testing.T) {
+	path := "advise_1"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"a.txt": "alpha", "sub/b.txt": "beta"})
+
+	// Expect every advice to be accepted
+	t.Run("advise", func(t *testing.T) {
+		file, err := os.Open(filepath.Join(path, "a.txt"))
+		if err != nil {
+			t.Fatalf("os.Open failed: %v", err)
+		}
+		defer file.Close()
+
+		for _, advice := range []Advice{AdviceNormal, AdviceSequential, AdviceRandom, AdviceWillNeed, AdviceDontNeed} {
+			if err := Advise(file, advice); err != nil {
+				t.Errorf("Expected %s advice to be accepted, got %v", advice, err)
+			}
+		}
+	})
+
+	// Expect advice not to change what is read
+	t.Run("read", func(t *testing.T) {
+		for _, advice := range []Advice{AdviceSequential, AdviceDontNeed} {
+			content, err := ReadBytesWithOptions(filepath.Join(path, "a.txt"), ReadOptions{Advice: advice})
+			if err != nil {
+				t.Fatalf("ReadBytesWithOptions failed: %v", err)
+			}
+			if string(content) != "alpha" {
+				t.Errorf("Expected alpha with %s advice, got %q", advice, content)
+			}
+		}
+	})
+
+	// Expect advice not to change the digest of a tree
+	t.Run("hash dir", func(t *testing.T) {
+		plain, err := HashDir(path, HashDirOptions{})
+		if err != nil {
+			t.Fatalf("HashDir failed: %v", err)
+		}
+		advised, err := HashDir(path, HashDirOptions{Advice: AdviceSequential})
+		if err != nil {
+			t.Fatalf("HashDir failed: %v", err)
+		}
+		if plain != advised {
+			t.Errorf("Expected %s, got %s", plain, advised)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// ModTime returns the modification time of a file or directory.
+func ModTime(path string) (time.Time, error) {
+	path = normalizePath(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, pathError("ModTime", path, fmt.Errorf("ModTime failed to get file stat: %w", err))
+	}
+
+	return info.ModTime(), nil
+}
+
+// Age returns how long ago a file or directory was last modified.
+func Age(path string) (time.Duration, error) {
+	modTime, err := ModTime(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Since(modTime), nil
+}
+
+// IsOlderThan reports whether a file or directory was last modified more than d ago.
+//
+// Example:
+//
+//	stale, err := IsOlderThan("cache/index.json", 24*time.Hour)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if stale {
+//	    refreshIndex()
+//	}
+func IsOlderThan(path string, d time.Duration) (bool, error) {
+	age, err := Age(path)
+	if err != nil {
+		return false, err
+	}
+
+	return age > d, nil
+}
+
+// IsNewerThan reports whether a was modified after b, like the -nt test of the shell.
+// Both must exist.
+//
+// Example:
+//
+//	newer, err := IsNewerThan("schema.sql", "schema.gen.go")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if newer {
+//	    regenerate()
+//	}
+func IsNewerThan(a, b string) (bool, error) {
+	modTimeA, err := ModTime(a)
+	if err != nil {
+		return false, err
+	}
+	modTimeB, err := ModTime(b)
+	if err != nil {
+		return false, err
+	}
+
+	return modTimeA.After(modTimeB), nil
+}
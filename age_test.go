@@ -0,0 +1,51 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAge(t *testing.T) {
+	old, recent := "age_old.txt", "age_recent.txt"
+	defer os.Remove(old)
+	defer os.Remove(recent)
+	os.WriteFile(old, []byte("old"), 0644)
+	os.WriteFile(recent, []byte("recent"), 0644)
+
+	hourAgo := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(old, hourAgo, hourAgo); err != nil {
+		t.Fatalf("os.Chtimes failed: %v", err)
+	}
+
+	// Expect the modification time to be reported
+	modTime, err := ModTime(old)
+	if err != nil {
+		t.Fatalf("ModTime failed: %v", err)
+	}
+	if !modTime.Equal(hourAgo) && modTime.Sub(hourAgo).Abs() > time.Second {
+		t.Errorf("Expected %v, got %v", hourAgo, modTime)
+	}
+
+	// Expect the age to be compared against the duration
+	if older, err := IsOlderThan(old, 30*time.Minute); err != nil || !older {
+		t.Errorf("Expected old file to be older than 30 minutes, got %v, %v", older, err)
+	}
+	if older, err := IsOlderThan(recent, 30*time.Minute); err != nil || older {
+		t.Errorf("Expected recent file not to be older than 30 minutes, got %v, %v", older, err)
+	}
+
+	// Expect files to be compared by modification time
+	if newer, err := IsNewerThan(recent, old); err != nil || !newer {
+		t.Errorf("Expected recent file to be newer, got %v, %v", newer, err)
+	}
+	if newer, err := IsNewerThan(old, recent); err != nil || newer {
+		t.Errorf("Expected old file not to be newer, got %v, %v", newer, err)
+	}
+
+	// Expect missing files to fail
+	if _, err := Age("missing.txt"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("Expected ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,434 @@
+package fs_go
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// archiveSeparator separates the archive from the path inside it, as in "bundle.zip!/config/app.json".
+const archiveSeparator = "!/"
+
+// ArchiveFS is a read-only file system backed by a zip or tar archive.
+// It implements fs.StatFS, fs.ReadDirFS and fs.ReadFileFS, so it works with fs.WalkDir,
+// fs.Glob and http.FS. Close it when done.
+type ArchiveFS struct {
+	fsys   fs.FS
+	closer io.Closer
+}
+
+// OpenArchiveFS opens a .zip, .jar, .tar, .tar.gz or .tgz archive as a file system.
+// Zip archives are read lazily, while tar archives are loaded into memory, since they can
+// only be read sequentially.
+//
+// Example:
+//
+//	fsys, err := OpenArchiveFS("bundle.zip")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer fsys.Close()
+//	content, err := fsys.ReadFile("config/app.json")
+func OpenArchiveFS(path string) (*ArchiveFS, error) {
+	var fsys *ArchiveFS
+	var err error
+	switch archiveKind(path) {
+	case "zip":
+		var reader *zip.ReadCloser
+		reader, err = zip.OpenReader(normalizePath(path))
+		if err == nil {
+			fsys = &ArchiveFS{fsys: reader, closer: reader}
+		}
+	case "tar", "tgz":
+		var mem memFS
+		mem, err = readTar(path, archiveKind(path) == "tgz")
+		if err == nil {
+			fsys = &ArchiveFS{fsys: mem}
+		}
+	default:
+		err = fmt.Errorf("unsupported archive type")
+	}
+	if err != nil {
+		return nil, pathError("OpenArchiveFS", path, fmt.Errorf("OpenArchiveFS failed to open archive: %w", err))
+	}
+
+	return fsys, nil
+}
+
+func (a *ArchiveFS) Open(name string) (fs.File, error) {
+	return a.fsys.Open(name)
+}
+
+func (a *ArchiveFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(a.fsys, name)
+}
+
+func (a *ArchiveFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(a.fsys, name)
+}
+
+func (a *ArchiveFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(a.fsys, name)
+}
+
+// Close releases the archive file.
+func (a *ArchiveFS) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+
+	return a.closer.Close()
+}
+
+// archiveKind returns "zip", "tar" or "tgz" based on the extension of path, or "" for
+// anything else.
+func archiveKind(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".zip"), strings.HasSuffix(lower, ".jar"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tgz"
+	default:
+		return ""
+	}
+}
+
+// splitArchivePath splits a path like "bundle.zip!/config/app.json" into the archive
+// and the slash-separated name inside it. It only reports ok if the part before the
+// separator has an archive extension and is a regular file, so ordinary paths that
+// happen to contain "!/" keep working.
+func splitArchivePath(path string) (archive, name string, ok bool) {
+	slashed := strings.ReplaceAll(path, "\\", "/")
+	index := strings.Index(slashed, archiveSeparator)
+	if index < 0 {
+		return "", "", false
+	}
+
+	archive, name = path[:index], strings.Trim(slashed[index+len(archiveSeparator):], "/")
+	if archiveKind(archive) == "" {
+		return "", "", false
+	}
+	info, err := os.Stat(normalizePath(archive))
+	if err != nil || !info.Mode().IsRegular() {
+		return "", "", false
+	}
+	if name == "" {
+		name = "."
+	}
+
+	return archive, name, true
+}
+
+// withArchive opens an archive, runs fn on it and closes it again.
+func withArchive(archive string, fn func(fsys *ArchiveFS) error) error {
+	fsys, err := OpenArchiveFS(archive)
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	return fn(fsys)
+}
+
+// readArchiveFile reads a single file out of an archive.
+func readArchiveFile(archive, name string) (content []byte, err error) {
+	err = withArchive(archive, func(fsys *ArchiveFS) error {
+		content, err = fsys.ReadFile(name)
+		return err
+	})
+
+	return content, err
+}
+
+// readArchiveDir lists a directory inside an archive.
+func readArchiveDir(archive, name string) ([]string, error) {
+	var names []string
+	err := withArchive(archive, func(fsys *ArchiveFS) error {
+		entries, err := fsys.ReadDir(name)
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return err
+	})
+
+	return names, err
+}
+
+// walkArchive returns the files below a directory inside an archive, as archive paths
+// that can be passed back to ReadBytes.
+func walkArchive(archive, root string) ([]string, error) {
+	var files []string
+	err := withArchive(archive, func(fsys *ArchiveFS) error {
+		return fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				files = append(files, archive+archiveSeparator+name)
+			}
+			return nil
+		})
+	})
+
+	return files, err
+}
+
+// readArchiveTree returns the stat of root inside an archive and, if it is a directory,
+// the entries of every directory below it, keyed by their path relative to root.
+func readArchiveTree(archive, root string) (fs.FileInfo, map[string][]fs.DirEntry, error) {
+	var info fs.FileInfo
+	dirs := make(map[string][]fs.DirEntry)
+	err := withArchive(archive, func(fsys *ArchiveFS) error {
+		var err error
+		info, err = fsys.Stat(root)
+		if err != nil || !info.IsDir() {
+			return err
+		}
+
+		return fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return err
+			}
+			entries, err := fsys.ReadDir(name)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(filepath.FromSlash(root), filepath.FromSlash(name))
+			if err != nil {
+				return err
+			}
+			dirs[rel] = entries
+			return nil
+		})
+	})
+
+	return info, dirs, err
+}
+
+// globArchive calls yield with the archive paths of the names inside an archive matching
+// a slash-separated pattern, in lexical order, until yield returns false.
+func globArchive(archive, pattern string, opts GlobOptions, yield func(string) bool) error {
+	if pattern == "." {
+		yield(archive + archiveSeparator)
+		return nil
+	}
+
+	components := strings.Split(pattern, "/")
+	for i, component := range components {
+		if opts.CaseInsensitive {
+			components[i] = strings.ToLower(component)
+		}
+		if _, err := path.Match(components[i], ""); err != nil {
+			return err
+		}
+	}
+	base := 0
+	for base < len(components) && !hasGlobMeta(components[base]) {
+		base++
+	}
+
+	return withArchive(archive, func(fsys *ArchiveFS) error {
+		return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil || name == "." {
+				return err
+			}
+
+			// Only directories matching a prefix of the pattern are walked into
+			parts := strings.Split(name, "/")
+			for i, part := range parts {
+				if opts.CaseInsensitive {
+					part = strings.ToLower(part)
+				}
+				if ok, _ := path.Match(components[i], part); !ok {
+					if d.IsDir() {
+						return fs.SkipDir
+					}
+					return nil
+				}
+			}
+			if len(parts) < len(components) {
+				return nil
+			}
+
+			if !opts.Ignore.Match(strings.Join(parts[base:], "/"), d.IsDir()) && !yield(archive+archiveSeparator+name) {
+				return fs.SkipAll
+			}
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		})
+	})
+}
+
+// readTar loads a tar archive into memory, decompressing it first if gzipped is set.
+func readTar(archivePath string, gzipped bool) (memFS, error) {
+	file, err := os.Open(normalizePath(archivePath))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if gzipped {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	mem := memFS{".": {name: ".", mode: fs.ModeDir | 0755}}
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return mem, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(strings.TrimLeft(header.Name, "/"))
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			mem.add(name, &memEntry{mode: fs.ModeDir | header.FileInfo().Mode().Perm(), modTime: header.ModTime})
+		case tar.TypeReg:
+			content, err := io.ReadAll(archive)
+			if err != nil {
+				return nil, err
+			}
+			mem.add(name, &memEntry{data: content, mode: header.FileInfo().Mode().Perm(), modTime: header.ModTime})
+		}
+	}
+}
+
+// memFS is an in-memory, read-only file system keyed by slash-separated names.
+type memFS map[string]*memEntry
+
+// memEntry is a file or directory in a memFS. It doubles as its own fs.FileInfo.
+type memEntry struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (e *memEntry) Name() string       { return e.name }
+func (e *memEntry) Size() int64        { return int64(len(e.data)) }
+func (e *memEntry) Mode() fs.FileMode  { return e.mode }
+func (e *memEntry) ModTime() time.Time { return e.modTime }
+func (e *memEntry) IsDir() bool        { return e.mode.IsDir() }
+func (e *memEntry) Sys() any           { return nil }
+
+// add stores an entry, creating any missing parent directories.
+func (m memFS) add(name string, entry *memEntry) {
+	entry.name = path.Base(name)
+	if existing, ok := m[name]; ok && existing.IsDir() && entry.IsDir() {
+		existing.mode, existing.modTime = entry.mode, entry.modTime
+	} else {
+		m[name] = entry
+	}
+
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := m[dir]; ok {
+			break
+		}
+		m[dir] = &memEntry{name: path.Base(dir), mode: fs.ModeDir | 0755, modTime: entry.modTime}
+	}
+}
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if !entry.IsDir() {
+		return &memFile{entry: entry, Reader: bytes.NewReader(entry.data)}, nil
+	}
+
+	var entries []fs.DirEntry
+	for key, child := range m {
+		if key != "." && path.Dir(key) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(child))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return &memDir{entry: entry, entries: entries}, nil
+}
+
+// memFile is an open file of a memFS.
+type memFile struct {
+	*bytes.Reader
+	entry *memEntry
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	return f.entry, nil
+}
+
+func (f *memFile) Close() error {
+	return nil
+}
+
+// memDir is an open directory of a memFS.
+type memDir struct {
+	entry   *memEntry
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) {
+	return d.entry, nil
+}
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.entry.name, Err: syscall.EISDIR}
+}
+
+func (d *memDir) Close() error {
+	return nil
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,215 @@
+package fs_go
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testArchiveFiles = map[string]string{
+	"config/app.json":  `{"name":"app"}`,
+	"assets/a.txt":     "a",
+	"assets/img/b.txt": "b",
+}
+
+func writeTestZip(t *testing.T, path string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("os.Create failed: %v", err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, content := range testArchiveFiles {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("zip.Writer.Create failed: %v", err)
+		}
+		entry.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("zip.Writer.Close failed: %v", err)
+	}
+}
+
+func writeTestTarGz(t *testing.T, path string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("os.Create failed: %v", err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	writer := tar.NewWriter(gz)
+	for name, content := range testArchiveFiles {
+		header := &tar.Header{Name: "./" + name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatalf("tar.Writer.WriteHeader failed: %v", err)
+		}
+		writer.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("tar.Writer.Close failed: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip.Writer.Close failed: %v", err)
+	}
+}
+
+func TestOpenArchiveFS(t *testing.T) {
+	// Expect zip archives to be valid file systems
+	t.Run("zip", func(t *testing.T) {
+		path := "open_archive_fs_1.zip"
+		defer os.Remove(path)
+		writeTestZip(t, path)
+
+		fsys, err := OpenArchiveFS(path)
+		if err != nil {
+			t.Fatalf("OpenArchiveFS failed: %v", err)
+		}
+		defer fsys.Close()
+
+		err = fstest.TestFS(fsys, "config/app.json", "assets/a.txt", "assets/img/b.txt")
+		if err != nil {
+			t.Errorf("fstest.TestFS failed: %v", err)
+		}
+	})
+
+	// Expect gzipped tar archives to be valid file systems, with directories synthesized
+	t.Run("tar.gz", func(t *testing.T) {
+		path := "open_archive_fs_2.tar.gz"
+		defer os.Remove(path)
+		writeTestTarGz(t, path)
+
+		fsys, err := OpenArchiveFS(path)
+		if err != nil {
+			t.Fatalf("OpenArchiveFS failed: %v", err)
+		}
+		defer fsys.Close()
+
+		err = fstest.TestFS(fsys, "config/app.json", "assets/a.txt", "assets/img/b.txt")
+		if err != nil {
+			t.Errorf("fstest.TestFS failed: %v", err)
+		}
+
+		content, err := fsys.ReadFile("assets/img/b.txt")
+		if err != nil || string(content) != "b" {
+			t.Errorf("Expected %q, got %q (%v)", "b", content, err)
+		}
+	})
+
+	// Expect unknown archive types to be rejected
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := OpenArchiveFS("open_archive_fs_3.rar")
+		if err == nil {
+			t.Errorf("Expected OpenArchiveFS to fail")
+		}
+	})
+}
+
+func TestArchivePaths(t *testing.T) {
+	path := "archive_paths_1.zip"
+	defer os.Remove(path)
+	writeTestZip(t, path)
+
+	// Expect read functions to read files inside the archive
+	t.Run("read", func(t *testing.T) {
+		var v struct{ Name string }
+		err := ReadJson(path+"!/config/app.json", &v)
+		if err != nil || v.Name != "app" {
+			t.Errorf("Expected name app, got %q (%v)", v.Name, err)
+		}
+
+		content, err := ReadText(path + "!/assets/a.txt")
+		if err != nil || content != "a" {
+			t.Errorf("Expected %q, got %q (%v)", "a", content, err)
+		}
+
+		_, err = ReadText(path + "!/missing.txt")
+		if !strings.Contains(err.Error(), "does not exist") {
+			t.Errorf("Expected a not exist error, got %v", err)
+		}
+	})
+
+	// Expect directories inside the archive to be listed
+	t.Run("list", func(t *testing.T) {
+		names, err := ReadDir(path + "!/assets")
+		sort.Strings(names)
+		if err != nil || strings.Join(names, ",") != "a.txt,img" {
+			t.Errorf("Expected a.txt,img, got %v (%v)", names, err)
+		}
+
+		files, err := ReadDirRec(path + "!/")
+		if err != nil || len(files) != 3 {
+			t.Fatalf("Expected 3 files, got %v (%v)", files, err)
+		}
+		for _, file := range files {
+			if _, err := ReadText(file); err != nil {
+				t.Errorf("Expected %s to be readable: %v", file, err)
+			}
+		}
+	})
+
+	// Expect patterns inside the archive to be matched against its names
+	t.Run("glob", func(t *testing.T) {
+		matches, err := Glob(path + "!/assets/*.txt")
+		if err != nil || strings.Join(matches, ",") != path+"!/assets/a.txt" {
+			t.Errorf("Expected %s!/assets/a.txt, got %v (%v)", path, matches, err)
+		}
+
+		matches, err = GlobWithOptions(path+"!/*/*", GlobOptions{CaseInsensitive: true, Ignore: NewIgnorer("img")})
+		expected := []string{path + "!/assets/a.txt", path + "!/config/app.json"}
+		if err != nil || strings.Join(matches, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected %v, got %v (%v)", expected, matches, err)
+		}
+
+		matches, err = GlobWithOptions(path+"!/ASSETS/IMG/*", GlobOptions{CaseInsensitive: true})
+		if err != nil || strings.Join(matches, ",") != path+"!/assets/img/b.txt" {
+			t.Errorf("Expected %s!/assets/img/b.txt, got %v (%v)", path, matches, err)
+		}
+	})
+
+	// Expect the options and iterator variants to walk inside the archive
+	t.Run("iterate", func(t *testing.T) {
+		files, err := ReadDirRecWithOptions(path+"!/assets", ReadDirRecOptions{Paths: PathRelative, IncludeDirs: true})
+		expected := []string{"a.txt", "img", filepath.Join("img", "b.txt")}
+		if err != nil || strings.Join(files, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected %v, got %v (%v)", expected, files, err)
+		}
+
+		it, err := IterDirRec(path+"!/", ReadDirRecOptions{})
+		if err != nil {
+			t.Fatalf("IterDirRec failed: %v", err)
+		}
+		var count int
+		for it.Next() {
+			count++
+			if _, err := ReadText(it.Path()); err != nil {
+				t.Errorf("Expected %s to be readable: %v", it.Path(), err)
+			}
+		}
+		if it.Err() != nil || count != 3 {
+			t.Errorf("Expected 3 files, got %d (%v)", count, it.Err())
+		}
+	})
+
+	// Expect paths that aren't inside an archive to be left alone
+	t.Run("plain", func(t *testing.T) {
+		if _, _, ok := splitArchivePath("notes!/todo.txt"); ok {
+			t.Errorf("Expected a path without archive extension to not be split")
+		}
+		if _, _, ok := splitArchivePath("missing.zip!/todo.txt"); ok {
+			t.Errorf("Expected a path to a missing archive to not be split")
+		}
+	})
+}
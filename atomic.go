@@ -0,0 +1,77 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeAtomic writes content to a temporary file next to path and renames it into place,
+// so readers observe either the old or the new content, never a partial write.
+// If durable is set, the file and its parent directory are synced to disk before returning.
+func writeAtomic(path string, content []byte, mode os.FileMode, durable bool) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("WriteBytes", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmp := file.Name()
+
+	err = writeAndClose(file, content, mode, durable)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	if durable {
+		err = syncDir(dir)
+		if err != nil {
+			return fmt.Errorf("failed to sync directory: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeAndClose writes content to an open file, sets its mode, optionally syncs it, and closes it.
+func writeAndClose(file *os.File, content []byte, mode os.FileMode, durable bool) error {
+	_, err := file.Write(content)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write content: %w", err)
+	}
+
+	err = file.Chmod(mode)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+
+	if durable {
+		err = file.Sync()
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to sync file: %w", err)
+		}
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	return nil
+}
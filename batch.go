@@ -0,0 +1,100 @@
+package fs_go
+
+import "fmt"
+
+// Batch is a list of file operations run in order, collecting the errors of the ones
+// that fail instead of stopping at the first, which suits setup scripts. Build it with
+// NewBatch and the chainable methods, then call Run.
+type Batch struct {
+	ops         []batchOp
+	stopOnError bool
+}
+
+// batchOp is a single operation of a Batch.
+type batchOp struct {
+	name string
+	path string
+	run  func() error
+}
+
+// NewBatch returns an empty Batch.
+//
+// Example:
+//
+//	err := NewBatch().
+//	    Mkdir("app/config").
+//	    Copy("defaults/app.yaml", "app/config/app.yaml").
+//	    Write("app/VERSION", []byte(version)).
+//	    Run()
+//	var multi *MultiError
+//	if errors.As(err, &multi) {
+//	    for _, err := range multi.Errors {
+//	        fmt.Println(err)
+//	    }
+//	}
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// StopOnError makes Run stop at the first failure, skipping the remaining operations.
+func (b *Batch) StopOnError() *Batch {
+	b.stopOnError = true
+	return b
+}
+
+// Copy adds a CopyFile from src to dst.
+func (b *Batch) Copy(src, dst string) *Batch {
+	return b.add("Copy", src, func() error { return CopyFile(src, dst) })
+}
+
+// Move adds a Move from src to dst.
+func (b *Batch) Move(src, dst string) *Batch {
+	return b.add("Move", src, func() error { return Move(src, dst) })
+}
+
+// Mkdir adds an EnsureDir for path.
+func (b *Batch) Mkdir(path string) *Batch {
+	return b.add("Mkdir", path, func() error { return EnsureDir(path) })
+}
+
+// Write adds a WriteBytes of content to path.
+func (b *Batch) Write(path string, content []byte) *Batch {
+	return b.add("Write", path, func() error { return WriteBytes(path, content) })
+}
+
+// Remove adds a RemoveAll of path.
+func (b *Batch) Remove(path string) *Batch {
+	return b.add("Remove", path, func() error { return RemoveAll(path) })
+}
+
+// Len returns the number of operations in the batch.
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Run runs the operations in order. If any fail, it returns a *MultiError with an error
+// for each failed operation, naming the operation and its path.
+func (b *Batch) Run() error {
+	var errs []error
+	for i, op := range b.ops {
+		err := op.run()
+		if err == nil {
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("%s %s (operation %d): %w", op.name, op.path, i+1, err))
+		if b.stopOnError {
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return &MultiError{Errors: errs}
+	}
+
+	return nil
+}
+
+func (b *Batch) add(name, path string, run func() error) *Batch {
+	b.ops = append(b.ops, batchOp{name: name, path: path, run: run})
+	return b
+}
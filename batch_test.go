@@ -0,0 +1,79 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	// Expect all operations to run in order
+	t.Run("run", func(t *testing.T) {
+		root := "batch_1"
+		defer os.RemoveAll(root)
+
+		err := NewBatch().
+			Mkdir(filepath.Join(root, "sub")).
+			Write(filepath.Join(root, "sub", "a.txt"), []byte("a")).
+			Copy(filepath.Join(root, "sub", "a.txt"), filepath.Join(root, "b.txt")).
+			Move(filepath.Join(root, "b.txt"), filepath.Join(root, "c.txt")).
+			Remove(filepath.Join(root, "sub")).
+			Run()
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		if content, _ := os.ReadFile(filepath.Join(root, "c.txt")); string(content) != "a" {
+			t.Errorf("Expected a in c.txt, got %q", content)
+		}
+		if _, err := os.Stat(filepath.Join(root, "sub")); !os.IsNotExist(err) {
+			t.Errorf("Expected sub to be removed, got %v", err)
+		}
+	})
+
+	// Expect failures to be collected while later operations still run
+	t.Run("partial failure", func(t *testing.T) {
+		root := "batch_2"
+		defer os.RemoveAll(root)
+		os.Mkdir(root, 0755)
+
+		err := NewBatch().
+			Copy(filepath.Join(root, "missing"), filepath.Join(root, "x")).
+			Write(filepath.Join(root, "ok.txt"), []byte("ok")).
+			Move(filepath.Join(root, "missing"), filepath.Join(root, "y")).
+			Run()
+
+		var multi *MultiError
+		if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+			t.Fatalf("Expected 2 errors, got %v", err)
+		}
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected errors to match ErrNotExist, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "ok.txt")); err != nil {
+			t.Errorf("Expected ok.txt to be written, got %v", err)
+		}
+	})
+
+	// Expect StopOnError to skip the rest after a failure
+	t.Run("stop on error", func(t *testing.T) {
+		root := "batch_3"
+		defer os.RemoveAll(root)
+		os.Mkdir(root, 0755)
+
+		err := NewBatch().
+			StopOnError().
+			Copy(filepath.Join(root, "missing"), filepath.Join(root, "x")).
+			Write(filepath.Join(root, "skipped.txt"), []byte("")).
+			Run()
+
+		var multi *MultiError
+		if !errors.As(err, &multi) || len(multi.Errors) != 1 {
+			t.Fatalf("Expected 1 error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "skipped.txt")); !os.IsNotExist(err) {
+			t.Errorf("Expected skipped.txt not to be written, got %v", err)
+		}
+	})
+}
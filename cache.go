@@ -0,0 +1,215 @@
+package fs_go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheOptions configures a Cache.
+type CacheOptions struct {
+	// MaxSize is the total size in bytes the cache may grow to before the least recently
+	// used entries are evicted. Zero disables eviction.
+	MaxSize int64
+}
+
+// Cache memoizes expensive results, like HTTP responses or build outputs, in files
+// under a directory. Entries are written atomically, so concurrent processes sharing
+// the directory never read partial values. It is safe for concurrent use.
+type Cache struct {
+	root string
+	opts CacheOptions
+	// mu serializes eviction
+	mu sync.Mutex
+}
+
+// cacheExt marks cache entries, so eviction never touches other files in the directory.
+const cacheExt = ".cache"
+
+// OpenCache returns the cache in root, creating the directory if needed.
+//
+// Example:
+//
+//	cache, err := OpenCache(filepath.Join(os.TempDir(), "myapp"), CacheOptions{
+//	    MaxSize: 100 << 20,
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func OpenCache(root string, opts CacheOptions) (*Cache, error) {
+	err := EnsureDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("OpenCache failed to create directory: %w", err)
+	}
+
+	return &Cache{root: root, opts: opts}, nil
+}
+
+// GetOrFill returns the cached value for key if it was stored less than ttl ago, and
+// otherwise calls fill and caches its result. A ttl of zero never expires. Errors from
+// fill are returned as is and nothing is cached. Concurrent calls for the same key
+// within a process call fill once.
+//
+// Example:
+//
+//	body, err := cache.GetOrFill(url, time.Hour, func() ([]byte, error) {
+//	    return fetch(url)
+//	})
+func (c *Cache) GetOrFill(key string, ttl time.Duration, fill func() ([]byte, error)) ([]byte, error) {
+	var content []byte
+	err := Locked(c.path(key)).Do(func(path string) error {
+		var hit bool
+		var err error
+		content, hit, err = c.get(path, ttl)
+		if err != nil || hit {
+			return err
+		}
+
+		content, err = fill()
+		if err != nil {
+			return err
+		}
+
+		err = writeAtomic(normalizePath(path), content, 0644, false)
+		if err != nil {
+			return fmt.Errorf("GetOrFill failed to store %q: %w", key, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.opts.MaxSize > 0 {
+		err = c.Prune()
+		if err != nil {
+			return content, fmt.Errorf("GetOrFill failed to evict entries: %w", err)
+		}
+	}
+
+	return content, nil
+}
+
+// Delete removes the entry for key. Deleting a missing entry succeeds.
+func (c *Cache) Delete(key string) error {
+	err := Locked(c.path(key)).Do(func(path string) error {
+		err := Remove(path)
+		if errors.Is(err, ErrNotExist) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Delete failed to remove %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// Prune evicts the least recently used entries until the cache fits in MaxSize.
+// GetOrFill calls it after storing a value.
+func (c *Cache) Prune() error {
+	if c.opts.MaxSize <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(normalizePath(c.root))
+	if err != nil {
+		return fmt.Errorf("Prune failed to list cache: %w", err)
+	}
+
+	type cacheEntry struct {
+		path string
+		size int64
+		used time.Time
+	}
+	var files []cacheEntry
+	var total int64
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), cacheExt) || !entry.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(c.root, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheEntry{path: path, size: info.Size(), used: lastUsed(normalizePath(path), info)})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].used.Before(files[j].used)
+	})
+	for _, file := range files {
+		if total <= c.opts.MaxSize {
+			break
+		}
+		err := Remove(file.path)
+		if err != nil && !errors.Is(err, ErrNotExist) {
+			return fmt.Errorf("Prune failed to evict %s: %w", file.path, err)
+		}
+		total -= file.size
+	}
+
+	return nil
+}
+
+// path returns the file of a key. Keys are hashed, so any string is a valid key.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.root, hex.EncodeToString(sum[:])+cacheExt)
+}
+
+// get reads a fresh entry and marks it as used.
+func (c *Cache) get(path string, ttl time.Duration) ([]byte, bool, error) {
+	info, err := os.Stat(normalizePath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if ttl > 0 && time.Since(info.ModTime()) >= ttl {
+		return nil, false, nil
+	}
+
+	content, err := os.ReadFile(normalizePath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		// Evicted since the stat
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The access time records use for eviction. It is set explicitly, since many
+	// filesystems are mounted with noatime, and the modification time is kept for ttl.
+	if !IsDryRun() && !IsReadOnly() {
+		os.Chtimes(normalizePath(path), time.Now(), info.ModTime())
+	}
+
+	return content, true, nil
+}
+
+// lastUsed returns when a cache entry was last used, falling back to when it was stored
+// where access times are unknown.
+func lastUsed(path string, info os.FileInfo) time.Time {
+	used := metaFromInfo(path, info).AccessTime
+	if used.IsZero() {
+		return info.ModTime()
+	}
+
+	return used
+}
@@ -0,0 +1,147 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	// Expect fill to be called once and its result to be returned from the cache after
+	t.Run("fill", func(t *testing.T) {
+		path := "cache_1"
+		defer os.RemoveAll(path)
+		cache, err := OpenCache(path, CacheOptions{})
+		if err != nil {
+			t.Fatalf("OpenCache failed: %v", err)
+		}
+
+		calls := 0
+		fill := func() ([]byte, error) {
+			calls++
+			return []byte("value"), nil
+		}
+		for i := 0; i < 3; i++ {
+			content, err := cache.GetOrFill("key", 0, fill)
+			if err != nil || string(content) != "value" {
+				t.Fatalf("Expected value, got %q (%v)", content, err)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("Expected fill to be called once, got %d", calls)
+		}
+	})
+
+	// Expect expired entries to be filled again
+	t.Run("ttl", func(t *testing.T) {
+		path := "cache_2"
+		defer os.RemoveAll(path)
+		cache, err := OpenCache(path, CacheOptions{})
+		if err != nil {
+			t.Fatalf("OpenCache failed: %v", err)
+		}
+
+		cache.GetOrFill("key", time.Minute, func() ([]byte, error) { return []byte("old"), nil })
+		stale := time.Now().Add(-time.Hour)
+		os.Chtimes(cache.path("key"), stale, stale)
+
+		content, err := cache.GetOrFill("key", time.Minute, func() ([]byte, error) { return []byte("new"), nil })
+		if err != nil || string(content) != "new" {
+			t.Errorf("Expected new, got %q (%v)", content, err)
+		}
+	})
+
+	// Expect fill errors to be returned and nothing to be cached
+	t.Run("error", func(t *testing.T) {
+		path := "cache_3"
+		defer os.RemoveAll(path)
+		cache, err := OpenCache(path, CacheOptions{})
+		if err != nil {
+			t.Fatalf("OpenCache failed: %v", err)
+		}
+
+		failure := errors.New("unavailable")
+		_, err = cache.GetOrFill("key", 0, func() ([]byte, error) { return nil, failure })
+		if !errors.Is(err, failure) {
+			t.Errorf("Expected fill error, got %v", err)
+		}
+		if _, err := os.Stat(cache.path("key")); !os.IsNotExist(err) {
+			t.Errorf("Expected nothing to be cached, got %v", err)
+		}
+	})
+
+	// Expect the least recently used entries to be evicted beyond MaxSize
+	t.Run("evict", func(t *testing.T) {
+		path := "cache_4"
+		defer os.RemoveAll(path)
+		cache, err := OpenCache(path, CacheOptions{MaxSize: 10})
+		if err != nil {
+			t.Fatalf("OpenCache failed: %v", err)
+		}
+
+		value := func() ([]byte, error) { return []byte("12345"), nil }
+		cache.GetOrFill("a", 0, value)
+		cache.GetOrFill("b", 0, value)
+		old := time.Now().Add(-time.Hour)
+		os.Chtimes(cache.path("a"), old, old)
+		cache.GetOrFill("c", 0, value)
+
+		if _, err := os.Stat(cache.path("a")); !os.IsNotExist(err) {
+			t.Errorf("Expected a to be evicted, got %v", err)
+		}
+		for _, key := range []string{"b", "c"} {
+			if _, err := os.Stat(cache.path(key)); err != nil {
+				t.Errorf("Expected %s to be kept, got %v", key, err)
+			}
+		}
+	})
+
+	// Expect concurrent calls for the same key to fill once
+	t.Run("concurrent", func(t *testing.T) {
+		path := "cache_5"
+		defer os.RemoveAll(path)
+		cache, err := OpenCache(path, CacheOptions{})
+		if err != nil {
+			t.Fatalf("OpenCache failed: %v", err)
+		}
+
+		var calls atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				cache.GetOrFill("key", 0, func() ([]byte, error) {
+					calls.Add(1)
+					return []byte("value"), nil
+				})
+			}()
+		}
+		wg.Wait()
+
+		if calls.Load() != 1 {
+			t.Errorf("Expected fill to be called once, got %d", calls.Load())
+		}
+	})
+
+	// Expect Delete to remove an entry and succeed for missing ones
+	t.Run("delete", func(t *testing.T) {
+		path := "cache_6"
+		defer os.RemoveAll(path)
+		cache, err := OpenCache(path, CacheOptions{})
+		if err != nil {
+			t.Fatalf("OpenCache failed: %v", err)
+		}
+
+		cache.GetOrFill("key", 0, func() ([]byte, error) { return []byte("value"), nil })
+		if err := cache.Delete("key"); err != nil {
+			t.Errorf("Delete failed: %v", err)
+		}
+		if err := cache.Delete("key"); err != nil {
+			t.Errorf("Expected deleting a missing entry to succeed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,390 @@
+// Package cas implements a content-addressable store on disk.
+//
+// Content is stored once under its SHA-256 checksum, so storing the same content twice
+// costs nothing, and objects can be hard-linked into place instead of copied. Objects
+// live in a sharded layout, "<root>/ab/abcdef...", to keep directories small.
+// Writes honor the read-only and dry-run modes of fs_go, set with fs_go.SetReadOnly and
+// fs_go.SetDryRun.
+//
+// Example:
+//
+//	store, err := cas.Open(".cache/objects")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	key, err := store.Put(output)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	content, err := store.Get(key)
+package cas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	fs_go "github.com/frodi-karlsson/fs_go"
+)
+
+// ErrInvalidKey means a key isn't a hex-encoded SHA-256 checksum.
+var ErrInvalidKey = errors.New("invalid key")
+
+// Key identifies content in a Store. It is the hex-encoded SHA-256 checksum of the content.
+type Key string
+
+// Valid reports whether the key is a well-formed checksum.
+func (k Key) Valid() bool {
+	if len(k) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(string(k))
+	return err == nil
+}
+
+// Store is a content-addressable store rooted at a directory.
+// It is safe for concurrent use, also by multiple processes.
+type Store struct {
+	root string
+}
+
+// tmpDir holds objects being written. It lives inside the store so the final rename
+// never crosses devices.
+const tmpDir = "tmp"
+
+// Open returns the store rooted at root, creating the directory if needed.
+func Open(root string) (*Store, error) {
+	err := fs_go.EnsureDir(filepath.Join(root, tmpDir))
+	if err != nil {
+		return nil, fmt.Errorf("Open failed to create store: %w", err)
+	}
+
+	return &Store{root: root}, nil
+}
+
+// Path returns the path of the object for a key, whether it exists or not.
+// Objects are read-only and must not be modified.
+func (s *Store) Path(key Key) (string, error) {
+	if !key.Valid() {
+		return "", fmt.Errorf("Path failed: %w: %q", ErrInvalidKey, key)
+	}
+
+	return s.path(key), nil
+}
+
+// path returns the path of the object for a valid key.
+func (s *Store) path(key Key) string {
+	return filepath.Join(s.root, string(key[:2]), string(key))
+}
+
+// Put stores content and returns its key.
+func (s *Store) Put(content []byte) (Key, error) {
+	return s.put(func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	})
+}
+
+// PutReader stores everything read from r and returns its key.
+func (s *Store) PutReader(r io.Reader) (Key, error) {
+	return s.put(func(w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+}
+
+// PutFile stores the content of a file and returns its key.
+func (s *Store) PutFile(path string) (Key, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("PutFile failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.PutReader(file)
+}
+
+// put writes content to a temporary file while hashing it, then moves it into place.
+// If the object already exists, the temporary file is dropped. Like the writes of fs_go,
+// it honors read-only and dry-run mode, where content is only hashed.
+func (s *Store) put(write func(w io.Writer) error) (Key, error) {
+	if skip, err := fs_go.WriteGuard("Put", s.root, ""); skip {
+		if err != nil {
+			return "", err
+		}
+		hash := sha256.New()
+		err = write(hash)
+		if err != nil {
+			return "", fmt.Errorf("Put failed to hash content: %w", err)
+		}
+		return Key(hex.EncodeToString(hash.Sum(nil))), nil
+	}
+
+	file, err := os.CreateTemp(filepath.Join(s.root, tmpDir), "put-*")
+	if err != nil {
+		return "", fmt.Errorf("Put failed to create temporary file: %w", err)
+	}
+	tmp := file.Name()
+	defer os.Remove(tmp)
+
+	hash := sha256.New()
+	err = write(io.MultiWriter(file, hash))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("Put failed to write content: %w", err)
+	}
+
+	key := Key(hex.EncodeToString(hash.Sum(nil)))
+	path := s.path(key)
+	if _, err := os.Stat(path); err == nil {
+		return key, nil
+	}
+
+	err = fs_go.EnsureDir(filepath.Dir(path))
+	if err != nil {
+		return "", fmt.Errorf("Put failed to create shard: %w", err)
+	}
+	err = os.Chmod(tmp, 0444)
+	if err != nil {
+		return "", fmt.Errorf("Put failed to make object read-only: %w", err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return "", fmt.Errorf("Put failed to move object into place: %w", err)
+	}
+
+	return key, nil
+}
+
+// Has reports whether the store holds content for key.
+func (s *Store) Has(key Key) bool {
+	if !key.Valid() {
+		return false
+	}
+
+	_, err := os.Stat(s.path(key))
+	return err == nil
+}
+
+// Get returns the content stored for key. It fails with fs_go.ErrNotExist if there is none.
+func (s *Store) Get(key Key) ([]byte, error) {
+	if !key.Valid() {
+		return nil, fmt.Errorf("Get failed: %w: %q", ErrInvalidKey, key)
+	}
+
+	content, err := fs_go.ReadBytes(s.path(key))
+	if err != nil {
+		return nil, fmt.Errorf("Get failed to read object: %w", err)
+	}
+
+	return content, nil
+}
+
+// Open opens the content stored for key for streaming reads.
+func (s *Store) Open(key Key) (*os.File, error) {
+	if !key.Valid() {
+		return nil, fmt.Errorf("Open failed: %w: %q", ErrInvalidKey, key)
+	}
+
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, fmt.Errorf("Open failed to open object: %w", err)
+	}
+
+	return file, nil
+}
+
+// Link makes the content stored for key available at dst, replacing anything there.
+// The object is hard-linked when possible, so many checkouts of the same content share
+// disk space, and copied otherwise, for example across devices. Hard-linked files are
+// read-only, since writing to them would change the stored object.
+func (s *Store) Link(key Key, dst string) error {
+	if !key.Valid() {
+		return fmt.Errorf("Link failed: %w: %q", ErrInvalidKey, key)
+	}
+
+	path := s.path(key)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("Link failed to find object: %w", err)
+	}
+	if skip, err := fs_go.WriteGuard("Link", path, dst); skip {
+		return err
+	}
+
+	err := os.Remove(dst)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Link failed to replace %s: %w", dst, err)
+	}
+
+	if os.Link(path, dst) == nil {
+		return nil
+	}
+
+	err = fs_go.CopyFile(path, dst)
+	if err != nil {
+		return fmt.Errorf("Link failed to copy object: %w", err)
+	}
+	err = os.Chmod(dst, 0644)
+	if err != nil {
+		return fmt.Errorf("Link failed to make copy writable: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the content stored for key. Deleting a missing key succeeds.
+func (s *Store) Delete(key Key) error {
+	if !key.Valid() {
+		return fmt.Errorf("Delete failed: %w: %q", ErrInvalidKey, key)
+	}
+
+	path := s.path(key)
+	if skip, err := fs_go.WriteGuard("Delete", path, ""); skip {
+		return err
+	}
+
+	err := removeObject(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Delete failed to remove object: %w", err)
+	}
+
+	return nil
+}
+
+// Keys returns the keys of all stored objects.
+func (s *Store) Keys() ([]Key, error) {
+	var keys []Key
+	err := s.walk(func(key Key, path string, info os.FileInfo) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Keys failed to list objects: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GCResult describes what a garbage collection removed.
+type GCResult struct {
+	Removed int   // Objects removed
+	Freed   int64 // Bytes freed, not counting objects still hard-linked elsewhere
+}
+
+// GC removes every object for which keep returns false, along with temporary files
+// left behind by writers that crashed more than an hour ago.
+//
+// Example:
+//
+//	live := map[cas.Key]bool{}
+//	for _, entry := range manifest {
+//	    live[entry.Key] = true
+//	}
+//	result, err := store.GC(func(key cas.Key) bool { return live[key] })
+func (s *Store) GC(keep func(Key) bool) (GCResult, error) {
+	var result GCResult
+	err := s.walk(func(key Key, path string, info os.FileInfo) error {
+		if keep(key) {
+			return nil
+		}
+
+		skip, err := fs_go.WriteGuard("GC", path, "")
+		if err != nil {
+			return err
+		}
+		if !skip {
+			err = removeObject(path)
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Removed++
+		if linkCount(info) <= 1 {
+			result.Freed += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("GC failed to remove objects: %w", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.root, tmpDir))
+	if err != nil {
+		return result, fmt.Errorf("GC failed to read temporary files: %w", err)
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) <= time.Hour {
+			continue
+		}
+		path := filepath.Join(s.root, tmpDir, entry.Name())
+		if skip, _ := fs_go.WriteGuard("GC", path, ""); !skip {
+			os.Remove(path)
+		}
+	}
+
+	return result, nil
+}
+
+// removeObject removes an object, making it writable first if needed, since Windows
+// refuses to delete read-only files.
+func removeObject(path string) error {
+	err := os.Remove(path)
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if os.Chmod(path, 0644) != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// walk calls fn for every object in the store.
+func (s *Store) walk(fn func(key Key, path string, info os.FileInfo) error) error {
+	shards, err := os.ReadDir(s.root)
+	if err != nil {
+		return err
+	}
+
+	for _, shard := range shards {
+		if !shard.IsDir() || len(shard.Name()) != 2 {
+			continue
+		}
+
+		dir := filepath.Join(s.root, shard.Name())
+		objects, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, object := range objects {
+			key := Key(object.Name())
+			if !key.Valid() || string(key[:2]) != shard.Name() {
+				continue
+			}
+			info, err := object.Info()
+			if err != nil {
+				return err
+			}
+			err = fn(key, filepath.Join(dir, object.Name()), info)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package cas
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	fs_go "github.com/frodi-karlsson/fs_go"
+)
+
+func TestStore(t *testing.T) {
+	// Expect content to round-trip under its SHA-256 key in a sharded layout
+	t.Run("put and get", func(t *testing.T) {
+		root := "store_1"
+		defer os.RemoveAll(root)
+		store, err := Open(root)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+
+		key, err := store.Put([]byte("hello"))
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if key != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+			t.Errorf("Unexpected key %s", key)
+		}
+		if _, err := os.Stat(filepath.Join(root, "2c", string(key))); err != nil {
+			t.Errorf("Expected object in shard 2c: %v", err)
+		}
+
+		content, err := store.Get(key)
+		if err != nil || string(content) != "hello" {
+			t.Errorf("Expected hello, got %q (%v)", content, err)
+		}
+
+		path, err := store.Path(key)
+		if err != nil {
+			t.Fatalf("Path failed: %v", err)
+		}
+		again, err := store.PutFile(path)
+		if err != nil || again != key {
+			t.Errorf("Expected the same key for the same content, got %s (%v)", again, err)
+		}
+	})
+
+	// Expect invalid and missing keys to fail
+	t.Run("errors", func(t *testing.T) {
+		root := "store_2"
+		defer os.RemoveAll(root)
+		store, err := Open(root)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+
+		_, err = store.Get("../../etc/passwd")
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Expected ErrInvalidKey, got %v", err)
+		}
+		for _, key := range []Key{"", "a", "../../etc/passwd"} {
+			if _, err := store.Path(key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Expected ErrInvalidKey for %q, got %v", key, err)
+			}
+		}
+
+		_, err = store.Get(Key("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"))
+		if !errors.Is(err, fs_go.ErrNotExist) {
+			t.Errorf("Expected ErrNotExist, got %v", err)
+		}
+	})
+
+	// Expect objects to be linked out and collected when no longer kept
+	t.Run("link and gc", func(t *testing.T) {
+		root := "store_3"
+		dst := "store_3_checkout.txt"
+		defer os.RemoveAll(root)
+		defer os.Remove(dst)
+		store, err := Open(root)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+
+		keep, _ := store.Put([]byte("keep"))
+		drop, _ := store.Put([]byte("drop"))
+
+		if err := store.Link(keep, dst); err != nil {
+			t.Fatalf("Link failed: %v", err)
+		}
+		content, err := os.ReadFile(dst)
+		if err != nil || string(content) != "keep" {
+			t.Errorf("Expected linked content, got %q (%v)", content, err)
+		}
+		if runtime.GOOS != "windows" {
+			a, _ := os.Stat(dst)
+			path, _ := store.Path(keep)
+			b, _ := os.Stat(path)
+			if !os.SameFile(a, b) {
+				t.Errorf("Expected checkout to be a hard link")
+			}
+		}
+
+		result, err := store.GC(func(key Key) bool { return key == keep })
+		if err != nil {
+			t.Fatalf("GC failed: %v", err)
+		}
+		if result.Removed != 1 || result.Freed != 4 {
+			t.Errorf("Expected one object of 4 bytes removed, got %+v", result)
+		}
+		if store.Has(drop) || !store.Has(keep) {
+			t.Errorf("Expected only the kept object to remain")
+		}
+
+		keys, err := store.Keys()
+		if err != nil || len(keys) != 1 || keys[0] != keep {
+			t.Errorf("Expected only the kept key, got %v (%v)", keys, err)
+		}
+	})
+
+	// Expect writes to fail in read-only mode and to be skipped in dry-run mode
+	t.Run("read-only and dry-run", func(t *testing.T) {
+		root := "store_4"
+		dst := "store_4_checkout.txt"
+		defer os.RemoveAll(root)
+		defer os.Remove(dst)
+		store, err := Open(root)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		key, err := store.Put([]byte("hello"))
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		os.WriteFile(dst, []byte("mine"), 0644)
+
+		fs_go.SetReadOnly(true)
+		_, putErr := store.Put([]byte("other"))
+		linkErr := store.Link(key, dst)
+		deleteErr := store.Delete(key)
+		_, gcErr := store.GC(func(Key) bool { return false })
+		fs_go.SetReadOnly(false)
+		for name, err := range map[string]error{"Put": putErr, "Link": linkErr, "Delete": deleteErr, "GC": gcErr} {
+			if !errors.Is(err, fs_go.ErrReadOnly) {
+				t.Errorf("Expected ErrReadOnly from %s, got %v", name, err)
+			}
+		}
+
+		plan, err := fs_go.DryRun(func() error {
+			if other, err := store.Put([]byte("other")); err != nil || !other.Valid() {
+				return errors.Join(err, errors.New("Put returned no key"))
+			}
+			if err := store.Link(key, dst); err != nil {
+				return err
+			}
+			result, err := store.GC(func(Key) bool { return false })
+			if err == nil && result.Removed != 1 {
+				return errors.New("GC reported no removal")
+			}
+			return err
+		})
+		if err != nil || len(plan) != 3 {
+			t.Errorf("Expected 3 planned actions, got %v (%v)", plan, err)
+		}
+
+		if content, _ := os.ReadFile(dst); string(content) != "mine" {
+			t.Errorf("Expected %s to be left alone, got %q", dst, content)
+		}
+		if !store.Has(key) {
+			t.Errorf("Expected the object to be kept")
+		}
+		if keys, _ := store.Keys(); len(keys) != 1 {
+			t.Errorf("Expected no new objects, got %v", keys)
+		}
+	})
+}
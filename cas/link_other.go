@@ -0,0 +1,10 @@
+//go:build !unix
+
+package cas
+
+import "os"
+
+// linkCount returns 1, since link counts aren't available on this platform.
+func linkCount(info os.FileInfo) uint64 {
+	return 1
+}
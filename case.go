@@ -0,0 +1,115 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsCaseSensitiveFS reports whether the file system holding dir tells apart names that only
+// differ in case. Most Linux file systems do, while macOS and Windows don't by default.
+// It creates and removes a temporary file in dir to find out.
+//
+// Example:
+//
+//	sensitive, err := IsCaseSensitiveFS("build")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if !sensitive {
+//	    fmt.Println("warning: Foo.go and foo.go will collide")
+//	}
+func IsCaseSensitiveFS(dir string) (bool, error) {
+	dir = normalizePath(dir)
+
+	file, err := os.CreateTemp(dir, ".case-probe-*")
+	if err != nil {
+		return false, pathError("IsCaseSensitiveFS", dir, fmt.Errorf("IsCaseSensitiveFS failed to create probe file: %w", err))
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	_, err = os.Lstat(filepath.Join(dir, strings.ToUpper(filepath.Base(file.Name()))))
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, pathError("IsCaseSensitiveFS", dir, fmt.Errorf("IsCaseSensitiveFS failed to get probe stat: %w", err))
+	}
+
+	return false, nil
+}
+
+// FindCaseInsensitive resolves a path whose components may differ in case from the ones on
+// disk, and returns it with the casing actually used on disk. Components that exist as
+// given are kept as they are. If several entries match a component, the first in directory
+// order wins. It fails with ErrNotExist if no match exists.
+//
+// Example:
+//
+//	// Returns "Assets/Logo.PNG" if that is how the file is named
+//	path, err := FindCaseInsensitive("assets/logo.png")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func FindCaseInsensitive(path string) (string, error) {
+	path = normalizePath(path)
+
+	volume := filepath.VolumeName(path)
+	rest := path[len(volume):]
+	resolved := volume
+	if rest != "" && os.IsPathSeparator(rest[0]) {
+		resolved += string(filepath.Separator)
+	}
+
+	for _, component := range splitComponents(rest) {
+		candidate := joinGlob(resolved, component)
+		if component == "." || component == ".." {
+			resolved = candidate
+			continue
+		}
+
+		dir := resolved
+		if dir == "" {
+			dir = "."
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", pathError("FindCaseInsensitive", path, fmt.Errorf("FindCaseInsensitive failed to read directory: %w", err))
+		}
+
+		name := ""
+		for _, entry := range entries {
+			if entry.Name() == component {
+				name = component
+				break
+			}
+			if name == "" && strings.EqualFold(entry.Name(), component) {
+				name = entry.Name()
+			}
+		}
+		if name == "" {
+			return "", pathError("FindCaseInsensitive", path, fmt.Errorf("FindCaseInsensitive failed: %s: %w", candidate, ErrNotExist))
+		}
+		resolved = joinGlob(resolved, name)
+	}
+
+	return resolved, nil
+}
+
+// ExistsCaseInsensitive checks if a file or directory exists with any casing of path.
+func ExistsCaseInsensitive(path string) (bool, error) {
+	_, err := FindCaseInsensitive(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
@@ -0,0 +1,61 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindCaseInsensitive(t *testing.T) {
+	path := "find_case_insensitive"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"Assets/Logo.PNG": ""})
+
+	// Expect the on-disk casing to be returned
+	found, err := FindCaseInsensitive(filepath.Join(path, "assets", "logo.png"))
+	if err != nil {
+		t.Fatalf("FindCaseInsensitive failed: %v", err)
+	}
+	if expected := filepath.Join(path, "Assets", "Logo.PNG"); found != expected {
+		t.Errorf("Expected %s, got %s", expected, found)
+	}
+
+	// Expect a missing path to fail with ErrNotExist
+	_, err = FindCaseInsensitive(filepath.Join(path, "assets", "missing.png"))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("Expected ErrNotExist, got %v", err)
+	}
+
+	// Expect ExistsCaseInsensitive to report both cases without an error
+	if exists, err := ExistsCaseInsensitive(filepath.Join(path, "ASSETS", "logo.png")); err != nil || !exists {
+		t.Errorf("Expected the file to exist, got %v, %v", exists, err)
+	}
+	if exists, err := ExistsCaseInsensitive(filepath.Join(path, "other")); err != nil || exists {
+		t.Errorf("Expected the file not to exist, got %v, %v", exists, err)
+	}
+}
+
+func TestIsCaseSensitiveFS(t *testing.T) {
+	path := "is_case_sensitive_fs"
+	defer os.RemoveAll(path)
+	os.Mkdir(path, 0755)
+
+	sensitive, err := IsCaseSensitiveFS(path)
+	if err != nil {
+		t.Fatalf("IsCaseSensitiveFS failed: %v", err)
+	}
+
+	// Expect the answer to agree with how the file system treats an upper case name
+	os.WriteFile(filepath.Join(path, "probe"), nil, 0644)
+	_, err = os.Stat(filepath.Join(path, "PROBE"))
+	if sensitive != os.IsNotExist(err) {
+		t.Errorf("Expected %v, got %v", os.IsNotExist(err), sensitive)
+	}
+
+	// Expect no probe file to be left behind
+	entries, _ := os.ReadDir(path)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the test's file, got %v", entries)
+	}
+}
@@ -0,0 +1,267 @@
+package fs_go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCheckpoint is returned by LoadLatestCheckpoint when no valid checkpoint exists.
+var ErrNoCheckpoint = errors.New("no valid checkpoint found")
+
+const checkpointExt = ".ckpt"
+
+// checkpointFile is the on-disk envelope of a checkpoint.
+// The checksum covers Data, so torn or corrupted checkpoints can be detected.
+type checkpointFile struct {
+	Sequence uint64          `json:"sequence"`
+	Time     time.Time       `json:"time"`
+	Checksum string          `json:"checksum"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// CheckpointOptions configures a Checkpointer.
+type CheckpointOptions struct {
+	// Keep is the number of most recent checkpoints to retain. Defaults to 3.
+	Keep int
+	// Mode is the file mode of checkpoint files. Defaults to 0644.
+	Mode os.FileMode
+	// OnError is called with errors from periodic checkpoints started with Start.
+	OnError func(error)
+}
+
+// Checkpointer periodically serializes a value to a directory as JSON.
+//
+// Every checkpoint is written atomically and synced to disk, and only the most recent
+// checkpoints are kept, so a crash at any point leaves at least one valid checkpoint behind.
+// Use LoadLatestCheckpoint to restore the value on startup.
+type Checkpointer[T any] struct {
+	dir      string
+	snapshot func() T
+	opts     CheckpointOptions
+
+	mu       sync.Mutex
+	sequence uint64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCheckpointer creates a Checkpointer that writes to dir.
+// snapshot is called for every checkpoint and must return a value that is safe to
+// serialize while the job keeps running, typically a copy of its state.
+//
+// Example:
+//
+//	state, err := LoadLatestCheckpoint[State]("checkpoints")
+//	if err != nil && !errors.Is(err, ErrNoCheckpoint) {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	checkpointer, err := NewCheckpointer("checkpoints", job.Snapshot, CheckpointOptions{})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	checkpointer.Start(time.Minute)
+//	defer checkpointer.Stop()
+func NewCheckpointer[T any](dir string, snapshot func() T, opts CheckpointOptions) (*Checkpointer[T], error) {
+	dir = normalizePath(dir)
+
+	if opts.Keep <= 0 {
+		opts.Keep = 3
+	}
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+
+	err := EnsureDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("NewCheckpointer failed to ensure directory: %w", err)
+	}
+
+	sequences, err := checkpointSequences(dir)
+	if err != nil {
+		return nil, fmt.Errorf("NewCheckpointer failed to list checkpoints: %w", err)
+	}
+
+	c := &Checkpointer[T]{dir: dir, snapshot: snapshot, opts: opts}
+	if len(sequences) > 0 {
+		c.sequence = sequences[len(sequences)-1]
+	}
+
+	return c, nil
+}
+
+// Checkpoint writes a checkpoint immediately and removes checkpoints beyond the retention limit.
+func (c *Checkpointer[T]) Checkpoint() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(c.snapshot())
+	if err != nil {
+		return fmt.Errorf("Checkpoint failed to marshal value: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	content, err := json.Marshal(checkpointFile{
+		Sequence: c.sequence + 1,
+		Time:     time.Now(),
+		Checksum: hex.EncodeToString(sum[:]),
+		Data:     data,
+	})
+	if err != nil {
+		return fmt.Errorf("Checkpoint failed to marshal checkpoint: %w", err)
+	}
+
+	err = writeAtomic(checkpointPath(c.dir, c.sequence+1), content, c.opts.Mode, true)
+	if err != nil {
+		return fmt.Errorf("Checkpoint failed to write checkpoint: %w", err)
+	}
+	if IsDryRun() {
+		// Nothing was written, so the sequence stays in line with the checkpoints on disk
+		return nil
+	}
+	c.sequence++
+
+	sequences, err := checkpointSequences(c.dir)
+	if err != nil {
+		return fmt.Errorf("Checkpoint failed to list checkpoints: %w", err)
+	}
+	for len(sequences) > c.opts.Keep {
+		err = Remove(checkpointPath(c.dir, sequences[0]))
+		if err != nil {
+			return fmt.Errorf("Checkpoint failed to rotate checkpoints: %w", err)
+		}
+		sequences = sequences[1:]
+	}
+
+	return nil
+}
+
+// Start writes a checkpoint every interval in a background goroutine until Stop is called.
+// Errors are passed to CheckpointOptions.OnError. Calling Start again while running has no effect.
+func (c *Checkpointer[T]) Start(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stop != nil {
+		return
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+
+	go func(stop, done chan struct{}) {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := c.Checkpoint()
+				if err != nil && c.opts.OnError != nil {
+					c.opts.OnError(err)
+				}
+			}
+		}
+	}(c.stop, c.done)
+}
+
+// Stop stops periodic checkpoints and writes a final checkpoint.
+func (c *Checkpointer[T]) Stop() error {
+	c.mu.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	return c.Checkpoint()
+}
+
+// LoadLatestCheckpoint loads the most recent valid checkpoint written by a Checkpointer.
+// Checkpoints that can't be read, fail their checksum, or don't decode into T are skipped.
+// If no valid checkpoint exists, an error wrapping ErrNoCheckpoint is returned.
+func LoadLatestCheckpoint[T any](dir string) (T, error) {
+	dir = normalizePath(dir)
+
+	var v T
+
+	sequences, err := checkpointSequences(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return v, fmt.Errorf("LoadLatestCheckpoint failed to list checkpoints: %w", err)
+	}
+
+	for i := len(sequences) - 1; i >= 0; i-- {
+		loaded, err := loadCheckpoint[T](checkpointPath(dir, sequences[i]))
+		if err == nil {
+			return loaded, nil
+		}
+	}
+
+	return v, fmt.Errorf("LoadLatestCheckpoint failed to load %s: %w", dir, ErrNoCheckpoint)
+}
+
+// loadCheckpoint reads and verifies a single checkpoint file.
+func loadCheckpoint[T any](path string) (T, error) {
+	var v T
+
+	var file checkpointFile
+	err := ReadJson(path, &file)
+	if err != nil {
+		return v, err
+	}
+
+	sum := sha256.Sum256(file.Data)
+	if hex.EncodeToString(sum[:]) != file.Checksum {
+		return v, fmt.Errorf("checksum mismatch in %s", path)
+	}
+
+	err = json.Unmarshal(file.Data, &v)
+	return v, err
+}
+
+// checkpointPath returns the path of the checkpoint with the given sequence number.
+// Sequence numbers are zero-padded so checkpoints sort naturally in directory listings.
+func checkpointPath(dir string, sequence uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", sequence, checkpointExt))
+}
+
+// checkpointSequences returns the sequence numbers of the checkpoints in dir, in ascending order.
+func checkpointSequences(dir string) ([]uint64, error) {
+	names, err := ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sequences []uint64
+	for _, name := range names {
+		if !strings.HasSuffix(name, checkpointExt) {
+			continue
+		}
+		sequence, err := strconv.ParseUint(strings.TrimSuffix(name, checkpointExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		sequences = append(sequences, sequence)
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+
+	return sequences, nil
+}
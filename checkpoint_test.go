@@ -0,0 +1,180 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type checkpointState struct {
+	Processed int `json:"processed"`
+}
+
+func TestCheckpointer(t *testing.T) {
+	// Expect on-demand checkpoints to be rotated and the latest one to be loaded
+	t.Run("checkpoint and rotate", func(t *testing.T) {
+		path := "checkpointer_1"
+		defer os.RemoveAll(path)
+
+		state := checkpointState{}
+		checkpointer, err := NewCheckpointer(path, func() checkpointState { return state }, CheckpointOptions{Keep: 2})
+		if err != nil {
+			t.Fatalf("NewCheckpointer failed: %v", err)
+		}
+
+		for i := 1; i <= 5; i++ {
+			state.Processed = i
+			err = checkpointer.Checkpoint()
+			if err != nil {
+				t.Errorf("Checkpoint failed: %v", err)
+			}
+		}
+
+		names, err := os.ReadDir(path)
+		if err != nil {
+			t.Fatalf("os.ReadDir failed: %v", err)
+		}
+
+		if len(names) != 2 {
+			t.Errorf("Expected 2 checkpoints to be kept, got %d", len(names))
+		}
+
+		loaded, err := LoadLatestCheckpoint[checkpointState](path)
+		if err != nil {
+			t.Errorf("LoadLatestCheckpoint failed: %v", err)
+		}
+
+		if loaded.Processed != 5 {
+			t.Errorf("Expected processed to be 5, got %d", loaded.Processed)
+		}
+	})
+
+	// Expect a new checkpointer to continue the sequence of an existing directory
+	t.Run("resume sequence", func(t *testing.T) {
+		path := "checkpointer_2"
+		defer os.RemoveAll(path)
+
+		for i := 1; i <= 2; i++ {
+			state := checkpointState{Processed: i}
+			checkpointer, err := NewCheckpointer(path, func() checkpointState { return state }, CheckpointOptions{})
+			if err != nil {
+				t.Fatalf("NewCheckpointer failed: %v", err)
+			}
+
+			err = checkpointer.Checkpoint()
+			if err != nil {
+				t.Errorf("Checkpoint failed: %v", err)
+			}
+		}
+
+		loaded, err := LoadLatestCheckpoint[checkpointState](path)
+		if err != nil {
+			t.Errorf("LoadLatestCheckpoint failed: %v", err)
+		}
+
+		if loaded.Processed != 2 {
+			t.Errorf("Expected processed to be 2, got %d", loaded.Processed)
+		}
+	})
+
+	// Expect Start to checkpoint periodically and Stop to write a final checkpoint
+	t.Run("start and stop", func(t *testing.T) {
+		path := "checkpointer_3"
+		defer os.RemoveAll(path)
+
+		checkpointer, err := NewCheckpointer(path, func() checkpointState { return checkpointState{Processed: 1} }, CheckpointOptions{
+			OnError: func(err error) { t.Errorf("Checkpoint failed: %v", err) },
+		})
+		if err != nil {
+			t.Fatalf("NewCheckpointer failed: %v", err)
+		}
+
+		checkpointer.Start(10 * time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+
+		err = checkpointer.Stop()
+		if err != nil {
+			t.Errorf("Stop failed: %v", err)
+		}
+
+		loaded, err := LoadLatestCheckpoint[checkpointState](path)
+		if err != nil {
+			t.Errorf("LoadLatestCheckpoint failed: %v", err)
+		}
+
+		if loaded.Processed != 1 {
+			t.Errorf("Expected processed to be 1, got %d", loaded.Processed)
+		}
+	})
+
+	// Expect checkpoints planned in dry-run mode not to advance the sequence
+	t.Run("dry run", func(t *testing.T) {
+		path := "checkpointer_4"
+		defer os.RemoveAll(path)
+
+		checkpointer, err := NewCheckpointer(path, func() checkpointState { return checkpointState{} }, CheckpointOptions{})
+		if err != nil {
+			t.Fatalf("NewCheckpointer failed: %v", err)
+		}
+
+		plan, err := DryRun(func() error {
+			checkpointer.Checkpoint()
+			return checkpointer.Checkpoint()
+		})
+		if err != nil {
+			t.Fatalf("DryRun failed: %v", err)
+		}
+		if len(plan) != 2 || plan[1].Path != checkpointPath(path, 1) {
+			t.Errorf("Expected both checkpoints to be planned as the first one, got %v", plan)
+		}
+
+		if err := checkpointer.Checkpoint(); err != nil {
+			t.Fatalf("Checkpoint failed: %v", err)
+		}
+		if _, err := os.Stat(checkpointPath(path, 1)); err != nil {
+			t.Errorf("Expected the first checkpoint to be written with sequence 1: %v", err)
+		}
+	})
+}
+
+func TestLoadLatestCheckpoint(t *testing.T) {
+	// Expect corrupt checkpoints to be skipped
+	t.Run("skip corrupt checkpoint", func(t *testing.T) {
+		path := "load_latest_checkpoint_1"
+		defer os.RemoveAll(path)
+
+		state := checkpointState{Processed: 1}
+		checkpointer, err := NewCheckpointer(path, func() checkpointState { return state }, CheckpointOptions{})
+		if err != nil {
+			t.Fatalf("NewCheckpointer failed: %v", err)
+		}
+		if err := checkpointer.Checkpoint(); err != nil {
+			t.Fatalf("Checkpoint failed: %v", err)
+		}
+
+		// A torn write of a newer checkpoint
+		err = os.WriteFile(filepath.Join(path, "00000000000000000002.ckpt"), []byte(`{"sequence":2,"da`), 0644)
+		if err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		loaded, err := LoadLatestCheckpoint[checkpointState](path)
+		if err != nil {
+			t.Errorf("LoadLatestCheckpoint failed: %v", err)
+		}
+
+		if loaded.Processed != 1 {
+			t.Errorf("Expected processed to be 1, got %d", loaded.Processed)
+		}
+	})
+
+	// Expect ErrNoCheckpoint when nothing has been written
+	t.Run("no checkpoint", func(t *testing.T) {
+		_, err := LoadLatestCheckpoint[checkpointState]("load_latest_checkpoint_2")
+		if !errors.Is(err, ErrNoCheckpoint) {
+			t.Errorf("Expected ErrNoCheckpoint, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package fs_go
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HashAlgorithm identifies a hash function used for checksums.
+type HashAlgorithm int
+
+const (
+	HashSHA256 HashAlgorithm = iota // SHA-256, the default
+	HashSHA512                      // SHA-512
+	HashSHA1                        // SHA-1, for compatibility only
+	HashMD5                         // MD5, for compatibility only
+)
+
+// String returns the name of the algorithm as used by coreutils, like "SHA256".
+func (a HashAlgorithm) String() string {
+	switch a {
+	case HashSHA256:
+		return "SHA256"
+	case HashSHA512:
+		return "SHA512"
+	case HashSHA1:
+		return "SHA1"
+	case HashMD5:
+		return "MD5"
+	default:
+		return fmt.Sprintf("HashAlgorithm(%d)", int(a))
+	}
+}
+
+// new returns a new hash of the algorithm, or nil if it is unknown.
+func (a HashAlgorithm) new() hash.Hash {
+	switch a {
+	case HashSHA256:
+		return sha256.New()
+	case HashSHA512:
+		return sha512.New()
+	case HashSHA1:
+		return sha1.New()
+	case HashMD5:
+		return md5.New()
+	default:
+		return nil
+	}
+}
+
+// hashAlgorithmForLength returns the algorithm producing hex digests of the given length.
+func hashAlgorithmForLength(length int) (HashAlgorithm, bool) {
+	for _, algo := range []HashAlgorithm{HashSHA256, HashSHA512, HashSHA1, HashMD5} {
+		if algo.new().Size()*2 == length {
+			return algo, true
+		}
+	}
+
+	return 0, false
+}
+
+// hashFileWith returns the hex-encoded digest of a file's content using algo.
+func hashFileWith(path string, algo HashAlgorithm) (string, error) {
+	return hashFileAdvised(path, algo, AdviceNormal)
+}
+
+// hashFileAdvised is hashFileWith, giving advice for reading the file.
+func hashFileAdvised(path string, algo HashAlgorithm, advice Advice) (string, error) {
+	h := algo.new()
+	if h == nil {
+		return "", fmt.Errorf("unknown hash algorithm %s", algo)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	adviseRead(file, advice)
+	defer adviseDone(file, advice)
+
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksumFile hashes files, given relative to dir, and writes their checksums to a
+// file in dir named after the algorithm, like "SHA256SUMS", in the format of sha256sum and
+// its siblings, so `sha256sum -c SHA256SUMS` verifies it. It returns the path of the
+// checksum file, which is written atomically.
+//
+// Example:
+//
+//	sums, err := WriteChecksumFile("dist", []string{"app-linux.tar.gz", "app-darwin.tar.gz"}, HashSHA256)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteChecksumFile(dir string, files []string, algo HashAlgorithm) (string, error) {
+	dir = normalizePath(dir)
+	path := filepath.Join(dir, algo.String()+"SUMS")
+
+	var content bytes.Buffer
+	for _, file := range files {
+		digest, err := hashFileWith(filepath.Join(dir, file), algo)
+		if err != nil {
+			return "", pathError("WriteChecksumFile", file, fmt.Errorf("WriteChecksumFile failed to hash %s: %w", file, err))
+		}
+
+		content.WriteString(checksumLine(digest, filepath.ToSlash(file)))
+	}
+
+	err := writeAtomic(path, content.Bytes(), 0644, false)
+	if err != nil {
+		return "", pathError("WriteChecksumFile", path, fmt.Errorf("WriteChecksumFile failed to write checksums: %w", err))
+	}
+
+	return path, nil
+}
+
+// VerifyChecksumFile checks the files listed in a checksum file in the format of sha256sum
+// and its siblings, relative to the directory of the checksum file, and returns the ones
+// that are missing or don't match. The algorithm is inferred from the length of the
+// digests. Blank lines and lines starting with "#" are skipped.
+//
+// Example:
+//
+//	mismatches, err := VerifyChecksumFile("dist/SHA256SUMS")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, mismatch := range mismatches {
+//	    fmt.Println(mismatch.Path, "FAILED")
+//	}
+func VerifyChecksumFile(path string) ([]Mismatch, error) {
+	path = normalizePath(path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("VerifyChecksumFile", path, fmt.Errorf("VerifyChecksumFile failed to open file: %w", err))
+	}
+	defer file.Close()
+
+	dir := filepath.Dir(path)
+	var mismatches []Mismatch
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		expected, name, err := parseChecksumLine(text)
+		if err != nil {
+			return nil, pathError("VerifyChecksumFile", path, fmt.Errorf("VerifyChecksumFile failed to parse line %d: %w", line, err))
+		}
+		algo, ok := hashAlgorithmForLength(len(expected))
+		if !ok {
+			return nil, pathError("VerifyChecksumFile", path, fmt.Errorf("VerifyChecksumFile failed to parse line %d: unknown digest length %d", line, len(expected)))
+		}
+
+		actual, err := hashFileWith(filepath.Join(dir, filepath.FromSlash(name)), algo)
+		if os.IsNotExist(err) {
+			mismatches = append(mismatches, Mismatch{Path: name, Expected: expected, Missing: true})
+			continue
+		}
+		if err != nil {
+			return nil, pathError("VerifyChecksumFile", name, fmt.Errorf("VerifyChecksumFile failed to hash %s: %w", name, err))
+		}
+		if actual != expected {
+			mismatches = append(mismatches, Mismatch{Path: name, Expected: expected, Actual: actual})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, pathError("VerifyChecksumFile", path, fmt.Errorf("VerifyChecksumFile failed to read file: %w", err))
+	}
+
+	return mismatches, nil
+}
+
+// checksumLine formats a line of a checksum file. Like coreutils, names containing
+// backslashes or newlines are escaped and the line is prefixed with a backslash.
+func checksumLine(digest, name string) string {
+	if !strings.ContainsAny(name, "\\\n") {
+		return digest + "  " + name + "\n"
+	}
+
+	name = strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(name)
+	return "\\" + digest + "  " + name + "\n"
+}
+
+// parseChecksumLine parses a line of a checksum file into its lowercase digest and name.
+// Names marked as binary with "*" are accepted.
+func parseChecksumLine(line string) (string, string, error) {
+	escaped := strings.HasPrefix(line, "\\")
+	line = strings.TrimPrefix(line, "\\")
+
+	digest, name, ok := strings.Cut(line, " ")
+	if !ok || digest == "" || len(name) < 2 || (name[0] != ' ' && name[0] != '*') {
+		return "", "", fmt.Errorf("malformed checksum line %q", line)
+	}
+	if _, err := hex.DecodeString(digest); err != nil {
+		return "", "", fmt.Errorf("malformed digest %q", digest)
+	}
+
+	name = name[1:]
+	if escaped {
+		name = strings.NewReplacer("\\\\", "\\", "\\n", "\n").Replace(name)
+	}
+
+	return strings.ToLower(digest), name, nil
+}
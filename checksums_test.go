@@ -0,0 +1,82 @@
+package fs_go
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumFile(t *testing.T) {
+	// Expect a written checksum file to verify cleanly
+	t.Run("write and verify", func(t *testing.T) {
+		path := "checksum_file_1"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"a.tar.gz": "a", "nested/b.zip": "b"})
+
+		sums, err := WriteChecksumFile(path, []string{"a.tar.gz", filepath.Join("nested", "b.zip")}, HashSHA256)
+		if err != nil {
+			t.Fatalf("WriteChecksumFile failed: %v", err)
+		}
+		if filepath.Base(sums) != "SHA256SUMS" {
+			t.Errorf("Expected SHA256SUMS, got %s", sums)
+		}
+
+		content, _ := os.ReadFile(sums)
+		expected := "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb  a.tar.gz\n" +
+			"3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d  nested/b.zip\n"
+		if string(content) != expected {
+			t.Errorf("Expected %q, got %q", expected, content)
+		}
+
+		mismatches, err := VerifyChecksumFile(sums)
+		if err != nil {
+			t.Fatalf("VerifyChecksumFile failed: %v", err)
+		}
+		if len(mismatches) != 0 {
+			t.Errorf("Expected no mismatches, got %+v", mismatches)
+		}
+
+		if _, err := exec.LookPath("sha256sum"); err == nil {
+			cmd := exec.Command("sha256sum", "-c", "SHA256SUMS")
+			cmd.Dir = path
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("Expected sha256sum -c to pass, got %v: %s", err, out)
+			}
+		}
+	})
+
+	// Expect changed and missing files to be reported, with the algorithm inferred
+	t.Run("mismatches", func(t *testing.T) {
+		path := "checksum_file_2"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{
+			"a.txt": "a",
+			"b.txt": "b",
+			"MD5SUMS": "# comment\n" +
+				"0cc175b9c0f1b6a831c399e269772661 *a.txt\n" +
+				"0cc175b9c0f1b6a831c399e269772661  b.txt\n" +
+				"0cc175b9c0f1b6a831c399e269772661  c.txt\n",
+		})
+
+		mismatches, err := VerifyChecksumFile(filepath.Join(path, "MD5SUMS"))
+		if err != nil {
+			t.Fatalf("VerifyChecksumFile failed: %v", err)
+		}
+		if len(mismatches) != 2 || mismatches[0].Path != "b.txt" || mismatches[1].Path != "c.txt" || !mismatches[1].Missing {
+			t.Errorf("Expected b.txt to mismatch and c.txt to be missing, got %+v", mismatches)
+		}
+	})
+
+	// Expect malformed lines to fail
+	t.Run("malformed", func(t *testing.T) {
+		path := "checksum_file_3"
+		defer os.Remove(path)
+		os.WriteFile(path, []byte("not a checksum\n"), 0644)
+
+		_, err := VerifyChecksumFile(path)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package fs_go
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ReadChunks reads a file in chunks of chunkSize bytes and calls fn with each chunk and
+// its offset in the file. The last chunk may be shorter. Reading stops at the first
+// error returned by fn, which is then returned.
+//
+// The chunk slice is reused between calls, so fn must copy it to keep it.
+//
+// Example:
+//
+//	hash := sha256.New()
+//	err := ReadChunks("disk.img", 1<<20, func(chunk []byte, offset int64) error {
+//	    hash.Write(chunk)
+//	    return nil
+//	})
+func ReadChunks(path string, chunkSize int, fn func(chunk []byte, offset int64) error) error {
+	chunks, err := Chunks(path, chunkSize)
+	if err != nil {
+		return fmt.Errorf("ReadChunks failed to open file: %w", err)
+	}
+	defer chunks.Close()
+
+	for chunks.Next() {
+		err = fn(chunks.Chunk(), chunks.Offset())
+		if err != nil {
+			return err
+		}
+	}
+
+	err = chunks.Err()
+	if err != nil {
+		return fmt.Errorf("ReadChunks failed to read file: %w", err)
+	}
+
+	return nil
+}
+
+// ChunkIterator iterates over a file in fixed-size chunks. It is created by Chunks.
+type ChunkIterator struct {
+	file   *os.File
+	buf    []byte
+	n      int
+	offset int64
+	next   int64
+	err    error
+}
+
+// Chunks opens a file for iteration in chunks of chunkSize bytes.
+// The iterator must be closed when done.
+//
+// Example:
+//
+//	chunks, err := Chunks("disk.img", 1<<20)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer chunks.Close()
+//
+//	for chunks.Next() {
+//	    process(chunks.Chunk(), chunks.Offset())
+//	}
+//	if err := chunks.Err(); err != nil {
+//	    fmt.Println(err)
+//	}
+func Chunks(path string, chunkSize int) (*ChunkIterator, error) {
+	path = normalizePath(path)
+
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("Chunks failed: chunk size must be positive, got %d", chunkSize)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Chunks failed to open file: %w", err)
+	}
+
+	return &ChunkIterator{file: file, buf: make([]byte, chunkSize)}, nil
+}
+
+// Next reads the next chunk and reports whether there was one.
+// It returns false at the end of the file or on error; check Err to tell them apart.
+func (it *ChunkIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	n, err := io.ReadFull(it.file, it.buf)
+	if err == io.EOF {
+		return false
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		it.err = err
+		return false
+	}
+
+	it.n = n
+	it.offset = it.next
+	it.next += int64(n)
+
+	return true
+}
+
+// Chunk returns the current chunk. It is only valid until the next call to Next.
+func (it *ChunkIterator) Chunk() []byte {
+	return it.buf[:it.n]
+}
+
+// Offset returns the offset of the current chunk in the file.
+func (it *ChunkIterator) Offset() int64 {
+	return it.offset
+}
+
+// Err returns the first error encountered while reading, if any.
+func (it *ChunkIterator) Err() error {
+	return it.err
+}
+
+// Close closes the underlying file.
+func (it *ChunkIterator) Close() error {
+	return it.file.Close()
+}
@@ -0,0 +1,99 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestReadChunks(t *testing.T) {
+	// Expect a file to be read in fixed-size chunks with their offsets
+	t.Run("read chunks", func(t *testing.T) {
+		path := "read_chunks.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var chunks []string
+		var offsets []int64
+		err := ReadChunks(path, 4, func(chunk []byte, offset int64) error {
+			chunks = append(chunks, string(chunk))
+			offsets = append(offsets, offset)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("ReadChunks failed: %v", err)
+		}
+
+		expected := []string{"0123", "4567", "89"}
+		if len(chunks) != len(expected) {
+			t.Fatalf("Expected %d chunks, got %d", len(expected), len(chunks))
+		}
+
+		for i, chunk := range chunks {
+			if chunk != expected[i] || offsets[i] != int64(i*4) {
+				t.Errorf("Expected chunk %q at %d, got %q at %d", expected[i], i*4, chunk, offsets[i])
+			}
+		}
+	})
+
+	// Expect errors from the callback to stop reading
+	t.Run("stop on error", func(t *testing.T) {
+		path := "read_chunks_error.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		stop := errors.New("stop")
+		calls := 0
+		err := ReadChunks(path, 2, func(chunk []byte, offset int64) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("Expected the callback error, got %v", err)
+		}
+
+		if calls != 1 {
+			t.Errorf("Expected 1 call, got %d", calls)
+		}
+	})
+}
+
+func TestChunks(t *testing.T) {
+	// Expect an empty file to yield no chunks
+	t.Run("empty file", func(t *testing.T) {
+		path := "chunks_empty.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		chunks, err := Chunks(path, 4)
+		if err != nil {
+			t.Fatalf("Chunks failed: %v", err)
+		}
+		defer chunks.Close()
+
+		if chunks.Next() {
+			t.Errorf("Expected no chunks")
+		}
+
+		if chunks.Err() != nil {
+			t.Errorf("Expected no error, got %v", chunks.Err())
+		}
+	})
+
+	// Expect an invalid chunk size to be rejected
+	t.Run("invalid chunk size", func(t *testing.T) {
+		_, err := Chunks("chunks_invalid.txt", 0)
+		if err == nil {
+			t.Errorf("Expected Chunks to fail for a zero chunk size")
+		}
+	})
+}
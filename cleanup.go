@@ -0,0 +1,153 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// RetentionPolicy selects the files Cleanup removes. Files are removed if they are older
+// than OlderThan, or are the oldest ones beyond MaxTotalSize, but never if they are among
+// the KeepNewest newest files. With neither OlderThan nor MaxTotalSize set, every file
+// beyond KeepNewest is removed, so such a policy needs KeepNewest or All, and the zero
+// policy is rejected rather than emptying the directory.
+type RetentionPolicy struct {
+	// OlderThan removes files last modified longer ago than this. Zero disables it.
+	OlderThan time.Duration
+	// Pattern is a filepath.Match pattern matched against both the slash-separated path
+	// relative to the root and the base name. Only matching files are considered.
+	// Empty matches every file.
+	Pattern string
+	// KeepNewest is the number of newest matching files that are always kept.
+	KeepNewest int
+	// MaxTotalSize removes the oldest matching files until the rest fit in this many bytes.
+	// Zero disables it.
+	MaxTotalSize int64
+	// All allows removing every matching file when none of OlderThan, MaxTotalSize and
+	// KeepNewest is set.
+	All bool
+	// DryRun reports what would be removed without removing anything.
+	DryRun bool
+}
+
+// CleanupResult describes the files removed (or, in dry-run mode, planned to be removed)
+// by Cleanup. Paths are relative to the root.
+type CleanupResult struct {
+	Removed []FileSize // Removed files, oldest first
+	Freed   int64      // Total size of the removed files
+}
+
+// Cleanup removes files below root according to a retention policy, for log and temp
+// directories that must not grow forever. Only regular files are removed; directories
+// are left in place.
+//
+// Example:
+//
+//	result, err := Cleanup("logs", RetentionPolicy{
+//	    Pattern:    "*.log.gz",
+//	    OlderThan:  30 * 24 * time.Hour,
+//	    KeepNewest: 7,
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println("freed", result.Freed, "bytes")
+func Cleanup(root string, policy RetentionPolicy) (CleanupResult, error) {
+	// In package-wide dry-run mode, plan the cleanup and record the planned actions
+	if !policy.DryRun && IsDryRun() {
+		policy.DryRun = true
+		result, err := Cleanup(root, policy)
+		for _, file := range result.Removed {
+			dryRunSkip("Remove", filepath.Join(root, file.Path), "")
+		}
+		return result, err
+	}
+
+	var result CleanupResult
+	if policy.OlderThan == 0 && policy.MaxTotalSize == 0 && policy.KeepNewest <= 0 && !policy.All {
+		return result, pathError("Cleanup", root, fmt.Errorf("Cleanup failed: policy would remove every file, set All to allow it"))
+	}
+	if !policy.DryRun {
+		err := checkWritable("Cleanup", root)
+		if err != nil {
+			return result, err
+		}
+		// Everything below root may be removed or replaced
+		invalidateStats(root)
+		defer invalidateStats(root)
+	}
+
+	type candidate struct {
+		FileSize
+		modTime time.Time
+	}
+	var files []candidate
+	err := filepath.WalkDir(normalizePath(root), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(normalizePath(root), path)
+		if err != nil {
+			return err
+		}
+		if policy.Pattern != "" && !matchAny([]string{policy.Pattern}, rel) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, candidate{FileSize{Path: rel, Size: info.Size()}, info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return result, pathError("Cleanup", root, fmt.Errorf("Cleanup failed to walk directory: %w", err))
+	}
+
+	// Newest first, so the kept files come first and the rest is removed from the end
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.After(files[j].modTime)
+		}
+		return files[i].Path < files[j].Path
+	})
+
+	cutoff := time.Now().Add(-policy.OlderThan)
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+
+	keep := max(policy.KeepNewest, 0)
+	for i := len(files) - 1; i >= min(keep, len(files)); i-- {
+		file := files[i]
+		expired := policy.OlderThan > 0 && file.modTime.Before(cutoff)
+		oversized := policy.MaxTotalSize > 0 && total > policy.MaxTotalSize
+		unbounded := policy.OlderThan == 0 && policy.MaxTotalSize == 0
+		if !expired && !oversized && !unbounded {
+			continue
+		}
+
+		if !policy.DryRun {
+			err := os.Remove(filepath.Join(normalizePath(root), file.Path))
+			observe("Remove", 0, 0, err)
+			if err != nil {
+				return result, pathError("Cleanup", filepath.Join(root, file.Path), fmt.Errorf("Cleanup failed to remove %s: %w", file.Path, err))
+			}
+		}
+		result.Removed = append(result.Removed, file.FileSize)
+		result.Freed += file.Size
+		total -= file.Size
+	}
+
+	return result, nil
+}
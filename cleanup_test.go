@@ -0,0 +1,124 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeAged writes files with modification times the given number of hours in the past.
+func writeAged(t *testing.T, root string, ages map[string]int) {
+	t.Helper()
+	for name, hours := range ages {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte("12345"), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		modTime := time.Now().Add(-time.Duration(hours) * time.Hour)
+		os.Chtimes(path, modTime, modTime)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	// Expect files older than OlderThan and matching Pattern to be removed
+	t.Run("older than", func(t *testing.T) {
+		root := "cleanup_1"
+		defer os.RemoveAll(root)
+		writeAged(t, root, map[string]int{"new.log": 1, "old.log": 48, "sub/older.log": 72, "old.txt": 48})
+
+		result, err := Cleanup(root, RetentionPolicy{OlderThan: 24 * time.Hour, Pattern: "*.log"})
+		if err != nil {
+			t.Fatalf("Cleanup failed: %v", err)
+		}
+		if len(result.Removed) != 2 || result.Removed[0].Path != filepath.Join("sub", "older.log") || result.Freed != 10 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		for _, name := range []string{"new.log", "old.txt"} {
+			if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+				t.Errorf("Expected %s to be kept, got %v", name, err)
+			}
+		}
+	})
+
+	// Expect the newest files to be kept even if they are old
+	t.Run("keep newest", func(t *testing.T) {
+		root := "cleanup_2"
+		defer os.RemoveAll(root)
+		writeAged(t, root, map[string]int{"a": 10, "b": 20, "c": 30})
+
+		result, err := Cleanup(root, RetentionPolicy{OlderThan: time.Hour, KeepNewest: 2})
+		if err != nil {
+			t.Fatalf("Cleanup failed: %v", err)
+		}
+		if len(result.Removed) != 1 || result.Removed[0].Path != "c" {
+			t.Errorf("Expected only c to be removed, got %+v", result.Removed)
+		}
+	})
+
+	// Expect the oldest files to be removed until the rest fit in MaxTotalSize
+	t.Run("max total size", func(t *testing.T) {
+		root := "cleanup_3"
+		defer os.RemoveAll(root)
+		writeAged(t, root, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})
+
+		result, err := Cleanup(root, RetentionPolicy{MaxTotalSize: 10})
+		if err != nil {
+			t.Fatalf("Cleanup failed: %v", err)
+		}
+		if len(result.Removed) != 2 || result.Removed[0].Path != "d" || result.Removed[1].Path != "c" {
+			t.Errorf("Expected d and c to be removed, got %+v", result.Removed)
+		}
+	})
+
+	// Expect nothing to be removed in dry-run mode
+	t.Run("dry run", func(t *testing.T) {
+		root := "cleanup_4"
+		defer os.RemoveAll(root)
+		writeAged(t, root, map[string]int{"a": 1, "b": 2})
+
+		result, err := Cleanup(root, RetentionPolicy{KeepNewest: 1, DryRun: true})
+		if err != nil {
+			t.Fatalf("Cleanup failed: %v", err)
+		}
+		if len(result.Removed) != 1 || result.Removed[0].Path != "b" {
+			t.Errorf("Expected b to be planned, got %+v", result.Removed)
+		}
+		if _, err := os.Stat(filepath.Join(root, "b")); err != nil {
+			t.Errorf("Expected b to be kept, got %v", err)
+		}
+
+		plan, err := DryRun(func() error {
+			_, err := Cleanup(root, RetentionPolicy{KeepNewest: 1})
+			return err
+		})
+		if err != nil || len(plan) != 1 {
+			t.Errorf("Expected 1 planned action, got %v (%v)", plan, err)
+		}
+	})
+
+	// Expect a policy that would remove everything to be rejected unless All is set
+	t.Run("unbounded", func(t *testing.T) {
+		root := "cleanup_5"
+		defer os.RemoveAll(root)
+		writeAged(t, root, map[string]int{"a.log": 1, "b.log": 2})
+
+		for _, policy := range []RetentionPolicy{{}, {Pattern: "*.log"}} {
+			_, err := Cleanup(root, policy)
+			var pathErr *PathError
+			if !errors.As(err, &pathErr) || pathErr.Path != root {
+				t.Errorf("Expected a path error for %+v, got %v", policy, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(root, "a.log")); err != nil {
+			t.Fatalf("Expected a.log to be kept, got %v", err)
+		}
+
+		result, err := Cleanup(root, RetentionPolicy{Pattern: "*.log", All: true})
+		if err != nil || len(result.Removed) != 2 {
+			t.Errorf("Expected both files to be removed, got %+v (%v)", result.Removed, err)
+		}
+	})
+}
@@ -0,0 +1,596 @@
+// Package cloudfs implements fs_go.FS over S3-compatible object storage.
+//
+// It speaks the S3 REST API directly, so it works with AWS S3, MinIO, Cloudflare R2,
+// and Google Cloud Storage through its XML interoperability API using HMAC keys.
+// Object storage has no real directories: a directory exists as long as some object
+// key starts with its name followed by a slash, and MkdirAll is a no-op.
+//
+// Example:
+//
+//	bucket, err := cloudfs.NewS3(cloudfs.Config{
+//	    Region:          "eu-west-1",
+//	    Bucket:          "my-bucket",
+//	    AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+//	    SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = bucket.WriteFile("reports/today.json", content, 0644)
+package cloudfs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	fs_go "github.com/frodi-karlsson/fs_go"
+)
+
+// Config configures an S3 file system.
+type Config struct {
+	// Endpoint is the base URL of the service, such as "http://localhost:9000" for MinIO or
+	// "https://storage.googleapis.com" for GCS. Defaults to the AWS endpoint of the region.
+	Endpoint string
+	// Region is used for request signing. Defaults to "us-east-1".
+	Region string
+	// Bucket is the bucket to operate on. Buckets are addressed path-style, as in
+	// "<endpoint>/<bucket>/<key>", which every S3-compatible service supports.
+	Bucket string
+	// Prefix is prepended to every key, so a part of a bucket can be used as the root.
+	Prefix string
+	// AccessKeyID and SecretAccessKey sign requests. Requests are sent unsigned if empty,
+	// which only works for public buckets.
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is sent along with temporary credentials.
+	SessionToken string
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// S3FS is an fs_go.FS backed by an S3-compatible bucket. Names map to object keys, so
+// "dir/file.txt" is stored under the key "<prefix>dir/file.txt".
+type S3FS struct {
+	config   Config
+	endpoint *url.URL
+	now      func() time.Time
+}
+
+var _ fs_go.FS = (*S3FS)(nil)
+
+// NewS3 returns a file system for the bucket described by config.
+func NewS3(config Config) (*S3FS, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("NewS3 failed: bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	config.Prefix = strings.Trim(config.Prefix, "/")
+	if config.Prefix != "" {
+		config.Prefix += "/"
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("NewS3 failed to parse endpoint: %w", err)
+	}
+
+	return &S3FS{config: config, endpoint: endpoint, now: time.Now}, nil
+}
+
+// Error is an error response from the storage service.
+// It matches fs.ErrNotExist and fs.ErrPermission with errors.Is.
+type Error struct {
+	StatusCode int
+	Code       string // Error code, such as "NoSuchKey" or "AccessDenied"
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("%s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case fs.ErrNotExist:
+		return e.StatusCode == http.StatusNotFound
+	case fs.ErrPermission:
+		return e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusUnauthorized
+	default:
+		return false
+	}
+}
+
+// key returns the object key for a name.
+func (s *S3FS) key(name string) string {
+	if name == "." {
+		return s.config.Prefix
+	}
+
+	return s.config.Prefix + name
+}
+
+// do sends a signed request for a key and returns the response if it succeeded.
+// The caller must close the body.
+func (s *S3FS) do(method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	objectPath := strings.TrimSuffix(u.Path, "/") + "/" + s.config.Bucket + "/" + key
+	u.Path = objectPath
+	u.RawPath = uriEncode(objectPath, false)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+	if s.config.AccessKeyID != "" {
+		payloadSum := emptyPayloadSum
+		if len(body) > 0 {
+			payloadSum = hashHex(body)
+		}
+		signV4(req, payloadSum, s.config.AccessKeyID, s.config.SecretAccessKey, s.config.Region, "s3", s.now())
+	}
+
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+
+	return resp, nil
+}
+
+// responseError reads the XML error document of a failed response.
+func responseError(resp *http.Response) error {
+	var body struct {
+		Code    string
+		Message string
+	}
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	xml.Unmarshal(content, &body)
+
+	return &Error{StatusCode: resp.StatusCode, Code: body.Code, Message: body.Message}
+}
+
+// listResult is the response of a ListObjectsV2 request.
+type listResult struct {
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+	CommonPrefixes []struct {
+		Prefix string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// list calls fn for every page of objects starting with prefix. With a delimiter,
+// keys below the next "/" are grouped into common prefixes.
+func (s *S3FS) list(prefix string, delimited bool, fn func(page listResult) error) error {
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	if delimited {
+		query.Set("delimiter", "/")
+	}
+
+	for {
+		resp, err := s.do(http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode listing: %w", err)
+		}
+
+		err = fn(page)
+		if err != nil {
+			return err
+		}
+		if !page.IsTruncated {
+			return nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+// dirPrefix returns the key prefix of the objects inside a directory.
+func (s *S3FS) dirPrefix(name string) string {
+	if name == "." {
+		return s.config.Prefix
+	}
+
+	return s.config.Prefix + name + "/"
+}
+
+// isDir reports whether any object lives below name.
+func (s *S3FS) isDir(name string) (bool, error) {
+	if name == "." {
+		return true, nil
+	}
+
+	found := false
+	err := s.list(s.dirPrefix(name), true, func(page listResult) error {
+		found = len(page.Contents) > 0 || len(page.CommonPrefixes) > 0
+		return nil
+	})
+
+	return found, err
+}
+
+func pathErr(op, name string, err error) error {
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+func (s *S3FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathErr("open", name, fs.ErrInvalid)
+	}
+
+	if name != "." {
+		resp, err := s.do(http.MethodGet, s.key(name), nil, nil, nil)
+		if err == nil {
+			return &objectFile{ReadCloser: resp.Body, info: objectInfoFromHeader(name, resp.Header)}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, pathErr("open", name, err)
+		}
+	}
+
+	entries, err := s.ReadDir(name)
+	if err != nil {
+		return nil, pathErr("open", name, fs.ErrNotExist)
+	}
+
+	return &dirFile{info: &objectInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+func (s *S3FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathErr("stat", name, fs.ErrInvalid)
+	}
+
+	if name != "." {
+		resp, err := s.do(http.MethodHead, s.key(name), nil, nil, nil)
+		if err == nil {
+			resp.Body.Close()
+			return objectInfoFromHeader(name, resp.Header), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, pathErr("stat", name, err)
+		}
+	}
+
+	dir, err := s.isDir(name)
+	if err != nil {
+		return nil, pathErr("stat", name, err)
+	}
+	if !dir {
+		return nil, pathErr("stat", name, fs.ErrNotExist)
+	}
+
+	return &objectInfo{name: path.Base(name), dir: true}, nil
+}
+
+func (s *S3FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathErr("readdir", name, fs.ErrInvalid)
+	}
+
+	prefix := s.dirPrefix(name)
+	var entries []fs.DirEntry
+	err := s.list(prefix, true, func(page listResult) error {
+		for _, object := range page.Contents {
+			base := strings.TrimPrefix(object.Key, prefix)
+			if base == "" {
+				// Directory marker objects created by other tools
+				continue
+			}
+			entries = append(entries, fs.FileInfoToDirEntry(&objectInfo{
+				name:    base,
+				size:    object.Size,
+				modTime: object.LastModified.Truncate(time.Second),
+			}))
+		}
+		for _, common := range page.CommonPrefixes {
+			base := strings.TrimSuffix(strings.TrimPrefix(common.Prefix, prefix), "/")
+			entries = append(entries, fs.FileInfoToDirEntry(&objectInfo{name: base, dir: true}))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, pathErr("readdir", name, err)
+	}
+	if len(entries) == 0 && name != "." {
+		return nil, pathErr("readdir", name, fs.ErrNotExist)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, nil
+}
+
+func (s *S3FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, pathErr("readfile", name, fs.ErrInvalid)
+	}
+
+	resp, err := s.do(http.MethodGet, s.key(name), nil, nil, nil)
+	if err != nil {
+		return nil, pathErr("readfile", name, err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, pathErr("readfile", name, err)
+	}
+
+	return content, nil
+}
+
+// WriteFile uploads the object. Object storage has no permissions, so perm is ignored.
+func (s *S3FS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return pathErr("writefile", name, fs.ErrInvalid)
+	}
+
+	resp, err := s.do(http.MethodPut, s.key(name), nil, nil, data)
+	if err != nil {
+		return pathErr("writefile", name, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Create returns a writer that buffers the content and uploads it on Close.
+func (s *S3FS) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, pathErr("create", name, fs.ErrInvalid)
+	}
+
+	return &objectWriter{fsys: s, name: name}, nil
+}
+
+// MkdirAll does nothing, since directories exist implicitly through the keys below them.
+func (s *S3FS) MkdirAll(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return pathErr("mkdir", name, fs.ErrInvalid)
+	}
+
+	return nil
+}
+
+func (s *S3FS) Remove(name string) error {
+	info, err := s.Stat(name)
+	if err != nil {
+		return pathErr("remove", name, err)
+	}
+	if info.IsDir() {
+		return pathErr("remove", name, syscall.ENOTEMPTY)
+	}
+
+	return s.delete(name)
+}
+
+// RemoveAll deletes the object and every object below it. It returns nil if nothing exists.
+func (s *S3FS) RemoveAll(name string) error {
+	if !fs.ValidPath(name) {
+		return pathErr("removeall", name, fs.ErrInvalid)
+	}
+
+	keys, err := s.keysBelow(name)
+	if err != nil {
+		return pathErr("removeall", name, err)
+	}
+	for _, key := range keys {
+		resp, err := s.do(http.MethodDelete, key, nil, nil, nil)
+		if err != nil {
+			return pathErr("removeall", name, err)
+		}
+		resp.Body.Close()
+	}
+	if name == "." {
+		return nil
+	}
+
+	return s.delete(name)
+}
+
+// Rename copies the object, or every object below a directory, to the new name on the
+// server and deletes the originals. It isn't atomic.
+func (s *S3FS) Rename(oldname, newname string) error {
+	if !fs.ValidPath(oldname) || !fs.ValidPath(newname) || oldname == "." || newname == "." {
+		return pathErr("rename", oldname, fs.ErrInvalid)
+	}
+
+	info, err := s.Stat(oldname)
+	if err != nil {
+		return pathErr("rename", oldname, err)
+	}
+
+	sources := []string{s.key(oldname)}
+	if info.IsDir() {
+		sources, err = s.keysBelow(oldname)
+		if err != nil {
+			return pathErr("rename", oldname, err)
+		}
+	}
+
+	for _, source := range sources {
+		target := s.key(newname) + strings.TrimPrefix(source, s.key(oldname))
+		header := http.Header{"X-Amz-Copy-Source": {uriEncode("/"+s.config.Bucket+"/"+source, false)}}
+		resp, err := s.do(http.MethodPut, target, nil, header, nil)
+		if err != nil {
+			return pathErr("rename", oldname, err)
+		}
+		resp.Body.Close()
+
+		resp, err = s.do(http.MethodDelete, source, nil, nil, nil)
+		if err != nil {
+			return pathErr("rename", oldname, err)
+		}
+		resp.Body.Close()
+	}
+
+	return nil
+}
+
+// keysBelow returns the keys of every object inside a directory, at any depth.
+func (s *S3FS) keysBelow(name string) ([]string, error) {
+	var keys []string
+	err := s.list(s.dirPrefix(name), false, func(page listResult) error {
+		for _, object := range page.Contents {
+			keys = append(keys, object.Key)
+		}
+		return nil
+	})
+
+	return keys, err
+}
+
+// delete deletes a single object. Deleting a missing object succeeds, like in S3.
+func (s *S3FS) delete(name string) error {
+	resp, err := s.do(http.MethodDelete, s.key(name), nil, nil, nil)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return pathErr("remove", name, err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	return nil
+}
+
+// objectInfo describes an object or an implicit directory.
+type objectInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i *objectInfo) Name() string       { return i.name }
+func (i *objectInfo) Size() int64        { return i.size }
+func (i *objectInfo) ModTime() time.Time { return i.modTime }
+func (i *objectInfo) IsDir() bool        { return i.dir }
+func (i *objectInfo) Sys() any           { return nil }
+
+func (i *objectInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0755
+	}
+
+	return 0644
+}
+
+// objectInfoFromHeader builds the info of an object from the headers of a GET or HEAD response.
+func objectInfoFromHeader(name string, header http.Header) *objectInfo {
+	info := &objectInfo{name: path.Base(name)}
+	fmt.Sscan(header.Get("Content-Length"), &info.size)
+	if modTime, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		info.modTime = modTime
+	}
+
+	return info
+}
+
+// objectFile streams the content of an object.
+type objectFile struct {
+	io.ReadCloser
+	info *objectInfo
+}
+
+func (f *objectFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// dirFile is an open implicit directory.
+type dirFile struct {
+	info    *objectInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, pathErr("read", d.info.name, syscall.EISDIR)
+}
+
+func (d *dirFile) Close() error {
+	return nil
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
+
+// objectWriter buffers writes and uploads the object on Close.
+type objectWriter struct {
+	bytes.Buffer
+	fsys   *S3FS
+	name   string
+	closed bool
+}
+
+func (w *objectWriter) Close() error {
+	if w.closed {
+		return pathErr("close", w.name, fs.ErrClosed)
+	}
+	w.closed = true
+
+	return w.fsys.WriteFile(w.name, w.Bytes(), 0644)
+}
@@ -0,0 +1,257 @@
+package cloudfs
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// fakeS3 is a minimal in-memory S3 server for a single bucket.
+// Listings are paginated two items at a time to exercise continuation tokens.
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	modTime time.Time
+}
+
+type fakeListResult struct {
+	XMLName  xml.Name `xml:"ListBucketResult"`
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+	CommonPrefixes []struct {
+		Prefix string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3FS) {
+	t.Helper()
+
+	fake := &fakeS3{bucket: "bucket", objects: make(map[string][]byte), modTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	fsys, err := NewS3(Config{
+		Endpoint:        server.URL,
+		Bucket:          "bucket",
+		Prefix:          "root",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewS3 failed: %v", err)
+	}
+
+	return fake, fsys
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		f.error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		f.error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		f.list(w, r.URL.Query())
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		content, ok := f.objects[key]
+		if !ok {
+			f.error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("Last-Modified", f.modTime.Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			w.Write(content)
+		}
+	case r.Method == http.MethodPut:
+		if source := r.Header.Get("X-Amz-Copy-Source"); source != "" {
+			source, _ = url.PathUnescape(source)
+			content, ok := f.objects[strings.TrimPrefix(source, "/"+f.bucket+"/")]
+			if !ok {
+				f.error(w, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+			f.objects[key] = content
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		f.objects[key] = content
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	w.Write([]byte("<Error><Code>" + code + "</Code><Message>" + code + "</Message></Error>"))
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, query url.Values) {
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+
+	var keys []string
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	type item struct {
+		key    string
+		common bool
+	}
+	var items []item
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		if index := strings.Index(rest, "/"); delimiter == "/" && index >= 0 {
+			common := prefix + rest[:index+1]
+			if !seen[common] {
+				seen[common] = true
+				items = append(items, item{key: common, common: true})
+			}
+			continue
+		}
+		items = append(items, item{key: key})
+	}
+
+	start, _ := strconv.Atoi(query.Get("continuation-token"))
+	end := min(start+2, len(items))
+	var result fakeListResult
+	for _, item := range items[start:end] {
+		if item.common {
+			result.CommonPrefixes = append(result.CommonPrefixes, struct{ Prefix string }{item.key})
+			continue
+		}
+		result.Contents = append(result.Contents, struct {
+			Key          string
+			Size         int64
+			LastModified time.Time
+		}{item.key, int64(len(f.objects[item.key])), f.modTime})
+	}
+	if end < len(items) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	}
+
+	content, _ := xml.Marshal(result)
+	w.Write(content)
+}
+
+func TestS3FS(t *testing.T) {
+	// Expect written objects to form a valid file system with implicit directories
+	t.Run("read", func(t *testing.T) {
+		fake, fsys := newFakeS3(t)
+
+		files := map[string]string{"a.txt": "a", "dir/b.txt": "b", "dir/c.txt": "c", "dir/sub/d.txt": "d"}
+		for name, content := range files {
+			if err := fsys.WriteFile(name, []byte(content), 0644); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+		}
+
+		if string(fake.objects["root/dir/sub/d.txt"]) != "d" {
+			t.Errorf("Expected object under the prefix, got %v", fake.objects)
+		}
+
+		err := fstest.TestFS(fsys, "a.txt", "dir/b.txt", "dir/c.txt", "dir/sub/d.txt")
+		if err != nil {
+			t.Errorf("fstest.TestFS failed: %v", err)
+		}
+	})
+
+	// Expect Create to upload on Close
+	t.Run("create", func(t *testing.T) {
+		_, fsys := newFakeS3(t)
+
+		writer, err := fsys.Create("log.txt")
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		io.WriteString(writer, "line 1\n")
+		io.WriteString(writer, "line 2\n")
+		if err := writer.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+
+		content, err := fsys.ReadFile("log.txt")
+		if err != nil || string(content) != "line 1\nline 2\n" {
+			t.Errorf("Expected uploaded content, got %q (%v)", content, err)
+		}
+	})
+
+	// Expect directories to be renamed and removed object by object
+	t.Run("rename and remove", func(t *testing.T) {
+		fake, fsys := newFakeS3(t)
+		fsys.WriteFile("dir/a.txt", []byte("a"), 0644)
+		fsys.WriteFile("dir/sub/b.txt", []byte("b"), 0644)
+
+		if err := fsys.Remove("dir"); err == nil {
+			t.Errorf("Expected removing a non-empty directory to fail")
+		}
+		if err := fsys.Rename("dir", "moved"); err != nil {
+			t.Fatalf("Rename failed: %v", err)
+		}
+		if _, err := fsys.Stat("dir"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected dir to be gone, got %v", err)
+		}
+		content, err := fsys.ReadFile("moved/sub/b.txt")
+		if err != nil || string(content) != "b" {
+			t.Errorf("Expected moved content, got %q (%v)", content, err)
+		}
+
+		if err := fsys.RemoveAll("moved"); err != nil {
+			t.Errorf("RemoveAll failed: %v", err)
+		}
+		if len(fake.objects) != 0 {
+			t.Errorf("Expected no objects left, got %v", fake.objects)
+		}
+	})
+
+	// Expect service errors to match standard errors
+	t.Run("errors", func(t *testing.T) {
+		_, fsys := newFakeS3(t)
+
+		_, err := fsys.ReadFile("missing.txt")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected fs.ErrNotExist, got %v", err)
+		}
+
+		fsys.config.AccessKeyID = "wrong"
+		_, err = fsys.ReadFile("missing.txt")
+		var serviceErr *Error
+		if !errors.Is(err, fs.ErrPermission) || !errors.As(err, &serviceErr) || serviceErr.Code != "AccessDenied" {
+			t.Errorf("Expected an AccessDenied error, got %v", err)
+		}
+	})
+}
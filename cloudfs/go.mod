@@ -0,0 +1,11 @@
+module github.com/frodi-karlsson/fs_go/cloudfs
+
+go 1.22.1
+
+require github.com/frodi-karlsson/fs_go v0.0.0-20261015145331-1b5d3a6729f5
+
+require (
+	github.com/BurntSushi/toml v1.4.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,186 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// compareChunkSize is the size of the chunks read when comparing file contents.
+const compareChunkSize = 32 * 1024
+
+// Equal reports whether two files have the same content.
+// The sizes are compared first, so files of different length are never read.
+func Equal(a, b string) (bool, error) {
+	a, b = normalizePath(a), normalizePath(b)
+
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, fmt.Errorf("Equal failed to get file stat: %w", err)
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, fmt.Errorf("Equal failed to get file stat: %w", err)
+	}
+	if infoA.Size() != infoB.Size() {
+		return false, nil
+	}
+
+	fileA, err := os.Open(a)
+	if err != nil {
+		return false, fmt.Errorf("Equal failed to open file: %w", err)
+	}
+	defer fileA.Close()
+
+	fileB, err := os.Open(b)
+	if err != nil {
+		return false, fmt.Errorf("Equal failed to open file: %w", err)
+	}
+	defer fileB.Close()
+
+	equal, err := equalReaders(fileA, fileB)
+	if err != nil {
+		return false, fmt.Errorf("Equal failed to compare files: %w", err)
+	}
+
+	return equal, nil
+}
+
+// EqualContent reports whether the content of a file is equal to the given byte slice.
+func EqualContent(path string, content []byte) (bool, error) {
+	path = normalizePath(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("EqualContent failed to get file stat: %w", err)
+	}
+	if info.Size() != int64(len(content)) {
+		return false, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("EqualContent failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	equal, err := equalReaders(file, bytes.NewReader(content))
+	if err != nil {
+		return false, fmt.Errorf("EqualContent failed to compare content: %w", err)
+	}
+
+	return equal, nil
+}
+
+// equalReaders compares two readers chunk by chunk until either differs or both are exhausted.
+func equalReaders(a, b io.Reader) (bool, error) {
+	bufA := make([]byte, compareChunkSize)
+	bufB := make([]byte, compareChunkSize)
+
+	for {
+		nA, errA := io.ReadFull(a, bufA)
+		if errA != nil && errA != io.EOF && errA != io.ErrUnexpectedEOF {
+			return false, errA
+		}
+		nB, errB := io.ReadFull(b, bufB)
+		if errB != nil && errB != io.EOF && errB != io.ErrUnexpectedEOF {
+			return false, errB
+		}
+
+		if !bytes.Equal(bufA[:nA], bufB[:nB]) {
+			return false, nil
+		}
+
+		doneA := errA == io.EOF || errA == io.ErrUnexpectedEOF
+		doneB := errB == io.EOF || errB == io.ErrUnexpectedEOF
+		if doneA || doneB {
+			return doneA && doneB, nil
+		}
+	}
+}
+
+// DirDiff describes the differences between two directory trees.
+// All paths are relative to the roots of the compared trees and sorted.
+type DirDiff struct {
+	Added   []string // Files present in b but not in a
+	Removed []string // Files present in a but not in b
+	Changed []string // Files present in both, but with different content
+}
+
+// Empty reports whether the two compared trees were identical.
+func (d DirDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffDirs compares two directory trees recursively and reports which files
+// were added, removed, or changed going from a to b.
+//
+// Example:
+//
+//	diff, err := DiffDirs("expected", "actual")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(diff.Changed)
+func DiffDirs(a, b string) (DirDiff, error) {
+	a, b = normalizePath(a), normalizePath(b)
+
+	filesA, err := relativeFiles(a)
+	if err != nil {
+		return DirDiff{}, fmt.Errorf("DiffDirs failed to read directory: %w", err)
+	}
+	filesB, err := relativeFiles(b)
+	if err != nil {
+		return DirDiff{}, fmt.Errorf("DiffDirs failed to read directory: %w", err)
+	}
+
+	var diff DirDiff
+	for rel := range filesA {
+		if !filesB[rel] {
+			diff.Removed = append(diff.Removed, rel)
+			continue
+		}
+
+		equal, err := Equal(filepath.Join(a, rel), filepath.Join(b, rel))
+		if err != nil {
+			return DirDiff{}, fmt.Errorf("DiffDirs failed to compare files: %w", err)
+		}
+		if !equal {
+			diff.Changed = append(diff.Changed, rel)
+		}
+	}
+	for rel := range filesB {
+		if !filesA[rel] {
+			diff.Added = append(diff.Added, rel)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+
+	return diff, nil
+}
+
+// relativeFiles returns the set of files under root, relative to root.
+func relativeFiles(root string) (map[string]bool, error) {
+	files, err := ReadDirRec(root)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return nil, err
+		}
+		set[rel] = true
+	}
+
+	return set, nil
+}
@@ -0,0 +1,181 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	// Expect files with the same content to be equal
+	t.Run("equal files", func(t *testing.T) {
+		a := "equal_1_a.txt"
+		b := "equal_1_b.txt"
+		defer os.Remove(a)
+		defer os.Remove(b)
+
+		// Larger than a single chunk to exercise the chunked comparison
+		content := []byte(strings.Repeat("test content", 10000))
+		if err := os.WriteFile(a, content, 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		if err := os.WriteFile(b, content, 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		equal, err := Equal(a, b)
+		if err != nil {
+			t.Errorf("Equal failed: %v", err)
+		}
+
+		if !equal {
+			t.Errorf("Expected files to be equal")
+		}
+	})
+
+	// Expect files with the same size but different content to not be equal
+	t.Run("different content", func(t *testing.T) {
+		a := "equal_2_a.txt"
+		b := "equal_2_b.txt"
+		defer os.Remove(a)
+		defer os.Remove(b)
+
+		if err := os.WriteFile(a, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		if err := os.WriteFile(b, []byte("test context"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		equal, err := Equal(a, b)
+		if err != nil {
+			t.Errorf("Equal failed: %v", err)
+		}
+
+		if equal {
+			t.Errorf("Expected files to not be equal")
+		}
+	})
+
+	// Expect files with different sizes to not be equal
+	t.Run("different size", func(t *testing.T) {
+		a := "equal_3_a.txt"
+		b := "equal_3_b.txt"
+		defer os.Remove(a)
+		defer os.Remove(b)
+
+		if err := os.WriteFile(a, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		if err := os.WriteFile(b, []byte("test content appended"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		equal, err := Equal(a, b)
+		if err != nil {
+			t.Errorf("Equal failed: %v", err)
+		}
+
+		if equal {
+			t.Errorf("Expected files to not be equal")
+		}
+	})
+}
+
+func TestEqualContent(t *testing.T) {
+	// Expect a file to be compared against in-memory content
+	t.Run("compare content", func(t *testing.T) {
+		path := "equal_content.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		equal, err := EqualContent(path, []byte("test content"))
+		if err != nil {
+			t.Errorf("EqualContent failed: %v", err)
+		}
+
+		if !equal {
+			t.Errorf("Expected content to be equal")
+		}
+
+		equal, err = EqualContent(path, []byte("other content"))
+		if err != nil {
+			t.Errorf("EqualContent failed: %v", err)
+		}
+
+		if equal {
+			t.Errorf("Expected content to not be equal")
+		}
+	})
+}
+
+func TestDiffDirs(t *testing.T) {
+	// Expect added, removed and changed files to be reported
+	t.Run("diff directories", func(t *testing.T) {
+		path := "diff_dirs"
+		defer os.RemoveAll(path)
+
+		a := filepath.Join(path, "a")
+		b := filepath.Join(path, "b")
+		files := map[string]string{
+			filepath.Join(a, "same.txt"):          "same",
+			filepath.Join(b, "same.txt"):          "same",
+			filepath.Join(a, "nested", "mod.txt"): "before",
+			filepath.Join(b, "nested", "mod.txt"): "after",
+			filepath.Join(a, "removed.txt"):       "removed",
+			filepath.Join(b, "nested", "new.txt"): "added",
+		}
+		for file, content := range files {
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatalf("os.MkdirAll failed: %v", err)
+			}
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatalf("os.WriteFile failed: %v", err)
+			}
+		}
+
+		diff, err := DiffDirs(a, b)
+		if err != nil {
+			t.Errorf("DiffDirs failed: %v", err)
+		}
+
+		expected := DirDiff{
+			Added:   []string{filepath.Join("nested", "new.txt")},
+			Removed: []string{"removed.txt"},
+			Changed: []string{filepath.Join("nested", "mod.txt")},
+		}
+		if !reflect.DeepEqual(diff, expected) {
+			t.Errorf("Expected diff to be %+v, got %+v", expected, diff)
+		}
+	})
+
+	// Expect identical trees to produce an empty diff
+	t.Run("identical directories", func(t *testing.T) {
+		path := "diff_dirs_same"
+		defer os.RemoveAll(path)
+
+		for _, dir := range []string{"a", "b"} {
+			file := filepath.Join(path, dir, "file.txt")
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatalf("os.MkdirAll failed: %v", err)
+			}
+			if err := os.WriteFile(file, []byte("test content"), 0644); err != nil {
+				t.Fatalf("os.WriteFile failed: %v", err)
+			}
+		}
+
+		diff, err := DiffDirs(filepath.Join(path, "a"), filepath.Join(path, "b"))
+		if err != nil {
+			t.Errorf("DiffDirs failed: %v", err)
+		}
+
+		if !diff.Empty() {
+			t.Errorf("Expected diff to be empty, got %+v", diff)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package fs_go
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ConcatOptions configures ConcatWithOptions.
+type ConcatOptions struct {
+	// Separator is written between consecutive files, like "\n" or ";\n".
+	Separator string
+	// EnsureNewline adds a newline after every file that doesn't end with one,
+	// so lines from different files are never merged.
+	EnsureNewline bool
+	// Mode is the file mode of dst. Defaults to 0644.
+	Mode os.FileMode
+}
+
+// Concat streams the content of srcs, in order, into dst, replacing it atomically.
+// dst may also be one of the sources.
+//
+// Example:
+//
+//	err := Concat("dist/app.js", "src/vendor.js", "src/main.js")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func Concat(dst string, srcs ...string) error {
+	return ConcatWithOptions(dst, srcs, ConcatOptions{})
+}
+
+// ConcatWithOptions streams the content of srcs into dst, with separators and newline
+// handling as configured.
+//
+// Example:
+//
+//	err := ConcatWithOptions("merged.log", logs, ConcatOptions{EnsureNewline: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ConcatWithOptions(dst string, srcs []string, opts ConcatOptions) (err error) {
+	dst = normalizePath(dst)
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+
+	if skip, err := writeGuard("Concat", dst, ""); skip {
+		return err
+	}
+	defer invalidateStats(dst)
+
+	var written int64
+	defer func() { observe("Concat", written, written, err) }()
+
+	file, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return pathError("Concat", dst, fmt.Errorf("Concat failed to create temporary file: %w", err))
+	}
+	tmp := file.Name()
+	defer os.Remove(tmp)
+
+	writer := &lastByteWriter{w: file}
+	for i, src := range srcs {
+		if i > 0 && opts.Separator != "" {
+			_, err = io.WriteString(writer, opts.Separator)
+			if err != nil {
+				file.Close()
+				return pathError("Concat", dst, fmt.Errorf("Concat failed to write separator: %w", err))
+			}
+		}
+
+		n, err := appendFile(writer, normalizePath(src))
+		if err != nil {
+			file.Close()
+			return pathError("Concat", src, fmt.Errorf("Concat failed to append %s: %w", src, err))
+		}
+
+		if opts.EnsureNewline && n > 0 && writer.last != '\n' {
+			_, err = io.WriteString(writer, "\n")
+			if err != nil {
+				file.Close()
+				return pathError("Concat", dst, fmt.Errorf("Concat failed to write newline: %w", err))
+			}
+		}
+	}
+	written = writer.n
+
+	err = file.Chmod(opts.Mode)
+	if err != nil {
+		file.Close()
+		return pathError("Concat", dst, fmt.Errorf("Concat failed to set file mode: %w", err))
+	}
+	err = file.Close()
+	if err != nil {
+		return pathError("Concat", dst, fmt.Errorf("Concat failed to close file: %w", err))
+	}
+
+	err = os.Rename(tmp, dst)
+	if err != nil {
+		return pathError("Concat", dst, fmt.Errorf("Concat failed to rename temporary file: %w", err))
+	}
+
+	return nil
+}
+
+// appendFile copies the file at path to writer and returns the number of bytes copied.
+func appendFile(writer io.Writer, path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return io.Copy(writer, file)
+}
+
+// lastByteWriter counts the bytes written through it and remembers the last one.
+type lastByteWriter struct {
+	w    io.Writer
+	n    int64
+	last byte
+}
+
+func (l *lastByteWriter) Write(p []byte) (int, error) {
+	n, err := l.w.Write(p)
+	l.n += int64(n)
+	if n > 0 {
+		l.last = p[n-1]
+	}
+
+	return n, err
+}
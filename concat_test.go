@@ -0,0 +1,74 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	// Expect files to be joined in order
+	t.Run("concat", func(t *testing.T) {
+		path := "concat_1"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"a.js": "a();", "b.js": "b();"})
+
+		dst := filepath.Join(path, "bundle.js")
+		err := Concat(dst, filepath.Join(path, "a.js"), filepath.Join(path, "b.js"))
+		if err != nil {
+			t.Fatalf("Concat failed: %v", err)
+		}
+		if content, _ := os.ReadFile(dst); string(content) != "a();b();" {
+			t.Errorf("Expected a();b();, got %q", content)
+		}
+	})
+
+	// Expect separators between files and newlines after files missing one
+	t.Run("options", func(t *testing.T) {
+		path := "concat_2"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"a.log": "a1\na2", "b.log": "b1\n", "c.log": ""})
+
+		dst := filepath.Join(path, "merged.log")
+		srcs := []string{filepath.Join(path, "a.log"), filepath.Join(path, "b.log"), filepath.Join(path, "c.log")}
+		err := ConcatWithOptions(dst, srcs, ConcatOptions{Separator: "--\n", EnsureNewline: true})
+		if err != nil {
+			t.Fatalf("ConcatWithOptions failed: %v", err)
+		}
+		if content, _ := os.ReadFile(dst); string(content) != "a1\na2\n--\nb1\n--\n" {
+			t.Errorf("Expected separated lines, got %q", content)
+		}
+	})
+
+	// Expect dst to be usable as a source
+	t.Run("append to self", func(t *testing.T) {
+		path := "concat_3"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"a.txt": "a", "b.txt": "b"})
+
+		dst := filepath.Join(path, "a.txt")
+		err := Concat(dst, dst, filepath.Join(path, "b.txt"))
+		if err != nil {
+			t.Fatalf("Concat failed: %v", err)
+		}
+		if content, _ := os.ReadFile(dst); string(content) != "ab" {
+			t.Errorf("Expected ab, got %q", content)
+		}
+	})
+
+	// Expect a missing source to leave dst untouched
+	t.Run("missing source", func(t *testing.T) {
+		path := "concat_4"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"out.txt": "old"})
+
+		dst := filepath.Join(path, "out.txt")
+		err := Concat(dst, filepath.Join(path, "missing.txt"))
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if content, _ := os.ReadFile(dst); string(content) != "old" {
+			t.Errorf("Expected old, got %q", content)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/BurntSushi/toml"
+)
+
+// yamlKeyLine matches lines that look like YAML mappings, such as "name: value" or "server:".
+var yamlKeyLine = regexp.MustCompile(`(?m)^\s*[\w.-]+\s*:(\s|$)`)
+
+// ReadConfig reads a config file into v. The format is picked from the extension:
+// .json, .yaml, .yml, .toml or .ini. For any other extension, like .conf or none at all,
+// it is detected from the content. A leading ~ in path is expanded to the home directory.
+//
+// Example:
+//
+//	var config Config
+//	err := ReadConfig("~/.config/app/config.toml", &config)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadConfig[T any](path string, v *T) error {
+	expanded, err := ExpandHome(path)
+	if err != nil {
+		return fmt.Errorf("ReadConfig failed to expand path: %w", err)
+	}
+
+	content, err := ReadBytes(expanded)
+	if err != nil {
+		return fmt.Errorf("ReadConfig failed to read file: %w", err)
+	}
+
+	format, err := FormatFromPath(expanded)
+	if err != nil || format == FormatCSV {
+		format = sniffFormat(content)
+	}
+
+	err = decodeFormat(format, content, v)
+	if err != nil {
+		return fmt.Errorf("ReadConfig failed to decode %s: %w", format, err)
+	}
+
+	return nil
+}
+
+// ReadFirstConfig reads the first of paths that exists into v with ReadConfig, and returns
+// the path it read. This supports fallback chains like a project config, then a user config,
+// then a system config. If none of the paths exist, the error wraps ErrNotExist.
+//
+// Example:
+//
+//	var config Config
+//	path, err := ReadFirstConfig(&config, "app.yaml", "~/.config/app/config.yaml", "/etc/app/config.yaml")
+//	if errors.Is(err, ErrNotExist) {
+//	    config = defaultConfig
+//	} else if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadFirstConfig[T any](v *T, paths ...string) (string, error) {
+	for _, path := range paths {
+		expanded, err := ExpandHome(path)
+		if err != nil {
+			return "", fmt.Errorf("ReadFirstConfig failed to expand path: %w", err)
+		}
+
+		exists, err := Exists(expanded)
+		if err != nil {
+			return "", fmt.Errorf("ReadFirstConfig failed to check %s: %w", path, err)
+		}
+		if !exists {
+			continue
+		}
+
+		err = ReadConfig(expanded, v)
+		if err != nil {
+			return path, fmt.Errorf("ReadFirstConfig failed to read %s: %w", path, err)
+		}
+
+		return path, nil
+	}
+
+	return "", fmt.Errorf("ReadFirstConfig failed: none of %v exist: %w", paths, ErrNotExist)
+}
+
+// sniffFormat guesses the format of config content.
+// Content with key = value lines is TOML if it parses as such, and INI otherwise.
+func sniffFormat(content []byte) Format {
+	trimmed := bytes.TrimSpace(content)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return FormatJSON
+	}
+	if yamlKeyLine.Match(trimmed) || bytes.HasPrefix(trimmed, []byte("---")) {
+		return FormatYAML
+	}
+
+	var v map[string]any
+	if toml.Unmarshal(trimmed, &v) == nil {
+		return FormatTOML
+	}
+	if _, err := parseIni(string(trimmed)); err == nil {
+		return FormatINI
+	}
+
+	return FormatYAML
+}
@@ -0,0 +1,127 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Name   string `json:"name" yaml:"name" toml:"name"`
+	Server struct {
+		Port    int           `json:"port" yaml:"port" toml:"port"`
+		Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	} `json:"server" yaml:"server" toml:"server"`
+}
+
+func TestReadConfig(t *testing.T) {
+	contents := map[string]string{
+		"json": `{"name": "app", "server": {"port": 8080}}`,
+		"yaml": "name: app\nserver:\n  port: 8080\n",
+		"toml": "name = \"app\"\n\n[server]\nport = 8080\n",
+		"ini":  "; comment\nname = app\n\n[server]\nport = 8080\n",
+	}
+
+	// Expect the format to be picked from the extension
+	for ext, content := range contents {
+		t.Run("extension "+ext, func(t *testing.T) {
+			path := "read_config." + ext
+			defer os.Remove(path)
+
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("os.WriteFile failed: %v", err)
+			}
+
+			var config testConfig
+			err := ReadConfig(path, &config)
+			if err != nil {
+				t.Errorf("ReadConfig failed: %v", err)
+			}
+
+			if config.Name != "app" || config.Server.Port != 8080 {
+				t.Errorf("Expected app on port 8080, got %+v", config)
+			}
+		})
+	}
+
+	// Expect the format to be detected from the content for unknown extensions
+	for ext, content := range contents {
+		t.Run("sniffed "+ext, func(t *testing.T) {
+			path := "read_config_" + ext + ".conf"
+			defer os.Remove(path)
+
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("os.WriteFile failed: %v", err)
+			}
+
+			var config testConfig
+			err := ReadConfig(path, &config)
+			if err != nil {
+				t.Errorf("ReadConfig failed: %v", err)
+			}
+
+			if config.Name != "app" || config.Server.Port != 8080 {
+				t.Errorf("Expected app on port 8080, got %+v", config)
+			}
+		})
+	}
+
+	// Expect INI values to be converted to the field types
+	t.Run("ini types", func(t *testing.T) {
+		path := "read_config_types.ini"
+		defer os.Remove(path)
+
+		content := "[server]\nport = 0x50\ntimeout = 1m30s\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var config testConfig
+		err := ReadConfig(path, &config)
+		if err != nil {
+			t.Errorf("ReadConfig failed: %v", err)
+		}
+
+		if config.Server.Port != 80 || config.Server.Timeout != 90*time.Second {
+			t.Errorf("Expected port 80 and timeout 1m30s, got %+v", config.Server)
+		}
+	})
+}
+
+func TestReadFirstConfig(t *testing.T) {
+	// Expect the first existing path to be read
+	t.Run("fallback", func(t *testing.T) {
+		path := "read_first_config_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"user.json":   `{"name": "user"}`,
+			"system.json": `{"name": "system"}`,
+		})
+
+		var config testConfig
+		used, err := ReadFirstConfig(&config,
+			filepath.Join(path, "project.json"),
+			filepath.Join(path, "user.json"),
+			filepath.Join(path, "system.json"),
+		)
+		if err != nil {
+			t.Errorf("ReadFirstConfig failed: %v", err)
+		}
+
+		if used != filepath.Join(path, "user.json") || config.Name != "user" {
+			t.Errorf("Expected user.json to be read, got %s with %+v", used, config)
+		}
+	})
+
+	// Expect ErrNotExist if no path exists
+	t.Run("none exist", func(t *testing.T) {
+		var config testConfig
+		_, err := ReadFirstConfig(&config, "read_first_config_missing.json")
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected error to match ErrNotExist, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package fs_go
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// CopyBetween copies n bytes from the current offset of src to the current offset of dst,
+// or everything up to the end of src if n is negative, and returns the number of bytes
+// copied. Like io.CopyN, it fails with io.EOF if src ends before n bytes were copied.
+//
+// The content is moved by the kernel with copy_file_range, splice or sendfile on Linux,
+// depending on what the files are, so pipes, sockets and regular files can all be copied
+// without going through userspace buffers. Elsewhere, or where those calls aren't supported,
+// it falls back to a regular copy. Like os.File.Fd, it puts the files in blocking mode.
+//
+// Example:
+//
+//	// Forward the payload of an archive entry to stdout
+//	_, err = archive.Seek(entryOffset, io.SeekStart)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	_, err = CopyBetween(archive, os.Stdout, entrySize)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func CopyBetween(srcFile, dstFile *os.File, n int64) (copied int64, err error) {
+	defer func() { observe("CopyBetween", copied, copied, err) }()
+
+	copied, fast, err := copyFast(srcFile, dstFile, n)
+	if err != nil {
+		return copied, pathError("CopyBetween", srcFile.Name(), fmt.Errorf("CopyBetween failed to copy: %w", err))
+	}
+
+	var rest int64
+	switch {
+	case fast && (n < 0 || copied == n):
+		return copied, nil
+	case fast:
+		// The source ended early, which io.CopyN reports
+		rest, err = io.CopyN(dstFile, srcFile, n-copied)
+	case n < 0:
+		rest, err = io.Copy(dstFile, srcFile)
+	default:
+		rest, err = io.CopyN(dstFile, srcFile, n)
+	}
+	copied += rest
+	if err == io.EOF {
+		return copied, err
+	}
+	if err != nil {
+		return copied, pathError("CopyBetween", srcFile.Name(), fmt.Errorf("CopyBetween failed to copy: %w", err))
+	}
+
+	return copied, nil
+}
@@ -0,0 +1,97 @@
+package fs_go
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyBetween(t *testing.T) {
+	// Expect n bytes to be copied from the current offsets
+	t.Run("files", func(t *testing.T) {
+		path := "copy_between_1"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"src": "headerPAYLOADtrailer", "dst": "out:"})
+
+		src, _ := os.Open(filepath.Join(path, "src"))
+		defer src.Close()
+		dst, _ := os.OpenFile(filepath.Join(path, "dst"), os.O_WRONLY|os.O_APPEND, 0644)
+		defer dst.Close()
+
+		src.Seek(6, io.SeekStart)
+		n, err := CopyBetween(src, dst, 7)
+		if err != nil {
+			t.Fatalf("CopyBetween failed: %v", err)
+		}
+		if n != 7 {
+			t.Errorf("Expected 7 bytes, got %d", n)
+		}
+
+		// Expect the offset of the source to have moved past the copied bytes
+		rest, _ := io.ReadAll(src)
+		if string(rest) != "trailer" {
+			t.Errorf("Expected trailer, got %q", rest)
+		}
+
+		content, _ := os.ReadFile(filepath.Join(path, "dst"))
+		if string(content) != "out:PAYLOAD" {
+			t.Errorf("Expected out:PAYLOAD, got %q", content)
+		}
+	})
+
+	// Expect a negative n to copy everything, and a short source to report io.EOF
+	t.Run("until end", func(t *testing.T) {
+		path := "copy_between_2"
+		defer os.RemoveAll(path)
+		content := bytes.Repeat([]byte("data"), 1<<16)
+		writeTree(t, path, map[string]string{"src": string(content)})
+
+		src, _ := os.Open(filepath.Join(path, "src"))
+		defer src.Close()
+		dst, _ := os.Create(filepath.Join(path, "dst"))
+		defer dst.Close()
+
+		n, err := CopyBetween(src, dst, -1)
+		if err != nil || n != int64(len(content)) {
+			t.Errorf("Expected %d bytes, got %d, %v", len(content), n, err)
+		}
+
+		src.Seek(-4, io.SeekEnd)
+		n, err = CopyBetween(src, dst, 10)
+		if err != io.EOF || n != 4 {
+			t.Errorf("Expected 4 bytes and io.EOF, got %d, %v", n, err)
+		}
+	})
+
+	// Expect pipes to be copied too
+	t.Run("pipe", func(t *testing.T) {
+		path := "copy_between_3"
+		defer os.RemoveAll(path)
+		os.Mkdir(path, 0755)
+
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("os.Pipe failed: %v", err)
+		}
+		defer r.Close()
+		go func() {
+			w.WriteString("through a pipe")
+			w.Close()
+		}()
+
+		dst, _ := os.Create(filepath.Join(path, "dst"))
+		defer dst.Close()
+
+		_, err = CopyBetween(r, dst, -1)
+		if err != nil {
+			t.Fatalf("CopyBetween failed: %v", err)
+		}
+
+		content, _ := os.ReadFile(filepath.Join(path, "dst"))
+		if string(content) != "through a pipe" {
+			t.Errorf("Expected the piped content, got %q", content)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CopyDirOptions configures CopyDirWithOptions.
+type CopyDirOptions struct {
+	// Scheduler controls how files are copied concurrently. Defaults are used if nil.
+	Scheduler *Scheduler
+	// PreserveTimes keeps the modification and access times of files and directories.
+	PreserveTimes bool
+	// PreserveOwner keeps the owner of files and directories, which usually requires privileges.
+	PreserveOwner bool
+	// Ignore skips the files and directories it matches, relative to the source.
+	Ignore *Ignorer
+	// Progress receives the bytes of the files copied, once the tree was walked.
+	Progress Progress
+	// KeepPartial leaves whatever was copied in place when the copy fails. By default, the
+	// files and directories the copy created are removed again. Files that existed before
+	// are never removed, but those that were overwritten keep their new content.
+	KeepPartial bool
+}
+
+// CopyDir copies a directory tree from source to destination, merging into the
+// destination if it exists. File permissions and symlinks are preserved, while
+// times and owners are only preserved if requested through CopyDirWithOptions.
+func CopyDir(src, dst string) error {
+	return CopyDirWithOptions(src, dst, CopyDirOptions{})
+}
+
+// CopyDirWithOptions copies a directory tree from source to destination.
+// Files are copied concurrently, as scheduled by the options' Scheduler. If the copy fails,
+// what it created is removed, unless the options keep it.
+//
+// Example:
+//
+//	err := CopyDirWithOptions("assets", "dist/assets", CopyDirOptions{
+//	    Scheduler: &Scheduler{SmallWorkers: 64, ChunkSize: 64 << 20},
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func CopyDirWithOptions(src, dst string, opts CopyDirOptions) (err error) {
+	src, dst = normalizePath(src), normalizePath(dst)
+
+	var jobs []Job
+	var dirs []string
+	var created []string // Paths that didn't exist before the copy, in creation order
+	defer func() {
+		if err != nil && !opts.KeepPartial && !IsDryRun() {
+			removeCreated(created)
+		}
+	}()
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if opts.Ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(target); os.IsNotExist(err) {
+			created = append(created, target)
+		}
+
+		switch {
+		case info.IsDir():
+			dirs = append(dirs, path)
+			return EnsureDirWithMode(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			return copySymlink(path, target)
+		case info.Mode().IsRegular():
+			jobs = append(jobs, Job{Size: info.Size(), Run: func() error {
+				return copyRegular(opts.Scheduler, path, target, info, opts.PreserveTimes, opts.PreserveOwner, nil)
+			}})
+			return nil
+		default:
+			return fmt.Errorf("%s has unsupported file type %s", path, info.Mode().Type())
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("CopyDir failed to walk directory: %w", err)
+	}
+
+	progress := startProgress(opts.Progress, 0, jobsSize(jobs))
+	progress.track(jobs)
+	err = opts.Scheduler.Run(jobs)
+	progress.finish(err)
+	if err != nil {
+		return fmt.Errorf("CopyDir failed to copy files: %w", err)
+	}
+
+	if (!opts.PreserveTimes && !opts.PreserveOwner) || IsDryRun() {
+		return nil
+	}
+
+	// Copying files into a directory changes its modification time, so directories
+	// are handled last, deepest first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		meta, err := CaptureMeta(dirs[i])
+		if err != nil {
+			return fmt.Errorf("CopyDir failed to capture metadata: %w", err)
+		}
+
+		rel, err := filepath.Rel(src, dirs[i])
+		if err != nil {
+			return fmt.Errorf("CopyDir failed to resolve path: %w", err)
+		}
+
+		err = applyMeta(filepath.Join(dst, rel), meta, false, opts.PreserveTimes, opts.PreserveOwner)
+		if err != nil {
+			return fmt.Errorf("CopyDir failed to apply metadata: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeCreated removes the paths a failed operation created, last first, so directories
+// are empty by the time they are removed. Errors are ignored, since the operation already failed.
+func removeCreated(paths []string) {
+	for i := len(paths) - 1; i >= 0; i-- {
+		os.Remove(paths[i])
+	}
+}
+
+// copyRegular copies a regular file and applies the permissions of the source,
+// and optionally its times and owner. The copy is limited by limiter if it isn't nil.
+func copyRegular(scheduler *Scheduler, src, dst string, info os.FileInfo, times, owner bool, limiter *rateLimiter) error {
+	err := scheduler.copyFile(src, dst, info.Size(), limiter)
+	if err != nil {
+		return err
+	}
+	if IsDryRun() {
+		return nil
+	}
+
+	return applyMeta(dst, metaFromInfo(src, info), true, times, owner)
+}
+
+// copySymlink recreates a symlink, replacing whatever exists at the destination.
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	if skip, err := writeGuard("Symlink", target, dst); skip {
+		return err
+	}
+	defer invalidateStats(target, dst)
+
+	err = os.RemoveAll(dst)
+	if err != nil {
+		return err
+	}
+
+	return os.Symlink(target, dst)
+}
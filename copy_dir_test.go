@@ -0,0 +1,152 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCopyDir(t *testing.T) {
+	// Expect a directory tree to be copied with file permissions
+	t.Run("copy tree", func(t *testing.T) {
+		path := "copy_dir_1"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{
+			"a.txt":          "a",
+			"nested/b.txt":   "b",
+			"nested/c/d.txt": "d",
+		})
+		if err := os.Chmod(filepath.Join(src, "a.txt"), 0600); err != nil {
+			t.Fatalf("os.Chmod failed: %v", err)
+		}
+
+		err := CopyDir(src, dst)
+		if err != nil {
+			t.Errorf("CopyDir failed: %v", err)
+		}
+
+		diff, err := DiffDirs(src, dst)
+		if err != nil {
+			t.Errorf("DiffDirs failed: %v", err)
+		}
+
+		if !diff.Empty() {
+			t.Errorf("Expected trees to be equal, got %+v", diff)
+		}
+
+		info, err := os.Stat(filepath.Join(dst, "a.txt"))
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("Expected file mode to be 0600, got %#o", info.Mode().Perm())
+		}
+	})
+
+	// Expect symlinks to be recreated rather than followed
+	t.Run("symlinks", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks require privileges on Windows")
+		}
+
+		path := "copy_dir_2"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{"a.txt": "a"})
+		if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+			t.Fatalf("os.Symlink failed: %v", err)
+		}
+
+		err := CopyDir(src, dst)
+		if err != nil {
+			t.Errorf("CopyDir failed: %v", err)
+		}
+
+		target, err := os.Readlink(filepath.Join(dst, "link"))
+		if err != nil {
+			t.Errorf("os.Readlink failed: %v", err)
+		}
+
+		if target != "a.txt" {
+			t.Errorf("Expected link to point to a.txt, got %s", target)
+		}
+	})
+
+	// Expect file and directory times to be preserved when requested
+	t.Run("preserve times", func(t *testing.T) {
+		path := "copy_dir_3"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{"nested/a.txt": "a"})
+
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		for _, name := range []string{"nested/a.txt", "nested"} {
+			if err := os.Chtimes(filepath.Join(src, name), mtime, mtime); err != nil {
+				t.Fatalf("os.Chtimes failed: %v", err)
+			}
+		}
+
+		err := CopyDirWithOptions(src, dst, CopyDirOptions{PreserveTimes: true})
+		if err != nil {
+			t.Errorf("CopyDirWithOptions failed: %v", err)
+		}
+
+		for _, name := range []string{"nested/a.txt", "nested"} {
+			info, err := os.Stat(filepath.Join(dst, name))
+			if err != nil {
+				t.Fatalf("os.Stat failed: %v", err)
+			}
+
+			if !info.ModTime().Equal(mtime) {
+				t.Errorf("Expected modification time of %s to be %v, got %v", name, mtime, info.ModTime())
+			}
+		}
+	})
+
+	// Expect a failed copy to remove what it created, and only that
+	t.Run("cleanup", func(t *testing.T) {
+		path := "copy_dir_cleanup"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{
+			"src/sub/b.txt":   "b",
+			"src/z.txt":       "z",
+			"dst/kept.txt":    "kept",
+			"dst/z.txt/x.txt": "in the way",
+		})
+		src, dst := filepath.Join(path, "src"), filepath.Join(path, "dst")
+
+		err := CopyDir(src, dst)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+
+		if _, err := os.Stat(filepath.Join(dst, "sub")); !os.IsNotExist(err) {
+			t.Errorf("Expected the created directory to be removed, got %v", err)
+		}
+		for _, name := range []string{"kept.txt", "z.txt/x.txt"} {
+			if _, err := os.Stat(filepath.Join(dst, name)); err != nil {
+				t.Errorf("Expected %s to be kept, got %v", name, err)
+			}
+		}
+
+		// Expect KeepPartial to leave the copied files. A single worker copies sub/b.txt
+		// before failing on z.txt, since no new copies start after a failure.
+		err = CopyDirWithOptions(src, dst, CopyDirOptions{KeepPartial: true, Scheduler: &Scheduler{SmallWorkers: 1}})
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if content, _ := os.ReadFile(filepath.Join(dst, "sub", "b.txt")); string(content) != "b" {
+			t.Errorf("Expected the copied file to be kept, got %q", content)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package fs_go
+
+// Regular copies are done by the kernel where the platform supports it, which avoids
+// moving the content through userspace buffers:
+//
+//   - copyFileNative copies by path before any file is opened, like CopyFileEx on Windows.
+//     It reports false if the platform has no such call or it failed, and the copy then
+//     continues as usual, overwriting whatever it left behind.
+//   - copyFast copies up to a limit between open files, like copy_file_range, splice and
+//     sendfile on Linux, or until the end of the source if the limit is negative.
+//     It reports false if nothing was copied and the caller must fall back to io.Copy.
+
+// copyChunkSize is the most a single kernel copy call is asked to copy.
+const copyChunkSize = 1 << 30
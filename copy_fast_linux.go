@@ -0,0 +1,71 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func copyFileNative(src, dst string) (int64, bool, error) {
+	return 0, false, nil
+}
+
+func copyFast(source, destination *os.File, limit int64) (int64, bool, error) {
+	in, out := int(source.Fd()), int(destination.Fd())
+
+	copied, ok, err := kernelCopy(limit, func(n int) (int, error) {
+		return unix.CopyFileRange(in, nil, out, nil, n, 0)
+	})
+	if ok || err != nil {
+		return copied, ok, err
+	}
+
+	// Splice needs a pipe on one side, and fails for other files
+	copied, ok, err = kernelCopy(limit, func(n int) (int, error) {
+		n64, err := unix.Splice(in, nil, out, nil, n, unix.SPLICE_F_MOVE)
+		return int(n64), err
+	})
+	if ok || err != nil {
+		return copied, ok, err
+	}
+
+	return kernelCopy(limit, func(n int) (int, error) {
+		return unix.Sendfile(out, in, nil, n)
+	})
+}
+
+// kernelCopy calls copyChunk until it reports the end of the source or limit bytes were
+// copied, unless limit is negative. It reports false if the first call failed in a way
+// that means the call isn't supported for these files.
+func kernelCopy(limit int64, copyChunk func(n int) (int, error)) (int64, bool, error) {
+	var copied int64
+	for limit < 0 || copied < limit {
+		chunk := int64(copyChunkSize)
+		if limit >= 0 {
+			chunk = min(chunk, limit-copied)
+		}
+
+		n, err := copyChunk(int(chunk))
+		if copied == 0 && unsupportedCopy(err) {
+			return 0, false, nil
+		}
+		if err != nil {
+			return copied, true, err
+		}
+		if n == 0 {
+			// Some files, like those in /proc, report no content to these calls
+			return copied, copied > 0, nil
+		}
+
+		copied += int64(n)
+	}
+
+	return copied, true, nil
+}
+
+// unsupportedCopy reports whether a kernel copy error means the copy must be done another way.
+func unsupportedCopy(err error) bool {
+	return errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EXDEV) || errors.Is(err, unix.EINVAL) ||
+		errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.EPERM) || errors.Is(err, unix.EBADF)
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package fs_go
+
+import "os"
+
+func copyFileNative(src, dst string) (int64, bool, error) {
+	return 0, false, nil
+}
+
+func copyFast(source, destination *os.File, limit int64) (int64, bool, error) {
+	return 0, false, nil
+}
@@ -0,0 +1,56 @@
+package fs_go
+
+import (
+	"os"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32        = windows.NewLazySystemDLL("kernel32.dll")
+	procCopyFileExW = kernel32.NewProc("CopyFileExW")
+)
+
+func copyFileNative(src, dst string) (int64, bool, error) {
+	if procCopyFileExW.Find() != nil {
+		return 0, false, nil
+	}
+
+	from, err := windows.UTF16PtrFromString(src)
+	if err != nil {
+		return 0, false, nil
+	}
+	to, err := windows.UTF16PtrFromString(dst)
+	if err != nil {
+		return 0, false, nil
+	}
+
+	ret, _, _ := procCopyFileExW.Call(uintptr(unsafe.Pointer(from)), uintptr(unsafe.Pointer(to)), 0, 0, 0, 0)
+	if ret == 0 {
+		return 0, false, nil
+	}
+
+	// CopyFileEx carries over the attributes and modification time of the source, while
+	// other platforms create a writable file modified now
+	info, err := os.Stat(dst)
+	if err != nil {
+		return 0, true, err
+	}
+	err = os.Chmod(dst, 0666)
+	if err != nil {
+		return info.Size(), true, err
+	}
+	now := time.Now()
+	err = os.Chtimes(dst, now, now)
+	if err != nil {
+		return info.Size(), true, err
+	}
+
+	return info.Size(), true, nil
+}
+
+func copyFast(source, destination *os.File, limit int64) (int64, bool, error) {
+	return 0, false, nil
+}
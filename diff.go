@@ -0,0 +1,217 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around changes in a unified diff.
+const diffContext = 3
+
+// diffOp is a line of an edit script: kept (' '), deleted ('-') or inserted ('+').
+// a and b are the indexes of the line before the operation in the old and new text.
+type diffOp struct {
+	kind byte
+	line string
+	a, b int
+}
+
+// DiffFiles compares two text files line by line and returns the differences as a unified
+// diff, with the paths as labels, which tools like patch can apply. Identical files give an
+// empty string. Each file is read into memory.
+//
+// Example:
+//
+//	diff, err := DiffFiles("config.yaml", "config.yaml.new")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Print(diff)
+func DiffFiles(a, b string) (string, error) {
+	contentA, err := os.ReadFile(normalizePath(a))
+	if err != nil {
+		return "", pathError("DiffFiles", a, fmt.Errorf("DiffFiles failed to read file: %w", err))
+	}
+	contentB, err := os.ReadFile(normalizePath(b))
+	if err != nil {
+		return "", pathError("DiffFiles", b, fmt.Errorf("DiffFiles failed to read file: %w", err))
+	}
+
+	return unifiedDiff(a, b, string(contentA), string(contentB)), nil
+}
+
+// DiffTexts returns the differences between two texts as a unified diff labelled "a" and
+// "b", or an empty string if they are equal. It pairs with DryRun to show what a write
+// would change before performing it.
+//
+// Example:
+//
+//	current, _ := ReadText("app.conf")
+//	fmt.Print(DiffTexts(current, rendered))
+func DiffTexts(a, b string) string {
+	return unifiedDiff("a", "b", a, b)
+}
+
+// unifiedDiff formats the differences between two texts as a unified diff.
+func unifiedDiff(labelA, labelB, a, b string) string {
+	ops := diffLines(strings.SplitAfter(a, "\n"), strings.SplitAfter(b, "\n"))
+
+	var out strings.Builder
+	end := 0
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", labelA, labelB)
+		}
+
+		// Merge changes separated by few enough unchanged lines into a single hunk
+		start := max(i-diffContext, end)
+		last := i
+		for {
+			for last < len(ops) && ops[last].kind != ' ' {
+				last++
+			}
+			next := last
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-last > 2*diffContext {
+				break
+			}
+			last = next
+		}
+		end = min(last+diffContext, len(ops))
+
+		writeHunk(&out, ops[start:end])
+		i = end
+	}
+
+	return out.String()
+}
+
+// writeHunk writes a hunk header and its lines.
+func writeHunk(out *strings.Builder, ops []diffOp) {
+	lenA, lenB := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			lenA++
+		}
+		if op.kind != '-' {
+			lenB++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(ops[0].a, lenA), hunkRange(ops[0].b, lenB))
+
+	for _, op := range ops {
+		out.WriteByte(op.kind)
+		out.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the 1-based range of a hunk starting after index start, in the style of
+// GNU diff: the length is left out if it is 1, and empty ranges point at the line before.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, length)
+	}
+}
+
+// diffLines returns a shortest edit script turning a into b, using Myers' algorithm.
+// An empty last element, left by splitting text that ends with a newline, is ignored.
+func diffLines(a, b []string) []diffOp {
+	if len(a) > 0 && a[len(a)-1] == "" {
+		a = a[:len(a)-1]
+	}
+	if len(b) > 0 && b[len(b)-1] == "" {
+		b = b[:len(b)-1]
+	}
+
+	n, m := len(a), len(b)
+	limit := n + m
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+
+	// trace[d] holds the furthest x of every diagonal k in [-d-1, d+1] before step d
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int{}, v[offset-d-1:offset+d+2]...))
+
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	// Walk back from the end, collecting operations in reverse
+	var reversed []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffOp{kind: ' ', line: a[x], a: x, b: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, diffOp{kind: '+', line: b[y], a: x, b: y})
+		} else {
+			x--
+			reversed = append(reversed, diffOp{kind: '-', line: a[x], a: x, b: y})
+		}
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+
+	return ops
+}
@@ -0,0 +1,59 @@
+package fs_go
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDiffTexts(t *testing.T) {
+	// Expect equal texts to have no diff
+	if diff := DiffTexts("a\nb\n", "a\nb\n"); diff != "" {
+		t.Errorf("Expected no diff, got %q", diff)
+	}
+
+	// Expect changes with context, in the format of diff -u
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	expected := "--- a\n+++ b\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	if diff := DiffTexts(a, b); diff != expected {
+		t.Errorf("Expected %q, got %q", expected, diff)
+	}
+
+	// Expect a missing trailing newline to be marked
+	expected = "--- a\n+++ b\n@@ -1 +1 @@\n-x\n+x\n\\ No newline at end of file\n"
+	if diff := DiffTexts("x\n", "x"); diff != expected {
+		t.Errorf("Expected %q, got %q", expected, diff)
+	}
+
+	// Expect insertions into empty text to point at line 0
+	expected = "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+	if diff := DiffTexts("", "x\ny\n"); diff != expected {
+		t.Errorf("Expected %q, got %q", expected, diff)
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	a, b := "diff_files_a.txt", "diff_files_b.txt"
+	defer os.Remove(a)
+	defer os.Remove(b)
+	os.WriteFile(a, []byte("keep\nold\n"), 0644)
+	os.WriteFile(b, []byte("keep\nnew\n"), 0644)
+
+	// Expect the paths to be used as labels
+	diff, err := DiffFiles(a, b)
+	if err != nil {
+		t.Fatalf("DiffFiles failed: %v", err)
+	}
+	expected := "--- diff_files_a.txt\n+++ diff_files_b.txt\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n"
+	if diff != expected {
+		t.Errorf("Expected %q, got %q", expected, diff)
+	}
+
+	// Expect missing files to fail
+	_, err = DiffFiles(a, "missing.txt")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
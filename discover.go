@@ -0,0 +1,102 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// projectMarkers are the files and directories that mark the root of a project.
+var projectMarkers = []string{"go.mod", ".git", "package.json"}
+
+// ExecutableDir returns the directory of the running executable, with symlinks resolved,
+// so files shipped next to a binary can be found wherever it is started from.
+//
+// Example:
+//
+//	dir, err := ExecutableDir()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	templates := filepath.Join(dir, "templates")
+func ExecutableDir() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("ExecutableDir failed to find executable: %w", err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return "", fmt.Errorf("ExecutableDir failed to resolve symlinks: %w", err)
+	}
+
+	return filepath.Dir(resolved), nil
+}
+
+// FindUp looks for name in start and each of its ancestors, and returns the path of the
+// closest match. name can be a file or a directory. If there is none, the error wraps
+// ErrNotExist.
+//
+// Example:
+//
+//	gomod, err := FindUp(".", "go.mod")
+//	if errors.Is(err, ErrNotExist) {
+//	    fmt.Println("not in a Go module")
+//	    return
+//	}
+func FindUp(start, name string) (string, error) {
+	path, err := findUp(start, []string{name})
+	if err != nil {
+		return "", fmt.Errorf("FindUp failed: %w", err)
+	}
+
+	return path, nil
+}
+
+// ProjectRoot returns the closest directory at or above the working directory that
+// contains a go.mod, .git or package.json. If there is none, the error wraps ErrNotExist.
+//
+// Example:
+//
+//	root, err := ProjectRoot()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	config := filepath.Join(root, "config.yaml")
+func ProjectRoot() (string, error) {
+	path, err := findUp(".", projectMarkers)
+	if err != nil {
+		return "", fmt.Errorf("ProjectRoot failed: %w", err)
+	}
+
+	return filepath.Dir(path), nil
+}
+
+// findUp returns the path of the first of names found in start or its closest ancestor.
+func findUp(start string, names []string) (string, error) {
+	dir, err := NormalizePath(start)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			_, err := os.Stat(normalizePath(path))
+			if err == nil {
+				return path, nil
+			}
+			if !os.IsNotExist(err) {
+				return "", err
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %v found above %s: %w", names, start, ErrNotExist)
+		}
+		dir = parent
+	}
+}
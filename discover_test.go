@@ -0,0 +1,63 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecutableDir(t *testing.T) {
+	// Expect the directory of the test binary
+	t.Run("test binary", func(t *testing.T) {
+		dir, err := ExecutableDir()
+		if err != nil {
+			t.Fatalf("ExecutableDir failed: %v", err)
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Errorf("Expected a directory, got %s (%v)", dir, err)
+		}
+	})
+}
+
+func TestFindUp(t *testing.T) {
+	// Expect the closest match to be found from a nested directory
+	t.Run("closest", func(t *testing.T) {
+		root := "find_up_1"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{
+			"marker":             "outer",
+			"a/marker":           "inner",
+			"a/b/c/.placeholder": "",
+		})
+
+		path, err := FindUp(filepath.Join(root, "a", "b", "c"), "marker")
+		if err != nil {
+			t.Fatalf("FindUp failed: %v", err)
+		}
+		if content, _ := os.ReadFile(path); string(content) != "inner" {
+			t.Errorf("Expected the inner marker, got %s", path)
+		}
+	})
+
+	// Expect ErrNotExist if nothing is found
+	t.Run("missing", func(t *testing.T) {
+		_, err := FindUp(".", "find_up_2_does_not_exist")
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected ErrNotExist, got %v", err)
+		}
+	})
+}
+
+func TestProjectRoot(t *testing.T) {
+	// Expect the module root of this package
+	t.Run("module", func(t *testing.T) {
+		root, err := ProjectRoot()
+		if err != nil {
+			t.Fatalf("ProjectRoot failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+			t.Errorf("Expected go.mod in %s, got %v", root, err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DiskSpace is the capacity of the file system holding a path.
+type DiskSpace struct {
+	Total      uint64 // Size of the file system in bytes
+	Free       uint64 // Free bytes, including those reserved for privileged users
+	Available  uint64 // Free bytes available to the current user
+	Inodes     uint64 // Total number of inodes, zero if unknown
+	FreeInodes uint64 // Free inodes, zero if unknown
+}
+
+// DiskUsage returns the capacity of the file system holding path. It uses statfs on
+// Unix and GetDiskFreeSpaceEx on Windows, where inode counts are unknown.
+//
+// Example:
+//
+//	space, err := DiskUsage("/var/lib/app")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Printf("%d of %d bytes available\n", space.Available, space.Total)
+func DiskUsage(path string) (DiskSpace, error) {
+	path = normalizePath(path)
+
+	space, err := diskUsage(path)
+	if err != nil {
+		return DiskSpace{}, pathError("DiskUsage", path, fmt.Errorf("DiskUsage failed: %w", err))
+	}
+
+	return space, nil
+}
+
+// EnsureFreeSpace returns an error wrapping ErrNoSpace if fewer than n bytes are available
+// on the file system where path is or would be created, so large writes can fail early
+// instead of leaving partial files behind. path doesn't need to exist yet.
+//
+// Example:
+//
+//	err := EnsureFreeSpace("backups/today.tar", estimatedSize)
+//	if errors.Is(err, ErrNoSpace) {
+//	    fmt.Println("disk is too full for a backup")
+//	    return
+//	}
+func EnsureFreeSpace(path string, n int64) error {
+	path = normalizePath(path)
+
+	existing, err := existingAncestor(path)
+	if err != nil {
+		return pathError("EnsureFreeSpace", path, fmt.Errorf("EnsureFreeSpace failed: %w", err))
+	}
+	space, err := diskUsage(existing)
+	if err != nil {
+		return pathError("EnsureFreeSpace", path, fmt.Errorf("EnsureFreeSpace failed: %w", err))
+	}
+
+	if n > 0 && space.Available < uint64(n) {
+		return pathError("EnsureFreeSpace", path, fmt.Errorf("EnsureFreeSpace failed: %d bytes needed, %d available: %w", n, space.Available, ErrNoSpace))
+	}
+
+	return nil
+}
+
+// existingAncestor returns path, or its closest ancestor that exists.
+func existingAncestor(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		_, err := os.Stat(path)
+		if err == nil || !errors.Is(err, os.ErrNotExist) {
+			return path, err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path, err
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !freebsd && !dragonfly && !windows
+
+package fs_go
+
+import (
+	"errors"
+	"os"
+)
+
+func diskUsage(path string) (DiskSpace, error) {
+	_, err := os.Stat(path)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+
+	return DiskSpace{}, errors.ErrUnsupported
+}
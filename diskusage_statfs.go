@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || dragonfly
+
+package fs_go
+
+import "golang.org/x/sys/unix"
+
+func diskUsage(path string) (DiskSpace, error) {
+	var stat unix.Statfs_t
+	err := unix.Statfs(path, &stat)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+
+	// Field types differ between platforms
+	blockSize := uint64(stat.Bsize)
+	return DiskSpace{
+		Total:      uint64(stat.Blocks) * blockSize,
+		Free:       uint64(stat.Bfree) * blockSize,
+		Available:  uint64(stat.Bavail) * blockSize,
+		Inodes:     uint64(stat.Files),
+		FreeInodes: uint64(stat.Ffree),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package fs_go
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	// Expect the capacity of the current file system to be consistent
+	t.Run("current", func(t *testing.T) {
+		space, err := DiskUsage(".")
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("DiskUsage not supported")
+		}
+		if err != nil {
+			t.Fatalf("DiskUsage failed: %v", err)
+		}
+		if space.Total == 0 || space.Free > space.Total || space.Available > space.Free {
+			t.Errorf("Unexpected disk space: %+v", space)
+		}
+	})
+
+	// Expect a missing path to fail
+	t.Run("missing", func(t *testing.T) {
+		_, err := DiskUsage("disk_usage_1")
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected ErrNotExist, got %v", err)
+		}
+	})
+}
+
+func TestEnsureFreeSpace(t *testing.T) {
+	if _, err := DiskUsage("."); errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("DiskUsage not supported")
+	}
+
+	// Expect small amounts to fit, even for paths that don't exist yet
+	t.Run("enough", func(t *testing.T) {
+		err := EnsureFreeSpace("ensure_free_space_1/sub/file", 1)
+		if err != nil {
+			t.Errorf("EnsureFreeSpace failed: %v", err)
+		}
+		if _, err := os.Stat("ensure_free_space_1"); !os.IsNotExist(err) {
+			t.Errorf("Expected nothing to be created, got %v", err)
+		}
+	})
+
+	// Expect impossible amounts to fail with ErrNoSpace
+	t.Run("too much", func(t *testing.T) {
+		err := EnsureFreeSpace(".", math.MaxInt64)
+		if !errors.Is(err, ErrNoSpace) {
+			t.Errorf("Expected ErrNoSpace, got %v", err)
+		}
+	})
+}
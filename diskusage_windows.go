@@ -0,0 +1,18 @@
+package fs_go
+
+import "golang.org/x/sys/windows"
+
+func diskUsage(path string) (DiskSpace, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+
+	var space DiskSpace
+	err = windows.GetDiskFreeSpaceEx(name, &space.Available, &space.Total, &space.Free)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+
+	return space, nil
+}
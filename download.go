@@ -0,0 +1,169 @@
+package fs_go
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DownloadOptions configures DownloadWithOptions.
+type DownloadOptions struct {
+	// Checksum is the expected hex-encoded SHA-256 checksum of the content. If set, the
+	// download fails with ErrChecksumMismatch and the partial file is discarded on mismatch.
+	Checksum string
+	// Timeout limits the whole download, on top of any deadline of the context.
+	Timeout time.Duration
+	// Client sends the request. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Mode is the file mode of the downloaded file. Defaults to 0644.
+	Mode os.FileMode
+	// BytesPerSecond limits the bandwidth of the download, if positive.
+	BytesPerSecond int64
+	// Progress receives the bytes downloaded, including those of a resumed partial file.
+	// The total is -1 if the server doesn't send the content length.
+	Progress Progress
+	// KeepPartial keeps the partial file of a failed download, so the next call resumes it.
+	// By default, a partial file the download wrote to is removed when it fails.
+	KeepPartial bool
+}
+
+// Download fetches a URL into a file. See DownloadWithOptions.
+//
+// Example:
+//
+//	err := Download(ctx, "https://example.com/data.csv", "data.csv")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func Download(ctx context.Context, url, path string) error {
+	return DownloadWithOptions(ctx, url, path, DownloadOptions{})
+}
+
+// DownloadWithOptions fetches a URL into a file.
+//
+// The content is written to path + ".part" and renamed into place once complete and
+// verified, so path never holds a partial download. If the download fails or is canceled,
+// the partial file is removed, unless the options keep it. If a ".part" file is left over
+// from an interrupted download, only the rest is requested with a Range header. Servers
+// that ignore the range send the whole content, which replaces the partial file.
+//
+// Example:
+//
+//	err := DownloadWithOptions(ctx, url, "tool.tar.gz", DownloadOptions{
+//	    Checksum: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	    Timeout:  5 * time.Minute,
+//	})
+func DownloadWithOptions(ctx context.Context, url, path string, opts DownloadOptions) (err error) {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("Download", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+	var written int64
+	defer func() { observe("Download", 0, written, err) }()
+
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	partial := path + ".part"
+	var offset int64
+	if info, err := os.Stat(partial); err == nil && info.Mode().IsRegular() {
+		offset = info.Size()
+	}
+
+	written, err = fetchPartial(ctx, url, partial, offset, opts)
+	if err != nil {
+		return pathError("Download", path, fmt.Errorf("Download failed to fetch %s: %w", url, err))
+	}
+
+	if opts.Checksum != "" {
+		actual, err := hashFile(partial)
+		if err != nil {
+			return pathError("Download", path, fmt.Errorf("Download failed to hash content: %w", err))
+		}
+		if !strings.EqualFold(actual, opts.Checksum) {
+			os.Remove(partial)
+			return pathError("Download", path, fmt.Errorf("Download failed: %w: expected %s, got %s", ErrChecksumMismatch, opts.Checksum, actual))
+		}
+	}
+
+	err = os.Rename(partial, path)
+	if err != nil {
+		return pathError("Download", path, fmt.Errorf("Download failed to rename partial file: %w", err))
+	}
+
+	return nil
+}
+
+// fetchPartial requests the content of url from offset on and writes it to the partial
+// file, appending if the server honors the range. It returns the number of bytes written.
+func fetchPartial(ctx context.Context, url, partial string, offset int64, opts DownloadOptions) (written int64, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC
+		offset = 0
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return 0, fmt.Errorf("server returned unexpected range %q", resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file already holds everything
+		startProgress(opts.Progress, offset, offset).finish(nil)
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	file, err := os.OpenFile(partial, flags, opts.Mode)
+	if err != nil {
+		return 0, err
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	progress := startProgress(opts.Progress, offset, total)
+	defer func() { progress.finish(err) }()
+
+	written, err = io.Copy(progress.writer(newRateLimiter(opts.BytesPerSecond).writer(ctx, file)), resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil && !opts.KeepPartial {
+		os.Remove(partial)
+	}
+
+	return written, err
+}
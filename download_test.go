@@ -0,0 +1,151 @@
+package fs_go
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownload(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	// Requests are served on their own goroutines
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/broken":
+			// Send half the content, then drop the connection
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			w.Write(content[:len(content)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "data", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	// Expect the content to be downloaded and verified
+	t.Run("download", func(t *testing.T) {
+		path := "download_1"
+		defer os.Remove(path)
+
+		err := DownloadWithOptions(context.Background(), server.URL+"/data", path, DownloadOptions{Checksum: checksum})
+		if err != nil {
+			t.Errorf("DownloadWithOptions failed: %v", err)
+		}
+
+		read, err := os.ReadFile(path)
+		if err != nil || !bytes.Equal(read, content) {
+			t.Errorf("Expected downloaded content to match (%v)", err)
+		}
+		if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+			t.Errorf("Expected partial file to be gone")
+		}
+	})
+
+	// Expect an interrupted download to resume with a range request
+	t.Run("resume", func(t *testing.T) {
+		path := "download_2"
+		defer os.Remove(path)
+		if err := os.WriteFile(path+".part", content[:400], 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		mu.Lock()
+		ranges = nil
+		mu.Unlock()
+
+		err := DownloadWithOptions(context.Background(), server.URL+"/data", path, DownloadOptions{Checksum: checksum})
+		if err != nil {
+			t.Errorf("DownloadWithOptions failed: %v", err)
+		}
+
+		mu.Lock()
+		if len(ranges) != 1 || ranges[0] != "bytes=400-" {
+			t.Errorf("Expected a single range request from byte 400, got %v", ranges)
+		}
+		mu.Unlock()
+		read, err := os.ReadFile(path)
+		if err != nil || !bytes.Equal(read, content) {
+			t.Errorf("Expected resumed content to match (%v)", err)
+		}
+	})
+
+	// Expect a checksum mismatch to fail and discard the partial file
+	t.Run("checksum", func(t *testing.T) {
+		path := "download_3"
+		defer os.Remove(path)
+
+		err := DownloadWithOptions(context.Background(), server.URL+"/data", path, DownloadOptions{Checksum: strings.Repeat("0", 64)})
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected no file at the destination")
+		}
+		if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+			t.Errorf("Expected partial file to be removed")
+		}
+	})
+
+	// Expect error statuses and timeouts to fail
+	t.Run("errors", func(t *testing.T) {
+		path := "download_4"
+		defer os.Remove(path)
+		defer os.Remove(path + ".part")
+
+		err := Download(context.Background(), server.URL+"/missing", path)
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("Expected a 404 error, got %v", err)
+		}
+
+		err = DownloadWithOptions(context.Background(), server.URL+"/slow", path, DownloadOptions{Timeout: 50 * time.Millisecond})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	// Expect a broken download to remove its partial file, unless it is kept for resuming
+	t.Run("cleanup", func(t *testing.T) {
+		path := "download_5"
+		defer os.Remove(path)
+		defer os.Remove(path + ".part")
+
+		err := Download(context.Background(), server.URL+"/broken", path)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+			t.Errorf("Expected partial file to be removed")
+		}
+
+		err = DownloadWithOptions(context.Background(), server.URL+"/broken", path, DownloadOptions{KeepPartial: true})
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		if info, err := os.Stat(path + ".part"); err != nil || info.Size() != int64(len(content)/2) {
+			t.Errorf("Expected partial file with half the content to be kept (%v)", err)
+		}
+	})
+}
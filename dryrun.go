@@ -0,0 +1,97 @@
+package fs_go
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Action describes a mutating operation that was recorded instead of performed in dry-run mode.
+type Action struct {
+	Op   string // The operation, like "WriteBytes" or "Remove"
+	Path string // The path being modified
+	Dest string // The destination, for operations like "CopyFile" and "Move"
+}
+
+// String returns a human-readable description of the action, suitable for CLI previews.
+func (a Action) String() string {
+	if a.Dest != "" {
+		return fmt.Sprintf("%s %s -> %s", a.Op, a.Path, a.Dest)
+	}
+
+	return fmt.Sprintf("%s %s", a.Op, a.Path)
+}
+
+var dryRun struct {
+	sync.RWMutex
+	record func(Action)
+}
+
+// SetDryRun enables dry-run mode for the whole package. While enabled, functions that
+// would modify the file system call record with the intended action instead and
+// report success. Reads are still performed. Passing nil disables dry-run mode.
+//
+// record may be called from multiple goroutines at once.
+func SetDryRun(record func(Action)) {
+	dryRun.Lock()
+	defer dryRun.Unlock()
+
+	dryRun.record = record
+}
+
+// IsDryRun reports whether dry-run mode is enabled.
+func IsDryRun() bool {
+	dryRun.RLock()
+	defer dryRun.RUnlock()
+
+	return dryRun.record != nil
+}
+
+// DryRun runs fn in dry-run mode and returns the actions it would have performed,
+// in order. The previous dry-run setting is restored afterwards.
+//
+// Example:
+//
+//	plan, err := DryRun(func() error {
+//	    return WriteText("out/report.txt", report)
+//	})
+//	for _, action := range plan {
+//	    fmt.Println(action)
+//	}
+func DryRun(fn func() error) ([]Action, error) {
+	var mu sync.Mutex
+	var plan []Action
+
+	dryRun.Lock()
+	previous := dryRun.record
+	dryRun.record = func(action Action) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		plan = append(plan, action)
+	}
+	dryRun.Unlock()
+
+	defer SetDryRun(previous)
+
+	err := fn()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return plan, err
+}
+
+// dryRunSkip records the action and reports true if dry-run mode is enabled,
+// in which case the caller must not perform the operation.
+func dryRunSkip(op, path, dest string) bool {
+	dryRun.RLock()
+	record := dryRun.record
+	dryRun.RUnlock()
+
+	if record == nil {
+		return false
+	}
+
+	record(Action{Op: op, Path: path, Dest: dest})
+	return true
+}
@@ -0,0 +1,116 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	// Expect mutating operations to be recorded instead of performed
+	t.Run("record actions", func(t *testing.T) {
+		path := "dry_run_1"
+		defer os.RemoveAll(path)
+
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+		existing := filepath.Join(path, "existing.txt")
+		if err := os.WriteFile(existing, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		written := filepath.Join(path, "written.txt")
+		copied := filepath.Join(path, "copied.txt")
+		moved := filepath.Join(path, "moved.txt")
+		plan, err := DryRun(func() error {
+			if err := WriteText(written, "test content"); err != nil {
+				return err
+			}
+			if err := CopyFile(existing, copied); err != nil {
+				return err
+			}
+			if err := Move(existing, moved); err != nil {
+				return err
+			}
+			return Remove(existing)
+		})
+		if err != nil {
+			t.Errorf("DryRun failed: %v", err)
+		}
+
+		expected := []Action{
+			{Op: "WriteBytes", Path: written},
+			{Op: "CopyFile", Path: existing, Dest: copied},
+			{Op: "Move", Path: existing, Dest: moved},
+			{Op: "Remove", Path: existing},
+		}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Errorf("Expected plan to be %v, got %v", expected, plan)
+		}
+
+		names, err := ReadDir(path)
+		if err != nil {
+			t.Errorf("ReadDir failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(names, []string{"existing.txt"}) {
+			t.Errorf("Expected directory to be untouched, got %v", names)
+		}
+	})
+
+	// Expect dry-run mode to be disabled afterwards
+	t.Run("restore mode", func(t *testing.T) {
+		_, err := DryRun(func() error {
+			if !IsDryRun() {
+				t.Errorf("Expected dry-run mode to be enabled")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("DryRun failed: %v", err)
+		}
+
+		if IsDryRun() {
+			t.Errorf("Expected dry-run mode to be disabled")
+		}
+	})
+
+	// Expect EnsureDir to plan every missing directory
+	t.Run("ensure nested directories", func(t *testing.T) {
+		path := "dry_run_3"
+		defer os.RemoveAll(path)
+
+		nested := filepath.Join(path, "a", "b")
+		plan, err := DryRun(func() error {
+			return EnsureDir(nested)
+		})
+		if err != nil {
+			t.Errorf("DryRun failed: %v", err)
+		}
+
+		if len(plan) != 3 || plan[2].Path != nested {
+			t.Errorf("Expected three directories to be planned, got %v", plan)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected directory to not be created")
+		}
+	})
+}
+
+func TestAction(t *testing.T) {
+	// Expect actions to be printable
+	t.Run("string", func(t *testing.T) {
+		action := Action{Op: "CopyFile", Path: "a", Dest: "b"}
+		if action.String() != "CopyFile a -> b" {
+			t.Errorf("Expected 'CopyFile a -> b', got '%s'", action)
+		}
+
+		action = Action{Op: "Remove", Path: "a"}
+		if action.String() != "Remove a" {
+			t.Errorf("Expected 'Remove a', got '%s'", action)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding identifies a text encoding.
+type Encoding int
+
+const (
+	EncodingUTF8 Encoding = iota
+	EncodingUTF16LE
+	EncodingUTF16BE
+	EncodingLatin1 // ISO-8859-1
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// String returns the conventional name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case EncodingUTF8:
+		return "utf-8"
+	case EncodingUTF16LE:
+		return "utf-16le"
+	case EncodingUTF16BE:
+		return "utf-16be"
+	case EncodingLatin1:
+		return "iso-8859-1"
+	default:
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+}
+
+// ReadTextOptions configures ReadTextWithOptions.
+type ReadTextOptions struct {
+	// StripBOM removes a leading UTF-8 byte order mark, which many Windows tools write.
+	StripBOM bool
+}
+
+// ReadTextWithOptions reads the content of a file and returns it as a string.
+//
+// Example:
+//
+//	content, err := ReadTextWithOptions("output.csv", ReadTextOptions{StripBOM: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadTextWithOptions(path string, opts ReadTextOptions) (string, error) {
+	content, err := ReadBytes(path)
+	if err != nil {
+		return "", fmt.Errorf("ReadText failed to read file: %w", err)
+	}
+	if opts.StripBOM {
+		content = bytes.TrimPrefix(content, bomUTF8)
+	}
+
+	return string(content), nil
+}
+
+// DetectEncoding guesses the encoding of a text file from its first 8000 bytes.
+// A byte order mark decides the encoding if present. Otherwise, UTF-16 is recognized by
+// the NUL bytes of ASCII characters, valid UTF-8 is UTF-8, and anything else is Latin-1.
+//
+// Example:
+//
+//	encoding, err := DetectEncoding("report.txt")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	content, err := ReadTextWithEncoding("report.txt", encoding)
+func DetectEncoding(path string) (Encoding, error) {
+	head, err := readHead(path, binarySniffLength)
+	if err != nil {
+		return 0, fmt.Errorf("DetectEncoding failed to read file: %w", err)
+	}
+
+	return detectEncoding(head), nil
+}
+
+// ReadTextWithEncoding reads a file in the given encoding and returns it as a UTF-8 string.
+// A byte order mark at the start is removed. Invalid sequences become U+FFFD.
+func ReadTextWithEncoding(path string, encoding Encoding) (string, error) {
+	content, err := ReadBytes(path)
+	if err != nil {
+		return "", fmt.Errorf("ReadTextWithEncoding failed to read file: %w", err)
+	}
+
+	text, err := decodeText(content, encoding)
+	if err != nil {
+		return "", fmt.Errorf("ReadTextWithEncoding failed to decode %s: %w", path, err)
+	}
+
+	return text, nil
+}
+
+// detectEncoding guesses the encoding of a sample of text.
+func detectEncoding(sample []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		return EncodingUTF8
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	// ASCII characters in UTF-16 have a NUL high byte, so NULs cluster on one side
+	var evenNuls, oddNuls int
+	for i, b := range sample {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenNuls++
+		} else {
+			oddNuls++
+		}
+	}
+	pairs := len(sample) / 2
+	switch {
+	case pairs > 0 && oddNuls > pairs/2 && evenNuls <= pairs/10:
+		return EncodingUTF16LE
+	case pairs > 0 && evenNuls > pairs/2 && oddNuls <= pairs/10:
+		return EncodingUTF16BE
+	}
+
+	if validUTF8Sample(sample) {
+		return EncodingUTF8
+	}
+
+	return EncodingLatin1
+}
+
+// validUTF8Sample reports whether a sample is valid UTF-8, allowing the sample to end
+// in the middle of a multi-byte rune.
+func validUTF8Sample(sample []byte) bool {
+	for len(sample) > 0 {
+		r, size := utf8.DecodeRune(sample)
+		if r == utf8.RuneError && size <= 1 {
+			return !utf8.FullRune(sample)
+		}
+		sample = sample[size:]
+	}
+
+	return true
+}
+
+// decodeText converts content in the given encoding to a UTF-8 string without a byte order mark.
+func decodeText(content []byte, encoding Encoding) (string, error) {
+	switch encoding {
+	case EncodingUTF8:
+		return strings.ToValidUTF8(string(bytes.TrimPrefix(content, bomUTF8)), "�"), nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		bom, order := bomUTF16LE, 0
+		if encoding == EncodingUTF16BE {
+			bom, order = bomUTF16BE, 1
+		}
+		content = bytes.TrimPrefix(content, bom)
+
+		units := make([]uint16, len(content)/2)
+		for i := range units {
+			hi, lo := content[2*i+1-order], content[2*i+order]
+			units[i] = uint16(hi)<<8 | uint16(lo)
+		}
+		text := string(utf16.Decode(units))
+		if len(content)%2 != 0 {
+			text += "�"
+		}
+		return text, nil
+	case EncodingLatin1:
+		// Latin-1 bytes are the first 256 code points
+		runes := make([]rune, len(content))
+		for i, b := range content {
+			runes[i] = rune(b)
+		}
+		return string(runes), nil
+	default:
+		return "", fmt.Errorf("unsupported encoding %s", encoding)
+	}
+}
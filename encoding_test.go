@@ -0,0 +1,102 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Text encodings of "hé!" used by the tests below
+var encodingSamples = map[string]string{
+	"utf8":        "h\xc3\xa9!",
+	"utf8-bom":    "\xef\xbb\xbfh\xc3\xa9!",
+	"utf16le":     "h\x00\xe9\x00!\x00",
+	"utf16le-bom": "\xff\xfeh\x00\xe9\x00!\x00",
+	"utf16be-bom": "\xfe\xff\x00h\x00\xe9\x00!",
+	"latin1":      "h\xe9!",
+}
+
+func TestDetectEncoding(t *testing.T) {
+	// Expect byte order marks and content heuristics to identify the encoding
+	t.Run("detect", func(t *testing.T) {
+		path := "detect_encoding_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, encodingSamples)
+
+		cases := map[string]Encoding{
+			"utf8":        EncodingUTF8,
+			"utf8-bom":    EncodingUTF8,
+			"utf16le":     EncodingUTF16LE,
+			"utf16le-bom": EncodingUTF16LE,
+			"utf16be-bom": EncodingUTF16BE,
+			"latin1":      EncodingLatin1,
+		}
+		for name, expected := range cases {
+			encoding, err := DetectEncoding(filepath.Join(path, name))
+			if err != nil {
+				t.Errorf("DetectEncoding failed: %v", err)
+			}
+
+			if encoding != expected {
+				t.Errorf("Expected %s to be %s, got %s", name, expected, encoding)
+			}
+		}
+	})
+}
+
+func TestReadTextWithEncoding(t *testing.T) {
+	// Expect every encoding to decode to the same text without a byte order mark
+	t.Run("decode", func(t *testing.T) {
+		path := "read_text_with_encoding_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, encodingSamples)
+
+		for name := range encodingSamples {
+			encoding, err := DetectEncoding(filepath.Join(path, name))
+			if err != nil {
+				t.Fatalf("DetectEncoding failed: %v", err)
+			}
+
+			text, err := ReadTextWithEncoding(filepath.Join(path, name), encoding)
+			if err != nil {
+				t.Errorf("ReadTextWithEncoding failed: %v", err)
+			}
+
+			if text != "hé!" {
+				t.Errorf("Expected %s to decode to %q, got %q", name, "hé!", text)
+			}
+		}
+	})
+}
+
+func TestReadTextWithOptions(t *testing.T) {
+	// Expect the byte order mark to be stripped only when requested
+	t.Run("strip bom", func(t *testing.T) {
+		path := "read_text_with_options_1.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("\xef\xbb\xbfid,name"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		content, err := ReadTextWithOptions(path, ReadTextOptions{StripBOM: true})
+		if err != nil {
+			t.Errorf("ReadTextWithOptions failed: %v", err)
+		}
+
+		if content != "id,name" {
+			t.Errorf("Expected %q, got %q", "id,name", content)
+		}
+
+		content, err = ReadTextWithOptions(path, ReadTextOptions{})
+		if err != nil {
+			t.Errorf("ReadTextWithOptions failed: %v", err)
+		}
+
+		if content != "\ufeffid,name" {
+			t.Errorf("Expected the byte order mark to be kept, got %q", content)
+		}
+	})
+}
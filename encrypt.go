@@ -0,0 +1,112 @@
+package fs_go
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"os"
+)
+
+// encryptedMagic starts every encrypted file, so the format can change later and
+// plaintext files are rejected early.
+var encryptedMagic = []byte("FSGOENC1")
+
+// WriteEncrypted encrypts content with AES-GCM and writes it atomically with mode 0600.
+// The key must be 16, 24 or 32 bytes, for AES-128, AES-192 or AES-256. A random nonce is
+// generated for every write, so the same key can be reused safely.
+//
+// Example:
+//
+//	key, err := hex.DecodeString(os.Getenv("SECRETS_KEY"))
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = WriteEncrypted("secrets.enc", []byte(token), key)
+func WriteEncrypted(path string, content, key []byte) error {
+	path = normalizePath(path)
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return pathError("WriteEncrypted", path, fmt.Errorf("WriteEncrypted failed: %w", err))
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return pathError("WriteEncrypted", path, fmt.Errorf("WriteEncrypted failed to generate nonce: %w", err))
+	}
+
+	sealed := make([]byte, 0, len(encryptedMagic)+len(nonce)+len(content)+aead.Overhead())
+	sealed = append(sealed, encryptedMagic...)
+	sealed = append(sealed, nonce...)
+	sealed = aead.Seal(sealed, nonce, content, encryptedMagic)
+
+	err = writeAtomic(path, sealed, 0600, false)
+	if err != nil {
+		return pathError("WriteEncrypted", path, fmt.Errorf("WriteEncrypted failed to write file: %w", err))
+	}
+
+	return nil
+}
+
+// WriteEncryptedText encrypts a string and writes it. See WriteEncrypted.
+func WriteEncryptedText(path, content string, key []byte) error {
+	return WriteEncrypted(path, []byte(content), key)
+}
+
+// ReadEncrypted reads a file written by WriteEncrypted and decrypts it. It returns an
+// error wrapping ErrDecrypt if the key is wrong or the file was modified.
+//
+// Example:
+//
+//	token, err := ReadEncrypted("secrets.enc", key)
+//	if errors.Is(err, ErrDecrypt) {
+//	    fmt.Println("wrong key")
+//	    return
+//	}
+func ReadEncrypted(path string, key []byte) ([]byte, error) {
+	path = normalizePath(path)
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, pathError("ReadEncrypted", path, fmt.Errorf("ReadEncrypted failed: %w", err))
+	}
+
+	sealed, err := os.ReadFile(path)
+	observe("ReadEncrypted", int64(len(sealed)), 0, err)
+	if err != nil {
+		return nil, pathError("ReadEncrypted", path, fmt.Errorf("ReadEncrypted failed to read file: %w", err))
+	}
+
+	header := len(encryptedMagic) + aead.NonceSize()
+	if len(sealed) < header+aead.Overhead() || !bytes.HasPrefix(sealed, encryptedMagic) {
+		return nil, pathError("ReadEncrypted", path, fmt.Errorf("ReadEncrypted failed: not an encrypted file: %w", ErrDecrypt))
+	}
+
+	nonce := sealed[len(encryptedMagic):header]
+	content, err := aead.Open(nil, nonce, sealed[header:], encryptedMagic)
+	if err != nil {
+		return nil, pathError("ReadEncrypted", path, fmt.Errorf("ReadEncrypted failed: %w", ErrDecrypt))
+	}
+
+	return content, nil
+}
+
+// ReadEncryptedText reads and decrypts a file as a string. See ReadEncrypted.
+func ReadEncryptedText(path string, key []byte) (string, error) {
+	content, err := ReadEncrypted(path, key)
+	return string(content), err
+}
+
+// newGCM returns AES-GCM for a key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,73 @@
+package fs_go
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestEncrypted(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	// Expect content to round-trip and not be stored in plaintext
+	t.Run("round trip", func(t *testing.T) {
+		path := "encrypted_1"
+		defer os.Remove(path)
+
+		err := WriteEncryptedText(path, "secret token", key)
+		if err != nil {
+			t.Fatalf("WriteEncryptedText failed: %v", err)
+		}
+
+		raw, _ := os.ReadFile(path)
+		if bytes.Contains(raw, []byte("secret token")) {
+			t.Error("Expected content to be encrypted on disk")
+		}
+		if info, _ := os.Stat(path); runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+		}
+
+		content, err := ReadEncryptedText(path, key)
+		if err != nil || content != "secret token" {
+			t.Errorf("Expected secret token, got %q (%v)", content, err)
+		}
+	})
+
+	// Expect a wrong key, modified content or a plaintext file to fail with ErrDecrypt
+	t.Run("decrypt failures", func(t *testing.T) {
+		path := "encrypted_2"
+		defer os.Remove(path)
+
+		WriteEncrypted(path, []byte("secret"), key)
+		if _, err := ReadEncrypted(path, bytes.Repeat([]byte{2}, 32)); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("Expected ErrDecrypt for a wrong key, got %v", err)
+		}
+
+		raw, _ := os.ReadFile(path)
+		raw[len(raw)-1] ^= 1
+		os.WriteFile(path, raw, 0600)
+		if _, err := ReadEncrypted(path, key); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("Expected ErrDecrypt for modified content, got %v", err)
+		}
+
+		os.WriteFile(path, []byte("plain"), 0600)
+		if _, err := ReadEncrypted(path, key); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("Expected ErrDecrypt for a plaintext file, got %v", err)
+		}
+	})
+
+	// Expect keys of invalid length to be rejected
+	t.Run("invalid key", func(t *testing.T) {
+		path := "encrypted_3"
+		defer os.Remove(path)
+
+		if err := WriteEncrypted(path, []byte("secret"), []byte("short")); err == nil {
+			t.Error("Expected an error for an invalid key")
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected no file to be written, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// envKey matches valid variable names in .env files.
+var envKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// envSafeValue matches values that can be written without quotes.
+var envSafeValue = regexp.MustCompile(`^[A-Za-z0-9_./:@,+=-]*$`)
+
+// ReadEnv reads a dotenv file into a map.
+//
+// Lines have the form KEY=VALUE, optionally prefixed with "export". Blank lines and lines
+// starting with # are ignored. Unquoted values are trimmed and end at " #". Single-quoted
+// values are taken literally, while double-quoted values support the escapes \n, \r, \t,
+// \", \\ and \$. Quoted values may span multiple lines. Variables are not expanded.
+//
+// Example:
+//
+//	env, err := ReadEnv(".env")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(env["DATABASE_URL"])
+func ReadEnv(path string) (map[string]string, error) {
+	content, err := ReadText(path)
+	if err != nil {
+		return nil, fmt.Errorf("ReadEnv failed to read file: %w", err)
+	}
+
+	env, err := parseEnv(content)
+	if err != nil {
+		return nil, fmt.Errorf("ReadEnv failed to parse %s: %w", path, err)
+	}
+
+	return env, nil
+}
+
+// LoadEnv reads a dotenv file and sets its variables in the environment of the process.
+// Variables that are already set are kept, so the real environment overrides the file.
+func LoadEnv(path string) error {
+	env, err := ReadEnv(path)
+	if err != nil {
+		return fmt.Errorf("LoadEnv failed to read file: %w", err)
+	}
+
+	for key, value := range env {
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		err = os.Setenv(key, value)
+		if err != nil {
+			return fmt.Errorf("LoadEnv failed to set %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// WriteEnv writes variables to a dotenv file that ReadEnv reads back unchanged.
+// Keys are sorted, and values are double-quoted when they contain anything other than
+// letters, digits and _./:@,+=-. The file is created with mode 0600, since dotenv files
+// usually hold secrets.
+func WriteEnv(path string, env map[string]string) error {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		if !envKey.MatchString(key) {
+			return fmt.Errorf("WriteEnv failed: invalid variable name %q", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(quoteEnvValue(env[key]))
+		b.WriteByte('\n')
+	}
+
+	err := WriteBytesWithMode(path, []byte(b.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("WriteEnv failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// quoteEnvValue quotes a value for a dotenv file if needed.
+func quoteEnvValue(value string) string {
+	if envSafeValue.MatchString(value) {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// parseEnv parses the content of a dotenv file.
+func parseEnv(content string) (map[string]string, error) {
+	env := make(map[string]string)
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	line := 1
+	for content != "" {
+		var current string
+		current, content, _ = strings.Cut(content, "\n")
+		start := line
+		line++
+
+		trimmed := strings.TrimSpace(current)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(trimmed, "export "); ok {
+			trimmed = strings.TrimSpace(rest)
+		}
+
+		key, value, ok := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envKey.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", start)
+		}
+		value = strings.TrimLeft(value, " \t")
+
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = value[:i]
+			}
+			env[key] = strings.TrimSpace(value)
+			continue
+		}
+
+		// Quoted values may continue on the following lines
+		quote := value[0]
+		value = value[1:]
+		for {
+			parsed, rest, closed := parseEnvQuoted(value, quote)
+			if closed {
+				rest = strings.TrimSpace(rest)
+				if rest != "" && !strings.HasPrefix(rest, "#") {
+					return nil, fmt.Errorf("line %d: unexpected %q after closing quote", line-1, rest)
+				}
+				env[key] = parsed
+				break
+			}
+			if content == "" {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", start)
+			}
+
+			var next string
+			next, content, _ = strings.Cut(content, "\n")
+			value += "\n" + next
+			line++
+		}
+	}
+
+	return env, nil
+}
+
+// parseEnvQuoted parses a quoted value after its opening quote. It returns the value and
+// what follows the closing quote, or false if the closing quote is missing.
+func parseEnvQuoted(value string, quote byte) (string, string, bool) {
+	if quote == '\'' {
+		i := strings.IndexByte(value, '\'')
+		if i < 0 {
+			return "", "", false
+		}
+		return value[:i], value[i+1:], true
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"':
+			return b.String(), value[i+1:], true
+		case c == '\\' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(value[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(value[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,127 @@
+package fs_go
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReadEnv(t *testing.T) {
+	// Expect comments, exports, quoting and multi-line values to be handled
+	t.Run("parse", func(t *testing.T) {
+		path := "read_env_1.env"
+		defer os.Remove(path)
+
+		content := "# comment\n" +
+			"export PLAIN=value # trailing\n" +
+			"SPACED = spaced value \n" +
+			"SINGLE='literal \\n $HOME'\n" +
+			"DOUBLE=\"escaped\\n\\\"quote\\\"\"\n" +
+			"MULTI=\"first\r\nsecond\"\n" +
+			"EMPTY=\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		env, err := ReadEnv(path)
+		if err != nil {
+			t.Errorf("ReadEnv failed: %v", err)
+		}
+
+		expected := map[string]string{
+			"PLAIN":  "value",
+			"SPACED": "spaced value",
+			"SINGLE": "literal \\n $HOME",
+			"DOUBLE": "escaped\n\"quote\"",
+			"MULTI":  "first\nsecond",
+			"EMPTY":  "",
+		}
+		if !reflect.DeepEqual(env, expected) {
+			t.Errorf("Expected %v, got %v", expected, env)
+		}
+	})
+
+	// Expect malformed lines to be reported
+	t.Run("invalid", func(t *testing.T) {
+		path := "read_env_2.env"
+		defer os.Remove(path)
+
+		for _, content := range []string{"NO_EQUALS\n", "1KEY=value\n", "KEY=\"unterminated\n", "KEY=\"a\" b\n"} {
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("os.WriteFile failed: %v", err)
+			}
+
+			_, err := ReadEnv(path)
+			if err == nil {
+				t.Errorf("Expected ReadEnv to fail for %q", content)
+			}
+		}
+	})
+}
+
+func TestLoadEnv(t *testing.T) {
+	// Expect variables to be set without overriding existing ones
+	t.Run("load", func(t *testing.T) {
+		path := "load_env_1.env"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("FS_GO_NEW=file\nFS_GO_EXISTING=file\n"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		t.Setenv("FS_GO_EXISTING", "env")
+		t.Setenv("FS_GO_NEW", "")
+		os.Unsetenv("FS_GO_NEW")
+
+		err := LoadEnv(path)
+		if err != nil {
+			t.Errorf("LoadEnv failed: %v", err)
+		}
+
+		if os.Getenv("FS_GO_NEW") != "file" {
+			t.Errorf("Expected FS_GO_NEW to be set from the file, got %q", os.Getenv("FS_GO_NEW"))
+		}
+
+		if os.Getenv("FS_GO_EXISTING") != "env" {
+			t.Errorf("Expected FS_GO_EXISTING to be kept, got %q", os.Getenv("FS_GO_EXISTING"))
+		}
+	})
+}
+
+func TestWriteEnv(t *testing.T) {
+	// Expect written variables to be read back unchanged
+	t.Run("round trip", func(t *testing.T) {
+		path := "write_env_1.env"
+		defer os.Remove(path)
+
+		env := map[string]string{
+			"URL":     "postgres://user@host:5432/db",
+			"MESSAGE": "hello \"world\" # not a comment",
+			"MULTI":   "a\nb\t$HOME\\",
+			"EMPTY":   "",
+		}
+		err := WriteEnv(path, env)
+		if err != nil {
+			t.Errorf("WriteEnv failed: %v", err)
+		}
+
+		read, err := ReadEnv(path)
+		if err != nil {
+			t.Errorf("ReadEnv failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(read, env) {
+			t.Errorf("Expected %v, got %v", env, read)
+		}
+	})
+
+	// Expect invalid names to be rejected
+	t.Run("invalid name", func(t *testing.T) {
+		path := "write_env_2.env"
+		defer os.Remove(path)
+
+		err := WriteEnv(path, map[string]string{"NOT VALID": "a"})
+		if err == nil {
+			t.Errorf("Expected WriteEnv to fail for an invalid name")
+		}
+	})
+}
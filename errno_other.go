@@ -0,0 +1,11 @@
+//go:build !plan9
+
+package fs_go
+
+import "syscall"
+
+// errNoSpace is the error of a write to a full file system.
+var errNoSpace error = syscall.ENOSPC
+
+// errNotEmpty is the error of removing a directory that isn't empty.
+var errNotEmpty error = syscall.ENOTEMPTY
@@ -0,0 +1,10 @@
+package fs_go
+
+import "errors"
+
+// errNoSpace is the error of a write to a full file system. Plan 9 reports errors as
+// strings rather than numbers, so there is no errno to match.
+var errNoSpace error = ErrNoSpace
+
+// errNotEmpty is the error of removing a directory that isn't empty.
+var errNotEmpty = errors.New("directory not empty")
@@ -0,0 +1,261 @@
+package fs_go
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+	"syscall"
+)
+
+// ErrorFS wraps an FS and injects failures, so error handling can be tested deterministically.
+// Failures are configured with FailWrite, FailPath and ShortReads. It is safe for concurrent use.
+//
+// Example:
+//
+//	fsys := NewErrorFS(DirFS(t.TempDir())).FailWrite(2, nil)
+//	err := Export(fsys)
+//	if !errors.Is(err, syscall.ENOSPC) {
+//	    t.Errorf("Expected Export to report a full disk, got %v", err)
+//	}
+type ErrorFS struct {
+	fsys FS
+
+	mu        sync.Mutex
+	writes    int
+	failWrite map[int]error
+	failPaths []pathFailure
+	shortRead int
+}
+
+// pathFailure is an error injected for names matching a pattern.
+type pathFailure struct {
+	pattern string
+	err     error
+}
+
+// NewErrorFS returns an ErrorFS that passes every operation through to fsys until
+// failures are configured.
+func NewErrorFS(fsys FS) *ErrorFS {
+	return &ErrorFS{fsys: fsys, failWrite: make(map[int]error)}
+}
+
+// FailWrite makes the nth write fail with err, counting from 1. Every WriteFile call and
+// every Write on a file from Create counts as one write. A nil err means syscall.ENOSPC,
+// or ErrNoSpace on Plan 9.
+func (e *ErrorFS) FailWrite(n int, err error) *ErrorFS {
+	if err == nil {
+		err = errNoSpace
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failWrite[n] = err
+	return e
+}
+
+// FailPath makes every operation on a name matching pattern fail with err.
+// Patterns use path.Match syntax, such as "secrets/*". A nil err means syscall.EACCES,
+// which matches fs.ErrPermission.
+func (e *ErrorFS) FailPath(pattern string, err error) *ErrorFS {
+	if err == nil {
+		err = syscall.EACCES
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failPaths = append(e.failPaths, pathFailure{pattern: pattern, err: err})
+	return e
+}
+
+// ShortReads limits every Read on files from Open to at most n bytes, like a slow pipe
+// or network file system would. Zero removes the limit.
+func (e *ErrorFS) ShortReads(n int) *ErrorFS {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.shortRead = n
+	return e
+}
+
+// Writes returns the number of writes seen so far, including failed ones.
+func (e *ErrorFS) Writes() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.writes
+}
+
+// checkPath returns the injected error for a name, if any.
+func (e *ErrorFS) checkPath(op, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, failure := range e.failPaths {
+		if ok, _ := path.Match(failure.pattern, name); ok {
+			return &fs.PathError{Op: op, Path: name, Err: failure.err}
+		}
+	}
+
+	return nil
+}
+
+// checkWrite counts a write and returns the injected error for it, if any.
+func (e *ErrorFS) checkWrite(op, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.writes++
+	if err, ok := e.failWrite[e.writes]; ok {
+		return &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	return nil
+}
+
+func (e *ErrorFS) Open(name string) (fs.File, error) {
+	if err := e.checkPath("open", name); err != nil {
+		return nil, err
+	}
+
+	file, err := e.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.shortRead > 0 {
+		return &shortReadFile{File: file, n: e.shortRead}, nil
+	}
+	return file, nil
+}
+
+func (e *ErrorFS) Stat(name string) (fs.FileInfo, error) {
+	if err := e.checkPath("stat", name); err != nil {
+		return nil, err
+	}
+
+	return e.fsys.Stat(name)
+}
+
+func (e *ErrorFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err := e.checkPath("readdir", name); err != nil {
+		return nil, err
+	}
+
+	return e.fsys.ReadDir(name)
+}
+
+// ReadFile reads the named file through Open, so short reads apply to it too.
+func (e *ErrorFS) ReadFile(name string) ([]byte, error) {
+	file, err := e.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+func (e *ErrorFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := e.checkPath("writefile", name); err != nil {
+		return err
+	}
+	if err := e.checkWrite("writefile", name); err != nil {
+		return err
+	}
+
+	return e.fsys.WriteFile(name, data, perm)
+}
+
+func (e *ErrorFS) Create(name string) (io.WriteCloser, error) {
+	if err := e.checkPath("create", name); err != nil {
+		return nil, err
+	}
+
+	file, err := e.fsys.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &errorWriter{WriteCloser: file, fsys: e, name: name}, nil
+}
+
+func (e *ErrorFS) MkdirAll(name string, perm fs.FileMode) error {
+	if err := e.checkPath("mkdir", name); err != nil {
+		return err
+	}
+
+	return e.fsys.MkdirAll(name, perm)
+}
+
+func (e *ErrorFS) Remove(name string) error {
+	if err := e.checkPath("remove", name); err != nil {
+		return err
+	}
+
+	return e.fsys.Remove(name)
+}
+
+func (e *ErrorFS) RemoveAll(name string) error {
+	if err := e.checkPath("removeall", name); err != nil {
+		return err
+	}
+
+	return e.fsys.RemoveAll(name)
+}
+
+func (e *ErrorFS) Rename(oldname, newname string) error {
+	if err := e.checkPath("rename", oldname); err != nil {
+		return err
+	}
+	if err := e.checkPath("rename", newname); err != nil {
+		return err
+	}
+
+	return e.fsys.Rename(oldname, newname)
+}
+
+// errorWriter counts writes to a file created through an ErrorFS.
+type errorWriter struct {
+	io.WriteCloser
+	fsys *ErrorFS
+	name string
+}
+
+func (w *errorWriter) Write(p []byte) (int, error) {
+	if err := w.fsys.checkWrite("write", w.name); err != nil {
+		return 0, err
+	}
+
+	return w.WriteCloser.Write(p)
+}
+
+// shortReadFile returns at most n bytes per Read.
+type shortReadFile struct {
+	fs.File
+	n int
+}
+
+func (f *shortReadFile) Read(p []byte) (int, error) {
+	if len(p) > f.n {
+		p = p[:f.n]
+	}
+
+	return f.File.Read(p)
+}
+
+// ReadDir passes through to the wrapped file, so directories opened with short reads
+// can still be listed.
+func (f *shortReadFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	dir, ok := f.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Err: syscall.ENOTDIR}
+	}
+
+	return dir.ReadDir(n)
+}
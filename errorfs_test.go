@@ -0,0 +1,91 @@
+package fs_go
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestErrorFS(t *testing.T) {
+	// Expect the nth write to fail with ENOSPC and others to succeed
+	t.Run("write", func(t *testing.T) {
+		path := "error_fs_1"
+		defer os.RemoveAll(path)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+		fsys := NewErrorFS(DirFS(path)).FailWrite(2, nil)
+
+		if err := fsys.WriteFile("a.txt", []byte("a"), 0644); err != nil {
+			t.Errorf("Expected first write to succeed, got %v", err)
+		}
+
+		writer, err := fsys.Create("b.txt")
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		_, err = writer.Write([]byte("b"))
+		if !errors.Is(err, errNoSpace) {
+			t.Errorf("Expected second write to fail with ENOSPC, got %v", err)
+		}
+		_, err = writer.Write([]byte("b"))
+		if err != nil {
+			t.Errorf("Expected third write to succeed, got %v", err)
+		}
+		writer.Close()
+
+		if fsys.Writes() != 3 {
+			t.Errorf("Expected 3 writes, got %d", fsys.Writes())
+		}
+	})
+
+	// Expect operations on matching paths to fail with a permission error
+	t.Run("path", func(t *testing.T) {
+		path := "error_fs_2"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"public/a.txt": "a", "secret/b.txt": "b"})
+		fsys := NewErrorFS(DirFS(path)).FailPath("secret/*", nil)
+
+		_, err := fsys.ReadFile("secret/b.txt")
+		if !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("Expected fs.ErrPermission, got %v", err)
+		}
+
+		var pathErr *fs.PathError
+		if !errors.As(err, &pathErr) || pathErr.Path != "secret/b.txt" {
+			t.Errorf("Expected a *fs.PathError for secret/b.txt, got %v", err)
+		}
+
+		content, err := fsys.ReadFile("public/a.txt")
+		if err != nil || string(content) != "a" {
+			t.Errorf("Expected public/a.txt to be readable, got %q (%v)", content, err)
+		}
+	})
+
+	// Expect reads to be limited but still return the full content
+	t.Run("short reads", func(t *testing.T) {
+		path := "error_fs_3"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"a.txt": "hello world"})
+		fsys := NewErrorFS(DirFS(path)).ShortReads(3)
+
+		file, err := fsys.Open("a.txt")
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		defer file.Close()
+
+		buf := make([]byte, 64)
+		n, err := file.Read(buf)
+		if err != nil || n != 3 {
+			t.Errorf("Expected a 3 byte read, got %d (%v)", n, err)
+		}
+
+		rest, err := io.ReadAll(file)
+		if err != nil || string(buf[:n])+string(rest) != "hello world" {
+			t.Errorf("Expected the full content, got %q (%v)", string(buf[:n])+string(rest), err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"syscall"
+)
+
+var (
+	// ErrNotExist means a file or directory doesn't exist. It is the same error as
+	// fs.ErrNotExist, so errors from the standard library match it too.
+	ErrNotExist = fs.ErrNotExist
+	// ErrExist means a file or directory already exists. It is the same error as fs.ErrExist,
+	// so errors from the standard library match it too.
+	ErrExist = fs.ErrExist
+	// ErrIsDirectory means a file was expected, but a directory was found.
+	ErrIsDirectory = errors.New("is a directory")
+	// ErrNotDirectory means a directory was expected, but something else was found.
+	ErrNotDirectory = errors.New("not a directory")
+	// ErrReadOnly means a file system modification was refused because read-only mode is enabled.
+	ErrReadOnly = errors.New("read-only mode is enabled")
+	// ErrChecksumMismatch means content didn't match its expected checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrDecrypt means content couldn't be decrypted, because the key is wrong or the
+	// content was modified.
+	ErrDecrypt = errors.New("decryption failed")
+	// ErrNoSpace means a file system doesn't have enough free space for an operation.
+	ErrNoSpace = errors.New("not enough free space")
+)
+
+// PathError records the operation and path that caused an error.
+// Its message is the message of the wrapped error, which already describes the failure.
+//
+// Example:
+//
+//	err := EnsureFile("logs")
+//	var pathErr *PathError
+//	if errors.As(err, &pathErr) && errors.Is(err, ErrIsDirectory) {
+//	    fmt.Println(pathErr.Path, "is in the way")
+//	}
+type PathError struct {
+	Op   string // Function that failed, such as "CopyFile"
+	Path string // Path the operation failed on
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets errors from the operating system match ErrIsDirectory and ErrNotDirectory.
+func (e *PathError) Is(target error) bool {
+	switch target {
+	case ErrIsDirectory:
+		return errors.Is(e.Err, syscall.EISDIR)
+	case ErrNotDirectory:
+		return errors.Is(e.Err, syscall.ENOTDIR)
+	default:
+		return false
+	}
+}
+
+// MultiError collects the errors of operations that continue past failures, such as a
+// Batch. errors.Is and errors.As match any of the errors.
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d operations failed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// pathError wraps err in a PathError.
+func pathError(op, path string, err error) error {
+	return &PathError{Op: op, Path: path, Err: err}
+}
@@ -0,0 +1,70 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestPathError(t *testing.T) {
+	// Expect missing files to match ErrNotExist and carry the operation and path
+	t.Run("not exist", func(t *testing.T) {
+		path := "path_error_missing.txt"
+
+		_, err := ReadText(path)
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected error to match ErrNotExist, got %v", err)
+		}
+
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) {
+			t.Fatalf("Expected error to be a PathError, got %T", err)
+		}
+
+		if pathErr.Op != "ReadText" || pathErr.Path != path {
+			t.Errorf("Expected ReadText on %s, got %s on %s", path, pathErr.Op, pathErr.Path)
+		}
+	})
+
+	// Expect a directory in place of a file to match ErrIsDirectory
+	t.Run("is directory", func(t *testing.T) {
+		path := "path_error_dir"
+		defer os.RemoveAll(path)
+
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+
+		err := EnsureFile(path)
+		if !errors.Is(err, ErrIsDirectory) {
+			t.Errorf("Expected error to match ErrIsDirectory, got %v", err)
+		}
+	})
+
+	// Expect a file in place of a directory to match ErrNotDirectory
+	t.Run("not directory", func(t *testing.T) {
+		path := "path_error_file.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := EnsureDir(path)
+		if !errors.Is(err, ErrNotDirectory) {
+			t.Errorf("Expected error to match ErrNotDirectory, got %v", err)
+		}
+
+		if errors.Is(err, ErrIsDirectory) {
+			t.Errorf("Expected error to not match ErrIsDirectory")
+		}
+	})
+
+	// Expect the message to stay the same as the wrapped error
+	t.Run("message", func(t *testing.T) {
+		err := pathError("Op", "path", errors.New("Op failed"))
+		if err.Error() != "Op failed" {
+			t.Errorf("Expected message to be %q, got %q", "Op failed", err.Error())
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// FindExecutables returns every executable named name in the directories of $PATH, in
+// order of precedence, so the first one is what a shell would run. On Windows, the
+// extensions in %PATHEXT% are tried. Relative directories in $PATH are skipped, as by
+// exec.LookPath. It returns an empty slice if there is no match.
+//
+// Example:
+//
+//	pythons, err := FindExecutables("python3")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, path := range pythons {
+//	    fmt.Println(path)
+//	}
+func FindExecutables(name string) ([]string, error) {
+	if name == "" {
+		return nil, fmt.Errorf("FindExecutables failed: name is empty")
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("FindExecutables failed: %q is a path, not a name", name)
+	}
+
+	matches := []string{}
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+
+		for _, candidate := range executableCandidates(name, runtime.GOOS) {
+			path := filepath.Join(dir, candidate)
+			if seen[path] {
+				continue
+			}
+			ok, err := IsExecutable(path)
+			if err != nil || !ok {
+				continue
+			}
+			seen[path] = true
+			matches = append(matches, path)
+		}
+	}
+
+	return matches, nil
+}
+
+// IsExecutable reports whether path is a regular file that can be executed, following
+// symlinks. On Unix any execute bit counts, and on Windows its extension must be in
+// %PATHEXT%. A missing file is not executable and not an error.
+func IsExecutable(path string) (bool, error) {
+	info, err := os.Stat(normalizePath(path))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("IsExecutable failed to get file stat: %w", err)
+	}
+
+	return info.Mode().IsRegular() && isExecutable(path, info.Mode(), runtime.GOOS), nil
+}
+
+// MakeExecutable makes a file executable, like chmod +x. Execute bits are added for
+// everyone who can read the file. On Windows, where executability is decided by the
+// extension, it only checks that the file exists.
+//
+// Example:
+//
+//	err := WriteText("bin/run.sh", script)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = MakeExecutable("bin/run.sh")
+func MakeExecutable(path string) error {
+	path = normalizePath(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return pathError("MakeExecutable", path, fmt.Errorf("MakeExecutable failed to get file stat: %w", err))
+	}
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	mode := info.Mode() & metaModeMask
+	executable := mode | (mode&0444)>>2
+	if executable == mode {
+		return nil
+	}
+	if skip, err := writeGuard("MakeExecutable", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err = os.Chmod(path, executable)
+	if err != nil {
+		return pathError("MakeExecutable", path, fmt.Errorf("MakeExecutable failed to change mode: %w", err))
+	}
+
+	return nil
+}
+
+// executableCandidates returns the file names to try for a command name on the given GOOS.
+func executableCandidates(name, goos string) []string {
+	if goos != "windows" {
+		return []string{name}
+	}
+
+	extensions := windowsExecutableExtensions()
+	for _, ext := range extensions {
+		if strings.EqualFold(filepath.Ext(name), ext) {
+			return []string{name}
+		}
+	}
+
+	candidates := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		candidates = append(candidates, name+ext)
+	}
+	return candidates
+}
+
+// isExecutable reports whether a file with the given mode is executable on the given GOOS.
+func isExecutable(path string, mode os.FileMode, goos string) bool {
+	if goos != "windows" {
+		return mode&0111 != 0
+	}
+
+	for _, ext := range windowsExecutableExtensions() {
+		if strings.EqualFold(filepath.Ext(path), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowsExecutableExtensions returns the extensions in %PATHEXT%, or the default ones.
+func windowsExecutableExtensions() []string {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		return []string{".com", ".exe", ".bat", ".cmd"}
+	}
+
+	var extensions []string
+	for _, ext := range strings.Split(strings.ToLower(pathext), ";") {
+		if ext != "" && ext[0] == '.' {
+			extensions = append(extensions, ext)
+		}
+	}
+	return extensions
+}
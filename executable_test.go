@@ -0,0 +1,98 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFindExecutables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Executability is decided by extension on Windows")
+	}
+
+	// Expect every executable match on PATH, in order, skipping non-executables
+	t.Run("matches", func(t *testing.T) {
+		root, err := filepath.Abs("find_executables_1")
+		if err != nil {
+			t.Fatalf("Abs failed: %v", err)
+		}
+		defer os.RemoveAll(root)
+		for _, dir := range []string{"a", "b", "c"} {
+			os.MkdirAll(filepath.Join(root, dir), 0755)
+		}
+		os.WriteFile(filepath.Join(root, "a", "tool"), nil, 0755)
+		os.WriteFile(filepath.Join(root, "b", "tool"), nil, 0644)
+		os.WriteFile(filepath.Join(root, "c", "tool"), nil, 0755)
+		t.Setenv("PATH", filepath.Join(root, "a")+string(os.PathListSeparator)+
+			"relative"+string(os.PathListSeparator)+
+			filepath.Join(root, "b")+string(os.PathListSeparator)+
+			filepath.Join(root, "c"))
+
+		matches, err := FindExecutables("tool")
+		if err != nil {
+			t.Fatalf("FindExecutables failed: %v", err)
+		}
+		expected := []string{filepath.Join(root, "a", "tool"), filepath.Join(root, "c", "tool")}
+		if len(matches) != 2 || matches[0] != expected[0] || matches[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, matches)
+		}
+	})
+
+	// Expect paths to be rejected
+	t.Run("path", func(t *testing.T) {
+		if _, err := FindExecutables("bin/tool"); err == nil {
+			t.Error("Expected an error for a path")
+		}
+	})
+}
+
+func TestMakeExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Executability is decided by extension on Windows")
+	}
+
+	// Expect execute bits to be added where the file is readable
+	t.Run("chmod", func(t *testing.T) {
+		path := "make_executable_1"
+		defer os.Remove(path)
+		os.WriteFile(path, nil, 0640)
+		os.Chmod(path, 0640)
+
+		if ok, _ := IsExecutable(path); ok {
+			t.Error("Expected file not to be executable yet")
+		}
+		err := MakeExecutable(path)
+		if err != nil {
+			t.Fatalf("MakeExecutable failed: %v", err)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0750 {
+			t.Errorf("Expected mode 0750, got %v", info.Mode().Perm())
+		}
+		if ok, err := IsExecutable(path); !ok || err != nil {
+			t.Errorf("Expected file to be executable, got %v (%v)", ok, err)
+		}
+	})
+
+	// Expect a missing file to fail
+	t.Run("missing", func(t *testing.T) {
+		if err := MakeExecutable("make_executable_2"); err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+	})
+}
+
+func TestExecutableCandidates(t *testing.T) {
+	// Expect PATHEXT extensions to be tried on Windows, unless the name has one
+	t.Run("windows", func(t *testing.T) {
+		t.Setenv("PATHEXT", ".EXE;.CMD")
+		candidates := executableCandidates("tool", "windows")
+		if len(candidates) != 2 || candidates[0] != "tool.exe" || candidates[1] != "tool.cmd" {
+			t.Errorf("Unexpected candidates: %v", candidates)
+		}
+		if candidates := executableCandidates("tool.EXE", "windows"); len(candidates) != 1 {
+			t.Errorf("Expected the name as is, got %v", candidates)
+		}
+	})
+}
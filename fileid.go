@@ -0,0 +1,92 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileIdentity identifies a file on a machine, independent of the path used to reach it.
+// Hard links to the same file share an identity.
+type FileIdentity struct {
+	Device uint64 // Device on Unix, volume serial number on Windows
+	Inode  uint64 // Inode on Unix, file index on Windows
+}
+
+// FileID returns the identity of a file or directory, following symlinks.
+//
+// Example:
+//
+//	seen := map[FileIdentity]bool{}
+//	for _, path := range paths {
+//	    id, err := FileID(path)
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	    if seen[id] {
+//	        fmt.Println(path, "is a hard link to a file seen before")
+//	    }
+//	    seen[id] = true
+//	}
+func FileID(path string) (FileIdentity, error) {
+	path = normalizePath(path)
+
+	id, err := fileID(path)
+	if err != nil {
+		return FileIdentity{}, pathError("FileID", path, fmt.Errorf("FileID failed to identify file: %w", err))
+	}
+
+	return id, nil
+}
+
+// SameFile reports whether two paths refer to the same file or directory, through hard
+// links, symlinks or different spellings of the path. Both must exist.
+func SameFile(a, b string) (bool, error) {
+	a, b = normalizePath(a), normalizePath(b)
+
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, pathError("SameFile", a, fmt.Errorf("SameFile failed to get file stat: %w", err))
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, pathError("SameFile", b, fmt.Errorf("SameFile failed to get file stat: %w", err))
+	}
+
+	return os.SameFile(infoA, infoB), nil
+}
+
+// IsOnSameFilesystem reports whether two paths are on the same file system, so a file can
+// be renamed from one to the other instead of copied. Paths that don't exist yet, like the
+// destination of a move, are judged by their closest existing parent directory.
+func IsOnSameFilesystem(a, b string) (bool, error) {
+	a, b = normalizePath(a), normalizePath(b)
+
+	idA, err := fileID(closestExisting(a))
+	if err != nil {
+		return false, pathError("IsOnSameFilesystem", a, fmt.Errorf("IsOnSameFilesystem failed to identify file: %w", err))
+	}
+	idB, err := fileID(closestExisting(b))
+	if err != nil {
+		return false, pathError("IsOnSameFilesystem", b, fmt.Errorf("IsOnSameFilesystem failed to identify file: %w", err))
+	}
+
+	return idA.Device == idB.Device, nil
+}
+
+// closestExisting returns path if it exists, or otherwise its closest existing parent.
+func closestExisting(path string) string {
+	for {
+		_, err := os.Stat(path)
+		if err == nil || !os.IsNotExist(err) {
+			return path
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
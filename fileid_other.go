@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package fs_go
+
+import "errors"
+
+func fileID(path string) (FileIdentity, error) {
+	return FileIdentity{}, errors.ErrUnsupported
+}
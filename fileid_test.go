@@ -0,0 +1,69 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFileID(t *testing.T) {
+	path := "file_id"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"a.txt": "a", "b.txt": "a"})
+	a, b := filepath.Join(path, "a.txt"), filepath.Join(path, "b.txt")
+	link := filepath.Join(path, "link.txt")
+	if err := os.Link(a, link); err != nil {
+		t.Fatalf("os.Link failed: %v", err)
+	}
+
+	// Expect hard links to share an identity, and other files not to
+	idA, err := FileID(a)
+	if err != nil {
+		t.Fatalf("FileID failed: %v", err)
+	}
+	idLink, _ := FileID(link)
+	idB, _ := FileID(b)
+	if idA != idLink {
+		t.Errorf("Expected hard links to share an identity, got %+v and %+v", idA, idLink)
+	}
+	if idA == idB {
+		t.Errorf("Expected different files to have different identities, got %+v", idA)
+	}
+
+	// Expect SameFile to see through hard links and path spellings
+	if same, err := SameFile(a, link); err != nil || !same {
+		t.Errorf("Expected the same file, got %v, %v", same, err)
+	}
+	if same, err := SameFile(a, filepath.Join(path, ".", "a.txt")); err != nil || !same {
+		t.Errorf("Expected the same file, got %v, %v", same, err)
+	}
+	if same, err := SameFile(a, b); err != nil || same {
+		t.Errorf("Expected different files, got %v, %v", same, err)
+	}
+}
+
+func TestIsOnSameFilesystem(t *testing.T) {
+	path := "is_on_same_filesystem"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"a.txt": "a"})
+
+	// Expect paths that don't exist yet to be judged by their parents
+	same, err := IsOnSameFilesystem(filepath.Join(path, "a.txt"), filepath.Join(path, "new", "b.txt"))
+	if err != nil || !same {
+		t.Errorf("Expected the same file system, got %v, %v", same, err)
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	if _, err := os.Stat("/proc/self"); err != nil {
+		return
+	}
+
+	// Expect a different file system to be detected
+	same, err = IsOnSameFilesystem(path, "/proc/self")
+	if err != nil || same {
+		t.Errorf("Expected different file systems, got %v, %v", same, err)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func fileID(path string) (FileIdentity, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileIdentity{}, err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return FileIdentity{}, fmt.Errorf("unexpected stat type %T", info.Sys())
+	}
+
+	return FileIdentity{Device: uint64(stat.Dev), Inode: uint64(stat.Ino)}, nil
+}
@@ -0,0 +1,31 @@
+package fs_go
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+func fileID(path string) (FileIdentity, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return FileIdentity{}, err
+	}
+
+	// Backup semantics are required to open directories
+	handle, err := windows.CreateFile(name, 0, windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING, windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return FileIdentity{}, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var data windows.ByHandleFileInformation
+	err = windows.GetFileInformationByHandle(handle, &data)
+	if err != nil {
+		return FileIdentity{}, err
+	}
+
+	return FileIdentity{
+		Device: uint64(data.VolumeSerialNumber),
+		Inode:  uint64(data.FileIndexHigh)<<32 | uint64(data.FileIndexLow),
+	}, nil
+}
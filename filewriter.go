@@ -0,0 +1,204 @@
+package fs_go
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrWriterClosed means a FileWriter was used after it was closed or aborted.
+var ErrWriterClosed = errors.New("file writer already closed")
+
+// FileWriterOptions configures NewFileWriter.
+type FileWriterOptions struct {
+	// Mode is the file mode of the written file. Defaults to 0644.
+	Mode os.FileMode
+	// BufferSize is the size of the write buffer in bytes. Defaults to 64 KiB.
+	BufferSize int
+	// Durable syncs the file and its parent directory to disk on Close.
+	Durable bool
+}
+
+// FileWriter builds a file incrementally through a buffer. The content goes to a temporary
+// file next to the destination, which only replaces it on Close, so readers never observe a
+// partial file and Abort leaves the destination untouched. It is created by NewFileWriter.
+//
+// A FileWriter is not safe for concurrent use.
+type FileWriter struct {
+	path    string
+	file    *os.File // Nil in dry-run mode, where writes are discarded
+	buf     *bufio.Writer
+	opts    FileWriterOptions
+	written int64
+	closed  bool
+}
+
+// NewFileWriter starts writing a file at path. Close or Abort must be called when done.
+//
+// Example:
+//
+//	w, err := NewFileWriter("out/events.ndjson", FileWriterOptions{})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer w.Abort() // No-op after a successful Close
+//	for _, event := range events {
+//	    err = w.WriteJsonLine(event)
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	}
+//	err = w.Close()
+func NewFileWriter(path string, opts FileWriterOptions) (*FileWriter, error) {
+	path = normalizePath(path)
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 64 << 10
+	}
+
+	w := &FileWriter{path: path, opts: opts}
+	if skip, err := writeGuard("NewFileWriter", path, ""); skip {
+		if err != nil {
+			return nil, err
+		}
+		w.buf = bufio.NewWriterSize(io.Discard, opts.BufferSize)
+		return w, nil
+	}
+	defer invalidateStats(path)
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, pathError("NewFileWriter", path, fmt.Errorf("NewFileWriter failed to create temporary file: %w", err))
+	}
+	w.file = file
+	w.buf = bufio.NewWriterSize(file, opts.BufferSize)
+
+	return w, nil
+}
+
+// Write appends p to the file. It implements io.Writer.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, pathError("FileWriter", w.path, fmt.Errorf("Write failed: %w", ErrWriterClosed))
+	}
+
+	n, err := w.buf.Write(p)
+	w.written += int64(n)
+	if err != nil {
+		return n, pathError("FileWriter", w.path, fmt.Errorf("Write failed: %w", err))
+	}
+
+	return n, nil
+}
+
+// WriteString appends s to the file. It implements io.StringWriter.
+func (w *FileWriter) WriteString(s string) (int, error) {
+	if w.closed {
+		return 0, pathError("FileWriter", w.path, fmt.Errorf("WriteString failed: %w", ErrWriterClosed))
+	}
+
+	n, err := w.buf.WriteString(s)
+	w.written += int64(n)
+	if err != nil {
+		return n, pathError("FileWriter", w.path, fmt.Errorf("WriteString failed: %w", err))
+	}
+
+	return n, nil
+}
+
+// WriteJsonLine appends v encoded as JSON, followed by a newline, as in a JSON Lines file.
+func (w *FileWriter) WriteJsonLine(v any) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return pathError("FileWriter", w.path, fmt.Errorf("WriteJsonLine failed to marshal value: %w", err))
+	}
+
+	_, err = w.Write(append(content, '\n'))
+	return err
+}
+
+// Flush writes buffered content to the temporary file. The destination is still only
+// replaced on Close.
+func (w *FileWriter) Flush() error {
+	if w.closed {
+		return pathError("FileWriter", w.path, fmt.Errorf("Flush failed: %w", ErrWriterClosed))
+	}
+
+	err := w.buf.Flush()
+	if err != nil {
+		return pathError("FileWriter", w.path, fmt.Errorf("Flush failed: %w", err))
+	}
+
+	return nil
+}
+
+// Close flushes the remaining content and atomically moves the file into place. If that
+// fails, the temporary file is removed and the destination is left untouched.
+func (w *FileWriter) Close() (err error) {
+	if w.closed {
+		return pathError("FileWriter", w.path, fmt.Errorf("Close failed: %w", ErrWriterClosed))
+	}
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	defer func() { observeWrite("FileWriter", int(w.written), err) }()
+
+	tmp := w.file.Name()
+	err = w.buf.Flush()
+	if err != nil {
+		w.file.Close()
+		os.Remove(tmp)
+		return pathError("FileWriter", w.path, fmt.Errorf("Close failed to flush: %w", err))
+	}
+
+	err = writeAndClose(w.file, nil, w.opts.Mode, w.opts.Durable)
+	if err != nil {
+		os.Remove(tmp)
+		return pathError("FileWriter", w.path, fmt.Errorf("Close failed: %w", err))
+	}
+
+	err = os.Rename(tmp, w.path)
+	invalidateStats(w.path)
+	if err != nil {
+		os.Remove(tmp)
+		return pathError("FileWriter", w.path, fmt.Errorf("Close failed to rename temporary file: %w", err))
+	}
+
+	if w.opts.Durable {
+		err = syncDir(filepath.Dir(w.path))
+		if err != nil {
+			return pathError("FileWriter", w.path, fmt.Errorf("Close failed to sync directory: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// Abort discards everything written and leaves the destination untouched.
+// It does nothing if the writer was already closed, so it can be deferred.
+func (w *FileWriter) Abort() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+
+	w.file.Close()
+	err := os.Remove(w.file.Name())
+	if err != nil {
+		return pathError("FileWriter", w.path, fmt.Errorf("Abort failed to remove temporary file: %w", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileWriter(t *testing.T) {
+	// Expect the file to only appear once closed
+	t.Run("close", func(t *testing.T) {
+		path := "file_writer_1"
+		defer os.RemoveAll(path)
+		os.Mkdir(path, 0755)
+		dst := filepath.Join(path, "events.ndjson")
+
+		w, err := NewFileWriter(dst, FileWriterOptions{BufferSize: 16})
+		if err != nil {
+			t.Fatalf("NewFileWriter failed: %v", err)
+		}
+		w.WriteString("# events\n")
+		w.WriteJsonLine(map[string]int{"id": 1})
+		w.Write([]byte(`{"id":2}` + "\n"))
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("Expected no file before Close, got %v", err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if content, _ := os.ReadFile(dst); string(content) != "# events\n{\"id\":1}\n{\"id\":2}\n" {
+			t.Errorf("Expected the written lines, got %q", content)
+		}
+
+		// Expect writes after Close to fail
+		if _, err := w.WriteString("late"); !errors.Is(err, ErrWriterClosed) {
+			t.Errorf("Expected ErrWriterClosed, got %v", err)
+		}
+		if err := w.Abort(); err != nil {
+			t.Errorf("Expected Abort after Close to do nothing, got %v", err)
+		}
+
+		entries, _ := os.ReadDir(path)
+		if len(entries) != 1 {
+			t.Errorf("Expected no temporary files, got %v", entries)
+		}
+	})
+
+	// Expect Abort to leave the destination untouched
+	t.Run("abort", func(t *testing.T) {
+		path := "file_writer_2"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"out.txt": "old"})
+		dst := filepath.Join(path, "out.txt")
+
+		w, err := NewFileWriter(dst, FileWriterOptions{})
+		if err != nil {
+			t.Fatalf("NewFileWriter failed: %v", err)
+		}
+		w.WriteString("new")
+		if err := w.Abort(); err != nil {
+			t.Fatalf("Abort failed: %v", err)
+		}
+
+		if content, _ := os.ReadFile(dst); string(content) != "old" {
+			t.Errorf("Expected old, got %q", content)
+		}
+		entries, _ := os.ReadDir(path)
+		if len(entries) != 1 {
+			t.Errorf("Expected no temporary files, got %v", entries)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package fs_go
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies a file encoding.
+type Format int
+
+const (
+	FormatJSON Format = iota
+	FormatYAML
+	FormatTOML
+	FormatCSV
+	FormatINI
+)
+
+// String returns the conventional name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatYAML:
+		return "yaml"
+	case FormatTOML:
+		return "toml"
+	case FormatCSV:
+		return "csv"
+	case FormatINI:
+		return "ini"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// FormatFromPath infers the format of a file from its extension.
+// The extension is matched case-insensitively.
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".ini":
+		return FormatINI, nil
+	default:
+		return 0, fmt.Errorf("FormatFromPath failed: unknown file extension %q in %s", ext, path)
+	}
+}
+
+// WriteAuto encodes v and writes it to a file, picking the encoder from the file extension.
+// Supported extensions are .json, .yaml, .yml, .toml and .csv.
+// For .csv files v must be a [][]string. INI files can be read, but not written.
+//
+// Example:
+//
+//	err := WriteAuto("out/config.yaml", config)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteAuto(path string, v any) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return fmt.Errorf("WriteAuto failed to infer format: %w", err)
+	}
+
+	content, err := encodeFormat(format, v)
+	if err != nil {
+		return fmt.Errorf("WriteAuto failed to encode %s: %w", format, err)
+	}
+
+	return WriteBytes(path, content)
+}
+
+// ReadAuto reads a file and decodes it into v, picking the decoder from the file extension.
+// Supported extensions are .json, .yaml, .yml, .toml, .csv and .ini.
+// For .csv files v must be a *[][]string. For .ini files v must be a pointer to a struct,
+// a map[string]map[string]string of sections, or a map[string]string.
+//
+// Example:
+//
+//	var v MyStruct
+//	err := ReadAuto("config.toml", &v)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadAuto(path string, v any) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return fmt.Errorf("ReadAuto failed to infer format: %w", err)
+	}
+
+	content, err := ReadBytes(path)
+	if err != nil {
+		return fmt.Errorf("ReadAuto failed to read file: %w", err)
+	}
+
+	err = decodeFormat(format, content, v)
+	if err != nil {
+		return fmt.Errorf("ReadAuto failed to decode %s: %w", format, err)
+	}
+
+	return nil
+}
+
+// encodeFormat marshals v using the encoder for the given format.
+func encodeFormat(format Format, v any) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.Marshal(v)
+	case FormatYAML:
+		return yaml.Marshal(v)
+	case FormatTOML:
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(v)
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatCSV:
+		records, ok := v.([][]string)
+		if !ok {
+			return nil, fmt.Errorf("csv requires [][]string, got %T", v)
+		}
+		var buf bytes.Buffer
+		err := csv.NewWriter(&buf).WriteAll(records)
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatINI:
+		return nil, fmt.Errorf("writing ini is not supported")
+	default:
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+}
+
+// decodeFormat unmarshals content into v using the decoder for the given format.
+func decodeFormat(format Format, content []byte, v any) error {
+	switch format {
+	case FormatJSON:
+		return json.Unmarshal(content, v)
+	case FormatYAML:
+		return yaml.Unmarshal(content, v)
+	case FormatTOML:
+		return toml.Unmarshal(content, v)
+	case FormatCSV:
+		records, ok := v.(*[][]string)
+		if !ok {
+			return fmt.Errorf("csv requires *[][]string, got %T", v)
+		}
+		parsed, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+		if err != nil {
+			return err
+		}
+		*records = parsed
+		return nil
+	case FormatINI:
+		sections, err := parseIni(string(content))
+		if err != nil {
+			return err
+		}
+		return decodeIni(sections, v)
+	default:
+		return fmt.Errorf("unsupported format %s", format)
+	}
+}
@@ -0,0 +1,106 @@
+package fs_go
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	// Expect formats to be inferred from the extension
+	t.Run("known extensions", func(t *testing.T) {
+		cases := map[string]Format{
+			"a.json": FormatJSON,
+			"a.yaml": FormatYAML,
+			"a.YML":  FormatYAML,
+			"a.toml": FormatTOML,
+			"a.csv":  FormatCSV,
+			"a.ini":  FormatINI,
+		}
+		for path, expected := range cases {
+			format, err := FormatFromPath(path)
+			if err != nil {
+				t.Errorf("FormatFromPath failed: %v", err)
+			}
+
+			if format != expected {
+				t.Errorf("Expected format of %s to be %s, got %s", path, expected, format)
+			}
+		}
+	})
+
+	// Expect an error for unknown extensions
+	t.Run("unknown extension", func(t *testing.T) {
+		_, err := FormatFromPath("a.txt")
+		if err == nil {
+			t.Errorf("Expected FormatFromPath to fail for unknown extension")
+		}
+	})
+}
+
+func TestWriteAuto(t *testing.T) {
+	type config struct {
+		Name  string `json:"name" yaml:"name" toml:"name"`
+		Count int    `json:"count" yaml:"count" toml:"count"`
+	}
+
+	// Expect structs to round-trip through every structured format
+	for _, path := range []string{"write_auto.json", "write_auto.yaml", "write_auto.toml"} {
+		t.Run(path, func(t *testing.T) {
+			defer os.Remove(path)
+
+			in := config{Name: "value", Count: 3}
+			err := WriteAuto(path, in)
+			if err != nil {
+				t.Errorf("WriteAuto failed: %v", err)
+			}
+
+			var out config
+			err = ReadAuto(path, &out)
+			if err != nil {
+				t.Errorf("ReadAuto failed: %v", err)
+			}
+
+			if out != in {
+				t.Errorf("Expected %+v, got %+v", in, out)
+			}
+		})
+	}
+
+	// Expect records to round-trip through csv
+	t.Run("write_auto.csv", func(t *testing.T) {
+		path := "write_auto.csv"
+		defer os.Remove(path)
+
+		in := [][]string{{"name", "count"}, {"value", "3"}}
+		err := WriteAuto(path, in)
+		if err != nil {
+			t.Errorf("WriteAuto failed: %v", err)
+		}
+
+		var out [][]string
+		err = ReadAuto(path, &out)
+		if err != nil {
+			t.Errorf("ReadAuto failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(out, in) {
+			t.Errorf("Expected %v, got %v", in, out)
+		}
+	})
+
+	// Expect an error and no file for unknown extensions
+	t.Run("unknown extension", func(t *testing.T) {
+		path := "write_auto.txt"
+		defer os.Remove(path)
+
+		err := WriteAuto(path, "content")
+		if err == nil {
+			t.Errorf("Expected WriteAuto to fail for unknown extension")
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected file to not be created")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package fs_go
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,29 +16,36 @@ func EnsureFile(path string) error {
 
 // EnsureFileWithMode creates a file if it doesn't exist, with the specified mode.
 func EnsureFileWithMode(path string, mode os.FileMode) error {
+	path = normalizePath(path)
+
 	info, err := os.Stat(path)
 	if err == nil {
 		// Check if it's a directory
 		if info.IsDir() {
-			return fmt.Errorf("EnsureFile failed: %s is a directory", path)
+			return pathError("EnsureFile", path, fmt.Errorf("EnsureFile failed: %s: %w", path, ErrIsDirectory))
 		}
 
 		return nil // File already exists
 	}
 	if !os.IsNotExist(err) {
-		return fmt.Errorf("EnsureFile failed to check file existence: %w", err)
+		return pathError("EnsureFile", path, fmt.Errorf("EnsureFile failed to check file existence: %w", err))
 	}
 
 	// Check if the directory exists
 	dir := filepath.Dir(path)
 	err = EnsureDirWithMode(dir, 0755)
 	if err != nil {
-		return fmt.Errorf("EnsureFile failed to ensure directory: %w", err)
+		return pathError("EnsureFile", path, fmt.Errorf("EnsureFile failed to ensure directory: %w", err))
+	}
+
+	if skip, err := writeGuard("EnsureFile", path, ""); skip {
+		return err
 	}
+	defer invalidateStats(path)
 
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, mode)
 	if err != nil {
-		return fmt.Errorf("EnsureFile failed to create file: %w", err)
+		return pathError("EnsureFile", path, fmt.Errorf("EnsureFile failed to create file: %w", err))
 	}
 
 	defer file.Close()
@@ -45,84 +53,131 @@ func EnsureFileWithMode(path string, mode os.FileMode) error {
 	return nil
 }
 
-// EnsureDir creates a directory if it doesn't exist, with default mode 0755.
+// EnsureDir creates a directory and any missing parents if they don't exist, with default mode 0755.
 func EnsureDir(path string) error {
-	return EnsureDirWithMode(path, 0755|os.ModeDir)
+	return EnsureDirWithMode(path, 0755)
 }
 
-// EnsureDirWithMode creates a directory if it doesn't exist, with the specified mode.
+// EnsureDirWithMode creates a directory and any missing parents if they don't exist, with the
+// specified mode. Missing parents get the same mode, plus owner permissions so the rest of
+// the path can be created inside them. Like os.Mkdir, the mode is subject to the umask.
+// Existing directories are left as they are.
+//
+// Example:
+//
+//	err := EnsureDirWithMode("secrets/keys", 0700)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
 func EnsureDirWithMode(path string, mode os.FileMode) error {
+	return ensureDir(normalizePath(path), mode, mode.Perm()|0700)
+}
+
+// ensureDir creates a directory with mode, creating missing parents with parentMode.
+func ensureDir(path string, mode, parentMode os.FileMode) error {
 	info, err := os.Stat(path)
 	if err == nil {
 		// Check if it's a file
 		if !info.IsDir() {
-			return fmt.Errorf("EnsureDir failed: %s is a file", path)
+			return pathError("EnsureDir", path, fmt.Errorf("EnsureDir failed: %s: %w", path, ErrNotDirectory))
 		}
 
 		return nil // Directory already exists
 	}
 	if !os.IsNotExist(err) {
-		return fmt.Errorf("EnsureDir failed to check directory existence: %w", err)
+		return pathError("EnsureDir", path, fmt.Errorf("EnsureDir failed to check directory existence: %w", err))
 	}
 
 	// Check if the parent directory exists
 	parent := filepath.Dir(path)
-	err = EnsureDirWithMode(parent, 0755)
+	err = ensureDir(parent, parentMode, parentMode)
 	if err != nil {
-		return fmt.Errorf("EnsureDir failed to ensure parent directory: %w", err)
+		return pathError("EnsureDir", path, fmt.Errorf("EnsureDir failed to ensure parent directory: %w", err))
+	}
+
+	if skip, err := writeGuard("EnsureDir", path, ""); skip {
+		return err
 	}
+	defer invalidateStats(path)
 
 	err = os.Mkdir(path, mode)
 	if err != nil {
-		return fmt.Errorf("EnsureDir failed to create directory: %w", err)
+		return pathError("EnsureDir", path, fmt.Errorf("EnsureDir failed to create directory: %w", err))
 	}
 
 	return nil
 }
 
-// Exists checks if a file or directory exists.
+// Exists checks if a file or directory exists. It answers from the stat cache, if one
+// was set with SetStatCache.
 func Exists(path string) (bool, error) {
-	_, err := os.Stat(path)
+	path = normalizePath(path)
+
+	_, err := cachedStat(path)
 	if err == nil {
 		return true, nil
 	}
 	if os.IsNotExist(err) {
 		return false, nil
 	}
-	return false, fmt.Errorf("Exists failed to check if file exists: %w", err)
+	return false, pathError("Exists", path, fmt.Errorf("Exists failed to check if file exists: %w", err))
 }
 
 // ReadDir reads the content of a directory and returns a list of file names.
 // The order of the files is not guaranteed.
+// Directories inside archives can be read with paths like "bundle.zip!/assets".
 func ReadDir(path string) ([]string, error) {
+	if archive, name, ok := splitArchivePath(path); ok {
+		names, err := readArchiveDir(archive, name)
+		if err != nil {
+			return nil, pathError("ReadDir", path, fmt.Errorf("ReadDir failed to read archive directory: %w", err))
+		}
+		return names, nil
+	}
+
+	path = normalizePath(path)
+
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("ReadDir failed to open directory: %w", err)
+		return nil, pathError("ReadDir", path, fmt.Errorf("ReadDir failed to open directory: %w", err))
 	}
 	defer file.Close()
 
-	return file.Readdirnames(-1)
+	names, err := file.Readdirnames(-1)
+	if err != nil {
+		return nil, pathError("ReadDir", path, fmt.Errorf("ReadDir failed to read directory: %w", err))
+	}
+
+	return names, nil
 }
 
 // ReadDirRec reads the content of a directory recursively and returns a list of file names.
-// The order of the files is not guaranteed.
+// The order of the files is not guaranteed. Symlinks are listed but not followed.
+// Inside archives, like "bundle.zip!/assets", the returned names are archive paths too.
+// Use ReadDirRecWithOptions or IterDirRec for more control over the results.
+// It answers from the stat cache, if one was set with SetStatCache.
 func ReadDirRec(path string) ([]string, error) {
-	var files []string
-
-	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+	if archive, name, ok := splitArchivePath(path); ok {
+		files, err := walkArchive(archive, name)
 		if err != nil {
-			return fmt.Errorf("ReadDirRec failed in walk function: %w", err)
-		}
-
-		if info.IsDir() {
-			return nil
+			return nil, pathError("ReadDirRec", path, fmt.Errorf("ReadDirRec failed to walk archive: %w", err))
 		}
+		return files, nil
+	}
 
-		files = append(files, path)
-		return nil
-	})
+	list := func() ([]string, error) {
+		return ReadDirRecWithOptions(path, ReadDirRecOptions{IncludeSymlinks: true})
+	}
+	var files []string
+	var err error
+	if c := activeStatCache(); c != nil {
+		files, err = c.readDirRec(normalizePath(path), list)
+	} else {
+		files, err = list()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("ReadDirRec failed to walk directory: %w", err)
+		return nil, pathError("ReadDirRec", path, fmt.Errorf("ReadDirRec failed to walk directory: %w", err))
 	}
 
 	return files, nil
@@ -141,7 +196,7 @@ func ReadDirRec(path string) ([]string, error) {
 func ReadJson[T any](path string, v *T) error {
 	content, err := ReadBytes(path)
 	if err != nil {
-		return fmt.Errorf("ReadJson failed to read file: %w", err)
+		return pathError("ReadJson", path, fmt.Errorf("ReadJson failed to read file: %w", err))
 	}
 
 	return json.Unmarshal(content, v)
@@ -160,13 +215,15 @@ func ReadJson[T any](path string, v *T) error {
 func ReadText(path string) (string, error) {
 	content, err := ReadBytes(path)
 	if err != nil {
-		return "", fmt.Errorf("ReadText failed to read file: %w", err)
+		return "", pathError("ReadText", path, fmt.Errorf("ReadText failed to read file: %w", err))
 	}
 
 	return string(content), nil
 }
 
 // ReadBytes reads the content of a file and returns it as a byte slice.
+// Files inside zip and tar archives can be read with paths like "bundle.zip!/config/app.json",
+// which also works for the functions built on ReadBytes, like ReadText and ReadJson.
 //
 // Example:
 //
@@ -176,16 +233,67 @@ func ReadText(path string) (string, error) {
 //	    return
 //	}
 func ReadBytes(path string) ([]byte, error) {
-	return os.ReadFile(path)
+	var content []byte
+	var err error
+	if archive, name, ok := splitArchivePath(path); ok {
+		content, err = readArchiveFile(archive, name)
+	} else {
+		content, err = os.ReadFile(normalizePath(path))
+	}
+	observe("ReadBytes", int64(len(content)), 0, err)
+	if err != nil {
+		return nil, pathError("ReadBytes", path, fmt.Errorf("ReadBytes failed to read file: %w", err))
+	}
+
+	return content, nil
+}
+
+// ReadBytesAt reads length bytes of a file starting at offset, without reading the rest of the file.
+// It fails if the file ends before length bytes could be read.
+//
+// Example:
+//
+//	record, err := ReadBytesAt("records.db", 3*recordSize, recordSize)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadBytesAt(path string, offset int64, length int) (content []byte, err error) {
+	path = normalizePath(path)
+	defer func() { observe("ReadBytesAt", int64(len(content)), 0, err) }()
+
+	if offset < 0 || length < 0 {
+		return nil, pathError("ReadBytesAt", path, fmt.Errorf("ReadBytesAt failed: invalid range %d+%d", offset, length))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("ReadBytesAt", path, fmt.Errorf("ReadBytesAt failed to open file: %w", err))
+	}
+	defer file.Close()
+
+	content = make([]byte, length)
+	_, err = file.ReadAt(content, offset)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, pathError("ReadBytesAt", path, fmt.Errorf("ReadBytesAt failed to read content: %w", err))
+	}
+
+	return content, nil
 }
 
 // GetSize returns the size of a file in bytes.
 // Crucially, it returns int instead of int64. This is to make `make` easier to use
 // with the result of this function.
+// It answers from the stat cache, if one was set with SetStatCache.
 func GetSize(path string) (int64, error) {
-	info, err := os.Stat(path)
+	path = normalizePath(path)
+
+	info, err := cachedStat(path)
 	if err != nil {
-		return 0, fmt.Errorf("GetSize failed to get file stat: %w", err)
+		return 0, pathError("GetSize", path, fmt.Errorf("GetSize failed to get file stat: %w", err))
 	}
 
 	return info.Size(), nil
@@ -195,7 +303,7 @@ func GetSize(path string) (int64, error) {
 func WriteJson[T any](path string, v T) error {
 	content, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("WriteJson failed to marshal content: %w", err)
+		return pathError("WriteJson", path, fmt.Errorf("WriteJson failed to marshal content: %w", err))
 	}
 
 	return WriteBytes(path, content)
@@ -205,7 +313,7 @@ func WriteJson[T any](path string, v T) error {
 func WriteJsonWithMode[T any](path string, v T, mode os.FileMode) error {
 	content, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("WriteJson failed to marshal content: %w", err)
+		return pathError("WriteJson", path, fmt.Errorf("WriteJson failed to marshal content: %w", err))
 	}
 
 	return WriteBytesWithMode(path, content, mode)
@@ -215,7 +323,7 @@ func WriteJsonWithMode[T any](path string, v T, mode os.FileMode) error {
 func WriteText(path, content string) error {
 	err := WriteBytes(path, []byte(content))
 	if err != nil {
-		return fmt.Errorf("WriteText failed to write content to file: %w", err)
+		return pathError("WriteText", path, fmt.Errorf("WriteText failed to write content to file: %w", err))
 	}
 
 	return nil
@@ -225,23 +333,31 @@ func WriteText(path, content string) error {
 func WriteTextWithMode(path, content string, mode os.FileMode) error {
 	err := WriteBytesWithMode(path, []byte(content), mode)
 	if err != nil {
-		return fmt.Errorf("WriteText failed to write content to file: %w", err)
+		return pathError("WriteText", path, fmt.Errorf("WriteText failed to write content to file: %w", err))
 	}
 
 	return nil
 }
 
 // WriteBytes writes a byte slice to a file.
-func WriteBytes(path string, content []byte) error {
+func WriteBytes(path string, content []byte) (err error) {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("WriteBytes", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+	defer func() { observeWrite("WriteBytes", len(content), err) }()
+
 	file, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("WriteBytes failed to create file: %w", err)
+		return pathError("WriteBytes", path, fmt.Errorf("WriteBytes failed to create file: %w", err))
 	}
 	defer file.Close()
 
 	_, err = file.Write(content)
 	if err != nil {
-		return fmt.Errorf("WriteBytes failed to write content to file: %w", err)
+		return pathError("WriteBytes", path, fmt.Errorf("WriteBytes failed to write content to file: %w", err))
 	}
 
 	return nil
@@ -249,9 +365,172 @@ func WriteBytes(path string, content []byte) error {
 
 // WriteBytes writes a byte slice to a file with a specific file mode.
 func WriteBytesWithMode(path string, content []byte, mode os.FileMode) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("WriteBytes", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
 	err := os.WriteFile(path, content, mode)
+	observeWrite("WriteBytes", len(content), err)
+	if err != nil {
+		return pathError("WriteBytes", path, fmt.Errorf("WriteBytes failed to write content to file: %w", err))
+	}
+
+	return nil
+}
+
+// WriteTextNew writes a string to a new file. See WriteBytesNewWithMode.
+func WriteTextNew(path, content string) error {
+	err := WriteBytesNew(path, []byte(content))
+	if err != nil {
+		return pathError("WriteTextNew", path, fmt.Errorf("WriteTextNew failed to write content to file: %w", err))
+	}
+
+	return nil
+}
+
+// WriteTextNewWithMode writes a string to a new file with a specific file mode.
+// See WriteBytesNewWithMode.
+func WriteTextNewWithMode(path, content string, mode os.FileMode) error {
+	err := WriteBytesNewWithMode(path, []byte(content), mode)
+	if err != nil {
+		return pathError("WriteTextNew", path, fmt.Errorf("WriteTextNew failed to write content to file: %w", err))
+	}
+
+	return nil
+}
+
+// WriteBytesNew writes a byte slice to a new file with mode 0644. See WriteBytesNewWithMode.
+func WriteBytesNew(path string, content []byte) error {
+	return WriteBytesNewWithMode(path, content, 0644)
+}
+
+// WriteBytesNewWithMode writes a byte slice to a new file with a specific file mode.
+// It fails with an error matching ErrExist if the file already exists. The file is
+// created with O_EXCL, so of several processes writing the same path at once, exactly
+// one succeeds. If writing the content fails, the file is removed again.
+//
+// Example:
+//
+//	err := WriteTextNew("config.yaml", defaultConfig)
+//	if err != nil && !errors.Is(err, ErrExist) {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteBytesNewWithMode(path string, content []byte, mode os.FileMode) (err error) {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("WriteBytesNew", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+	defer func() { observeWrite("WriteBytesNew", len(content), err) }()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return pathError("WriteBytesNew", path, fmt.Errorf("WriteBytesNew failed to create file: %w", err))
+	}
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
-		return fmt.Errorf("WriteBytes failed to write content to file: %w", err)
+		os.Remove(path)
+		return pathError("WriteBytesNew", path, fmt.Errorf("WriteBytesNew failed to write content to file: %w", err))
+	}
+
+	return nil
+}
+
+// WriteBytesAt writes a byte slice to a file at offset, leaving the rest of the file as is.
+// The file is created with mode 0644 if it doesn't exist, and grows if the write extends past its end.
+func WriteBytesAt(path string, offset int64, content []byte) (err error) {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("WriteBytesAt", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+	defer func() { observeWrite("WriteBytesAt", len(content), err) }()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return pathError("WriteBytesAt", path, fmt.Errorf("WriteBytesAt failed to open file: %w", err))
+	}
+	defer file.Close()
+
+	_, err = file.WriteAt(content, offset)
+	if err != nil {
+		return pathError("WriteBytesAt", path, fmt.Errorf("WriteBytesAt failed to write content to file: %w", err))
+	}
+
+	return nil
+}
+
+// WriteBytesDurable writes a byte slice to a file and syncs both the file and its
+// parent directory to disk, so the content survives a crash or power loss once it returns.
+func WriteBytesDurable(path string, content []byte) error {
+	return WriteBytesDurableWithMode(path, content, 0644)
+}
+
+// WriteBytesDurableWithMode writes a byte slice to a file with a specific file mode and
+// syncs both the file and its parent directory to disk.
+func WriteBytesDurableWithMode(path string, content []byte, mode os.FileMode) (err error) {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("WriteBytesDurable", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+	defer func() { observeWrite("WriteBytesDurable", len(content), err) }()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return pathError("WriteBytesDurable", path, fmt.Errorf("WriteBytesDurable failed to open file: %w", err))
+	}
+
+	_, err = file.Write(content)
+	if err != nil {
+		file.Close()
+		return pathError("WriteBytesDurable", path, fmt.Errorf("WriteBytesDurable failed to write content to file: %w", err))
+	}
+
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return pathError("WriteBytesDurable", path, fmt.Errorf("WriteBytesDurable failed to sync file: %w", err))
+	}
+
+	err = file.Close()
+	if err != nil {
+		return pathError("WriteBytesDurable", path, fmt.Errorf("WriteBytesDurable failed to close file: %w", err))
+	}
+
+	err = syncDir(filepath.Dir(path))
+	if err != nil {
+		return pathError("WriteBytesDurable", path, fmt.Errorf("WriteBytesDurable failed to sync directory: %w", err))
+	}
+
+	return nil
+}
+
+// Truncate changes the size of a file. Data beyond the new size is discarded,
+// and growing the file fills it with zeros.
+func Truncate(path string, size int64) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("Truncate", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err := os.Truncate(path, size)
+	observe("Truncate", 0, 0, err)
+	if err != nil {
+		return pathError("Truncate", path, fmt.Errorf("Truncate failed to truncate file: %w", err))
 	}
 
 	return nil
@@ -261,45 +540,278 @@ func WriteBytesWithMode(path string, content []byte, mode os.FileMode) error {
 func AppendText(path, content string) error {
 	err := AppendBytes(path, []byte(content))
 	if err != nil {
-		return fmt.Errorf("AppendText failed to append content to file: %w", err)
+		return pathError("AppendText", path, fmt.Errorf("AppendText failed to append content to file: %w", err))
 	}
 
 	return nil
 }
 
 // AppendBytes appends a byte slice to a file.
-func AppendBytes(path string, content []byte) error {
+func AppendBytes(path string, content []byte) (err error) {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("AppendBytes", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+	defer func() { observeWrite("AppendBytes", len(content), err) }()
+
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
-		return fmt.Errorf("AppendBytes failed to open file: %w", err)
+		return pathError("AppendBytes", path, fmt.Errorf("AppendBytes failed to open file: %w", err))
 	}
 	defer file.Close()
 
 	_, err = file.Write(content)
 	if err != nil {
-		return fmt.Errorf("AppendBytes failed to append content to file: %w", err)
+		return pathError("AppendBytes", path, fmt.Errorf("AppendBytes failed to append content to file: %w", err))
 	}
 
 	return nil
 }
 
 // CopyFile copies a file from source to destination.
+// Holes in sparse files are preserved where the platform supports finding them.
+// The content is copied by the kernel with copy_file_range or sendfile on Linux and
+// CopyFileEx on Windows, falling back to a regular copy where those aren't supported.
 func CopyFile(src, dst string) error {
+	return copyFile(src, dst, nil, nil)
+}
+
+// copyFile copies a file like CopyFile, limited by limiter if it isn't nil. Limited copies
+// go through userspace, since kernel copies can't be paced, and write holes out as zeros.
+// If chunked isn't nil, it copies the content in place of a sequential copy whenever the
+// native and sparse fast paths don't apply, and returns the number of bytes copied.
+func copyFile(src, dst string, limiter *rateLimiter, chunked func(source, destination *os.File) (int64, error)) (err error) {
+	src, dst = normalizePath(src), normalizePath(dst)
+
+	if skip, err := writeGuard("CopyFile", src, dst); skip {
+		return err
+	}
+	defer invalidateStats(src, dst)
+
+	var copied int64
+	defer func() { observe("CopyFile", copied, copied, err) }()
+
 	sourceFile, err := os.Open(src)
 	if err != nil {
-		return fmt.Errorf("CopyFile failed to open source file: %w", err)
+		return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to open source file: %w", err))
 	}
 	defer sourceFile.Close()
 
+	// Creating the destination would truncate the source if they are the same file
+	if same, _ := SameFile(src, dst); same {
+		return pathError("CopyFile", src, fmt.Errorf("CopyFile failed: %s and %s are the same file", src, dst))
+	}
+
+	if limiter == nil {
+		var native bool
+		copied, native, err = copyFileNative(src, dst)
+		if err != nil {
+			return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to copy: %w", err))
+		}
+		if native {
+			return nil
+		}
+	}
+
 	destinationFile, err := os.Create(dst)
 	if err != nil {
-		return fmt.Errorf("CopyFile failed to create destination file: %w", err)
+		return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to create destination file: %w", err))
 	}
 	defer destinationFile.Close()
 
-	_, err = io.Copy(destinationFile, sourceFile)
+	if limiter == nil {
+		// Preserve holes in sparse files instead of writing them out as zeros
+		var sparse bool
+		copied, sparse, err = copySparse(sourceFile, destinationFile)
+		if err != nil {
+			return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to copy sparse file: %w", err))
+		}
+		if sparse {
+			return nil
+		}
+	}
+
+	if chunked != nil {
+		copied, err = chunked(sourceFile, destinationFile)
+		if err != nil {
+			return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to copy: %w", err))
+		}
+		return nil
+	}
+
+	if limiter == nil {
+		var fast bool
+		copied, fast, err = copyFast(sourceFile, destinationFile, -1)
+		if err != nil {
+			return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to copy: %w", err))
+		}
+		if fast {
+			return nil
+		}
+	}
+
+	copied, err = io.Copy(limiter.writer(context.Background(), destinationFile), sourceFile)
+	if err != nil {
+		return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to copy: %w", err))
+	}
+
+	return nil
+}
+
+// CopyOptions configures CopyFileWithOptions.
+type CopyOptions struct {
+	PreserveMode  bool // Keep the permission bits of the source
+	PreserveTimes bool // Keep the modification and access times of the source
+	PreserveOwner bool // Keep the owner of the source, which usually requires privileges
+	// BytesPerSecond limits the bandwidth of the copy, if positive. Limited copies don't
+	// preserve holes in sparse files.
+	BytesPerSecond int64
+}
+
+// CopyFileWithOptions copies a file from source to destination, optionally preserving
+// the metadata of the source.
+//
+// Example:
+//
+//	err := CopyFileWithOptions("app.conf", "backup/app.conf", CopyOptions{
+//	    PreserveMode:  true,
+//	    PreserveTimes: true,
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func CopyFileWithOptions(src, dst string, opts CopyOptions) error {
+	err := copyFile(src, dst, newRateLimiter(opts.BytesPerSecond), nil)
+	if err != nil {
+		return err
+	}
+	if !opts.PreserveMode && !opts.PreserveTimes && !opts.PreserveOwner {
+		return nil
+	}
+	if IsDryRun() {
+		return nil
+	}
+
+	meta, err := CaptureMeta(src)
+	if err != nil {
+		return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to capture metadata: %w", err))
+	}
+
+	err = applyMeta(dst, meta, opts.PreserveMode, opts.PreserveTimes, opts.PreserveOwner)
+	if err != nil {
+		return pathError("CopyFile", src, fmt.Errorf("CopyFile failed to apply metadata: %w", err))
+	}
+
+	return nil
+}
+
+// Move moves a file or directory from source to destination.
+// If a file can't be renamed because the destination is on another device,
+// it is copied and the source removed instead, which is journaled in journal mode.
+func Move(src, dst string) (err error) {
+	src, dst = normalizePath(src), normalizePath(dst)
+
+	if skip, err := writeGuard("Move", src, dst); skip {
+		return err
+	}
+	defer invalidateStats(src, dst)
+	defer func() { observe("Move", 0, 0, err) }()
+
+	err = os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !isCrossDevice(err) {
+		return pathError("Move", src, fmt.Errorf("Move failed to rename: %w", err))
+	}
+
+	info, statErr := os.Stat(src)
+	if statErr != nil {
+		return pathError("Move", src, fmt.Errorf("Move failed to get source stat: %w", statErr))
+	}
+	if !info.Mode().IsRegular() {
+		return pathError("Move", src, fmt.Errorf("Move failed to rename across devices: %w", err))
+	}
+
+	j := activeJournal()
+	id, err := j.begin(journalRecord{Op: "Move", Src: src, Dst: dst})
+	if err != nil {
+		return pathError("Move", src, fmt.Errorf("Move failed: %w", err))
+	}
+
+	err = copyForMove(src, dst, info.Mode().Perm())
+	if err != nil {
+		return pathError("Move", src, fmt.Errorf("Move failed to copy across devices: %w", err))
+	}
+
+	err = j.step(id, journalRecord{State: "copied"})
+	if err != nil {
+		return pathError("Move", src, fmt.Errorf("Move failed: %w", err))
+	}
+
+	err = os.Remove(src)
+	if err != nil {
+		return pathError("Move", src, fmt.Errorf("Move failed to remove source: %w", err))
+	}
+
+	err = j.done(id)
+	if err != nil {
+		return pathError("Move", src, fmt.Errorf("Move failed: %w", err))
+	}
+
+	return nil
+}
+
+// copyForMove copies a regular file to another device with the mode of the source.
+func copyForMove(src, dst string, mode os.FileMode) error {
+	err := CopyFile(src, dst)
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(dst, mode)
+	if err != nil {
+		return fmt.Errorf("failed to set destination mode: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes a file or an empty directory.
+func Remove(path string) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("Remove", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err := os.Remove(path)
+	observe("Remove", 0, 0, err)
+	if err != nil {
+		return pathError("Remove", path, fmt.Errorf("Remove failed to remove: %w", err))
+	}
+
+	return nil
+}
+
+// RemoveAll removes a file or a directory and everything it contains.
+// It returns nil if the path doesn't exist.
+func RemoveAll(path string) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("RemoveAll", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err := os.RemoveAll(path)
+	observe("RemoveAll", 0, 0, err)
 	if err != nil {
-		return fmt.Errorf("CopyFile failed to copy: %w", err)
+		return pathError("RemoveAll", path, fmt.Errorf("RemoveAll failed to remove: %w", err))
 	}
 
 	return nil
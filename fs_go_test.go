@@ -1,9 +1,18 @@
 package fs_go
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // Prefer standard library functions internally in tests
@@ -122,6 +131,67 @@ func TestEnsureDir(t *testing.T) {
 			t.Errorf("Expected directory to be a directory")
 		}
 	})
+
+	// Expect nested directories to all be created with the requested mode
+	t.Run("nested directories get the requested mode", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("directory modes are not supported on Windows")
+		}
+
+		path := "ensure_dir_5"
+		defer os.RemoveAll(path)
+
+		leaf := filepath.Join(path, "a", "b", "c")
+		err := EnsureDirWithMode(leaf, 0750)
+		if err != nil {
+			t.Errorf("EnsureDirWithMode failed: %v", err)
+		}
+
+		for _, dir := range []string{path, filepath.Join(path, "a"), filepath.Join(path, "a", "b"), leaf} {
+			info, err := os.Stat(dir)
+			if err != nil {
+				t.Fatalf("os.Stat failed: %v", err)
+			}
+
+			if info.Mode().Perm() != 0750 {
+				t.Errorf("Expected %s mode to be 0750, got %#o", dir, info.Mode().Perm())
+			}
+		}
+	})
+
+	// Expect parents to stay usable when the leaf mode lacks owner permissions
+	t.Run("parents get owner permissions", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("directory modes are not supported on Windows")
+		}
+
+		path := "ensure_dir_6"
+		defer os.RemoveAll(path)
+
+		leaf := filepath.Join(path, "a", "b")
+		err := EnsureDirWithMode(leaf, 0500)
+		if err != nil {
+			t.Errorf("EnsureDirWithMode failed: %v", err)
+		}
+
+		info, err := os.Stat(filepath.Join(path, "a"))
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if info.Mode().Perm() != 0700 {
+			t.Errorf("Expected parent mode to be 0700, got %#o", info.Mode().Perm())
+		}
+
+		info, err = os.Stat(leaf)
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if info.Mode().Perm() != 0500 {
+			t.Errorf("Expected leaf mode to be 0500, got %#o", info.Mode().Perm())
+		}
+	})
 }
 
 func TestExists(t *testing.T) {
@@ -331,6 +401,53 @@ func TestReadBytes(t *testing.T) {
 	})
 }
 
+func TestReadBytesAt(t *testing.T) {
+	// Expect to read a region of a file
+	t.Run("read region", func(t *testing.T) {
+		path := "read_bytes_at.txt"
+		defer os.Remove(path)
+
+		err := WriteText(path, "test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		content, err := ReadBytesAt(path, 5, 4)
+		if err != nil {
+			t.Errorf("ReadBytesAt failed: %v", err)
+		}
+
+		if string(content) != "cont" {
+			t.Errorf("Expected content to be 'cont', got '%s'", content)
+		}
+	})
+
+	// Expect to fail when the region extends past the end of the file
+	t.Run("short read", func(t *testing.T) {
+		path := "read_bytes_at_short.txt"
+		defer os.Remove(path)
+
+		err := WriteText(path, "test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		_, err = ReadBytesAt(path, 10, 4)
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+		}
+
+		_, err = ReadBytesAt(path, 0, -1)
+		if err == nil {
+			t.Errorf("Expected a negative length to fail")
+		}
+		_, err = ReadBytesAt(path, -1, 4)
+		if err == nil {
+			t.Errorf("Expected a negative offset to fail")
+		}
+	})
+}
+
 func TestGetSize(t *testing.T) {
 	// Expect to return the size of a file
 	t.Run("get file size", func(t *testing.T) {
@@ -495,6 +612,224 @@ func TestWriteBytesWithMode(t *testing.T) {
 	})
 }
 
+func TestWriteBytesNew(t *testing.T) {
+	// Expect to create a file that doesn't exist
+	t.Run("new file", func(t *testing.T) {
+		path := "write_bytes_new.txt"
+		defer os.Remove(path)
+
+		err := WriteBytesNew(path, []byte("first"))
+		if err != nil {
+			t.Errorf("WriteBytesNew failed: %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "first" {
+			t.Errorf("Expected content to be 'first', got '%s'", content)
+		}
+	})
+
+	// Expect ErrExist and the existing content to be kept if the file exists
+	t.Run("existing file", func(t *testing.T) {
+		path := "write_bytes_new_existing.txt"
+		defer os.Remove(path)
+		os.WriteFile(path, []byte("first"), 0644)
+
+		err := WriteTextNew(path, "second")
+		if !errors.Is(err, ErrExist) {
+			t.Errorf("Expected ErrExist, got %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "first" {
+			t.Errorf("Expected content to be 'first', got '%s'", content)
+		}
+	})
+
+	// Expect exactly one of several concurrent writers to succeed
+	t.Run("concurrent", func(t *testing.T) {
+		path := "write_bytes_new_concurrent.txt"
+		defer os.Remove(path)
+
+		var wg sync.WaitGroup
+		var created atomic.Int32
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if WriteBytesNew(path, []byte("content")) == nil {
+					created.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if created.Load() != 1 {
+			t.Errorf("Expected 1 writer to succeed, got %d", created.Load())
+		}
+	})
+}
+
+func TestWriteBytesAt(t *testing.T) {
+	// Expect to overwrite a region of a file
+	t.Run("write region", func(t *testing.T) {
+		path := "write_bytes_at.txt"
+		defer os.Remove(path)
+
+		err := WriteText(path, "test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		err = WriteBytesAt(path, 5, []byte("CONT"))
+		if err != nil {
+			t.Errorf("WriteBytesAt failed: %v", err)
+		}
+
+		content, err := ReadText(path)
+		if err != nil {
+			t.Errorf("ReadText failed: %v", err)
+		}
+
+		if content != "test CONTent" {
+			t.Errorf("Expected content to be 'test CONTent', got '%s'", content)
+		}
+	})
+
+	// Expect to grow the file when writing past its end
+	t.Run("extend file", func(t *testing.T) {
+		path := "write_bytes_at_extend.txt"
+		defer os.Remove(path)
+
+		err := WriteBytesAt(path, 2, []byte("ab"))
+		if err != nil {
+			t.Errorf("WriteBytesAt failed: %v", err)
+		}
+
+		content, err := ReadBytes(path)
+		if err != nil {
+			t.Errorf("ReadBytes failed: %v", err)
+		}
+
+		if string(content) != "\x00\x00ab" {
+			t.Errorf("Expected content to be padded with zeros, got %q", content)
+		}
+	})
+}
+
+func TestWriteBytesDurable(t *testing.T) {
+	// Expect to write content to a file
+	t.Run("write bytes durably", func(t *testing.T) {
+		path := "write_bytes_durable.txt"
+		defer os.Remove(path)
+
+		err := WriteBytesDurable(path, []byte("test content"))
+		if err != nil {
+			t.Errorf("WriteBytesDurable failed: %v", err)
+		}
+
+		content, err := ReadText(path)
+		if err != nil {
+			t.Errorf("ReadText failed: %v", err)
+		}
+
+		if content != "test content" {
+			t.Errorf("Expected content to be 'test content', got '%s'", content)
+		}
+	})
+
+	// Expect existing content to be replaced
+	t.Run("overwrite file", func(t *testing.T) {
+		path := "write_bytes_durable_2.txt"
+		defer os.Remove(path)
+
+		err := WriteText(path, "longer test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		err = WriteBytesDurable(path, []byte("test content"))
+		if err != nil {
+			t.Errorf("WriteBytesDurable failed: %v", err)
+		}
+
+		content, err := ReadText(path)
+		if err != nil {
+			t.Errorf("ReadText failed: %v", err)
+		}
+
+		if content != "test content" {
+			t.Errorf("Expected content to be 'test content', got '%s'", content)
+		}
+	})
+
+	// Expect dry-run mode to record the write under its own name
+	t.Run("dry run", func(t *testing.T) {
+		path := "write_bytes_durable_3.txt"
+		defer os.Remove(path)
+
+		plan, err := DryRun(func() error {
+			return WriteBytesDurable(path, []byte("test content"))
+		})
+		if err != nil {
+			t.Errorf("DryRun failed: %v", err)
+		}
+
+		if len(plan) != 1 || plan[0].Op != "WriteBytesDurable" {
+			t.Errorf("Expected a WriteBytesDurable action, got %v", plan)
+		}
+	})
+}
+
+func TestWriteBytesDurableWithMode(t *testing.T) {
+	// Expect to write a file with a specific mode
+	t.Run("write bytes durably with mode", func(t *testing.T) {
+		path := "write_bytes_durable_mode.txt"
+		defer os.Remove(path)
+
+		err := WriteBytesDurableWithMode(path, []byte("test content"), 0600)
+		if err != nil {
+			t.Errorf("WriteBytesDurableWithMode failed: %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("os.Stat failed: %v", err)
+		}
+
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Expected file mode to be 0600, got %#o", info.Mode().Perm())
+		}
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	// Expect to shrink a file
+	t.Run("shrink file", func(t *testing.T) {
+		path := "truncate.txt"
+		defer os.Remove(path)
+
+		err := WriteText(path, "test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		err = Truncate(path, 4)
+		if err != nil {
+			t.Errorf("Truncate failed: %v", err)
+		}
+
+		content, err := ReadText(path)
+		if err != nil {
+			t.Errorf("ReadText failed: %v", err)
+		}
+
+		if content != "test" {
+			t.Errorf("Expected content to be 'test', got '%s'", content)
+		}
+	})
+}
+
 func TestAppendText(t *testing.T) {
 	// Expect to append content to a file
 	t.Run("append text file", func(t *testing.T) {
@@ -576,4 +911,213 @@ func TestCopyFile(t *testing.T) {
 			t.Errorf("Expected content to be 'test content', got '%s'", content)
 		}
 	})
+
+	// Expect copying a file onto itself to fail without truncating it
+	t.Run("same file", func(t *testing.T) {
+		src := "copy_file_same.txt"
+		defer os.Remove(src)
+
+		err := WriteText(src, "test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		err = CopyFile(src, "./"+src)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+
+		content, _ := ReadText(src)
+		if content != "test content" {
+			t.Errorf("Expected content to be kept, got '%s'", content)
+		}
+	})
+
+	// Expect large files to be copied whole, replacing the destination
+	t.Run("large file", func(t *testing.T) {
+		src := "copy_file_large_src.bin"
+		dst := "copy_file_large_dst.bin"
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		content := bytes.Repeat([]byte("0123456789abcdef"), 1<<18)
+		if err := os.WriteFile(src, content, 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		if err := os.WriteFile(dst, []byte("previous content that is overwritten"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := CopyFile(src, dst)
+		if err != nil {
+			t.Fatalf("CopyFile failed: %v", err)
+		}
+
+		copied, _ := os.ReadFile(dst)
+		if !bytes.Equal(copied, content) {
+			t.Errorf("Expected %d identical bytes, got %d", len(content), len(copied))
+		}
+	})
+
+	// Expect files that report no size, like those in /proc, to be copied through the fallback
+	t.Run("proc file", func(t *testing.T) {
+		if _, err := os.Stat("/proc/self/status"); err != nil {
+			t.Skip("no /proc")
+		}
+		dst := "copy_file_proc.txt"
+		defer os.Remove(dst)
+
+		err := CopyFile("/proc/self/status", dst)
+		if err != nil {
+			t.Fatalf("CopyFile failed: %v", err)
+		}
+
+		copied, _ := os.ReadFile(dst)
+		if !strings.Contains(string(copied), "Name:") {
+			t.Errorf("Expected the status content, got '%s'", copied)
+		}
+	})
+}
+
+func TestCopyFileWithOptions(t *testing.T) {
+	// Expect mode and times to be preserved when requested
+	t.Run("preserve mode and times", func(t *testing.T) {
+		src := "copy_file_options_src.txt"
+		dst := "copy_file_options_dst.txt"
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		if err := os.WriteFile(src, []byte("content"), 0600); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(src, mtime, mtime); err != nil {
+			t.Fatalf("os.Chtimes failed: %v", err)
+		}
+
+		err := CopyFileWithOptions(src, dst, CopyOptions{PreserveMode: true, PreserveTimes: true})
+		if err != nil {
+			t.Errorf("CopyFileWithOptions failed: %v", err)
+		}
+
+		info, err := os.Stat(dst)
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("Expected modification time to be %v, got %v", mtime, info.ModTime())
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("Expected mode to be 0600, got %#o", info.Mode().Perm())
+		}
+	})
+
+	// Expect times to not be preserved by default
+	t.Run("no preservation", func(t *testing.T) {
+		src := "copy_file_options_src_2.txt"
+		dst := "copy_file_options_dst_2.txt"
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		if err := os.WriteFile(src, []byte("content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(src, mtime, mtime); err != nil {
+			t.Fatalf("os.Chtimes failed: %v", err)
+		}
+
+		err := CopyFileWithOptions(src, dst, CopyOptions{})
+		if err != nil {
+			t.Errorf("CopyFileWithOptions failed: %v", err)
+		}
+
+		info, err := os.Stat(dst)
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if info.ModTime().Equal(mtime) {
+			t.Errorf("Expected modification time to not be preserved")
+		}
+	})
+}
+
+func TestMove(t *testing.T) {
+	// Expect to move a file
+	t.Run("move file", func(t *testing.T) {
+		src := "move_src.txt"
+		dst := "move_dst.txt"
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		err := WriteText(src, "test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		err = Move(src, dst)
+		if err != nil {
+			t.Errorf("Move failed: %v", err)
+		}
+
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Errorf("Expected source to not exist")
+		}
+
+		content, err := ReadText(dst)
+		if err != nil {
+			t.Errorf("ReadText failed: %v", err)
+		}
+
+		if content != "test content" {
+			t.Errorf("Expected content to be 'test content', got '%s'", content)
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	// Expect to remove a file
+	t.Run("remove file", func(t *testing.T) {
+		path := "remove.txt"
+		defer os.Remove(path)
+
+		err := WriteText(path, "test content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		err = Remove(path)
+		if err != nil {
+			t.Errorf("Remove failed: %v", err)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected file to not exist")
+		}
+	})
+}
+
+func TestRemoveAll(t *testing.T) {
+	// Expect to remove a directory tree
+	t.Run("remove directory", func(t *testing.T) {
+		path := "remove_all"
+		defer os.RemoveAll(path)
+
+		err := os.MkdirAll(path+"/nested", 0755)
+		if err != nil {
+			t.Fatalf("os.MkdirAll failed: %v", err)
+		}
+
+		err = RemoveAll(path)
+		if err != nil {
+			t.Errorf("RemoveAll failed: %v", err)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected directory to not exist")
+		}
+	})
 }
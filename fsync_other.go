@@ -0,0 +1,9 @@
+//go:build !unix
+
+package fs_go
+
+// syncDir is a no-op where directories can't be opened for syncing.
+// On Windows, metadata updates are journaled by NTFS.
+func syncDir(path string) error {
+	return nil
+}
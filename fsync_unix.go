@@ -0,0 +1,16 @@
+//go:build unix
+
+package fs_go
+
+import "os"
+
+// syncDir flushes a directory's entries to disk, making renames and creations in it durable.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
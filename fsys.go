@@ -0,0 +1,212 @@
+package fs_go
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// FS is a writable file system. Like io/fs, names are slash-separated, unrooted paths
+// such as "dir/file.txt", and "." is the root.
+//
+// DirFS implements FS for a directory on disk, and wrappers like ErrorFS build on any FS,
+// so code written against FS can be tested or redirected without touching the disk.
+type FS interface {
+	fs.StatFS
+	fs.ReadDirFS
+	fs.ReadFileFS
+
+	// WriteFile writes data to the named file, creating it with perm if needed.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// Create creates or truncates the named file and returns a writer for its content.
+	Create(name string) (io.WriteCloser, error)
+	// MkdirAll creates a directory along with any missing parents.
+	MkdirAll(name string, perm fs.FileMode) error
+	// Remove removes a file or empty directory.
+	Remove(name string) error
+	// RemoveAll removes a file or directory and everything it contains.
+	RemoveAll(name string) error
+	// Rename moves a file or directory.
+	Rename(oldname, newname string) error
+}
+
+// dirFS is the FS returned by DirFS.
+type dirFS string
+
+// DirFS returns an FS for the directory tree rooted at dir. Names are resolved with
+// SecureJoin, so neither ".." nor symlinks inside the tree lead outside of it.
+// Writes respect read-only and dry-run mode like the rest of the package.
+//
+// Example:
+//
+//	fsys := DirFS("build")
+//	err := fsys.WriteFile("index.html", content, 0644)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func DirFS(dir string) FS {
+	return dirFS(dir)
+}
+
+// join resolves a name to a path on disk with SecureJoin, so symlinks can't lead outside
+// the directory, rejecting names that aren't valid io/fs paths.
+func (dir dirFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	path, err := SecureJoin(string(dir), name)
+	if err != nil {
+		return "", &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	return path, nil
+}
+
+// joinEntry is join for operations on an entry itself, like removing it, which resolves
+// symlinks in its directory but not the entry, so a symlink is removed rather than its target.
+func (dir dirFS) joinEntry(op, name string) (string, error) {
+	if name == "." {
+		return dir.join(op, name)
+	}
+
+	parent, err := dir.join(op, path.Dir(name))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(parent, path.Base(name)), nil
+}
+
+func (dir dirFS) Open(name string) (fs.File, error) {
+	path, err := dir.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (dir dirFS) Stat(name string) (fs.FileInfo, error) {
+	path, err := dir.join("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Stat(path)
+}
+
+func (dir dirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	path, err := dir.join("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadDir(path)
+}
+
+func (dir dirFS) ReadFile(name string) ([]byte, error) {
+	path, err := dir.join("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+func (dir dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	path, err := dir.join("writefile", name)
+	if err != nil {
+		return err
+	}
+	if skip, err := writeGuard("WriteBytes", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	return os.WriteFile(path, data, perm)
+}
+
+func (dir dirFS) Create(name string) (io.WriteCloser, error) {
+	path, err := dir.join("create", name)
+	if err != nil {
+		return nil, err
+	}
+	if skip, err := writeGuard("WriteBytes", path, ""); skip {
+		if err != nil {
+			return nil, err
+		}
+		return nopWriteCloser{io.Discard}, nil
+	}
+	defer invalidateStats(path)
+
+	return os.Create(path)
+}
+
+func (dir dirFS) MkdirAll(name string, perm fs.FileMode) error {
+	path, err := dir.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+	if skip, err := writeGuard("EnsureDir", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	return os.MkdirAll(path, perm)
+}
+
+func (dir dirFS) Remove(name string) error {
+	path, err := dir.joinEntry("remove", name)
+	if err != nil {
+		return err
+	}
+	if skip, err := writeGuard("Remove", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	return os.Remove(path)
+}
+
+func (dir dirFS) RemoveAll(name string) error {
+	path, err := dir.joinEntry("removeall", name)
+	if err != nil {
+		return err
+	}
+	if skip, err := writeGuard("RemoveAll", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	return os.RemoveAll(path)
+}
+
+func (dir dirFS) Rename(oldname, newname string) error {
+	oldpath, err := dir.joinEntry("rename", oldname)
+	if err != nil {
+		return err
+	}
+	newpath, err := dir.joinEntry("rename", newname)
+	if err != nil {
+		return err
+	}
+	if skip, err := writeGuard("Move", oldpath, newpath); skip {
+		return err
+	}
+	defer invalidateStats(oldpath, newpath)
+
+	return os.Rename(oldpath, newpath)
+}
+
+// nopWriteCloser adds a no-op Close to a writer.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,116 @@
+package fs_go
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDirFS(t *testing.T) {
+	// Expect DirFS to behave like a standard read-only file system
+	t.Run("read", func(t *testing.T) {
+		path := "dir_fs_1"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"a.txt": "a", "dir/b.txt": "b"})
+
+		err := fstest.TestFS(DirFS(path), "a.txt", "dir/b.txt")
+		if err != nil {
+			t.Errorf("fstest.TestFS failed: %v", err)
+		}
+	})
+
+	// Expect files to be written, renamed and removed
+	t.Run("write", func(t *testing.T) {
+		path := "dir_fs_2"
+		defer os.RemoveAll(path)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+		fsys := DirFS(path)
+
+		if err := fsys.MkdirAll("a/b", 0755); err != nil {
+			t.Errorf("MkdirAll failed: %v", err)
+		}
+		if err := fsys.WriteFile("a/b/c.txt", []byte("content"), 0644); err != nil {
+			t.Errorf("WriteFile failed: %v", err)
+		}
+		writer, err := fsys.Create("a/d.txt")
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		writer.Write([]byte("created"))
+		if err := writer.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+		if err := fsys.Rename("a/b/c.txt", "c.txt"); err != nil {
+			t.Errorf("Rename failed: %v", err)
+		}
+
+		content, err := fsys.ReadFile("c.txt")
+		if err != nil || string(content) != "content" {
+			t.Errorf("Expected c.txt to contain %q, got %q (%v)", "content", content, err)
+		}
+		content, err = fsys.ReadFile("a/d.txt")
+		if err != nil || string(content) != "created" {
+			t.Errorf("Expected a/d.txt to contain %q, got %q (%v)", "created", content, err)
+		}
+
+		if err := fsys.Remove("c.txt"); err != nil {
+			t.Errorf("Remove failed: %v", err)
+		}
+		if err := fsys.RemoveAll("a"); err != nil {
+			t.Errorf("RemoveAll failed: %v", err)
+		}
+		entries, err := fsys.ReadDir(".")
+		if err != nil || len(entries) != 0 {
+			t.Errorf("Expected root to be empty, got %v (%v)", entries, err)
+		}
+	})
+
+	// Expect names escaping the root to be rejected
+	t.Run("invalid", func(t *testing.T) {
+		err := DirFS("dir_fs_3").WriteFile("../escape.txt", []byte("x"), 0644)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Expected fs.ErrInvalid, got %v", err)
+		}
+	})
+
+	// Expect symlinks to be resolved inside the root, and removed rather than their targets
+	t.Run("symlinks", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks need extra privileges on Windows")
+		}
+		path := "dir_fs_5"
+		defer os.RemoveAll(path)
+		writeTree(t, path, map[string]string{"root/a.txt": "inside", "secret.txt": "outside"})
+		os.Symlink("../secret.txt", filepath.Join(path, "root", "escape"))
+		fsys := DirFS(filepath.Join(path, "root"))
+
+		_, err := fsys.ReadFile("escape")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected fs.ErrNotExist, got %v", err)
+		}
+
+		if err := fsys.Remove("escape"); err != nil {
+			t.Errorf("Remove failed: %v", err)
+		}
+		if content, _ := os.ReadFile(filepath.Join(path, "secret.txt")); string(content) != "outside" {
+			t.Errorf("Expected the target to be kept, got %q", content)
+		}
+	})
+
+	// Expect writes to be refused in read-only mode
+	t.Run("read-only", func(t *testing.T) {
+		SetReadOnly(true)
+		defer SetReadOnly(false)
+
+		err := DirFS("dir_fs_4").WriteFile("a.txt", []byte("x"), 0644)
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Expected ErrReadOnly, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// GlobOptions configures GlobWithOptions.
+type GlobOptions struct {
+	// CaseInsensitive matches names regardless of case, even on case-sensitive file systems.
+	CaseInsensitive bool
+	// Ignore drops the matches it ignores, relative to the directory the pattern starts
+	// in, which is made of its leading components without special characters, like "logs"
+	// in "logs/*/app-*.log".
+	Ignore *Ignorer
+}
+
+// Glob returns the paths matching pattern, in the syntax of filepath.Match, like
+// "logs/*/app-*.log". The paths are sorted, and I/O errors such as unreadable directories
+// are ignored, as in filepath.Glob. Names inside archives are matched with patterns
+// like "bundle.zip!/config/*.json", and returned as archive paths.
+//
+// Example:
+//
+//	configs, err := Glob("~/.config/app/*.yaml")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func Glob(pattern string) ([]string, error) {
+	return GlobWithOptions(pattern, GlobOptions{})
+}
+
+// GlobWithOptions returns the paths matching pattern, optionally ignoring case.
+//
+// Example:
+//
+//	// Matches README.md, readme.MD and so on
+//	readmes, err := GlobWithOptions("docs/readme.md", GlobOptions{CaseInsensitive: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func GlobWithOptions(pattern string, opts GlobOptions) ([]string, error) {
+	pattern = normalizePath(pattern)
+
+	var matches []string
+	err := globWalk(pattern, opts, func(match string) bool {
+		matches = append(matches, match)
+		return true
+	})
+	if err != nil {
+		return nil, pathError("Glob", pattern, fmt.Errorf("Glob failed: %w", err))
+	}
+
+	return matches, nil
+}
+
+// globWalk calls yield with the paths matching pattern in lexical order, one path component
+// at a time, until yield returns false, skipping those ignored by the options. Only
+// malformed patterns and archives that can't be read are errors.
+func globWalk(pattern string, opts GlobOptions, yield func(string) bool) error {
+	if archive, name, ok := splitArchivePath(pattern); ok {
+		return globArchive(archive, name, opts, yield)
+	}
+
+	fold := opts.CaseInsensitive
+	volume := filepath.VolumeName(pattern)
+	rest := pattern[len(volume):]
+
+	// Relative patterns are matched against the working directory, but yield relative paths
+	prefix := volume
+	if rest != "" && os.IsPathSeparator(rest[0]) {
+		prefix += string(filepath.Separator)
+	}
+	components := splitComponents(rest)
+	if len(components) == 0 {
+		return nil
+	}
+	for i, component := range components {
+		if fold {
+			components[i] = strings.ToLower(component)
+		}
+		if _, err := filepath.Match(components[i], ""); err != nil {
+			return err
+		}
+	}
+
+	// Ignore patterns apply below the leading components without special characters
+	base := 0
+	for base < len(components) && !hasGlobMeta(components[base]) {
+		base++
+	}
+	if opts.Ignore != nil {
+		unfiltered := yield
+		yield = func(match string) bool {
+			rel := strings.Join(splitComponents(match[len(volume):])[base:], "/")
+			info, err := os.Lstat(match)
+			if opts.Ignore.Match(rel, err == nil && info.IsDir()) {
+				return true
+			}
+			return unfiltered(match)
+		}
+	}
+
+	globComponents(prefix, components, fold, yield)
+	return nil
+}
+
+// globComponents matches the remaining components below prefix and reports whether to go on.
+func globComponents(prefix string, components []string, fold bool, yield func(string) bool) bool {
+	if len(components) == 0 {
+		return yield(prefix)
+	}
+	component, rest := components[0], components[1:]
+
+	// Literal components don't need a directory listing, unless their case may differ
+	if component == "." || component == ".." || (!fold && !hasGlobMeta(component)) {
+		candidate := joinGlob(prefix, component)
+		if len(rest) == 0 {
+			if _, err := os.Lstat(candidate); err != nil {
+				return true
+			}
+		}
+		return globComponents(candidate, rest, fold, yield)
+	}
+
+	dir := prefix
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return true
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if fold {
+			name = strings.ToLower(name)
+		}
+		if ok, _ := filepath.Match(component, name); !ok {
+			continue
+		}
+		if !globComponents(joinGlob(prefix, entry.Name()), rest, fold, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasGlobMeta reports whether a path component contains any of the special characters of filepath.Match.
+func hasGlobMeta(component string) bool {
+	magic := `*?[`
+	if runtime.GOOS != "windows" {
+		magic = `*?[\`
+	}
+
+	return strings.ContainsAny(component, magic)
+}
+
+// joinGlob appends a name to a partial match, which may be empty or a bare root.
+func joinGlob(match, name string) string {
+	if match == "" {
+		return name
+	}
+	if os.IsPathSeparator(match[len(match)-1]) {
+		return match + name
+	}
+
+	return match + string(filepath.Separator) + name
+}
+
+// splitComponents splits a path at its separators, dropping empty components.
+func splitComponents(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r < 128 && os.IsPathSeparator(uint8(r)) })
+}
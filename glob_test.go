@@ -0,0 +1,59 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	path := "glob_1"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{
+		"a/one.log":   "",
+		"b/Two.LOG":   "",
+		"b/three.txt": "",
+	})
+
+	// Expect matches across directories, sorted
+	matches, err := Glob(filepath.Join(path, "*", "*.log"))
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	if !reflect.DeepEqual(matches, []string{filepath.Join(path, "a", "one.log")}) {
+		t.Errorf("Expected only one.log, got %v", matches)
+	}
+
+	// Expect case-insensitive matching to find names in any case
+	matches, err = GlobWithOptions(filepath.Join("GLOB_1", "*", "*.log"), GlobOptions{CaseInsensitive: true})
+	if err != nil {
+		t.Fatalf("GlobWithOptions failed: %v", err)
+	}
+	expected := []string{filepath.Join(path, "a", "one.log"), filepath.Join(path, "b", "Two.LOG")}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %v, got %v", expected, matches)
+	}
+
+	// Expect ignored matches to be dropped, relative to the directory the pattern starts in
+	matches, err = GlobWithOptions(filepath.Join(path, "*", "*"), GlobOptions{Ignore: NewIgnorer("b/", "*.log")})
+	if err != nil {
+		t.Fatalf("GlobWithOptions failed: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Expected every match to be ignored, got %v", matches)
+	}
+	matches, err = GlobWithOptions(filepath.Join(path, "*", "*"), GlobOptions{Ignore: NewIgnorer("/a")})
+	if err != nil {
+		t.Fatalf("GlobWithOptions failed: %v", err)
+	}
+	expected = []string{filepath.Join(path, "b", "Two.LOG"), filepath.Join(path, "b", "three.txt")}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %v, got %v", expected, matches)
+	}
+
+	// Expect malformed patterns to fail
+	if _, err := GlobWithOptions("[", GlobOptions{CaseInsensitive: true}); err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -1,3 +1,10 @@
 module github.com/frodi-karlsson/fs_go
 
 go 1.22.1
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.30.0
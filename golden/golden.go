@@ -0,0 +1,96 @@
+// Package golden compares test output against golden files in testdata.
+//
+// Golden files live at testdata/<name>.golden relative to the package under test.
+// Running the tests with -update rewrites them with the current output:
+//
+//	go test ./... -update
+package golden
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fs_go "github.com/frodi-karlsson/fs_go"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata with the current output")
+
+// Path returns the path of the golden file for name.
+func Path(name string) string {
+	return filepath.Join("testdata", filepath.FromSlash(name)+".golden")
+}
+
+// Golden fails the test if got differs from the content of the golden file for name.
+// With -update, the golden file is written with got instead, creating testdata if needed.
+//
+// Example:
+//
+//	func TestRender(t *testing.T) {
+//	    got, err := Render("input.md")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//
+//	    golden.Golden(t, "render", got)
+//	}
+func Golden(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	path := Path(name)
+	if *update {
+		err := fs_go.EnsureDir(filepath.Dir(path))
+		if err != nil {
+			t.Fatalf("Golden failed to create directory: %v", err)
+		}
+		err = fs_go.WriteBytes(path, got)
+		if err != nil {
+			t.Fatalf("Golden failed to update %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := fs_go.ReadBytes(path)
+	if errors.Is(err, fs_go.ErrNotExist) {
+		t.Fatalf("Golden file %s doesn't exist, run the tests with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("Golden failed to read %s: %v", path, err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("Output differs from %s, run the tests with -update to accept it:\n%s", path, diff(want, got))
+	}
+}
+
+// GoldenText is like Golden, but for string output.
+func GoldenText(t testing.TB, name, got string) {
+	t.Helper()
+
+	Golden(t, name, []byte(got))
+}
+
+// diff describes the first line where want and got differ.
+func diff(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var wantLine, gotLine string
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i >= len(wantLines) || i >= len(gotLines) || wantLine != gotLine {
+			return fmt.Sprintf("line %d:\n- %q\n+ %q", i+1, wantLine, gotLine)
+		}
+	}
+
+	return fmt.Sprintf("- %d bytes\n+ %d bytes", len(want), len(got))
+}
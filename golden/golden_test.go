@@ -0,0 +1,94 @@
+package golden
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// recorder captures test failures so assertions can be tested themselves.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, strings.TrimSpace(format))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.errors = append(r.errors, strings.TrimSpace(format))
+	r.fatal = true
+}
+
+func TestGolden(t *testing.T) {
+	defer os.RemoveAll("testdata")
+
+	// Expect a missing golden file to fail the test
+	t.Run("missing", func(t *testing.T) {
+		r := &recorder{TB: t}
+		Golden(r, "golden_1", []byte("output"))
+		if !r.fatal {
+			t.Errorf("Expected Golden to fail fatally")
+		}
+	})
+
+	// Expect -update to write the golden file and matching output to pass
+	t.Run("update", func(t *testing.T) {
+		*update = true
+		Golden(t, "nested/golden_2", []byte("line 1\nline 2\n"))
+		*update = false
+
+		content, err := os.ReadFile(Path("nested/golden_2"))
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+		if string(content) != "line 1\nline 2\n" {
+			t.Errorf("Expected golden file to contain the output, got %q", content)
+		}
+
+		r := &recorder{TB: t}
+		GoldenText(r, "nested/golden_2", "line 1\nline 2\n")
+		if len(r.errors) != 0 {
+			t.Errorf("Expected no failures, got %v", r.errors)
+		}
+	})
+
+	// Expect differing output to fail without touching the golden file
+	t.Run("differs", func(t *testing.T) {
+		r := &recorder{TB: t}
+		GoldenText(r, "nested/golden_2", "line 1\nchanged\n")
+		if len(r.errors) != 1 || r.fatal {
+			t.Errorf("Expected one non-fatal failure, got %v", r.errors)
+		}
+
+		content, err := os.ReadFile(Path("nested/golden_2"))
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+		if string(content) != "line 1\nline 2\n" {
+			t.Errorf("Expected golden file to be unchanged, got %q", content)
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	// Expect the first differing line to be reported
+	t.Run("line", func(t *testing.T) {
+		result := diff([]byte("a\nb\nc"), []byte("a\nx\nc"))
+		if result != "line 2:\n- \"b\"\n+ \"x\"" {
+			t.Errorf("Unexpected diff %q", result)
+		}
+	})
+
+	// Expect extra lines to be reported
+	t.Run("extra", func(t *testing.T) {
+		result := diff([]byte("a"), []byte("a\nb"))
+		if result != "line 2:\n- \"\"\n+ \"b\"" {
+			t.Errorf("Unexpected diff %q", result)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package fs_go
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// HashDirOptions configures HashDir and HasChangedSince.
+type HashDirOptions struct {
+	// Algorithm is the hash function for the files and the digest. Defaults to HashSHA256.
+	Algorithm HashAlgorithm
+	// Ignore skips the files and directories it matches, relative to the root.
+	Ignore *Ignorer
+	// Advice tells the operating system how the files are read. AdviceSequential speeds up
+	// hashing large files, and AdviceDontNeed keeps a scan from filling the page cache.
+	Advice Advice
+}
+
+// HashDir returns a digest of a directory tree that changes whenever a file is added,
+// removed, renamed or modified, or a symlink changes its target. It only depends on
+// relative paths and content, not on times, permissions or the order of directory
+// entries, so it is stable across checkouts and machines, which makes it a good cache key.
+//
+// Example:
+//
+//	key, err := HashDir("src", HashDirOptions{Ignore: NewIgnorer("*.log", "node_modules/")})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func HashDir(root string, opts HashDirOptions) (string, error) {
+	root = normalizePath(root)
+
+	h := opts.Algorithm.new()
+	if h == nil {
+		return "", pathError("HashDir", root, fmt.Errorf("HashDir failed: unknown hash algorithm %s", opts.Algorithm))
+	}
+
+	var entries []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if opts.Ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case d.Type().IsRegular():
+			digest, err := hashFileAdvised(path, opts.Algorithm, opts.Advice)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, rel+"\x00"+digest+"\n")
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, rel+"\x00->"+filepath.ToSlash(target)+"\n")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", pathError("HashDir", root, fmt.Errorf("HashDir failed to hash files: %w", err))
+	}
+
+	sort.Strings(entries)
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HasChangedSince reports whether the digest of a directory tree differs from a digest
+// returned by HashDir earlier, with the same options.
+//
+// Example:
+//
+//	changed, err := HasChangedSince("src", cachedKey, HashDirOptions{})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if !changed {
+//	    return // The build output is still valid
+//	}
+func HasChangedSince(root, previousDigest string, opts HashDirOptions) (bool, error) {
+	digest, err := HashDir(root, opts)
+	if err != nil {
+		return false, err
+	}
+
+	return digest != previousDigest, nil
+}
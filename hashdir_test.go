@@ -0,0 +1,54 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHashDir(t *testing.T) {
+	path := "hash_dir"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{
+		"a.txt":        "a",
+		"nested/b.txt": "b",
+		"debug.log":    "log",
+	})
+	opts := HashDirOptions{Ignore: NewIgnorer("*.log")}
+
+	digest, err := HashDir(path, opts)
+	if err != nil {
+		t.Fatalf("HashDir failed: %v", err)
+	}
+
+	// Expect the digest to ignore times and ignored files
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(path, "a.txt"), future, future)
+	os.WriteFile(filepath.Join(path, "debug.log"), []byte("more log"), 0644)
+	if changed, err := HasChangedSince(path, digest, opts); err != nil || changed {
+		t.Errorf("Expected no change, got %v, %v", changed, err)
+	}
+
+	// Expect renames to change the digest, even with the same content
+	os.Rename(filepath.Join(path, "nested", "b.txt"), filepath.Join(path, "nested", "c.txt"))
+	if changed, err := HasChangedSince(path, digest, opts); err != nil || !changed {
+		t.Errorf("Expected a change, got %v, %v", changed, err)
+	}
+	os.Rename(filepath.Join(path, "nested", "c.txt"), filepath.Join(path, "nested", "b.txt"))
+
+	// Expect modified content to change the digest
+	os.WriteFile(filepath.Join(path, "a.txt"), []byte("A"), 0644)
+	if changed, err := HasChangedSince(path, digest, opts); err != nil || !changed {
+		t.Errorf("Expected a change, got %v, %v", changed, err)
+	}
+
+	// Expect other algorithms to produce digests of their size
+	digest, err = HashDir(path, HashDirOptions{Algorithm: HashSHA512})
+	if err != nil {
+		t.Fatalf("HashDir failed: %v", err)
+	}
+	if len(digest) != 128 {
+		t.Errorf("Expected a SHA-512 digest, got %q", digest)
+	}
+}
@@ -0,0 +1,163 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// HashFiles hashes every regular file below root with algo, using workers workers.
+// See HashFilesWithOptions.
+//
+// Example:
+//
+//	digests, err := HashFiles("dist", HashSHA256, 0)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(digests["app.js"])
+func HashFiles(root string, algo HashAlgorithm, workers int) (map[string]string, error) {
+	return HashFilesWithOptions(root, HashFilesOptions{Algorithm: algo, Workers: workers})
+}
+
+// HashFilesOptions configures HashFilesWithOptions.
+type HashFilesOptions struct {
+	// Algorithm is the hash function. Defaults to HashSHA256.
+	Algorithm HashAlgorithm
+	// Workers is the number of files hashed concurrently. Defaults to one per CPU.
+	Workers int
+	// Progress receives the bytes of the files hashed. The total is only known once the
+	// whole tree was walked.
+	Progress Progress
+}
+
+// HashFilesWithOptions hashes every regular file below root and returns the hex-encoded
+// digests by slash-separated path relative to root. Files are hashed by a pool of workers
+// while the tree is still being walked, each streaming its file through the hash, so memory
+// use doesn't depend on file sizes.
+//
+// Example:
+//
+//	digests, err := HashFilesWithOptions("/srv/media", HashFilesOptions{Workers: 8, Progress: bar})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func HashFilesWithOptions(root string, opts HashFilesOptions) (digests map[string]string, err error) {
+	root = normalizePath(root)
+
+	progress := startProgress(opts.Progress, 0, -1)
+	defer func() { progress.finish(err) }()
+
+	files, err := hashTree(root, opts.Algorithm, opts.Workers, "", progress)
+	if err != nil {
+		return nil, pathError("HashFiles", root, fmt.Errorf("HashFiles failed to hash files: %w", err))
+	}
+
+	digests = make(map[string]string, len(files))
+	for _, file := range files {
+		digests[file.rel] = file.digest
+	}
+
+	return digests, nil
+}
+
+// hashedFile is a regular file hashed by hashTree.
+type hashedFile struct {
+	path   string
+	rel    string // Slash-separated path relative to the root
+	info   fs.FileInfo
+	digest string
+}
+
+// hashTree hashes the regular files below root, except exclude, with a pool of workers,
+// counting their sizes towards progress. The files are returned in no particular order.
+// After the first failure, no new files are hashed and the error is returned.
+func hashTree(root string, algo HashAlgorithm, workers int, exclude string, progress *progressCounter) ([]hashedFile, error) {
+	if algo.new() == nil {
+		return nil, fmt.Errorf("unknown hash algorithm %s", algo)
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var mu sync.Mutex
+	var files []hashedFile
+	var firstErr error
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	var wg sync.WaitGroup
+	queue := make(chan hashedFile, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range queue {
+				if failed() {
+					continue
+				}
+
+				digest, err := hashFileWith(file.path, algo)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("failed to hash %s: %w", file.rel, err)
+				}
+				if err == nil {
+					file.digest = digest
+					files = append(files, file)
+				}
+				mu.Unlock()
+				if err == nil {
+					progress.add(file.info.Size())
+				}
+			}
+		}()
+	}
+
+	var total int64
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if failed() {
+			return filepath.SkipAll
+		}
+		if !d.Type().IsRegular() || path == exclude {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		total += info.Size()
+		queue <- hashedFile{path: path, rel: filepath.ToSlash(rel), info: info}
+		return nil
+	})
+	if walkErr == nil {
+		progress.setTotal(total)
+	}
+	close(queue)
+	wg.Wait()
+
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return files, nil
+}
@@ -0,0 +1,82 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashFiles(t *testing.T) {
+	path := "hash_files_1"
+	defer os.RemoveAll(path)
+	files := map[string]string{}
+	for i := 0; i < 50; i++ {
+		files[fmt.Sprintf("dir%d/file%d.txt", i%5, i)] = fmt.Sprintf("content %d", i)
+	}
+	writeTree(t, path, files)
+
+	// Expect every file to be hashed, as the sequential equivalent would
+	t.Run("digests", func(t *testing.T) {
+		for _, workers := range []int{0, 1, 8} {
+			digests, err := HashFiles(path, HashSHA256, workers)
+			if err != nil {
+				t.Fatalf("HashFiles failed: %v", err)
+			}
+			if len(digests) != len(files) {
+				t.Errorf("Expected %d digests, got %d", len(files), len(digests))
+			}
+			for name := range files {
+				expected, _ := hashFile(filepath.Join(path, name))
+				if digests[name] != expected {
+					t.Errorf("Expected %s for %s with %d workers, got %s", expected, name, workers, digests[name])
+				}
+			}
+		}
+	})
+
+	// Expect unknown algorithms and missing roots to fail
+	t.Run("errors", func(t *testing.T) {
+		_, err := HashFiles(path, HashAlgorithm(99), 0)
+		if err == nil {
+			t.Error("Expected an error for an unknown algorithm")
+		}
+
+		_, err = HashFiles(filepath.Join(path, "missing"), HashSHA256, 0)
+		if err == nil {
+			t.Error("Expected an error for a missing root")
+		}
+	})
+}
+
+// benchmarkTree writes a tree of files to hash for benchmarks.
+func benchmarkTree(b *testing.B, root string) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 1<<14)
+	for i := 0; i < 64; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("dir%d", i%8))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatalf("os.MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d", i)), content, 0644); err != nil {
+			b.Fatalf("os.WriteFile failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkHashFiles(b *testing.B) {
+	path := "hash_files_bench"
+	defer os.RemoveAll(path)
+	benchmarkTree(b, path)
+
+	// One worker is the sequential equivalent, zero means one worker per CPU
+	for name, workers := range map[string]int{"sequential": 1, "concurrent": 0} {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := HashFiles(path, HashSHA256, workers); err != nil {
+					b.Fatalf("HashFiles failed: %v", err)
+				}
+			}
+		})
+	}
+}
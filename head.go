@@ -0,0 +1,84 @@
+package fs_go
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// HeadLines returns the first n lines of a file, without their line endings, reading no
+// further than needed. Files with fewer lines return all of them.
+//
+// Example:
+//
+//	header, err := HeadLines("export.csv", 1)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func HeadLines(path string, n int) (lines []string, err error) {
+	path = normalizePath(path)
+
+	var read int64
+	defer func() { observe("HeadLines", read, 0, err) }()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("HeadLines", path, fmt.Errorf("HeadLines failed to open file: %w", err))
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for len(lines) < n {
+		line, err := reader.ReadString('\n')
+		read += int64(len(line))
+		if err != nil && err != io.EOF {
+			return nil, pathError("HeadLines", path, fmt.Errorf("HeadLines failed to read line: %w", err))
+		}
+		if line == "" && err == io.EOF {
+			break
+		}
+
+		lines = append(lines, strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"))
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return lines, nil
+}
+
+// ReadFirstBytes returns the first n bytes of a file, or the whole file if it is shorter,
+// which is enough to sniff headers and magic numbers of huge files.
+//
+// Example:
+//
+//	magic, err := ReadFirstBytes("upload.bin", 4)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadFirstBytes(path string, n int) (content []byte, err error) {
+	path = normalizePath(path)
+	defer func() { observe("ReadFirstBytes", int64(len(content)), 0, err) }()
+
+	if n < 0 {
+		return nil, pathError("ReadFirstBytes", path, fmt.Errorf("ReadFirstBytes failed: invalid length %d", n))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("ReadFirstBytes", path, fmt.Errorf("ReadFirstBytes failed to open file: %w", err))
+	}
+	defer file.Close()
+
+	// A small file doesn't need a buffer of n bytes
+	content, err = io.ReadAll(io.LimitReader(file, int64(n)))
+	if err != nil {
+		return nil, pathError("ReadFirstBytes", path, fmt.Errorf("ReadFirstBytes failed to read content: %w", err))
+	}
+
+	return content, nil
+}
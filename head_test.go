@@ -0,0 +1,68 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestHeadLines(t *testing.T) {
+	path := "head_lines.txt"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("one\r\ntwo\nthree"), 0644)
+
+	// Expect only the requested lines, without line endings
+	lines, err := HeadLines(path, 2)
+	if err != nil {
+		t.Fatalf("HeadLines failed: %v", err)
+	}
+	if !reflect.DeepEqual(lines, []string{"one", "two"}) {
+		t.Errorf("Expected [one two], got %q", lines)
+	}
+
+	// Expect all lines, including an unterminated last one, if there are fewer than requested
+	lines, err = HeadLines(path, 10)
+	if err != nil {
+		t.Fatalf("HeadLines failed: %v", err)
+	}
+	if !reflect.DeepEqual(lines, []string{"one", "two", "three"}) {
+		t.Errorf("Expected [one two three], got %q", lines)
+	}
+}
+
+func TestReadFirstBytes(t *testing.T) {
+	path := "read_first_bytes.bin"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("PK\x03\x04rest"), 0644)
+
+	// Expect only the requested bytes
+	content, err := ReadFirstBytes(path, 4)
+	if err != nil {
+		t.Fatalf("ReadFirstBytes failed: %v", err)
+	}
+	if string(content) != "PK\x03\x04" {
+		t.Errorf("Expected zip magic, got %q", content)
+	}
+
+	// Expect the whole file if it is shorter than requested
+	content, err = ReadFirstBytes(path, 100)
+	if err != nil {
+		t.Fatalf("ReadFirstBytes failed: %v", err)
+	}
+	if len(content) != 8 {
+		t.Errorf("Expected 8 bytes, got %d", len(content))
+	}
+
+	// Expect an error for a negative length
+	_, err = ReadFirstBytes(path, -1)
+	if err == nil {
+		t.Errorf("Expected a negative length to fail")
+	}
+
+	// Expect an error for missing files
+	_, err = ReadFirstBytes("missing.bin", 4)
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("Expected ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package fs_go
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Ignorer decides which paths to skip using .gitignore-style patterns. Patterns are
+// matched against slash-separated paths relative to the directory they apply to.
+//
+// Supported syntax: blank lines and lines starting with "#" are skipped, "!" negates a
+// pattern, a trailing "/" matches directories only, a pattern containing a "/" elsewhere
+// is anchored to the root while others match at any depth, and "**" matches any number
+// of directories. The last matching pattern wins, and paths inside an ignored directory
+// are always ignored, as in Git.
+//
+// A nil Ignorer ignores nothing.
+type Ignorer struct {
+	rules    []ignoreRule
+	patterns []string // The lines the rules were parsed from, to recreate the Ignorer
+}
+
+// ignoreRule is a parsed ignore pattern.
+type ignoreRule struct {
+	segments []string // Pattern split at slashes, "**" matching any number of segments
+	negate   bool
+	dirOnly  bool
+	anchored bool // Whether the pattern matches from the root rather than at any depth
+}
+
+// NewIgnorer returns an Ignorer for patterns, one per line as in a .gitignore file.
+//
+// Example:
+//
+//	ignorer := NewIgnorer("*.log", "!important.log", "build/")
+func NewIgnorer(patterns ...string) *Ignorer {
+	ig := &Ignorer{}
+	for _, pattern := range patterns {
+		for _, line := range strings.Split(pattern, "\n") {
+			rule, ok := parseIgnoreRule(line)
+			if ok {
+				ig.rules = append(ig.rules, rule)
+				ig.patterns = append(ig.patterns, line)
+			}
+		}
+	}
+
+	return ig
+}
+
+// LoadIgnore reads patterns from an ignore file, such as .gitignore or .fsignore.
+// They apply to paths relative to the directory the file is in.
+//
+// Example:
+//
+//	ignorer, err := LoadIgnore("project/.gitignore")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = CopyDirWithOptions("project", "backup", CopyDirOptions{Ignore: ignorer})
+func LoadIgnore(path string) (*Ignorer, error) {
+	content, err := ReadText(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadIgnore failed to read file: %w", err)
+	}
+
+	return NewIgnorer(content), nil
+}
+
+// Match reports whether a path relative to the root is ignored. isDir tells whether it
+// is a directory, for patterns that only match directories.
+func (ig *Ignorer) Match(rel string, isDir bool) bool {
+	if ig == nil || len(ig.rules) == 0 {
+		return false
+	}
+
+	rel = strings.Trim(filepath.ToSlash(rel), "/")
+	if rel == "" || rel == "." {
+		return false
+	}
+
+	// Nothing inside an ignored directory can be included again
+	segments := strings.Split(rel, "/")
+	for i := 1; i < len(segments); i++ {
+		if ig.matchSegments(segments[:i], true) {
+			return true
+		}
+	}
+
+	return ig.matchSegments(segments, isDir)
+}
+
+// matchSegments applies the rules to a single path, the last matching rule winning.
+func (ig *Ignorer) matchSegments(segments []string, isDir bool) bool {
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.match(segments) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// parseIgnoreRule parses a single line of an ignore file.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		// Escapes a leading "#" or "!"
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	rule.segments = strings.Split(line, "/")
+
+	return rule, true
+}
+
+// match reports whether the rule matches a path.
+func (r ignoreRule) match(segments []string) bool {
+	if !r.anchored {
+		ok, _ := path.Match(r.segments[0], segments[len(segments)-1])
+		return ok
+	}
+
+	return matchSegments(r.segments, segments)
+}
+
+// matchSegments matches path segments against pattern segments, where "**" matches
+// zero or more segments.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+
+	return len(segments) == 0
+}
@@ -0,0 +1,128 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnorer(t *testing.T) {
+	ignorer := NewIgnorer(`
+# Comment
+*.log
+!keep.log
+build/
+/root.txt
+docs/**/*.tmp
+\#hash
+`)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"app.log", false, true},
+		{"sub/app.log", false, true},
+		{"keep.log", false, false},
+		{"sub/keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"sub/build/out.bin", false, true},
+		{"root.txt", false, true},
+		{"sub/root.txt", false, false},
+		{"docs/a.tmp", false, true},
+		{"docs/x/y/a.tmp", false, true},
+		{"other/a.tmp", false, false},
+		{"#hash", false, true},
+		{"main.go", false, false},
+	}
+
+	// Expect each path to be matched like Git does
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if ignored := ignorer.Match(test.path, test.isDir); ignored != test.ignored {
+				t.Errorf("Expected Match(%q, %v) to be %v", test.path, test.isDir, test.ignored)
+			}
+		})
+	}
+
+	// Expect files inside an ignored directory to stay ignored despite negation
+	t.Run("ignored parent", func(t *testing.T) {
+		ignorer := NewIgnorer("vendor/", "!vendor/keep.go")
+		if !ignorer.Match("vendor/keep.go", false) {
+			t.Error("Expected vendor/keep.go to be ignored")
+		}
+	})
+
+	// Expect a nil Ignorer to ignore nothing
+	t.Run("nil", func(t *testing.T) {
+		var ignorer *Ignorer
+		if ignorer.Match("anything", false) {
+			t.Error("Expected nothing to be ignored")
+		}
+	})
+}
+
+func TestLoadIgnore(t *testing.T) {
+	// Expect CopyDir to skip the paths an ignore file matches
+	t.Run("copy dir", func(t *testing.T) {
+		src := "load_ignore_1_src"
+		dst := "load_ignore_1_dst"
+		defer os.RemoveAll(src)
+		defer os.RemoveAll(dst)
+		writeTree(t, src, map[string]string{
+			".fsignore":      "*.log\nnode_modules/\n",
+			"main.go":        "",
+			"debug.log":      "",
+			"node_modules/x": "",
+		})
+
+		ignorer, err := LoadIgnore(filepath.Join(src, ".fsignore"))
+		if err != nil {
+			t.Fatalf("LoadIgnore failed: %v", err)
+		}
+		err = CopyDirWithOptions(src, dst, CopyDirOptions{Ignore: ignorer})
+		if err != nil {
+			t.Fatalf("CopyDir failed: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(dst, "main.go")); err != nil {
+			t.Errorf("Expected main.go to be copied, got %v", err)
+		}
+		for _, name := range []string{"debug.log", "node_modules"} {
+			if _, err := os.Stat(filepath.Join(dst, name)); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be skipped, got %v", name, err)
+			}
+		}
+	})
+
+	// Expect SyncDir to neither copy nor delete ignored paths
+	t.Run("sync dir", func(t *testing.T) {
+		src := "load_ignore_2_src"
+		dst := "load_ignore_2_dst"
+		defer os.RemoveAll(src)
+		defer os.RemoveAll(dst)
+		writeTree(t, src, map[string]string{"main.go": "", "debug.log": ""})
+		writeTree(t, dst, map[string]string{"local.log": ""})
+
+		result, err := SyncDirWithOptions(src, dst, SyncOptions{Delete: true, Ignore: NewIgnorer("*.log")})
+		if err != nil {
+			t.Fatalf("SyncDir failed: %v", err)
+		}
+		if len(result.Copied) != 1 || len(result.Deleted) != 0 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "local.log")); err != nil {
+			t.Errorf("Expected local.log to be kept, got %v", err)
+		}
+	})
+
+	// Expect a missing file to fail
+	t.Run("missing", func(t *testing.T) {
+		_, err := LoadIgnore("load_ignore_3")
+		if err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package fs_go
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseIni parses INI content into sections of keys and values.
+// Keys before the first section header are in the "" section. Lines starting with ; or #
+// are comments, and values may be wrapped in matching single or double quotes.
+func parseIni(content string) (map[string]map[string]string, error) {
+	sections := map[string]map[string]string{"": {}}
+	section := ""
+
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", i+1)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if sections[section] == nil {
+				sections[section] = make(map[string]string)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		sections[section][key] = value
+	}
+
+	return sections, nil
+}
+
+// decodeIni stores parsed INI sections in v, which must be a pointer to a struct,
+// a map[string]map[string]string, or a map[string]string.
+//
+// Struct fields are matched by their ini tag, their json tag, or case-insensitively by
+// name. Keys outside sections go to top-level fields, and sections go to struct fields.
+// Flat maps get section keys as "section.key".
+func decodeIni(sections map[string]map[string]string, v any) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("ini requires a non-nil pointer, got %T", v)
+	}
+	target = target.Elem()
+
+	switch dst := target.Addr().Interface().(type) {
+	case *map[string]map[string]string:
+		*dst = sections
+		return nil
+	case *map[string]string:
+		*dst = make(map[string]string)
+		for section, keys := range sections {
+			for key, value := range keys {
+				if section != "" {
+					key = section + "." + key
+				}
+				(*dst)[key] = value
+			}
+		}
+		return nil
+	}
+
+	if target.Kind() != reflect.Struct {
+		return fmt.Errorf("ini requires a struct or map, got %T", v)
+	}
+
+	for section, keys := range sections {
+		dst := target
+		if section != "" {
+			field, ok := iniField(target, section)
+			if !ok {
+				continue
+			}
+			if field.Kind() != reflect.Struct {
+				return fmt.Errorf("section [%s] must map to a struct field", section)
+			}
+			dst = field
+		}
+
+		for key, value := range keys {
+			field, ok := iniField(dst, key)
+			if !ok {
+				continue
+			}
+			err := setIniValue(field, value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s in [%s]: %w", key, section, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// iniField finds the settable struct field for an INI key or section name.
+func iniField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		for _, tag := range []string{"ini", "json"} {
+			tagName, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if tagName == name {
+				return v.Field(i), true
+			}
+		}
+		if strings.EqualFold(field.Name, name) {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// setIniValue parses an INI value into a field of a basic type.
+// Slices of strings are comma-separated.
+func setIniValue(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,556 @@
+package fs_go
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrIrreversible means an interrupted operation can't be rolled back, only resumed.
+var ErrIrreversible = errors.New("operation can't be rolled back")
+
+var journal atomic.Pointer[Journal]
+
+// SetJournal enables journal mode for the whole package. While enabled, operations that
+// take several steps record what they are about to do in j before doing it, so they can be
+// finished or undone after a crash with Resume or RollbackIncomplete. Passing nil disables
+// journal mode.
+//
+// The journaled operations are SyncDir, Transaction.Commit and Move across devices.
+// Operations that fail stay incomplete in the journal too, except for a Commit that
+// undid its own changes.
+//
+// Example:
+//
+//	j, err := OpenJournal("/var/lib/app/fs.journal")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer j.Close()
+//	if j.Incomplete() > 0 {
+//	    err = j.Resume()
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	}
+//	SetJournal(j)
+func SetJournal(j *Journal) {
+	journal.Store(j)
+}
+
+// activeJournal returns the journal of journal mode, or nil if it is disabled.
+func activeJournal() *Journal {
+	return journal.Load()
+}
+
+// Journal is a file recording the steps of operations as they are performed, appended one
+// JSON line at a time and synced to disk before each step. Once no recorded operation is
+// incomplete, the file is emptied. It is safe for concurrent use, and is created by OpenJournal.
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	nextID  int64
+	pending map[int64]*journalOp // Operations that began and aren't done yet
+}
+
+// journalOp is an operation recorded in a journal.
+type journalOp struct {
+	begin journalRecord
+	steps []journalRecord
+}
+
+// journalRecord is a line of a journal.
+type journalRecord struct {
+	ID    int64  `json:"id"`
+	Event string `json:"event"` // "begin", "step" or "done"
+
+	// Set when an operation begins
+	Op    string        `json:"op,omitempty"` // "Move", "SyncDir" or "Commit"
+	Src   string        `json:"src,omitempty"`
+	Dst   string        `json:"dst,omitempty"`
+	Sync  *journalSync  `json:"sync,omitempty"`
+	TxOps []journalTxOp `json:"tx_ops,omitempty"`
+
+	// Set when an operation takes a step
+	State string `json:"state,omitempty"` // "copied" for moves, "aside" or "applied" for commits
+	Index int    `json:"index,omitempty"` // Index of the transaction operation
+	Aside string `json:"aside,omitempty"` // Where a transaction moved the previous file aside
+}
+
+// journalSync records the options of a sync needed to resume it.
+type journalSync struct {
+	Delete   bool     `json:"delete,omitempty"`
+	Checksum bool     `json:"checksum,omitempty"`
+	Exclude  []string `json:"exclude,omitempty"`
+	Ignore   []string `json:"ignore,omitempty"`
+}
+
+// journalTxOp records a staged operation of a transaction, with normalized paths.
+type journalTxOp struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Dest string `json:"dest,omitempty"`
+	Tmp  string `json:"tmp,omitempty"` // Staged content of writes
+}
+
+// journalTxOps returns what a journal records of staged operations and their staged content.
+func journalTxOps(ops []txOp, staged []string) []journalTxOp {
+	records := make([]journalTxOp, len(ops))
+	for i, op := range ops {
+		records[i] = journalTxOp{Name: op.name, Path: normalizePath(op.path), Tmp: staged[i]}
+		if op.dest != "" {
+			records[i].Dest = normalizePath(op.dest)
+		}
+	}
+
+	return records
+}
+
+// op returns the staged operation.
+func (r journalTxOp) op() txOp {
+	return txOp{name: r.Name, path: r.Path, dest: r.Dest}
+}
+
+// OpenJournal opens the journal at path, creating it if it doesn't exist, and loads the
+// operations that a previous process left incomplete.
+//
+// Example:
+//
+//	j, err := OpenJournal("state/fs.journal")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer j.Close()
+func OpenJournal(path string) (*Journal, error) {
+	path = normalizePath(path)
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, pathError("OpenJournal", path, fmt.Errorf("OpenJournal failed to open file: %w", err))
+	}
+
+	j := &Journal{path: path, file: file, nextID: 1, pending: make(map[int64]*journalOp)}
+	err = j.load()
+	if err != nil {
+		file.Close()
+		return nil, pathError("OpenJournal", path, fmt.Errorf("OpenJournal failed to read journal: %w", err))
+	}
+
+	return j, nil
+}
+
+// load reads the records of the journal file. A last line that was only partly written
+// when the process crashed is cut off.
+func (j *Journal) load() error {
+	content, err := io.ReadAll(j.file)
+	if err != nil {
+		return err
+	}
+	if end := bytes.LastIndexByte(content, '\n') + 1; end < len(content) {
+		err = j.file.Truncate(int64(end))
+		if err != nil {
+			return err
+		}
+		content = content[:end]
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, len(content)+1)
+	for line := 1; scanner.Scan(); line++ {
+		var record journalRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return fmt.Errorf("malformed record on line %d: %w", line, err)
+		}
+
+		j.nextID = max(j.nextID, record.ID+1)
+		switch record.Event {
+		case "begin":
+			j.pending[record.ID] = &journalOp{begin: record}
+		case "step":
+			if op := j.pending[record.ID]; op != nil {
+				op.steps = append(op.steps, record)
+			}
+		case "done":
+			delete(j.pending, record.ID)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Close closes the journal file. Journal mode must be disabled before.
+func (j *Journal) Close() error {
+	err := j.file.Close()
+	if err != nil {
+		return pathError("Journal", j.path, fmt.Errorf("Close failed to close journal: %w", err))
+	}
+
+	return nil
+}
+
+// Incomplete returns the number of operations that began but aren't done, including
+// those currently running.
+func (j *Journal) Incomplete() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return len(j.pending)
+}
+
+// Resume finishes the operations a crash or failure left incomplete, in the order they
+// began. A Move or Commit carries on from its last recorded step, and a SyncDir runs
+// again with the same options. Operations that can't be finished stay in the journal,
+// and their errors are returned joined.
+//
+// Resume must be called before new operations are journaled, usually right after OpenJournal.
+func (j *Journal) Resume() error {
+	return j.recoverEach("Resume", func(op *journalOp) error {
+		switch op.begin.Op {
+		case "Move":
+			return resumeMove(op)
+		case "SyncDir":
+			return resumeSync(op)
+		case "Commit":
+			return resumeCommit(op)
+		default:
+			return fmt.Errorf("unknown operation %q", op.begin.Op)
+		}
+	})
+}
+
+// RollbackIncomplete undoes the operations a crash or failure left incomplete, newest
+// first. A Move removes what it copied, or moves the file back if its source is already
+// gone, and a Commit restores the files it replaced or deleted. A SyncDir overwrites files
+// in place and can't be rolled back, so it fails with ErrIrreversible and stays in the
+// journal for Resume. Errors are returned joined.
+//
+// RollbackIncomplete must be called before new operations are journaled, usually right
+// after OpenJournal.
+func (j *Journal) RollbackIncomplete() error {
+	return j.recoverEach("RollbackIncomplete", func(op *journalOp) error {
+		switch op.begin.Op {
+		case "Move":
+			return rollbackMove(op)
+		case "SyncDir":
+			return fmt.Errorf("SyncDir %s -> %s: %w", op.begin.Src, op.begin.Dst, ErrIrreversible)
+		case "Commit":
+			return rollbackCommit(op)
+		default:
+			return fmt.Errorf("unknown operation %q", op.begin.Op)
+		}
+	})
+}
+
+// recoverEach runs fn on each incomplete operation, oldest first for Resume and newest first
+// for RollbackIncomplete, and marks the ones it succeeds on as done.
+func (j *Journal) recoverEach(name string, fn func(op *journalOp) error) error {
+	if skip, err := writeGuard(name, j.path, ""); skip {
+		return err
+	}
+	defer invalidateStats(j.path)
+
+	// Recovering may journal new operations, so the lock isn't held while it runs
+	j.mu.Lock()
+	ids := make([]int64, 0, len(j.pending))
+	for id := range j.pending {
+		ids = append(ids, id)
+	}
+	ops := j.pending
+	j.mu.Unlock()
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	if name == "RollbackIncomplete" {
+		for a, b := 0, len(ids)-1; a < b; a, b = a+1, b-1 {
+			ids[a], ids[b] = ids[b], ids[a]
+		}
+	}
+
+	var errs []error
+	for _, id := range ids {
+		j.mu.Lock()
+		op := ops[id]
+		j.mu.Unlock()
+
+		err := fn(op)
+		if err == nil {
+			err = j.done(id)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s failed for %s of %s: %w", name, op.begin.Op, op.begin.Src, err))
+		}
+	}
+	if len(errs) > 0 {
+		return pathError(name, j.path, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// write appends a record to the journal file and syncs it to disk.
+func (j *Journal) write(record journalRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = j.file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return j.file.Sync()
+}
+
+// begin records that an operation is about to start and returns its ID.
+// A nil journal records nothing.
+func (j *Journal) begin(record journalRecord) (int64, error) {
+	if j == nil {
+		return 0, nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	record.ID, record.Event = j.nextID, "begin"
+	j.nextID++
+	err := j.write(record)
+	if err != nil {
+		return 0, fmt.Errorf("failed to journal %s: %w", record.Op, err)
+	}
+	j.pending[record.ID] = &journalOp{begin: record}
+
+	return record.ID, nil
+}
+
+// step records that an operation is about to take, or took, a step.
+func (j *Journal) step(id int64, record journalRecord) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	record.ID, record.Event = id, "step"
+	err := j.write(record)
+	if err != nil {
+		return fmt.Errorf("failed to journal step: %w", err)
+	}
+	if op := j.pending[id]; op != nil {
+		op.steps = append(op.steps, record)
+	}
+
+	return nil
+}
+
+// done records that an operation is complete, emptying the journal file if it was the
+// last incomplete one.
+func (j *Journal) done(id int64) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := j.write(journalRecord{ID: id, Event: "done"})
+	if err != nil {
+		return fmt.Errorf("failed to journal completion: %w", err)
+	}
+	delete(j.pending, id)
+	if len(j.pending) > 0 {
+		return nil
+	}
+
+	err = j.file.Truncate(0)
+	if err != nil {
+		return fmt.Errorf("failed to empty journal: %w", err)
+	}
+
+	return j.file.Sync()
+}
+
+// reached reports whether an operation recorded a step with state.
+func (op *journalOp) reached(state string) bool {
+	for _, step := range op.steps {
+		if step.State == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// txState returns the last recorded state of the transaction operation at index, and
+// where it moved the previous file aside, if it did.
+func (op *journalOp) txState(index int) (string, string) {
+	var state, aside string
+	for _, step := range op.steps {
+		if step.Index != index {
+			continue
+		}
+		state = step.State
+		if step.Aside != "" {
+			aside = step.Aside
+		}
+	}
+
+	return state, aside
+}
+
+// resumeMove finishes a move across devices.
+func resumeMove(op *journalOp) error {
+	src, dst := op.begin.Src, op.begin.Dst
+
+	if !op.reached("copied") {
+		info, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		err = copyForMove(src, dst, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+	}
+
+	err := os.Remove(src)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// rollbackMove undoes a move across devices.
+func rollbackMove(op *journalOp) error {
+	src, dst := op.begin.Src, op.begin.Dst
+
+	_, err := os.Lstat(src)
+	if os.IsNotExist(err) && op.reached("copied") {
+		// The source was already removed, so the copy is all that is left of it
+		return Move(dst, src)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(dst)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// journalSyncOptions returns what a journal records of sync options.
+func journalSyncOptions(opts SyncOptions) *journalSync {
+	sync := &journalSync{Delete: opts.Delete, Checksum: opts.Checksum, Exclude: opts.Exclude}
+	if opts.Ignore != nil {
+		sync.Ignore = opts.Ignore.patterns
+	}
+
+	return sync
+}
+
+// resumeSync runs an interrupted sync again with its recorded options.
+func resumeSync(op *journalOp) error {
+	opts := SyncOptions{}
+	if sync := op.begin.Sync; sync != nil {
+		opts.Delete, opts.Checksum, opts.Exclude = sync.Delete, sync.Checksum, sync.Exclude
+		if sync.Ignore != nil {
+			opts.Ignore = NewIgnorer(sync.Ignore...)
+		}
+	}
+
+	_, err := SyncDirWithOptions(op.begin.Src, op.begin.Dst, opts)
+	return err
+}
+
+// resumeCommit applies the operations of a transaction that weren't applied yet.
+func resumeCommit(op *journalOp) error {
+	var cleanup []string
+	for i, txOp := range op.begin.TxOps {
+		state, aside := op.txState(i)
+		if aside != "" {
+			cleanup = append(cleanup, filepath.Dir(aside))
+		}
+
+		var err error
+		switch {
+		case state == "applied":
+			continue
+		case state == "aside" && txOp.Name == "Write":
+			err = os.Rename(txOp.Tmp, txOp.Path)
+		case state == "aside" && txOp.Name == "Rename":
+			err = os.Rename(txOp.Path, txOp.Dest)
+		case state == "aside":
+			// A moved-aside file is already deleted
+		default:
+			var step txStep
+			step, err = applyTxOp(txOp.op(), txOp.Tmp, nil)
+			cleanup = append(cleanup, step.cleanup)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s %s: %w", txOp.Name, txOp.Path, err)
+		}
+	}
+
+	for _, dir := range cleanup {
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+	}
+
+	return nil
+}
+
+// rollbackCommit restores the state from before a transaction, newest operation first.
+// Files that are already gone are taken to be restored already, by a Commit that failed
+// while undoing its changes.
+func rollbackCommit(op *journalOp) error {
+	var errs []error
+	for i := len(op.begin.TxOps) - 1; i >= 0; i-- {
+		txOp := op.begin.TxOps[i]
+		state, aside := op.txState(i)
+
+		var err error
+		switch {
+		case state == "applied" && txOp.Name == "Write" && aside == "":
+			err = os.Remove(txOp.Path)
+		case state == "applied" && txOp.Name == "Rename":
+			err = os.Rename(txOp.Dest, txOp.Path)
+			if err == nil && aside != "" {
+				err = os.Rename(aside, txOp.Dest)
+			}
+		case state != "" && aside != "" && txOp.Name == "Rename":
+			err = os.Rename(aside, txOp.Dest)
+		case state != "" && aside != "":
+			err = os.Rename(aside, txOp.Path)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("failed to undo %s of %s: %w", txOp.Name, txOp.Path, err))
+			continue
+		}
+
+		if txOp.Tmp != "" {
+			os.Remove(txOp.Tmp)
+		}
+		if aside != "" {
+			os.Remove(filepath.Dir(aside))
+		}
+	}
+
+	return errors.Join(errs...)
+}
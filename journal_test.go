@@ -0,0 +1,249 @@
+package fs_go
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeJournal writes records to a journal file, as a process that crashed would have left it.
+func writeJournal(t *testing.T, path string, records ...journalRecord) {
+	t.Helper()
+
+	var content []byte
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+		content = append(append(content, line...), '\n')
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("os.WriteFile failed: %v", err)
+	}
+}
+
+// crashedCommit sets up a transaction writing a.txt and b.txt that crashed after moving
+// the old a.txt aside, and returns the path of its journal.
+func crashedCommit(t *testing.T, root string) string {
+	t.Helper()
+
+	aside := filepath.Join(root, ".a.txt.txn1", "a.txt")
+	writeTree(t, root, map[string]string{
+		".a.txt.txn1/a.txt": "old a",
+		".a.txt.tmp1":       "new a",
+		".b.txt.tmp1":       "new b",
+	})
+	path := filepath.Join(root, "fs.journal")
+	writeJournal(t, path,
+		journalRecord{ID: 1, Event: "begin", Op: "Commit", TxOps: []journalTxOp{
+			{Name: "Write", Path: filepath.Join(root, "a.txt"), Tmp: filepath.Join(root, ".a.txt.tmp1")},
+			{Name: "Write", Path: filepath.Join(root, "b.txt"), Tmp: filepath.Join(root, ".b.txt.tmp1")},
+		}},
+		journalRecord{ID: 1, Event: "step", State: "aside", Index: 0, Aside: aside},
+	)
+
+	return path
+}
+
+func TestJournal(t *testing.T) {
+	// Expect journaled operations that complete to leave nothing incomplete and the file empty
+	t.Run("complete", func(t *testing.T) {
+		root := "journal_1"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{"src/a.txt": "a", "b.txt": "old b"})
+
+		j, err := OpenJournal(filepath.Join(root, "fs.journal"))
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		defer j.Close()
+		SetJournal(j)
+		defer SetJournal(nil)
+
+		err = NewTransaction().Write(filepath.Join(root, "b.txt"), []byte("new b")).Commit()
+		if err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		_, err = SyncDir(filepath.Join(root, "src"), filepath.Join(root, "dst"))
+		if err != nil {
+			t.Fatalf("SyncDir failed: %v", err)
+		}
+
+		if j.Incomplete() != 0 {
+			t.Errorf("Expected no incomplete operations, got %d", j.Incomplete())
+		}
+		if info, _ := os.Stat(filepath.Join(root, "fs.journal")); info.Size() != 0 {
+			t.Errorf("Expected an empty journal, got %d bytes", info.Size())
+		}
+	})
+
+	// Expect a Commit that crashed midway to be finished by Resume
+	t.Run("resume commit", func(t *testing.T) {
+		root := "journal_2"
+		defer os.RemoveAll(root)
+		path := crashedCommit(t, root)
+
+		j, err := OpenJournal(path)
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		defer j.Close()
+		if j.Incomplete() != 1 {
+			t.Fatalf("Expected 1 incomplete operation, got %d", j.Incomplete())
+		}
+
+		err = j.Resume()
+		if err != nil {
+			t.Fatalf("Resume failed: %v", err)
+		}
+
+		if content, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(content) != "new a" {
+			t.Errorf("Expected new a, got %q", content)
+		}
+		if content, _ := os.ReadFile(filepath.Join(root, "b.txt")); string(content) != "new b" {
+			t.Errorf("Expected new b, got %q", content)
+		}
+		entries, _ := os.ReadDir(root)
+		if len(entries) != 3 {
+			t.Errorf("Expected a.txt, b.txt and the journal, got %v", entries)
+		}
+		if j.Incomplete() != 0 {
+			t.Errorf("Expected no incomplete operations, got %d", j.Incomplete())
+		}
+	})
+
+	// Expect a Commit that crashed midway to be undone by RollbackIncomplete
+	t.Run("rollback commit", func(t *testing.T) {
+		root := "journal_3"
+		defer os.RemoveAll(root)
+		path := crashedCommit(t, root)
+
+		j, err := OpenJournal(path)
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		defer j.Close()
+
+		err = j.RollbackIncomplete()
+		if err != nil {
+			t.Fatalf("RollbackIncomplete failed: %v", err)
+		}
+
+		if content, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(content) != "old a" {
+			t.Errorf("Expected old a, got %q", content)
+		}
+		entries, _ := os.ReadDir(root)
+		if len(entries) != 2 {
+			t.Errorf("Expected a.txt and the journal, got %v", entries)
+		}
+	})
+
+	// Expect a Move that crashed after copying to remove the source on Resume, and to
+	// remove the copy on RollbackIncomplete
+	t.Run("move", func(t *testing.T) {
+		root := "journal_4"
+		defer os.RemoveAll(root)
+		path := filepath.Join(root, "fs.journal")
+		src, dst := filepath.Join(root, "src.txt"), filepath.Join(root, "dst.txt")
+		records := []journalRecord{
+			{ID: 1, Event: "begin", Op: "Move", Src: src, Dst: dst},
+			{ID: 1, Event: "step", State: "copied"},
+		}
+
+		writeTree(t, root, map[string]string{"src.txt": "content", "dst.txt": "content"})
+		writeJournal(t, path, records...)
+		j, err := OpenJournal(path)
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		err = j.Resume()
+		j.Close()
+		if err != nil {
+			t.Fatalf("Resume failed: %v", err)
+		}
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Errorf("Expected the source to be removed, got %v", err)
+		}
+
+		writeTree(t, root, map[string]string{"src.txt": "content"})
+		writeJournal(t, path, records...)
+		j, err = OpenJournal(path)
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		err = j.RollbackIncomplete()
+		j.Close()
+		if err != nil {
+			t.Fatalf("RollbackIncomplete failed: %v", err)
+		}
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("Expected the copy to be removed, got %v", err)
+		}
+		if content, _ := os.ReadFile(src); string(content) != "content" {
+			t.Errorf("Expected the source to be kept, got %q", content)
+		}
+	})
+
+	// Expect a SyncDir to run again on Resume, and to stay incomplete on RollbackIncomplete
+	t.Run("sync", func(t *testing.T) {
+		root := "journal_5"
+		defer os.RemoveAll(root)
+		path := filepath.Join(root, "fs.journal")
+		src, dst := filepath.Join(root, "src"), filepath.Join(root, "dst")
+		writeTree(t, root, map[string]string{"src/a.txt": "a", "src/skip.log": "log", "dst/extra.txt": "extra"})
+		writeJournal(t, path, journalRecord{ID: 1, Event: "begin", Op: "SyncDir", Src: src, Dst: dst,
+			Sync: &journalSync{Delete: true, Ignore: []string{"*.log"}}})
+
+		j, err := OpenJournal(path)
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		defer j.Close()
+
+		err = j.RollbackIncomplete()
+		if !errors.Is(err, ErrIrreversible) {
+			t.Errorf("Expected ErrIrreversible, got %v", err)
+		}
+		if j.Incomplete() != 1 {
+			t.Errorf("Expected the sync to stay incomplete, got %d", j.Incomplete())
+		}
+
+		err = j.Resume()
+		if err != nil {
+			t.Fatalf("Resume failed: %v", err)
+		}
+		entries, _ := os.ReadDir(dst)
+		if len(entries) != 1 || entries[0].Name() != "a.txt" {
+			t.Errorf("Expected only a.txt in dst, got %v", entries)
+		}
+	})
+
+	// Expect a partly written last line to be cut off and the records before it kept
+	t.Run("torn record", func(t *testing.T) {
+		root := "journal_6"
+		defer os.RemoveAll(root)
+		os.Mkdir(root, 0755)
+		path := filepath.Join(root, "fs.journal")
+		writeJournal(t, path, journalRecord{ID: 1, Event: "begin", Op: "Move", Src: "a", Dst: "b"})
+		file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		file.WriteString(`{"id":1,"event":"st`)
+		file.Close()
+
+		j, err := OpenJournal(path)
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		defer j.Close()
+
+		if j.Incomplete() != 1 {
+			t.Errorf("Expected 1 incomplete operation, got %d", j.Incomplete())
+		}
+		content, _ := os.ReadFile(path)
+		if content[len(content)-1] != '\n' {
+			t.Errorf("Expected the torn line to be cut off, got %q", content)
+		}
+	})
+}
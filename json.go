@@ -0,0 +1,307 @@
+package fs_go
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// JsonError describes invalid JSON content with the position of the problem, so it can
+// be reported to users as "config.json:3:5: unknown field "nmae"".
+type JsonError struct {
+	Path   string
+	Field  string // The offending field, if known
+	Line   int    // 1-based line, 0 if unknown
+	Column int    // 1-based column in bytes, 0 if unknown
+	Err    error
+}
+
+func (e *JsonError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %v", e.Path, e.Line, e.Column, e.Err)
+}
+
+func (e *JsonError) Unwrap() error {
+	return e.Err
+}
+
+// ReadJsonStrict reads the content of a JSON file and unmarshals it into a struct like
+// ReadJson, but fails on fields the struct doesn't have and on content after the value.
+// Decoding errors wrap a *JsonError with the offending field and its line and column,
+// so config loaders can point users at typos instead of silently ignoring them.
+//
+// Example:
+//
+//	var config Config
+//	err := ReadJsonStrict("config.json", &config)
+//	var jsonErr *JsonError
+//	if errors.As(err, &jsonErr) {
+//	    fmt.Printf("line %d: %v\n", jsonErr.Line, jsonErr.Err)
+//	}
+func ReadJsonStrict[T any](path string, v *T) error {
+	content, err := ReadBytes(path)
+	if err != nil {
+		return fmt.Errorf("ReadJsonStrict failed to read file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(v)
+	if err == nil {
+		_, err = decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			err = errors.New("unexpected content after value")
+		}
+		return fmt.Errorf("ReadJsonStrict failed to decode: %w", jsonError(path, content, decoder.InputOffset(), "", err))
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		err = jsonError(path, content, syntaxErr.Offset, "", err)
+	case errors.As(err, &typeErr):
+		err = jsonError(path, content, typeErr.Offset, typeErr.Field, err)
+	default:
+		// Unknown fields are reported without a position, so look up the key
+		if field, ok := unknownJsonField(err); ok {
+			err = jsonError(path, content, jsonKeyOffset(content, field), field, err)
+		} else {
+			err = jsonError(path, content, -1, "", err)
+		}
+	}
+
+	return fmt.Errorf("ReadJsonStrict failed to decode: %w", err)
+}
+
+// jsonError creates a JsonError for the byte offset in content, or without a position
+// if offset is negative.
+func jsonError(path string, content []byte, offset int64, field string, err error) *JsonError {
+	jsonErr := &JsonError{Path: path, Field: field, Err: err}
+	if offset < 0 || offset > int64(len(content)) {
+		return jsonErr
+	}
+
+	before := content[:offset]
+	jsonErr.Line = bytes.Count(before, []byte("\n")) + 1
+	jsonErr.Column = len(before) - bytes.LastIndexByte(before, '\n')
+
+	return jsonErr
+}
+
+// unknownJsonField extracts the field name from an unknown field error of encoding/json,
+// which has no error type of its own.
+func unknownJsonField(err error) (string, bool) {
+	quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+
+	field, err := strconv.Unquote(quoted)
+	return field, err == nil
+}
+
+// jsonKeyOffset returns the offset of the first object key named key in content,
+// at any depth, or -1 if there is none.
+func jsonKeyOffset(content []byte, key string) int64 {
+	// Each frame is an open object or array, and tracks whether an object expects a key next
+	type frame struct {
+		object    bool
+		expectKey bool
+	}
+	var stack []frame
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return -1
+		}
+
+		var top *frame
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+
+		switch token {
+		case json.Delim('{'):
+			stack = append(stack, frame{object: true, expectKey: true})
+		case json.Delim('['):
+			stack = append(stack, frame{})
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 && stack[len(stack)-1].object {
+				stack[len(stack)-1].expectKey = true
+			}
+		default:
+			if top == nil || !top.object {
+				continue
+			}
+			if !top.expectKey {
+				top.expectKey = true
+				continue
+			}
+			top.expectKey = false
+			if token == key {
+				// The decoder stops right after the quoted key
+				quoted, _ := json.Marshal(key)
+				return decoder.InputOffset() - int64(len(quoted))
+			}
+		}
+	}
+}
+
+// UpdateJson reads a JSON file into a T, applies update to it, and atomically writes the
+// result back, keeping the file mode. A missing file starts out as the zero value and is
+// created with mode 0644. If update returns an error, the file is left untouched.
+// Updates through UpdateJson and MergeJson in the same process don't interleave.
+//
+// Example:
+//
+//	err := UpdateJson("settings.json", func(s *Settings) error {
+//	    s.Theme = "dark"
+//	    return nil
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func UpdateJson[T any](path string, update func(*T) error) error {
+	return Locked(path).Do(func(path string) error {
+		var v T
+		mode, err := readJsonForUpdate(path, &v, json.Unmarshal)
+		if err != nil {
+			return fmt.Errorf("UpdateJson failed to read file: %w", err)
+		}
+
+		err = update(&v)
+		if err != nil {
+			return fmt.Errorf("UpdateJson failed to update value: %w", err)
+		}
+
+		content, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("UpdateJson failed to marshal content: %w", err)
+		}
+
+		err = writeAtomic(path, content, mode, false)
+		if err != nil {
+			return fmt.Errorf("UpdateJson failed to write file: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// MergeJson applies a JSON merge patch (RFC 7386) to a JSON file and atomically writes
+// the result back. Objects in the patch are merged recursively, null values remove keys,
+// and everything else replaces the existing value. A missing file is treated as null.
+//
+// Example:
+//
+//	err := MergeJson("settings.json", map[string]any{
+//	    "theme":   "dark",
+//	    "plugins": map[string]any{"legacy": nil},
+//	})
+func MergeJson(path string, patch map[string]any) error {
+	// Round-trip the patch so typed values like structs merge like their JSON form
+	content, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("MergeJson failed to marshal patch: %w", err)
+	}
+	var normalized any
+	err = unmarshalNumbers(content, &normalized)
+	if err != nil {
+		return fmt.Errorf("MergeJson failed to unmarshal patch: %w", err)
+	}
+
+	return Locked(path).Do(func(path string) error {
+		var target any
+		mode, err := readJsonForUpdate(path, &target, unmarshalNumbers)
+		if err != nil {
+			return fmt.Errorf("MergeJson failed to read file: %w", err)
+		}
+
+		content, err := json.Marshal(mergePatch(target, normalized))
+		if err != nil {
+			return fmt.Errorf("MergeJson failed to marshal content: %w", err)
+		}
+
+		err = writeAtomic(path, content, mode, false)
+		if err != nil {
+			return fmt.Errorf("MergeJson failed to write file: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// readJsonForUpdate reads a JSON file into v with unmarshal and returns its mode.
+// A missing file leaves v untouched and reports mode 0644.
+func readJsonForUpdate(path string, v any, unmarshal func([]byte, any) error) (os.FileMode, error) {
+	info, err := os.Stat(normalizePath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0644, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	content, err := ReadBytes(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Mode().Perm(), unmarshal(content, v)
+}
+
+// unmarshalNumbers is json.Unmarshal, but decodes numbers into untyped values as
+// json.Number, so integers beyond the precision of float64 are written back unchanged.
+func unmarshalNumbers(content []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid content after top-level value")
+	}
+
+	return nil
+}
+
+// mergePatch applies an RFC 7386 merge patch to target and returns the result.
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
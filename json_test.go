@@ -0,0 +1,284 @@
+package fs_go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReadJsonStrict(t *testing.T) {
+	type config struct {
+		Name  string `json:"name"`
+		Inner struct {
+			Port int `json:"port"`
+		} `json:"inner"`
+	}
+
+	// Expect valid content to be decoded
+	t.Run("valid", func(t *testing.T) {
+		path := "read_json_strict_1.json"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte(`{"name": "a", "inner": {"port": 80}}`), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var v config
+		err := ReadJsonStrict(path, &v)
+		if err != nil {
+			t.Errorf("ReadJsonStrict failed: %v", err)
+		}
+
+		if v.Name != "a" || v.Inner.Port != 80 {
+			t.Errorf("Expected name a and port 80, got %+v", v)
+		}
+	})
+
+	// Expect unknown fields to be reported with their position
+	t.Run("unknown field", func(t *testing.T) {
+		path := "read_json_strict_2.json"
+		defer os.Remove(path)
+
+		content := "{\n  \"name\": \"a\",\n  \"inner\": {\n    \"prot\": 80\n  }\n}"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var v config
+		err := ReadJsonStrict(path, &v)
+
+		var jsonErr *JsonError
+		if !errors.As(err, &jsonErr) {
+			t.Fatalf("Expected a JsonError, got %v", err)
+		}
+
+		if jsonErr.Field != "prot" || jsonErr.Line != 4 || jsonErr.Column != 5 {
+			t.Errorf("Expected prot at 4:5, got %s at %d:%d", jsonErr.Field, jsonErr.Line, jsonErr.Column)
+		}
+	})
+
+	// Expect type errors to be reported with the field and line
+	t.Run("type error", func(t *testing.T) {
+		path := "read_json_strict_3.json"
+		defer os.Remove(path)
+
+		content := "{\n  \"name\": 1\n}"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var v config
+		err := ReadJsonStrict(path, &v)
+
+		var jsonErr *JsonError
+		if !errors.As(err, &jsonErr) {
+			t.Fatalf("Expected a JsonError, got %v", err)
+		}
+
+		if jsonErr.Field != "name" || jsonErr.Line != 2 {
+			t.Errorf("Expected name on line 2, got %s on line %d", jsonErr.Field, jsonErr.Line)
+		}
+	})
+
+	// Expect content after the value to be rejected
+	t.Run("trailing content", func(t *testing.T) {
+		path := "read_json_strict_4.json"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte(`{"name": "a"} {}`), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var v config
+		err := ReadJsonStrict(path, &v)
+		if err == nil {
+			t.Errorf("Expected ReadJsonStrict to fail for trailing content")
+		}
+	})
+}
+
+func TestUpdateJson(t *testing.T) {
+	type settings struct {
+		Theme string `json:"theme"`
+		Count int    `json:"count"`
+	}
+
+	// Expect the update to be applied and the mode kept
+	t.Run("update", func(t *testing.T) {
+		path := "update_json_1.json"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte(`{"theme": "light", "count": 1}`), 0600); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := UpdateJson(path, func(s *settings) error {
+			s.Theme = "dark"
+			s.Count++
+			return nil
+		})
+		if err != nil {
+			t.Errorf("UpdateJson failed: %v", err)
+		}
+
+		var v settings
+		if err := ReadJson(path, &v); err != nil {
+			t.Fatalf("ReadJson failed: %v", err)
+		}
+
+		if v != (settings{Theme: "dark", Count: 2}) {
+			t.Errorf("Expected updated settings, got %+v", v)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("Expected file mode to be 0600, got %#o", info.Mode().Perm())
+		}
+	})
+
+	// Expect the file to be left untouched if the update fails
+	t.Run("failed update", func(t *testing.T) {
+		path := "update_json_2.json"
+		defer os.Remove(path)
+
+		original := `{"theme":"light","count":1}`
+		if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := UpdateJson(path, func(s *settings) error {
+			s.Theme = "dark"
+			return errors.New("invalid")
+		})
+		if err == nil {
+			t.Errorf("Expected UpdateJson to fail")
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != original {
+			t.Errorf("Expected file to be unchanged, got %s", content)
+		}
+	})
+
+	// Expect a missing file to be created from the zero value
+	t.Run("missing file", func(t *testing.T) {
+		path := "update_json_3.json"
+		defer os.Remove(path)
+
+		err := UpdateJson(path, func(s *settings) error {
+			s.Count = 1
+			return nil
+		})
+		if err != nil {
+			t.Errorf("UpdateJson failed: %v", err)
+		}
+
+		var v settings
+		if err := ReadJson(path, &v); err != nil {
+			t.Fatalf("ReadJson failed: %v", err)
+		}
+
+		if v.Count != 1 {
+			t.Errorf("Expected count to be 1, got %d", v.Count)
+		}
+	})
+
+	// Expect untyped numbers to be decoded as float64, like json.Unmarshal does
+	t.Run("untyped numbers", func(t *testing.T) {
+		path := "update_json_4.json"
+		defer os.Remove(path)
+		if err := os.WriteFile(path, []byte(`{"count": 1}`), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := UpdateJson(path, func(m *map[string]any) error {
+			count, ok := (*m)["count"].(float64)
+			if !ok {
+				return fmt.Errorf("count is a %T", (*m)["count"])
+			}
+			(*m)["count"] = count + 1
+			return nil
+		})
+		if err != nil {
+			t.Errorf("UpdateJson failed: %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != `{"count":2}` {
+			t.Errorf("Expected count to be 2, got %s", content)
+		}
+	})
+}
+
+func TestMergeJson(t *testing.T) {
+	// Expect integers beyond the precision of float64 to be written back unchanged
+	t.Run("large integers", func(t *testing.T) {
+		path := "merge_json_large.json"
+		defer os.Remove(path)
+		if err := os.WriteFile(path, []byte(`{"id": 1234567890123456789, "n": 1}`), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := MergeJson(path, map[string]any{"n": int64(9007199254740993)})
+		if err != nil {
+			t.Fatalf("MergeJson failed: %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if !strings.Contains(string(content), `"id":1234567890123456789`) || !strings.Contains(string(content), `"n":9007199254740993`) {
+			t.Errorf("Expected the integers to be kept exactly, got %s", content)
+		}
+	})
+
+	// Expect the merge patch to be applied as described in RFC 7386
+	t.Run("merge patch", func(t *testing.T) {
+		path := "merge_json_1.json"
+		defer os.Remove(path)
+
+		original := `{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "text"}`
+		if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := MergeJson(path, map[string]any{
+			"title":       "Hello!",
+			"phoneNumber": "+01-123-456-7890",
+			"author":      map[string]any{"familyName": nil},
+			"tags":        []string{"example"},
+		})
+		if err != nil {
+			t.Errorf("MergeJson failed: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		var actual, expected any
+		if err := json.Unmarshal(content, &actual); err != nil {
+			t.Fatalf("json.Unmarshal failed: %v", err)
+		}
+		err = json.Unmarshal([]byte(`{"title": "Hello!", "author": {"givenName": "John"}, "tags": ["example"], "content": "text", "phoneNumber": "+01-123-456-7890"}`), &expected)
+		if err != nil {
+			t.Fatalf("json.Unmarshal failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v, got %v", expected, actual)
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package fs_go
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ReadJsonLines reads a JSON Lines (NDJSON) file, with one JSON value per line, into a slice.
+// Blank lines are skipped. Decoding errors wrap a *JsonError with the line of the value.
+//
+// Example:
+//
+//	events, err := ReadJsonLines[Event]("events.ndjson")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadJsonLines[T any](path string) ([]T, error) {
+	lines, err := JsonLines[T](path)
+	if err != nil {
+		return nil, fmt.Errorf("ReadJsonLines failed to open file: %w", err)
+	}
+	defer lines.Close()
+
+	var values []T
+	for lines.Next() {
+		values = append(values, lines.Value())
+	}
+
+	err = lines.Err()
+	if err != nil {
+		return nil, fmt.Errorf("ReadJsonLines failed to read file: %w", err)
+	}
+
+	return values, nil
+}
+
+// JsonLinesIterator iterates over the values of a JSON Lines file. It is created by JsonLines.
+type JsonLinesIterator[T any] struct {
+	path   string
+	file   *os.File
+	reader *bufio.Reader
+	value  T
+	line   int
+	err    error
+}
+
+// JsonLines opens a JSON Lines file for iteration, one value at a time, so files larger
+// than memory can be processed. The iterator must be closed when done.
+//
+// Example:
+//
+//	lines, err := JsonLines[Event]("events.ndjson")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer lines.Close()
+//
+//	for lines.Next() {
+//	    process(lines.Value())
+//	}
+//	if err := lines.Err(); err != nil {
+//	    fmt.Println(err)
+//	}
+func JsonLines[T any](path string) (*JsonLinesIterator[T], error) {
+	file, err := os.Open(normalizePath(path))
+	if err != nil {
+		return nil, fmt.Errorf("JsonLines failed to open file: %w", err)
+	}
+
+	return &JsonLinesIterator[T]{path: path, file: file, reader: bufio.NewReader(file)}, nil
+}
+
+// Next decodes the next value and reports whether there was one.
+// It returns false at the end of the file or on error; check Err to tell them apart.
+func (it *JsonLinesIterator[T]) Next() bool {
+	for it.err == nil {
+		content, err := it.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			it.err = err
+			return false
+		}
+		if len(content) == 0 && err == io.EOF {
+			return false
+		}
+		it.line++
+
+		content = bytes.TrimSpace(content)
+		if len(content) == 0 {
+			continue
+		}
+
+		var value T
+		decodeErr := json.Unmarshal(content, &value)
+		if decodeErr != nil {
+			column := 1
+			var syntaxErr *json.SyntaxError
+			if errors.As(decodeErr, &syntaxErr) {
+				column = int(syntaxErr.Offset)
+			}
+			it.err = &JsonError{Path: it.path, Line: it.line, Column: column, Err: decodeErr}
+			return false
+		}
+
+		it.value = value
+		return true
+	}
+
+	return false
+}
+
+// Value returns the current value.
+func (it *JsonLinesIterator[T]) Value() T {
+	return it.value
+}
+
+// Line returns the line number of the current value, starting at 1.
+func (it *JsonLinesIterator[T]) Line() int {
+	return it.line
+}
+
+// Err returns the first error encountered while reading or decoding, if any.
+func (it *JsonLinesIterator[T]) Err() error {
+	return it.err
+}
+
+// Close closes the underlying file.
+func (it *JsonLinesIterator[T]) Close() error {
+	return it.file.Close()
+}
+
+// WriteJsonLines writes values to a file as JSON Lines, one value per line.
+//
+// Example:
+//
+//	err := WriteJsonLines("events.ndjson", events)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteJsonLines[T any](path string, values []T) error {
+	content, err := encodeJsonLines(values)
+	if err != nil {
+		return fmt.Errorf("WriteJsonLines failed to marshal content: %w", err)
+	}
+
+	err = WriteBytes(path, content)
+	if err != nil {
+		return fmt.Errorf("WriteJsonLines failed to write content to file: %w", err)
+	}
+
+	return nil
+}
+
+// AppendJsonLines appends values to a JSON Lines file, one value per line.
+// The file is created with mode 0644 if it doesn't exist.
+func AppendJsonLines[T any](path string, values []T) error {
+	content, err := encodeJsonLines(values)
+	if err != nil {
+		return fmt.Errorf("AppendJsonLines failed to marshal content: %w", err)
+	}
+
+	err = EnsureFile(path)
+	if err != nil {
+		return fmt.Errorf("AppendJsonLines failed to ensure file: %w", err)
+	}
+
+	err = AppendBytes(path, content)
+	if err != nil {
+		return fmt.Errorf("AppendJsonLines failed to append content to file: %w", err)
+	}
+
+	return nil
+}
+
+// encodeJsonLines encodes values as JSON Lines. Every line ends with a newline.
+func encodeJsonLines[T any](values []T) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, value := range values {
+		err := encoder.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestJsonLines(t *testing.T) {
+	type event struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// Expect values to round-trip through write, append and read
+	t.Run("round trip", func(t *testing.T) {
+		path := "json_lines_1.ndjson"
+		defer os.Remove(path)
+
+		err := WriteJsonLines(path, []event{{1, "a"}, {2, "b"}})
+		if err != nil {
+			t.Errorf("WriteJsonLines failed: %v", err)
+		}
+
+		err = AppendJsonLines(path, []event{{3, "c"}})
+		if err != nil {
+			t.Errorf("AppendJsonLines failed: %v", err)
+		}
+
+		values, err := ReadJsonLines[event](path)
+		if err != nil {
+			t.Errorf("ReadJsonLines failed: %v", err)
+		}
+
+		expected := []event{{1, "a"}, {2, "b"}, {3, "c"}}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("Expected %v, got %v", expected, values)
+		}
+	})
+
+	// Expect appending to a missing file to create it
+	t.Run("append creates file", func(t *testing.T) {
+		path := "json_lines_2.ndjson"
+		defer os.Remove(path)
+
+		err := AppendJsonLines(path, []event{{1, "a"}})
+		if err != nil {
+			t.Errorf("AppendJsonLines failed: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "{\"id\":1,\"name\":\"a\"}\n" {
+			t.Errorf("Expected one line, got %q", content)
+		}
+	})
+
+	// Expect blank lines to be skipped and errors to report the line
+	t.Run("iterator", func(t *testing.T) {
+		path := "json_lines_3.ndjson"
+		defer os.Remove(path)
+
+		content := "{\"id\":1}\n\n{\"id\":2}\r\n{\"id\":\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		lines, err := JsonLines[event](path)
+		if err != nil {
+			t.Fatalf("JsonLines failed: %v", err)
+		}
+		defer lines.Close()
+
+		var ids, numbers []int
+		for lines.Next() {
+			ids = append(ids, lines.Value().ID)
+			numbers = append(numbers, lines.Line())
+		}
+
+		if !reflect.DeepEqual(ids, []int{1, 2}) || !reflect.DeepEqual(numbers, []int{1, 3}) {
+			t.Errorf("Expected ids [1 2] on lines [1 3], got %v on %v", ids, numbers)
+		}
+
+		var jsonErr *JsonError
+		if !errors.As(lines.Err(), &jsonErr) || jsonErr.Line != 4 {
+			t.Errorf("Expected a JsonError on line 4, got %v", lines.Err())
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LineEnding identifies a line ending style.
+type LineEnding int
+
+const (
+	LineEndingAsIs LineEnding = iota // Leave line endings unchanged
+	LineEndingLF                     // Unix style, \n
+	LineEndingCRLF                   // Windows style, \r\n
+)
+
+// String returns the escaped line ending, or "as-is".
+func (l LineEnding) String() string {
+	switch l {
+	case LineEndingAsIs:
+		return "as-is"
+	case LineEndingLF:
+		return `\n`
+	case LineEndingCRLF:
+		return `\r\n`
+	default:
+		return fmt.Sprintf("LineEnding(%d)", int(l))
+	}
+}
+
+// WriteTextOptions configures WriteTextWithOptions.
+type WriteTextOptions struct {
+	// LineEnding converts all line endings, including lone \r, to one style.
+	LineEnding LineEnding
+	// TrailingNewline adds a line ending at the end of the content if it is missing.
+	// Empty content stays empty.
+	TrailingNewline bool
+	// Mode is the file mode used if the file is created. Defaults to 0644.
+	Mode os.FileMode
+}
+
+// WriteTextWithOptions writes a string to a file, normalizing its line endings first.
+// This keeps generated files stable across platforms.
+//
+// Example:
+//
+//	err := WriteTextWithOptions("gen/models.go", source, WriteTextOptions{
+//	    LineEnding:      LineEndingLF,
+//	    TrailingNewline: true,
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteTextWithOptions(path, content string, opts WriteTextOptions) error {
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+
+	content = normalizeLineEndings(content, opts.LineEnding)
+	if opts.TrailingNewline && content != "" && !strings.HasSuffix(content, "\n") && !strings.HasSuffix(content, "\r") {
+		if opts.LineEnding == LineEndingCRLF {
+			content += "\r\n"
+		} else {
+			content += "\n"
+		}
+	}
+
+	err := WriteBytesWithMode(path, []byte(content), opts.Mode)
+	if err != nil {
+		return fmt.Errorf("WriteText failed to write content to file: %w", err)
+	}
+
+	return nil
+}
+
+// NormalizeLineEndings converts the line endings of a text file to one style in place.
+// The file is replaced atomically and keeps its mode. Files that already use the style
+// are left untouched.
+func NormalizeLineEndings(path string, ending LineEnding) error {
+	info, err := os.Stat(normalizePath(path))
+	if err != nil {
+		return fmt.Errorf("NormalizeLineEndings failed to get file stat: %w", err)
+	}
+
+	content, err := ReadText(path)
+	if err != nil {
+		return fmt.Errorf("NormalizeLineEndings failed to read file: %w", err)
+	}
+
+	normalized := normalizeLineEndings(content, ending)
+	if normalized == content {
+		return nil
+	}
+
+	err = writeAtomic(normalizePath(path), []byte(normalized), info.Mode().Perm(), false)
+	if err != nil {
+		return fmt.Errorf("NormalizeLineEndings failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeLineEndings converts \r\n, \r and \n to the given line ending.
+func normalizeLineEndings(content string, ending LineEnding) string {
+	if ending == LineEndingAsIs {
+		return content
+	}
+
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	if ending == LineEndingCRLF {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+
+	return content
+}
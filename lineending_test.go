@@ -0,0 +1,90 @@
+package fs_go
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestNormalizeLineEndings(t *testing.T) {
+	// Expect mixed line endings to be converted to one style
+	t.Run("convert", func(t *testing.T) {
+		path := "normalize_line_endings_1.txt"
+		defer os.Remove(path)
+
+		cases := map[LineEnding]string{
+			LineEndingLF:   "a\nb\nc\nd",
+			LineEndingCRLF: "a\r\nb\r\nc\r\nd",
+		}
+		for ending, expected := range cases {
+			if err := os.WriteFile(path, []byte("a\r\nb\rc\nd"), 0600); err != nil {
+				t.Fatalf("os.WriteFile failed: %v", err)
+			}
+
+			err := NormalizeLineEndings(path, ending)
+			if err != nil {
+				t.Errorf("NormalizeLineEndings failed: %v", err)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("os.ReadFile failed: %v", err)
+			}
+
+			if string(content) != expected {
+				t.Errorf("Expected %q for %s, got %q", expected, ending, content)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("os.Stat failed: %v", err)
+			}
+
+			if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+				t.Errorf("Expected file mode to be 0600, got %#o", info.Mode().Perm())
+			}
+		}
+	})
+}
+
+func TestWriteTextWithOptions(t *testing.T) {
+	// Expect line endings to be forced and a trailing newline added
+	t.Run("line ending and trailing newline", func(t *testing.T) {
+		path := "write_text_with_options_1.txt"
+		defer os.Remove(path)
+
+		err := WriteTextWithOptions(path, "a\nb", WriteTextOptions{LineEnding: LineEndingCRLF, TrailingNewline: true})
+		if err != nil {
+			t.Errorf("WriteTextWithOptions failed: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "a\r\nb\r\n" {
+			t.Errorf("Expected %q, got %q", "a\r\nb\r\n", content)
+		}
+	})
+
+	// Expect content to be written unchanged by default
+	t.Run("defaults", func(t *testing.T) {
+		path := "write_text_with_options_2.txt"
+		defer os.Remove(path)
+
+		err := WriteTextWithOptions(path, "a\r\nb", WriteTextOptions{})
+		if err != nil {
+			t.Errorf("WriteTextWithOptions failed: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "a\r\nb" {
+			t.Errorf("Expected %q, got %q", "a\r\nb", content)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrMarkerNotFound means a marker line that an edit is anchored to doesn't exist in the file.
+var ErrMarkerNotFound = errors.New("marker not found")
+
+// EnsureLineInFile appends line to a file unless a line equal to it already exists, and
+// reports whether the file changed. Lines are compared without surrounding whitespace.
+// A missing file is created with mode 0644.
+//
+// Example:
+//
+//	_, err := EnsureLineInFile("~/.bashrc", `export PATH="$HOME/.app/bin:$PATH"`)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func EnsureLineInFile(path, line string) (bool, error) {
+	return editLines("EnsureLineInFile", path, true, func(lines []string) ([]string, error) {
+		if findLine(lines, line, 0) >= 0 {
+			return lines, nil
+		}
+		return append(lines, line), nil
+	})
+}
+
+// InsertAfterMarker inserts block, which may span several lines, after the first line equal
+// to marker, and reports whether the file changed. If the block already follows the marker,
+// the file is left alone, so the call is safe to repeat. It fails with ErrMarkerNotFound if
+// there is no such line.
+//
+// Example:
+//
+//	_, err := InsertAfterMarker("/etc/hosts", "# BEGIN app", "127.0.0.1 app.local")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func InsertAfterMarker(path, marker, block string) (bool, error) {
+	return editLines("InsertAfterMarker", path, false, func(lines []string) ([]string, error) {
+		at := findLine(lines, marker, 0)
+		if at < 0 {
+			return nil, fmt.Errorf("%q: %w", marker, ErrMarkerNotFound)
+		}
+
+		blockLines := splitLines(block)
+		if hasLinesAt(lines, blockLines, at+1) {
+			return lines, nil
+		}
+
+		edited := append([]string{}, lines[:at+1]...)
+		edited = append(edited, blockLines...)
+		return append(edited, lines[at+1:]...), nil
+	})
+}
+
+// RemoveBlockBetweenMarkers removes the first line equal to begin, the next line equal to
+// end, and everything between them, and reports whether the file changed. A file without
+// the begin marker is left alone, while a begin marker without an end marker fails with
+// ErrMarkerNotFound.
+//
+// Example:
+//
+//	_, err := RemoveBlockBetweenMarkers("/etc/hosts", "# BEGIN app", "# END app")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func RemoveBlockBetweenMarkers(path, begin, end string) (bool, error) {
+	return editLines("RemoveBlockBetweenMarkers", path, false, func(lines []string) ([]string, error) {
+		start := findLine(lines, begin, 0)
+		if start < 0 {
+			return lines, nil
+		}
+		stop := findLine(lines, end, start+1)
+		if stop < 0 {
+			return nil, fmt.Errorf("%q: %w", end, ErrMarkerNotFound)
+		}
+
+		return append(lines[:start:start], lines[stop+1:]...), nil
+	})
+}
+
+// editLines rewrites a file with the lines returned by edit, atomically and only if they
+// differ. The line ending style of the file is kept, and the file ends with a line ending.
+// If create is set, a missing file is treated as empty.
+func editLines(op, path string, create bool, edit func(lines []string) ([]string, error)) (bool, error) {
+	path = normalizePath(path)
+
+	var mode os.FileMode = 0644
+	content, err := os.ReadFile(path)
+	if err != nil && !(create && os.IsNotExist(err)) {
+		return false, pathError(op, path, fmt.Errorf("%s failed to read file: %w", op, err))
+	}
+	if err == nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, pathError(op, path, fmt.Errorf("%s failed to get file stat: %w", op, err))
+		}
+		mode = info.Mode().Perm()
+	}
+
+	text := string(content)
+	lines := splitLines(text)
+	edited, err := edit(append([]string{}, lines...))
+	if err != nil {
+		return false, pathError(op, path, fmt.Errorf("%s failed: %w", op, err))
+	}
+	if equalLines(lines, edited) {
+		return false, nil
+	}
+
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+	}
+	output := ""
+	if len(edited) > 0 {
+		output = strings.Join(edited, newline) + newline
+	}
+
+	err = writeAtomic(path, []byte(output), mode, false)
+	if err != nil {
+		return false, pathError(op, path, fmt.Errorf("%s failed to write file: %w", op, err))
+	}
+
+	return true, nil
+}
+
+// splitLines splits text into lines without their line endings.
+// A trailing line ending doesn't start another line.
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines
+}
+
+// findLine returns the index of the first line at or after from that equals target,
+// ignoring surrounding whitespace, or -1.
+func findLine(lines []string, target string, from int) int {
+	target = strings.TrimSpace(target)
+	for i := from; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == target {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// hasLinesAt reports whether lines contains want starting at index at.
+func hasLinesAt(lines, want []string, at int) bool {
+	if at+len(want) > len(lines) {
+		return false
+	}
+
+	return equalLines(lines[at:at+len(want)], want)
+}
+
+// equalLines reports whether two lists of lines are equal.
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
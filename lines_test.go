@@ -0,0 +1,90 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestEnsureLineInFile(t *testing.T) {
+	path := "ensure_line.txt"
+	defer os.Remove(path)
+
+	// Expect a missing file to be created with the line
+	changed, err := EnsureLineInFile(path, "export A=1")
+	if err != nil || !changed {
+		t.Fatalf("Expected the file to change, got %v, %v", changed, err)
+	}
+
+	// Expect an existing line not to be added again
+	changed, err = EnsureLineInFile(path, "  export A=1")
+	if err != nil || changed {
+		t.Errorf("Expected no change, got %v, %v", changed, err)
+	}
+
+	// Expect new lines to be appended with the file's line endings
+	os.WriteFile(path, []byte("a\r\nb"), 0644)
+	_, err = EnsureLineInFile(path, "c")
+	if err != nil {
+		t.Fatalf("EnsureLineInFile failed: %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "a\r\nb\r\nc\r\n" {
+		t.Errorf("Expected CRLF lines, got %q", content)
+	}
+}
+
+func TestInsertAfterMarker(t *testing.T) {
+	path := "insert_after_marker.txt"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("127.0.0.1 localhost\n# BEGIN app\n# END app\n"), 0644)
+
+	// Expect the block to be inserted after the marker
+	changed, err := InsertAfterMarker(path, "# BEGIN app", "10.0.0.1 app\n10.0.0.2 db\n")
+	if err != nil || !changed {
+		t.Fatalf("Expected the file to change, got %v, %v", changed, err)
+	}
+	expected := "127.0.0.1 localhost\n# BEGIN app\n10.0.0.1 app\n10.0.0.2 db\n# END app\n"
+	if content, _ := os.ReadFile(path); string(content) != expected {
+		t.Errorf("Expected %q, got %q", expected, content)
+	}
+
+	// Expect repeating the insert to change nothing
+	changed, err = InsertAfterMarker(path, "# BEGIN app", "10.0.0.1 app\n10.0.0.2 db\n")
+	if err != nil || changed {
+		t.Errorf("Expected no change, got %v, %v", changed, err)
+	}
+
+	// Expect a missing marker to fail
+	_, err = InsertAfterMarker(path, "# BEGIN other", "x")
+	if !errors.Is(err, ErrMarkerNotFound) {
+		t.Errorf("Expected ErrMarkerNotFound, got %v", err)
+	}
+}
+
+func TestRemoveBlockBetweenMarkers(t *testing.T) {
+	path := "remove_block.txt"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("keep\n# BEGIN app\nx\ny\n# END app\nkeep too\n"), 0644)
+
+	// Expect the markers and everything between them to be removed
+	changed, err := RemoveBlockBetweenMarkers(path, "# BEGIN app", "# END app")
+	if err != nil || !changed {
+		t.Fatalf("Expected the file to change, got %v, %v", changed, err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "keep\nkeep too\n" {
+		t.Errorf("Expected the block to be removed, got %q", content)
+	}
+
+	// Expect a file without the block to be left alone
+	changed, err = RemoveBlockBetweenMarkers(path, "# BEGIN app", "# END app")
+	if err != nil || changed {
+		t.Errorf("Expected no change, got %v, %v", changed, err)
+	}
+
+	// Expect an unterminated block to fail
+	os.WriteFile(path, []byte("# BEGIN app\nx\n"), 0644)
+	_, err = RemoveBlockBetweenMarkers(path, "# BEGIN app", "# END app")
+	if !errors.Is(err, ErrMarkerNotFound) {
+		t.Errorf("Expected ErrMarkerNotFound, got %v", err)
+	}
+}
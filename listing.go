@@ -0,0 +1,268 @@
+package fs_go
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Field is a column of a listing written by WriteListing.
+type Field int
+
+const (
+	FieldPath    Field = iota // Slash-separated path relative to the listed root
+	FieldSize                 // Size in bytes
+	FieldModTime              // Modification time in RFC 3339 format
+	FieldHash                 // Hex-encoded SHA-256 digest of the content
+)
+
+// String returns the name of the field, as used in JSON keys and CSV headers.
+func (f Field) String() string {
+	switch f {
+	case FieldPath:
+		return "path"
+	case FieldSize:
+		return "size"
+	case FieldModTime:
+		return "mtime"
+	case FieldHash:
+		return "hash"
+	default:
+		return fmt.Sprintf("Field(%d)", int(f))
+	}
+}
+
+// WriteListing walks root recursively and writes a listing of its files to dst,
+// one entry at a time, so the listing never has to fit in memory.
+//
+// FormatCSV writes a header row followed by one row per file. FormatJSON writes a
+// JSON array with one object per line. Other formats are not supported.
+// Hashing is only done if FieldHash is requested. Symlinks and special files are listed
+// without following them, with an empty hash. The listing is written to a temporary file
+// that replaces dst once complete, and is left out of the listing if dst is below root.
+//
+// Example:
+//
+//	err := WriteListing("/data", "inventory.csv", FormatCSV, []Field{FieldPath, FieldSize, FieldHash})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteListing(root, dst string, format Format, fields []Field) error {
+	root, dst = normalizePath(root), normalizePath(dst)
+
+	if format != FormatJSON && format != FormatCSV {
+		return fmt.Errorf("WriteListing failed: unsupported format %s", format)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("WriteListing failed: no fields requested")
+	}
+	if skip, err := writeGuard("WriteListing", dst, ""); skip {
+		return err
+	}
+	defer invalidateStats(dst)
+
+	file, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to create file: %w", err)
+	}
+	tmp := file.Name()
+	defer func() {
+		file.Close()
+		os.Remove(tmp) // Fails harmlessly once renamed into place
+	}()
+
+	// Neither the listing nor its temporary file are listed if they are below root
+	skip := make(map[string]bool)
+	for _, path := range []string{dst, tmp} {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("WriteListing failed to resolve path: %w", err)
+		}
+		skip[abs] = true
+	}
+
+	buffered := bufio.NewWriter(file)
+	var writer listingWriter
+	if format == FormatCSV {
+		writer = &csvListingWriter{csv: csv.NewWriter(buffered)}
+	} else {
+		writer = &jsonListingWriter{w: buffered}
+	}
+
+	err = writer.begin(fields)
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to write header: %w", err)
+	}
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if abs, err := filepath.Abs(path); err == nil && skip[abs] {
+			return nil
+		}
+
+		values, err := listingValues(root, path, d, fields)
+		if err != nil {
+			return err
+		}
+
+		return writer.entry(fields, values)
+	})
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to walk directory: %w", err)
+	}
+
+	err = writer.end()
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to write footer: %w", err)
+	}
+
+	err = buffered.Flush()
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to flush file: %w", err)
+	}
+
+	// Temporary files are private, but the listing gets the mode os.Create would give it
+	err = file.Chmod(0644)
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to set file mode: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to close file: %w", err)
+	}
+	err = os.Rename(tmp, dst)
+	if err != nil {
+		return fmt.Errorf("WriteListing failed to rename temporary file: %w", err)
+	}
+
+	return nil
+}
+
+// listingValues returns the requested fields of a single file, formatted as strings.
+func listingValues(root, path string, d fs.DirEntry, fields []Field) ([]string, error) {
+	var info fs.FileInfo
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		if (field == FieldSize || field == FieldModTime) && info == nil {
+			var err error
+			info, err = d.Info()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		switch field {
+		case FieldPath:
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = filepath.ToSlash(rel)
+		case FieldSize:
+			values[i] = strconv.FormatInt(info.Size(), 10)
+		case FieldModTime:
+			values[i] = info.ModTime().UTC().Format(time.RFC3339Nano)
+		case FieldHash:
+			// Hashing would follow symlinks, and block on named pipes
+			if !d.Type().IsRegular() {
+				continue
+			}
+			hash, err := hashFile(path)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = hash
+		default:
+			return nil, fmt.Errorf("unknown field %s", field)
+		}
+	}
+
+	return values, nil
+}
+
+// listingWriter encodes a stream of listing entries.
+type listingWriter interface {
+	begin(fields []Field) error
+	entry(fields []Field, values []string) error
+	end() error
+}
+
+type csvListingWriter struct {
+	csv *csv.Writer
+}
+
+func (w *csvListingWriter) begin(fields []Field) error {
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.String()
+	}
+
+	return w.csv.Write(header)
+}
+
+func (w *csvListingWriter) entry(fields []Field, values []string) error {
+	return w.csv.Write(values)
+}
+
+func (w *csvListingWriter) end() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+type jsonListingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (w *jsonListingWriter) begin(fields []Field) error {
+	_, err := io.WriteString(w.w, "[")
+	return err
+}
+
+func (w *jsonListingWriter) entry(fields []Field, values []string) error {
+	separator := "\n"
+	if w.written {
+		separator = ",\n"
+	}
+	w.written = true
+
+	line := []byte(separator + "{")
+	for i, field := range fields {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		key, _ := json.Marshal(field.String())
+		line = append(line, key...)
+		line = append(line, ':')
+
+		// Sizes are numbers, everything else is a string
+		if field == FieldSize {
+			line = append(line, values[i]...)
+		} else {
+			value, _ := json.Marshal(values[i])
+			line = append(line, value...)
+		}
+	}
+	line = append(line, '}')
+
+	_, err := w.w.Write(line)
+	return err
+}
+
+func (w *jsonListingWriter) end() error {
+	_, err := io.WriteString(w.w, "\n]\n")
+	return err
+}
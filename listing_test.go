@@ -0,0 +1,162 @@
+package fs_go
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestWriteListing(t *testing.T) {
+	// Expect a CSV listing with a header and one row per file
+	t.Run("csv", func(t *testing.T) {
+		path := "write_listing_1"
+		defer os.RemoveAll(path)
+
+		root := filepath.Join(path, "root")
+		writeTree(t, root, map[string]string{
+			"a.txt":        "a",
+			"nested/b.txt": "bb",
+		})
+
+		dst := filepath.Join(path, "listing.csv")
+		err := WriteListing(root, dst, FormatCSV, []Field{FieldPath, FieldSize})
+		if err != nil {
+			t.Errorf("WriteListing failed: %v", err)
+		}
+
+		var records [][]string
+		err = ReadAuto(dst, &records)
+		if err != nil {
+			t.Errorf("ReadAuto failed: %v", err)
+		}
+
+		expected := [][]string{{"path", "size"}, {"a.txt", "1"}, {"nested/b.txt", "2"}}
+		if !reflect.DeepEqual(records, expected) {
+			t.Errorf("Expected %v, got %v", expected, records)
+		}
+	})
+
+	// Expect a JSON listing to be a valid array including hashes
+	t.Run("json", func(t *testing.T) {
+		path := "write_listing_2"
+		defer os.RemoveAll(path)
+
+		root := filepath.Join(path, "root")
+		writeTree(t, root, map[string]string{
+			"a.txt": "test content",
+			"b.txt": "",
+		})
+
+		dst := filepath.Join(path, "listing.json")
+		err := WriteListing(root, dst, FormatJSON, []Field{FieldPath, FieldSize, FieldModTime, FieldHash})
+		if err != nil {
+			t.Errorf("WriteListing failed: %v", err)
+		}
+
+		content, err := os.ReadFile(dst)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		var entries []struct {
+			Path  string `json:"path"`
+			Size  int64  `json:"size"`
+			MTime string `json:"mtime"`
+			Hash  string `json:"hash"`
+		}
+		err = json.Unmarshal(content, &entries)
+		if err != nil {
+			t.Fatalf("json.Unmarshal failed: %v\n%s", err, content)
+		}
+
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+		if len(entries) != 2 || entries[0].Path != "a.txt" || entries[0].Size != 12 {
+			t.Errorf("Unexpected entries %+v", entries)
+		}
+
+		// SHA-256 of the empty string
+		if entries[1].Hash != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+			t.Errorf("Unexpected hash %s", entries[1].Hash)
+		}
+
+		if entries[0].MTime == "" {
+			t.Errorf("Expected mtime to be set")
+		}
+	})
+
+	// Expect an empty tree to produce an empty array
+	t.Run("empty json", func(t *testing.T) {
+		path := "write_listing_3"
+		defer os.RemoveAll(path)
+
+		if err := os.MkdirAll(filepath.Join(path, "root"), 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed: %v", err)
+		}
+
+		dst := filepath.Join(path, "listing.json")
+		err := WriteListing(filepath.Join(path, "root"), dst, FormatJSON, []Field{FieldPath})
+		if err != nil {
+			t.Errorf("WriteListing failed: %v", err)
+		}
+
+		var entries []map[string]any
+		if err := ReadJson(dst, &entries); err != nil {
+			t.Errorf("ReadJson failed: %v", err)
+		}
+
+		if len(entries) != 0 {
+			t.Errorf("Expected no entries, got %v", entries)
+		}
+	})
+
+	// Expect a listing below its root to leave itself out, and symlinks not to be followed
+	t.Run("inside root", func(t *testing.T) {
+		root := "write_listing_4"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{"a.txt": "a"})
+		if err := os.Symlink("missing.txt", filepath.Join(root, "dangling")); err != nil {
+			t.Skipf("os.Symlink failed: %v", err)
+		}
+
+		dst := filepath.Join(root, "listing.csv")
+		for i := 0; i < 2; i++ {
+			err := WriteListing(root, dst, FormatCSV, []Field{FieldPath, FieldHash})
+			if err != nil {
+				t.Fatalf("WriteListing failed: %v", err)
+			}
+		}
+
+		var records [][]string
+		if err := ReadAuto(dst, &records); err != nil {
+			t.Fatalf("ReadAuto failed: %v", err)
+		}
+		if len(records) != 3 || records[1][0] != "a.txt" || records[2][0] != "dangling" || records[2][1] != "" {
+			t.Errorf("Expected a.txt and an unhashed dangling symlink, got %v", records)
+		}
+	})
+
+	// Expect a failed listing to leave no partial file behind
+	t.Run("failure", func(t *testing.T) {
+		dst := "write_listing_5.csv"
+		defer os.Remove(dst)
+
+		err := WriteListing("write_listing_missing", dst, FormatCSV, []Field{FieldPath})
+		if err == nil {
+			t.Errorf("Expected WriteListing to fail for a missing root")
+		}
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("Expected no listing to be left behind, got %v", err)
+		}
+	})
+
+	// Expect unsupported formats to be rejected
+	t.Run("unsupported format", func(t *testing.T) {
+		err := WriteListing(".", "write_listing.yaml", FormatYAML, []Field{FieldPath})
+		if err == nil {
+			t.Errorf("Expected WriteListing to fail for yaml")
+		}
+	})
+}
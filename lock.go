@@ -0,0 +1,75 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+)
+
+// RangeLock is a lock held on a byte range of a file.
+// It must be released with Unlock.
+type RangeLock struct {
+	file   *os.File
+	off    int64
+	length int64
+}
+
+// LockRange locks length bytes of the file at path, starting at off, blocking until
+// the lock can be acquired. A length of 0 extends the lock to the end of the file,
+// including any bytes appended later.
+//
+// Exclusive locks conflict with every other lock on an overlapping range, while shared
+// locks only conflict with exclusive ones. The locks are advisory: they coordinate
+// processes that use LockRange, but don't prevent plain reads or writes.
+//
+// Locks are implemented with fcntl on Unix and LockFileEx on Windows. On Linux,
+// open file description locks are used, so locks taken by the same process through
+// separate LockRange calls conflict with each other like they would across processes.
+//
+// Example:
+//
+//	lock, err := LockRange("records.db", 128, 64, true)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer lock.Unlock()
+func LockRange(path string, off, length int64, exclusive bool) (*RangeLock, error) {
+	path = normalizePath(path)
+
+	if off < 0 || length < 0 {
+		return nil, fmt.Errorf("LockRange failed: invalid range %d+%d", off, length)
+	}
+
+	// Exclusive fcntl locks require the file to be open for writing
+	flag := os.O_RDONLY
+	if exclusive {
+		flag = os.O_RDWR
+	}
+
+	file, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, fmt.Errorf("LockRange failed to open file: %w", err)
+	}
+
+	err = lockRange(file, off, length, exclusive)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("LockRange failed to lock range: %w", err)
+	}
+
+	return &RangeLock{file: file, off: off, length: length}, nil
+}
+
+// Unlock releases the lock.
+func (l *RangeLock) Unlock() error {
+	err := unlockRange(l.file, l.off, l.length)
+	closeErr := l.file.Close()
+	if err != nil {
+		return fmt.Errorf("Unlock failed to unlock range: %w", err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("Unlock failed to close file: %w", closeErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package fs_go
+
+import "golang.org/x/sys/unix"
+
+// Open file description locks are owned by the open file rather than the process.
+const (
+	setLock     = unix.F_OFD_SETLK
+	setLockWait = unix.F_OFD_SETLKW
+)
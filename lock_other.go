@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package fs_go
+
+import (
+	"errors"
+	"os"
+)
+
+func lockRange(file *os.File, off, length int64, exclusive bool) error {
+	return errors.ErrUnsupported
+}
+
+func unlockRange(file *os.File, off, length int64) error {
+	return errors.ErrUnsupported
+}
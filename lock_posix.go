@@ -0,0 +1,10 @@
+//go:build unix && !linux
+
+package fs_go
+
+import "golang.org/x/sys/unix"
+
+const (
+	setLock     = unix.F_SETLK
+	setLockWait = unix.F_SETLKW
+)
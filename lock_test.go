@@ -0,0 +1,91 @@
+package fs_go
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestLockRange(t *testing.T) {
+	// Expect non-overlapping exclusive locks to be held at the same time
+	t.Run("non-overlapping ranges", func(t *testing.T) {
+		path := "lock_range_1.db"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, make([]byte, 64), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		first, err := LockRange(path, 0, 32, true)
+		if err != nil {
+			t.Fatalf("LockRange failed: %v", err)
+		}
+		defer first.Unlock()
+
+		second, err := LockRange(path, 32, 32, true)
+		if err != nil {
+			t.Fatalf("LockRange failed: %v", err)
+		}
+
+		if err := second.Unlock(); err != nil {
+			t.Errorf("Unlock failed: %v", err)
+		}
+	})
+
+	// Expect an overlapping exclusive lock to block until the first is released
+	t.Run("overlapping ranges", func(t *testing.T) {
+		// Classic fcntl locks are per process, so only Linux and Windows locks conflict here
+		if runtime.GOOS != "linux" && runtime.GOOS != "windows" {
+			t.Skip("fcntl locks don't conflict within a single process")
+		}
+
+		path := "lock_range_2.db"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, make([]byte, 64), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		first, err := LockRange(path, 0, 32, true)
+		if err != nil {
+			t.Fatalf("LockRange failed: %v", err)
+		}
+
+		acquired := make(chan *RangeLock)
+		go func() {
+			lock, err := LockRange(path, 16, 32, true)
+			if err != nil {
+				t.Errorf("LockRange failed: %v", err)
+			}
+			acquired <- lock
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatalf("Expected overlapping lock to block")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if err := first.Unlock(); err != nil {
+			t.Errorf("Unlock failed: %v", err)
+		}
+
+		select {
+		case lock := <-acquired:
+			if lock != nil {
+				lock.Unlock()
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected overlapping lock to be acquired after unlock")
+		}
+	})
+
+	// Expect negative ranges to be rejected
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := LockRange("lock_range_3.db", -1, 10, false)
+		if err == nil {
+			t.Errorf("Expected LockRange to fail for a negative offset")
+		}
+	})
+}
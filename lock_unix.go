@@ -0,0 +1,39 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func lockRange(file *os.File, off, length int64, exclusive bool) error {
+	lockType := int16(unix.F_RDLCK)
+	if exclusive {
+		lockType = unix.F_WRLCK
+	}
+
+	return fcntlRange(file, setLockWait, lockType, off, length)
+}
+
+func unlockRange(file *os.File, off, length int64) error {
+	return fcntlRange(file, setLock, unix.F_UNLCK, off, length)
+}
+
+func fcntlRange(file *os.File, cmd int, lockType int16, off, length int64) error {
+	lock := unix.Flock_t{
+		Type:   lockType,
+		Whence: io.SeekStart,
+		Start:  off,
+		Len:    length,
+	}
+
+	for {
+		err := unix.FcntlFlock(file.Fd(), cmd, &lock)
+		if err != unix.EINTR {
+			return err
+		}
+	}
+}
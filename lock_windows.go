@@ -0,0 +1,44 @@
+package fs_go
+
+import (
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func lockRange(file *os.File, off, length int64, exclusive bool) error {
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+
+	low, high := windowsRangeLength(length)
+	overlapped := windowsOverlapped(off)
+
+	return windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, low, high, &overlapped)
+}
+
+func unlockRange(file *os.File, off, length int64) error {
+	low, high := windowsRangeLength(length)
+	overlapped := windowsOverlapped(off)
+
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, low, high, &overlapped)
+}
+
+// windowsRangeLength splits a lock length into its low and high parts.
+// A length of 0 means the rest of the file, which LockFileEx spells as the maximum length.
+func windowsRangeLength(length int64) (uint32, uint32) {
+	if length == 0 {
+		return math.MaxUint32, math.MaxUint32
+	}
+
+	return uint32(length), uint32(length >> 32)
+}
+
+func windowsOverlapped(off int64) windows.Overlapped {
+	return windows.Overlapped{
+		Offset:     uint32(off),
+		OffsetHigh: uint32(off >> 32),
+	}
+}
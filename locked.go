@@ -0,0 +1,147 @@
+package fs_go
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// LockedPath serializes operations on a single path between goroutines of the same process.
+// Writes are exclusive, while reads only wait for writes, so a reader never sees a
+// half-written file written through the same LockedPath.
+//
+// Unlike LockRange, it doesn't coordinate with other processes, but it needs no file
+// descriptor and works for files that don't exist yet.
+type LockedPath struct {
+	path string
+}
+
+// pathLock is a lock shared by all LockedPath values for the same path.
+type pathLock struct {
+	sync.RWMutex
+	refs int
+}
+
+var pathLocks struct {
+	sync.Mutex
+	locks map[string]*pathLock
+}
+
+// Locked returns a LockedPath for path. Paths are compared after resolving them to
+// absolute paths, so "data.json" and "./data.json" share a lock.
+//
+// Example:
+//
+//	err := Locked("state.json").WriteText(state)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func Locked(path string) *LockedPath {
+	return &LockedPath{path: normalizePath(path)}
+}
+
+// Do runs fn while holding the write lock for the path, for sequences of operations
+// that must not interleave with other writers, like read-modify-write cycles.
+//
+// Example:
+//
+//	err := Locked("counter.txt").Do(func(path string) error {
+//	    count, err := ReadText(path)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return WriteText(path, increment(count))
+//	})
+func (l *LockedPath) Do(fn func(path string) error) error {
+	unlock := lockPath(l.path, true)
+	defer unlock()
+
+	return fn(l.path)
+}
+
+// ReadBytes reads the file while holding the read lock. See ReadBytes.
+func (l *LockedPath) ReadBytes() ([]byte, error) {
+	unlock := lockPath(l.path, false)
+	defer unlock()
+
+	return ReadBytes(l.path)
+}
+
+// ReadText reads the file as a string while holding the read lock. See ReadText.
+func (l *LockedPath) ReadText() (string, error) {
+	unlock := lockPath(l.path, false)
+	defer unlock()
+
+	return ReadText(l.path)
+}
+
+// WriteBytes writes the file while holding the write lock. See WriteBytes.
+func (l *LockedPath) WriteBytes(content []byte) error {
+	return l.Do(func(path string) error {
+		return WriteBytes(path, content)
+	})
+}
+
+// WriteText writes a string to the file while holding the write lock. See WriteText.
+func (l *LockedPath) WriteText(content string) error {
+	return l.Do(func(path string) error {
+		return WriteText(path, content)
+	})
+}
+
+// AppendBytes appends to the file while holding the write lock. See AppendBytes.
+func (l *LockedPath) AppendBytes(content []byte) error {
+	return l.Do(func(path string) error {
+		return AppendBytes(path, content)
+	})
+}
+
+// AppendText appends a string to the file while holding the write lock. See AppendText.
+func (l *LockedPath) AppendText(content string) error {
+	return l.Do(func(path string) error {
+		return AppendText(path, content)
+	})
+}
+
+// lockPath acquires the lock for a path and returns a function that releases it.
+// Locks are reference counted and dropped once unused, so locking many paths doesn't leak.
+func lockPath(path string, write bool) func() {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = filepath.Clean(path)
+	}
+
+	pathLocks.Lock()
+	if pathLocks.locks == nil {
+		pathLocks.locks = make(map[string]*pathLock)
+	}
+	lock, ok := pathLocks.locks[key]
+	if !ok {
+		lock = &pathLock{}
+		pathLocks.locks[key] = lock
+	}
+	lock.refs++
+	pathLocks.Unlock()
+
+	if write {
+		lock.Lock()
+	} else {
+		lock.RLock()
+	}
+
+	return func() {
+		if write {
+			lock.Unlock()
+		} else {
+			lock.RUnlock()
+		}
+
+		pathLocks.Lock()
+		defer pathLocks.Unlock()
+
+		lock.refs--
+		if lock.refs == 0 {
+			delete(pathLocks.locks, key)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package fs_go
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestLocked(t *testing.T) {
+	// Expect concurrent read-modify-write cycles to not lose updates
+	t.Run("read modify write", func(t *testing.T) {
+		path := "locked_1.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("0"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := Locked(path).Do(func(path string) error {
+					content, err := ReadText(path)
+					if err != nil {
+						return err
+					}
+					count, err := strconv.Atoi(content)
+					if err != nil {
+						return err
+					}
+					return WriteText(path, strconv.Itoa(count+1))
+				})
+				if err != nil {
+					t.Errorf("Do failed: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "50" {
+			t.Errorf("Expected count to be 50, got %s", content)
+		}
+	})
+
+	// Expect readers to never see a partial write
+	t.Run("readers and writers", func(t *testing.T) {
+		path := "locked_2.txt"
+		defer os.Remove(path)
+
+		a := string(make([]byte, 1<<16))
+		b := string(make([]byte, 1<<15))
+		if err := Locked(path).WriteText(a); err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				content := a
+				if i%2 == 0 {
+					content = b
+				}
+				if err := Locked("./" + path).WriteText(content); err != nil {
+					t.Errorf("WriteText failed: %v", err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				content, err := Locked(path).ReadText()
+				if err != nil {
+					t.Errorf("ReadText failed: %v", err)
+					return
+				}
+				if len(content) != len(a) && len(content) != len(b) {
+					t.Errorf("Expected a complete write, got %d bytes", len(content))
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	// Expect unused locks to be dropped
+	t.Run("no leaks", func(t *testing.T) {
+		path := "locked_3.txt"
+		defer os.Remove(path)
+
+		if err := Locked(path).AppendText(""); err == nil {
+			t.Errorf("Expected AppendText to fail for a missing file")
+		}
+
+		pathLocks.Lock()
+		defer pathLocks.Unlock()
+		if len(pathLocks.locks) != 0 {
+			t.Errorf("Expected no locks to be held, got %d", len(pathLocks.locks))
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package fs_go
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// Manifest lists the files of a directory tree with their size, mode and checksum.
+type Manifest struct {
+	Files []ManifestEntry `json:"files"` // Sorted by path
+}
+
+// ManifestEntry describes a single file of a Manifest.
+type ManifestEntry struct {
+	Path   string      `json:"path"` // Slash-separated and relative to the root
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+	SHA256 string      `json:"sha256"` // Hex-encoded digest of the content
+}
+
+// ManifestReport describes how a directory tree differs from its manifest.
+// All paths are slash-separated, relative to the root and sorted.
+type ManifestReport struct {
+	Missing  []string // Files in the manifest that don't exist
+	Modified []string // Files whose size, mode or content changed
+	Extra    []string // Files that aren't in the manifest
+}
+
+// OK reports whether the tree matched its manifest exactly.
+func (r ManifestReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Modified) == 0 && len(r.Extra) == 0
+}
+
+// WriteManifest records the relative path, size, mode and SHA-256 checksum of every regular
+// file below root in a JSON manifest at manifestPath, written atomically. If the manifest
+// is inside root, it leaves itself out.
+//
+// Example:
+//
+//	err := WriteManifest("dist", "dist/MANIFEST.json")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteManifest(root, manifestPath string) error {
+	root, manifestPath = normalizePath(root), normalizePath(manifestPath)
+
+	files, err := manifestFiles(root, manifestPath)
+	if err != nil {
+		return pathError("WriteManifest", root, fmt.Errorf("WriteManifest failed to hash files: %w", err))
+	}
+
+	content, err := json.MarshalIndent(Manifest{Files: files}, "", "  ")
+	if err != nil {
+		return pathError("WriteManifest", manifestPath, fmt.Errorf("WriteManifest failed to marshal manifest: %w", err))
+	}
+
+	err = writeAtomic(manifestPath, append(content, '\n'), 0644, false)
+	if err != nil {
+		return pathError("WriteManifest", manifestPath, fmt.Errorf("WriteManifest failed to write manifest: %w", err))
+	}
+
+	return nil
+}
+
+// VerifyManifest compares the files below root against the manifest at manifestPath
+// and reports the files that are missing, modified or extra.
+// The manifest itself doesn't count as an extra file.
+//
+// Example:
+//
+//	report, err := VerifyManifest("dist", "dist/MANIFEST.json")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if !report.OK() {
+//	    fmt.Println("tampered:", report.Modified)
+//	}
+func VerifyManifest(root, manifestPath string) (ManifestReport, error) {
+	root, manifestPath = normalizePath(root), normalizePath(manifestPath)
+
+	var manifest Manifest
+	err := ReadJson(manifestPath, &manifest)
+	if err != nil {
+		return ManifestReport{}, pathError("VerifyManifest", manifestPath, fmt.Errorf("VerifyManifest failed to read manifest: %w", err))
+	}
+
+	files, err := manifestFiles(root, manifestPath)
+	if err != nil {
+		return ManifestReport{}, pathError("VerifyManifest", root, fmt.Errorf("VerifyManifest failed to hash files: %w", err))
+	}
+
+	actual := make(map[string]ManifestEntry, len(files))
+	for _, file := range files {
+		actual[file.Path] = file
+	}
+
+	var report ManifestReport
+	for _, expected := range manifest.Files {
+		file, ok := actual[expected.Path]
+		if !ok {
+			report.Missing = append(report.Missing, expected.Path)
+			continue
+		}
+		delete(actual, expected.Path)
+
+		if file != expected {
+			report.Modified = append(report.Modified, expected.Path)
+		}
+	}
+	for path := range actual {
+		report.Extra = append(report.Extra, path)
+	}
+
+	sort.Strings(report.Missing)
+	sort.Strings(report.Modified)
+	sort.Strings(report.Extra)
+
+	return report, nil
+}
+
+// manifestFiles returns the manifest entries of the regular files below root, sorted by
+// path, leaving out the file at exclude.
+func manifestFiles(root, exclude string) ([]ManifestEntry, error) {
+	hashed, err := hashTree(root, HashSHA256, 0, filepath.Clean(exclude), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]ManifestEntry, 0, len(hashed))
+	for _, file := range hashed {
+		files = append(files, ManifestEntry{
+			Path:   file.rel,
+			Size:   file.info.Size(),
+			Mode:   file.info.Mode().Perm(),
+			SHA256: file.digest,
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	return files, nil
+}
@@ -0,0 +1,80 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	// Expect an untouched tree to match its manifest
+	t.Run("verify", func(t *testing.T) {
+		path := "manifest_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"a.txt":        "a",
+			"nested/b.txt": "b",
+		})
+		manifestPath := filepath.Join(path, "MANIFEST.json")
+
+		err := WriteManifest(path, manifestPath)
+		if err != nil {
+			t.Fatalf("WriteManifest failed: %v", err)
+		}
+
+		var manifest Manifest
+		if err := ReadJson(manifestPath, &manifest); err != nil {
+			t.Fatalf("ReadJson failed: %v", err)
+		}
+		if len(manifest.Files) != 2 || manifest.Files[1].Path != "nested/b.txt" || manifest.Files[1].Size != 1 {
+			t.Errorf("Expected a.txt and nested/b.txt, got %+v", manifest.Files)
+		}
+
+		report, err := VerifyManifest(path, manifestPath)
+		if err != nil {
+			t.Fatalf("VerifyManifest failed: %v", err)
+		}
+		if !report.OK() {
+			t.Errorf("Expected tree to match, got %+v", report)
+		}
+	})
+
+	// Expect missing, modified and extra files to be reported
+	t.Run("changes", func(t *testing.T) {
+		path := "manifest_2"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"a.txt":        "a",
+			"b.txt":        "b",
+			"nested/c.txt": "c",
+		})
+		manifestPath := path + ".json"
+		defer os.Remove(manifestPath)
+
+		err := WriteManifest(path, manifestPath)
+		if err != nil {
+			t.Fatalf("WriteManifest failed: %v", err)
+		}
+
+		os.Remove(filepath.Join(path, "a.txt"))
+		os.WriteFile(filepath.Join(path, "b.txt"), []byte("B"), 0644)
+		os.WriteFile(filepath.Join(path, "nested", "d.txt"), []byte("d"), 0644)
+
+		report, err := VerifyManifest(path, manifestPath)
+		if err != nil {
+			t.Fatalf("VerifyManifest failed: %v", err)
+		}
+
+		expected := ManifestReport{
+			Missing:  []string{"a.txt"},
+			Modified: []string{"b.txt"},
+			Extra:    []string{"nested/d.txt"},
+		}
+		if !reflect.DeepEqual(report, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, report)
+		}
+	})
+}
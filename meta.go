@@ -0,0 +1,109 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileMeta is the metadata of a file that copies usually lose.
+type FileMeta struct {
+	Mode       os.FileMode // Permission bits, including setuid, setgid and sticky
+	ModTime    time.Time
+	AccessTime time.Time // Zero if unknown
+	UID        int       // Owner user ID, -1 if unknown or not applicable
+	GID        int       // Owner group ID, -1 if unknown or not applicable
+}
+
+// metaModeMask selects the mode bits that ApplyMeta can set.
+const metaModeMask = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+// CaptureMeta returns the metadata of a file, following symlinks.
+// Owners are only captured on Unix.
+//
+// Example:
+//
+//	meta, err := CaptureMeta("original.conf")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = WriteText("original.conf", updated)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = ApplyMeta("original.conf", meta)
+func CaptureMeta(path string) (FileMeta, error) {
+	path = normalizePath(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileMeta{}, fmt.Errorf("CaptureMeta failed to get file stat: %w", err)
+	}
+
+	return metaFromInfo(path, info), nil
+}
+
+// ApplyMeta applies metadata to a file, following symlinks.
+// The owner is only changed if UID or GID is set, which usually requires privileges.
+// The access time defaults to the modification time if unknown.
+func ApplyMeta(path string, meta FileMeta) error {
+	err := applyMeta(path, meta, true, true, true)
+	if err != nil {
+		return fmt.Errorf("ApplyMeta failed: %w", err)
+	}
+
+	return nil
+}
+
+// metaFromInfo extracts metadata from a file's stat, looking up platform-specific parts by path.
+func metaFromInfo(path string, info os.FileInfo) FileMeta {
+	meta := FileMeta{
+		Mode:    info.Mode() & metaModeMask,
+		ModTime: info.ModTime(),
+		UID:     -1,
+		GID:     -1,
+	}
+	platformMeta(path, info, &meta)
+
+	return meta
+}
+
+// applyMeta applies the selected parts of the metadata to a file.
+// The owner is changed first, since that can clear setuid and setgid bits.
+func applyMeta(path string, meta FileMeta, mode, times, owner bool) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("ApplyMeta", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	if owner && (meta.UID >= 0 || meta.GID >= 0) {
+		err := os.Chown(path, meta.UID, meta.GID)
+		if err != nil {
+			return fmt.Errorf("failed to change owner: %w", err)
+		}
+	}
+
+	if mode {
+		err := os.Chmod(path, meta.Mode)
+		if err != nil {
+			return fmt.Errorf("failed to change mode: %w", err)
+		}
+	}
+
+	if times {
+		atime := meta.AccessTime
+		if atime.IsZero() {
+			atime = meta.ModTime
+		}
+		err := os.Chtimes(path, atime, meta.ModTime)
+		if err != nil {
+			return fmt.Errorf("failed to change times: %w", err)
+		}
+	}
+
+	return nil
+}
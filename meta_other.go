@@ -0,0 +1,7 @@
+//go:build !unix && !windows
+
+package fs_go
+
+import "os"
+
+func platformMeta(path string, info os.FileInfo, meta *FileMeta) {}
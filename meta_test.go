@@ -0,0 +1,90 @@
+package fs_go
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCaptureMeta(t *testing.T) {
+	// Expect mode and modification time to be captured
+	t.Run("capture", func(t *testing.T) {
+		path := "capture_meta.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("content"), 0640); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("os.Chtimes failed: %v", err)
+		}
+
+		meta, err := CaptureMeta(path)
+		if err != nil {
+			t.Errorf("CaptureMeta failed: %v", err)
+		}
+
+		if !meta.ModTime.Equal(mtime) {
+			t.Errorf("Expected modification time to be %v, got %v", mtime, meta.ModTime)
+		}
+
+		if runtime.GOOS != "windows" && meta.Mode.Perm() != 0640 {
+			t.Errorf("Expected mode to be 0640, got %#o", meta.Mode.Perm())
+		}
+	})
+
+	// Expect an error for a missing file
+	t.Run("missing file", func(t *testing.T) {
+		_, err := CaptureMeta("capture_meta_missing.txt")
+		if err == nil {
+			t.Errorf("Expected CaptureMeta to fail for a missing file")
+		}
+	})
+}
+
+func TestApplyMeta(t *testing.T) {
+	// Expect metadata captured from one file to be applied to another
+	t.Run("round trip", func(t *testing.T) {
+		src := "apply_meta_src.txt"
+		dst := "apply_meta_dst.txt"
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		if err := os.WriteFile(src, []byte("src"), 0600); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		if err := os.WriteFile(dst, []byte("dst"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		mtime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(src, mtime, mtime); err != nil {
+			t.Fatalf("os.Chtimes failed: %v", err)
+		}
+
+		meta, err := CaptureMeta(src)
+		if err != nil {
+			t.Fatalf("CaptureMeta failed: %v", err)
+		}
+
+		// Applying our own owner doesn't require privileges
+		err = ApplyMeta(dst, meta)
+		if err != nil {
+			t.Errorf("ApplyMeta failed: %v", err)
+		}
+
+		info, err := os.Stat(dst)
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("Expected modification time to be %v, got %v", mtime, info.ModTime())
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("Expected mode to be 0600, got %#o", info.Mode().Perm())
+		}
+	})
+}
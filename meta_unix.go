@@ -0,0 +1,22 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func platformMeta(path string, info os.FileInfo, meta *FileMeta) {
+	// x/sys/unix names the access time consistently across platforms, unlike syscall
+	var stat unix.Stat_t
+	if unix.Stat(path, &stat) != nil {
+		return
+	}
+
+	meta.UID = int(stat.Uid)
+	meta.GID = int(stat.Gid)
+	meta.AccessTime = time.Unix(stat.Atim.Unix())
+}
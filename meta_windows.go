@@ -0,0 +1,16 @@
+package fs_go
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func platformMeta(path string, info os.FileInfo, meta *FileMeta) {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return
+	}
+
+	meta.AccessTime = time.Unix(0, data.LastAccessTime.Nanoseconds())
+}
@@ -0,0 +1,111 @@
+package fs_go
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Metrics receives a record of every file operation, so services can monitor their file
+// system usage. Implementations can forward to expvar, Prometheus, or any other backend,
+// and must be safe for concurrent use.
+type Metrics interface {
+	// Observe is called once per operation, such as "ReadBytes" or "CopyFile",
+	// with the number of bytes read and written and the error it returned, if any.
+	Observe(op string, bytesRead, bytesWritten int64, err error)
+}
+
+// OpCounters holds the totals of a single operation.
+type OpCounters struct {
+	Calls        int64 `json:"calls"`
+	Errors       int64 `json:"errors"`
+	BytesRead    int64 `json:"bytes_read"`
+	BytesWritten int64 `json:"bytes_written"`
+}
+
+// Counters is a Metrics implementation that keeps totals per operation in memory.
+// It implements expvar.Var, so it can be published directly.
+//
+// Example:
+//
+//	counters := &Counters{}
+//	SetMetrics(counters)
+//	expvar.Publish("fs_go", counters)
+type Counters struct {
+	mu  sync.Mutex
+	ops map[string]OpCounters
+}
+
+// Observe adds an operation to the totals.
+func (c *Counters) Observe(op string, bytesRead, bytesWritten int64, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ops == nil {
+		c.ops = make(map[string]OpCounters)
+	}
+	counters := c.ops[op]
+	counters.Calls++
+	if err != nil {
+		counters.Errors++
+	}
+	counters.BytesRead += bytesRead
+	counters.BytesWritten += bytesWritten
+	c.ops[op] = counters
+}
+
+// Snapshot returns a copy of the totals, keyed by operation.
+func (c *Counters) Snapshot() map[string]OpCounters {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[string]OpCounters, len(c.ops))
+	for op, counters := range c.ops {
+		snapshot[op] = counters
+	}
+
+	return snapshot
+}
+
+// String returns the totals as JSON.
+func (c *Counters) String() string {
+	content, err := json.Marshal(c.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+
+	return string(content)
+}
+
+var metrics struct {
+	sync.RWMutex
+	sink Metrics
+}
+
+// SetMetrics sets the Metrics sink for the whole package. Passing nil disables metrics.
+// Operations skipped in dry-run mode aren't observed.
+func SetMetrics(m Metrics) {
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	metrics.sink = m
+}
+
+// observe reports an operation to the Metrics sink, if one is set.
+func observe(op string, bytesRead, bytesWritten int64, err error) {
+	metrics.RLock()
+	sink := metrics.sink
+	metrics.RUnlock()
+
+	if sink != nil {
+		sink.Observe(op, bytesRead, bytesWritten, err)
+	}
+}
+
+// observeWrite reports a write of content to the Metrics sink, counting its bytes only if it succeeded.
+func observeWrite(op string, length int, err error) {
+	if err != nil {
+		length = 0
+	}
+
+	observe(op, 0, int64(length), err)
+}
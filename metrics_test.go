@@ -0,0 +1,81 @@
+package fs_go
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSetMetrics(t *testing.T) {
+	// Expect calls, bytes and errors to be counted per operation
+	t.Run("counters", func(t *testing.T) {
+		path := "set_metrics_1.txt"
+		copied := "set_metrics_1_copy.txt"
+		defer os.Remove(path)
+		defer os.Remove(copied)
+
+		counters := &Counters{}
+		SetMetrics(counters)
+		defer SetMetrics(nil)
+
+		if err := WriteBytes(path, []byte("hello")); err != nil {
+			t.Fatalf("WriteBytes failed: %v", err)
+		}
+		if _, err := ReadBytes(path); err != nil {
+			t.Fatalf("ReadBytes failed: %v", err)
+		}
+		if err := CopyFile(path, copied); err != nil {
+			t.Fatalf("CopyFile failed: %v", err)
+		}
+		if _, err := ReadBytes("set_metrics_missing.txt"); err == nil {
+			t.Fatalf("Expected ReadBytes to fail for a missing file")
+		}
+
+		snapshot := counters.Snapshot()
+		expected := map[string]OpCounters{
+			"WriteBytes": {Calls: 1, BytesWritten: 5},
+			"ReadBytes":  {Calls: 2, Errors: 1, BytesRead: 5},
+			"CopyFile":   {Calls: 1, BytesRead: 5, BytesWritten: 5},
+		}
+		for op, counts := range expected {
+			if snapshot[op] != counts {
+				t.Errorf("Expected %s counters to be %+v, got %+v", op, counts, snapshot[op])
+			}
+		}
+	})
+
+	// Expect operations skipped in dry-run mode to not be counted
+	t.Run("dry run", func(t *testing.T) {
+		counters := &Counters{}
+		SetMetrics(counters)
+		defer SetMetrics(nil)
+
+		_, err := DryRun(func() error {
+			return WriteBytes("set_metrics_2.txt", []byte("hello"))
+		})
+		if err != nil {
+			t.Errorf("DryRun failed: %v", err)
+		}
+
+		if len(counters.Snapshot()) != 0 {
+			t.Errorf("Expected no operations to be counted, got %v", counters.Snapshot())
+		}
+	})
+}
+
+func TestCounters(t *testing.T) {
+	// Expect String to return the totals as JSON for expvar
+	t.Run("string", func(t *testing.T) {
+		counters := &Counters{}
+		counters.Observe("Remove", 0, 0, nil)
+
+		var decoded map[string]OpCounters
+		if err := json.Unmarshal([]byte(counters.String()), &decoded); err != nil {
+			t.Fatalf("json.Unmarshal failed: %v", err)
+		}
+
+		if decoded["Remove"].Calls != 1 {
+			t.Errorf("Expected Remove to be counted once, got %+v", decoded["Remove"])
+		}
+	})
+}
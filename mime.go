@@ -0,0 +1,90 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// sniffLength is the number of bytes DetectMime looks at, as specified by the WHATWG
+// MIME sniffing algorithm.
+const sniffLength = 512
+
+// binarySniffLength is the number of bytes IsBinary and IsTextFile look at.
+// It is the same amount git uses to tell binary files apart.
+const binarySniffLength = 8000
+
+// DetectMime returns the MIME type of a file based on its first 512 bytes, such as
+// "image/png" or "text/plain; charset=utf-8". It falls back to "application/octet-stream".
+//
+// Example:
+//
+//	mime, err := DetectMime("upload.bin")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if !strings.HasPrefix(mime, "image/") {
+//	    fmt.Println("not an image")
+//	}
+func DetectMime(path string) (string, error) {
+	head, err := readHead(path, sniffLength)
+	if err != nil {
+		return "", fmt.Errorf("DetectMime failed to read file: %w", err)
+	}
+
+	return http.DetectContentType(head), nil
+}
+
+// IsBinary reports whether a file looks binary, because it contains a NUL byte in its
+// first 8000 bytes. This is the heuristic git uses, and is cheap enough to skip binaries
+// when searching or diffing. Empty files are not binary.
+func IsBinary(path string) (bool, error) {
+	head, err := readHead(path, binarySniffLength)
+	if err != nil {
+		return false, fmt.Errorf("IsBinary failed to read file: %w", err)
+	}
+
+	return bytes.IndexByte(head, 0) >= 0, nil
+}
+
+// IsTextFile reports whether a file looks like UTF-8 text, because its first 8000 bytes
+// are valid UTF-8 without control characters other than whitespace, backspace and escape.
+// It is stricter than !IsBinary, which accepts anything without NUL bytes. Empty files
+// are text.
+func IsTextFile(path string) (bool, error) {
+	head, err := readHead(path, binarySniffLength)
+	if err != nil {
+		return false, fmt.Errorf("IsTextFile failed to read file: %w", err)
+	}
+
+	if !validUTF8Sample(head) {
+		return false, nil
+	}
+
+	isControl := func(r rune) bool {
+		return (r < 0x20 && !strings.ContainsRune("\t\n\v\f\r\b\x1b", r)) || r == 0x7f
+	}
+
+	return !bytes.ContainsFunc(head, isControl), nil
+}
+
+// readHead reads up to n bytes from the start of a file.
+func readHead(path string, n int) ([]byte, error) {
+	file, err := os.Open(normalizePath(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, n)
+	read, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	return head[:read], nil
+}
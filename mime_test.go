@@ -0,0 +1,95 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectMime(t *testing.T) {
+	// Expect common types to be detected from the content
+	t.Run("content types", func(t *testing.T) {
+		path := "detect_mime_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"image.bin": "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+			"page.txt":  "<!DOCTYPE html><html></html>",
+			"plain":     "just some text",
+		})
+
+		cases := map[string]string{
+			"image.bin": "image/png",
+			"page.txt":  "text/html; charset=utf-8",
+			"plain":     "text/plain; charset=utf-8",
+		}
+		for name, expected := range cases {
+			mime, err := DetectMime(filepath.Join(path, name))
+			if err != nil {
+				t.Errorf("DetectMime failed: %v", err)
+			}
+
+			if mime != expected {
+				t.Errorf("Expected %s to be %s, got %s", name, expected, mime)
+			}
+		}
+	})
+}
+
+func TestIsBinary(t *testing.T) {
+	// Expect files with NUL bytes to be binary and others to not be
+	t.Run("heuristic", func(t *testing.T) {
+		path := "is_binary_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"binary": "abc\x00def",
+			"text":   "hello\nworld\n",
+			"empty":  "",
+		})
+
+		cases := map[string]bool{"binary": true, "text": false, "empty": false}
+		for name, expected := range cases {
+			binary, err := IsBinary(filepath.Join(path, name))
+			if err != nil {
+				t.Errorf("IsBinary failed: %v", err)
+			}
+
+			if binary != expected {
+				t.Errorf("Expected IsBinary(%s) to be %v, got %v", name, expected, binary)
+			}
+		}
+	})
+}
+
+func TestIsTextFile(t *testing.T) {
+	// Expect UTF-8 text to be text, and invalid UTF-8 or control characters to not be
+	t.Run("heuristic", func(t *testing.T) {
+		path := "is_text_file_1"
+		defer os.RemoveAll(path)
+
+		// The multi-byte rune is cut off by the sample size, which must not count as invalid
+		cut := strings.Repeat("a", binarySniffLength-1) + "é"
+
+		writeTree(t, path, map[string]string{
+			"utf8":    "héllo wörld\t\r\n",
+			"latin1":  "h\xe9llo",
+			"control": "bell\x07",
+			"cut":     cut,
+			"empty":   "",
+		})
+
+		cases := map[string]bool{"utf8": true, "latin1": false, "control": false, "cut": true, "empty": true}
+		for name, expected := range cases {
+			text, err := IsTextFile(filepath.Join(path, name))
+			if err != nil {
+				t.Errorf("IsTextFile failed: %v", err)
+			}
+
+			if text != expected {
+				t.Errorf("Expected IsTextFile(%s) to be %v, got %v", name, expected, text)
+			}
+		}
+	})
+}
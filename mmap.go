@@ -0,0 +1,162 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+)
+
+// MappedFile is a file, or a region of one, mapped into memory.
+// It must be released with Unmap, after which its bytes must no longer be used.
+type MappedFile struct {
+	data     []byte   // The requested region
+	mapping  []byte   // The whole mapping, which starts at an aligned offset
+	handle   uintptr  // Platform-specific handle of the mapping, if any
+	file     *os.File // The mapped file, kept open while changes can be flushed to it
+	writable bool     // Whether changes are written back to the file
+}
+
+// Mmap maps a whole file into memory for reading.
+//
+// Example:
+//
+//	mapped, err := Mmap("index.bin")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer mapped.Unmap()
+//	header := mapped.Bytes()[:16]
+func Mmap(path string) (*MappedFile, error) {
+	mapped, err := MmapRegion(path, 0, 0, false)
+	if err != nil {
+		return nil, fmt.Errorf("Mmap failed to map file: %w", err)
+	}
+
+	return mapped, nil
+}
+
+// MmapRegion maps length bytes of a file, starting at off, into memory.
+// A length of 0 maps everything from off to the end of the file.
+// If writable is set, changes to the bytes are written back to the file,
+// at the latest when Flush or Unmap is called. In dry-run mode writable mappings are
+// private copy-on-write mappings, so changes to the bytes never reach the file.
+func MmapRegion(path string, off int64, length int, writable bool) (*MappedFile, error) {
+	path = normalizePath(path)
+
+	if off < 0 || length < 0 {
+		return nil, fmt.Errorf("MmapRegion failed: invalid region %d+%d", off, length)
+	}
+
+	flag := os.O_RDONLY
+	private := false
+	if writable {
+		if skip, err := writeGuard("MmapRegion", path, ""); skip {
+			if err != nil {
+				return nil, err
+			}
+			private = true
+		} else {
+			flag = os.O_RDWR
+		}
+	}
+	shared := writable && !private
+
+	file, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, fmt.Errorf("MmapRegion failed to open file: %w", err)
+	}
+	// The mapping stays valid after the file is closed, but flushing needs it on some platforms
+	keep := false
+	defer func() {
+		if !keep {
+			file.Close()
+		}
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("MmapRegion failed to get file stat: %w", err)
+	}
+	if off > info.Size() {
+		return nil, fmt.Errorf("MmapRegion failed: offset %d is past the end of the file of size %d", off, info.Size())
+	}
+	if length == 0 {
+		length = int(info.Size() - off)
+	}
+	if length < 0 || off+int64(length) > info.Size() {
+		return nil, fmt.Errorf("MmapRegion failed: region %d+%d exceeds file size %d", off, length, info.Size())
+	}
+	if length == 0 {
+		// Empty mappings aren't allowed, but an empty view is harmless
+		return &MappedFile{}, nil
+	}
+
+	// Mappings must start at an aligned offset, so map from there and slice off the difference
+	aligned := off - off%int64(mmapAlignment())
+	delta := int(off - aligned)
+
+	mapping, handle, err := mmap(file, aligned, delta+length, writable, private)
+	if err != nil {
+		return nil, fmt.Errorf("MmapRegion failed to map file: %w", err)
+	}
+
+	mapped := &MappedFile{
+		data:     mapping[delta : delta+length],
+		mapping:  mapping,
+		handle:   handle,
+		writable: shared,
+	}
+	if shared {
+		mapped.file, keep = file, true
+	}
+
+	return mapped, nil
+}
+
+// Bytes returns the mapped bytes. Writing to them is only allowed for writable mappings.
+func (m *MappedFile) Bytes() []byte {
+	return m.data
+}
+
+// Flush writes changes to the mapped bytes back to the file and waits for it to complete.
+// It does nothing for read-only mappings and the private mappings of dry-run mode.
+func (m *MappedFile) Flush() error {
+	if !m.writable || m.mapping == nil {
+		return nil
+	}
+
+	err := flushMapping(m.mapping, m.handle, m.file)
+	if err != nil {
+		return fmt.Errorf("Flush failed to flush mapping: %w", err)
+	}
+
+	return nil
+}
+
+// Unmap releases the mapping. Changes to writable mappings are flushed first.
+func (m *MappedFile) Unmap() error {
+	if m.mapping == nil {
+		return nil
+	}
+
+	err := m.Flush()
+	if err != nil {
+		return fmt.Errorf("Unmap failed: %w", err)
+	}
+
+	err = unmap(m.mapping, m.handle)
+	if err != nil {
+		return fmt.Errorf("Unmap failed to unmap: %w", err)
+	}
+	m.data, m.mapping = nil, nil
+
+	if m.file != nil {
+		err = m.file.Close()
+		m.file = nil
+		if err != nil {
+			return fmt.Errorf("Unmap failed to close file: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build !unix && !windows
+
+package fs_go
+
+import (
+	"errors"
+	"os"
+)
+
+func mmapAlignment() int {
+	return 1
+}
+
+func mmap(file *os.File, off int64, length int, writable, private bool) ([]byte, uintptr, error) {
+	return nil, 0, errors.ErrUnsupported
+}
+
+func flushMapping(mapping []byte, handle uintptr, file *os.File) error {
+	return errors.ErrUnsupported
+}
+
+func unmap(mapping []byte, handle uintptr) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,145 @@
+package fs_go
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestMmap(t *testing.T) {
+	// Expect the whole file to be mapped
+	t.Run("map file", func(t *testing.T) {
+		path := "mmap.bin"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		mapped, err := Mmap(path)
+		if err != nil {
+			t.Fatalf("Mmap failed: %v", err)
+		}
+
+		if string(mapped.Bytes()) != "test content" {
+			t.Errorf("Expected mapped bytes to be 'test content', got '%s'", mapped.Bytes())
+		}
+
+		if err := mapped.Unmap(); err != nil {
+			t.Errorf("Unmap failed: %v", err)
+		}
+	})
+
+	// Expect empty files to map to an empty view
+	t.Run("empty file", func(t *testing.T) {
+		path := "mmap_empty.bin"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		mapped, err := Mmap(path)
+		if err != nil {
+			t.Fatalf("Mmap failed: %v", err)
+		}
+
+		if len(mapped.Bytes()) != 0 {
+			t.Errorf("Expected no mapped bytes, got %d", len(mapped.Bytes()))
+		}
+
+		if err := mapped.Unmap(); err != nil {
+			t.Errorf("Unmap failed: %v", err)
+		}
+	})
+}
+
+func TestMmapRegion(t *testing.T) {
+	// Expect an unaligned region to be mapped and written back
+	t.Run("writable region", func(t *testing.T) {
+		path := "mmap_region.bin"
+		defer os.Remove(path)
+
+		content := bytes.Repeat([]byte{'a'}, 100000)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		mapped, err := MmapRegion(path, 70001, 5, true)
+		if err != nil {
+			t.Fatalf("MmapRegion failed: %v", err)
+		}
+
+		if len(mapped.Bytes()) != 5 {
+			t.Fatalf("Expected 5 mapped bytes, got %d", len(mapped.Bytes()))
+		}
+		copy(mapped.Bytes(), "hello")
+
+		if err := mapped.Unmap(); err != nil {
+			t.Errorf("Unmap failed: %v", err)
+		}
+
+		written, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(written[70000:70007]) != "ahelloa" {
+			t.Errorf("Expected region to be written back, got '%s'", written[70000:70007])
+		}
+	})
+
+	// Expect writes through a mapping made in dry-run mode not to reach the file
+	t.Run("dry run", func(t *testing.T) {
+		path := "mmap_dry_run.bin"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		plan, err := DryRun(func() error {
+			mapped, err := MmapRegion(path, 0, 0, true)
+			if err != nil {
+				return err
+			}
+			copy(mapped.Bytes(), "TEST")
+			if string(mapped.Bytes()[:4]) != "TEST" {
+				t.Errorf("Expected the mapping to see its own changes, got '%s'", mapped.Bytes()[:4])
+			}
+			return mapped.Unmap()
+		})
+		if err != nil {
+			t.Fatalf("DryRun failed: %v", err)
+		}
+
+		if len(plan) != 1 || plan[0].Op != "MmapRegion" {
+			t.Errorf("Expected the mapping to be recorded, got %v", plan)
+		}
+		content, _ := os.ReadFile(path)
+		if string(content) != "test content" {
+			t.Errorf("Expected the file to be unchanged, got '%s'", content)
+		}
+	})
+
+	// Expect regions beyond the end of the file to be rejected
+	t.Run("out of bounds", func(t *testing.T) {
+		path := "mmap_region_bounds.bin"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		_, err := MmapRegion(path, 8, 8, false)
+		if err == nil {
+			t.Errorf("Expected MmapRegion to fail for an out of bounds region")
+		}
+
+		// The rest of the file from past its end
+		_, err = MmapRegion(path, 13, 0, false)
+		if err == nil {
+			t.Errorf("Expected MmapRegion to fail for an offset past the end")
+		}
+	})
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func mmapAlignment() int {
+	return os.Getpagesize()
+}
+
+func mmap(file *os.File, off int64, length int, writable, private bool) ([]byte, uintptr, error) {
+	prot := unix.PROT_READ
+	if writable {
+		prot |= unix.PROT_WRITE
+	}
+	flags := unix.MAP_SHARED
+	if private {
+		flags = unix.MAP_PRIVATE
+	}
+
+	mapping, err := unix.Mmap(int(file.Fd()), off, length, prot, flags)
+	return mapping, 0, err
+}
+
+func flushMapping(mapping []byte, handle uintptr, file *os.File) error {
+	return unix.Msync(mapping, unix.MS_SYNC)
+}
+
+func unmap(mapping []byte, handle uintptr) error {
+	return unix.Munmap(mapping)
+}
@@ -0,0 +1,61 @@
+package fs_go
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Views must start at a multiple of the allocation granularity, which is 64 KiB on all Windows versions.
+func mmapAlignment() int {
+	return 64 * 1024
+}
+
+func mmap(file *os.File, off int64, length int, writable, private bool) ([]byte, uintptr, error) {
+	protect := uint32(windows.PAGE_READONLY)
+	access := uint32(windows.FILE_MAP_READ)
+	switch {
+	case private:
+		protect = windows.PAGE_WRITECOPY
+		access = windows.FILE_MAP_COPY
+	case writable:
+		protect = windows.PAGE_READWRITE
+		access = windows.FILE_MAP_WRITE
+	}
+
+	handle, err := windows.CreateFileMapping(windows.Handle(file.Fd()), nil, protect, 0, 0, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	addr, err := windows.MapViewOfFile(handle, access, uint32(off>>32), uint32(off), uintptr(length))
+	if err != nil {
+		windows.CloseHandle(handle)
+		return nil, 0, err
+	}
+
+	// Convert through a pointer to the address, since the view isn't Go-managed memory
+	data := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&addr))), length)
+	return data, uintptr(handle), nil
+}
+
+// FlushViewOfFile only starts writing the pages back, FlushFileBuffers waits for them to reach the disk.
+func flushMapping(mapping []byte, handle uintptr, file *os.File) error {
+	err := windows.FlushViewOfFile(uintptr(unsafe.Pointer(&mapping[0])), uintptr(len(mapping)))
+	if err != nil {
+		return err
+	}
+
+	return windows.FlushFileBuffers(windows.Handle(file.Fd()))
+}
+
+func unmap(mapping []byte, handle uintptr) error {
+	err := windows.UnmapViewOfFile(uintptr(unsafe.Pointer(&mapping[0])))
+	closeErr := windows.CloseHandle(windows.Handle(handle))
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package fs_go
+
+// isCrossDevice reports whether a rename failed because source and destination are on different devices.
+func isCrossDevice(err error) bool {
+	return false
+}
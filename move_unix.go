@@ -0,0 +1,13 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDevice reports whether a rename failed because source and destination are on different devices.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,108 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// OpenRead opens a file for reading. The caller must close it.
+//
+// Example:
+//
+//	file, err := OpenRead("data/input.bin")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+func OpenRead(path string) (*os.File, error) {
+	path = normalizePath(path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("OpenRead", path, fmt.Errorf("OpenRead failed to open file: %w", err))
+	}
+
+	return file, nil
+}
+
+// OpenWrite opens a file for writing, creating it with mode and any missing parent
+// directories if needed, and truncating it otherwise. The caller must close it.
+//
+// In dry-run mode, the returned file discards everything written to it.
+//
+// Example:
+//
+//	file, err := OpenWrite("out/report.csv", 0644)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+func OpenWrite(path string, mode os.FileMode) (*os.File, error) {
+	return openForWrite("OpenWrite", path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+}
+
+// OpenAppend opens a file for appending, creating it with mode and any missing parent
+// directories if needed. The caller must close it.
+//
+// In dry-run mode, the returned file discards everything written to it.
+//
+// Example:
+//
+//	file, err := OpenAppend("logs/app.log", 0644)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+func OpenAppend(path string, mode os.FileMode) (*os.File, error) {
+	return openForWrite("OpenAppend", path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, mode)
+}
+
+// OpenExclusive creates a new file for writing with mode, creating any missing parent
+// directories. It fails with an error matching ErrExist if the file already exists, so
+// only one caller can ever create it. The caller must close it.
+//
+// In dry-run mode, the returned file discards everything written to it.
+//
+// Example:
+//
+//	file, err := OpenExclusive("jobs/42.claim", 0644)
+//	if errors.Is(err, ErrExist) {
+//	    return // Someone else got it
+//	}
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+func OpenExclusive(path string, mode os.FileMode) (*os.File, error) {
+	return openForWrite("OpenExclusive", path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+}
+
+// openForWrite opens a file with flag after ensuring its parent directory exists.
+func openForWrite(op, path string, flag int, mode os.FileMode) (*os.File, error) {
+	path = normalizePath(path)
+
+	err := EnsureDirWithMode(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, pathError(op, path, fmt.Errorf("%s failed to ensure directory: %w", op, err))
+	}
+
+	if skip, err := writeGuard(op, path, ""); skip {
+		if err != nil {
+			return nil, err
+		}
+		path, flag = os.DevNull, os.O_WRONLY
+	}
+	defer invalidateStats(path)
+
+	file, err := os.OpenFile(path, flag, mode)
+	if err != nil {
+		return nil, pathError(op, path, fmt.Errorf("%s failed to open file: %w", op, err))
+	}
+
+	return file, nil
+}
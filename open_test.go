@@ -0,0 +1,78 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	// Expect the write helpers to create missing parent directories
+	t.Run("write and append", func(t *testing.T) {
+		path := "open_1"
+		defer os.RemoveAll(path)
+		file := filepath.Join(path, "nested", "log.txt")
+
+		f, err := OpenWrite(file, 0644)
+		if err != nil {
+			t.Fatalf("OpenWrite failed: %v", err)
+		}
+		f.WriteString("first\n")
+		f.Close()
+
+		f, err = OpenAppend(file, 0644)
+		if err != nil {
+			t.Fatalf("OpenAppend failed: %v", err)
+		}
+		f.WriteString("second\n")
+		f.Close()
+
+		f, err = OpenRead(file)
+		if err != nil {
+			t.Fatalf("OpenRead failed: %v", err)
+		}
+		defer f.Close()
+		content := make([]byte, 64)
+		n, _ := f.Read(content)
+		if string(content[:n]) != "first\nsecond\n" {
+			t.Errorf("Expected both lines, got %q", content[:n])
+		}
+
+		// Expect OpenWrite to truncate
+		f, err = OpenWrite(file, 0644)
+		if err != nil {
+			t.Fatalf("OpenWrite failed: %v", err)
+		}
+		f.Close()
+		if size, _ := GetSize(file); size != 0 {
+			t.Errorf("Expected an empty file, got %d bytes", size)
+		}
+	})
+
+	// Expect OpenExclusive to fail if the file exists
+	t.Run("exclusive", func(t *testing.T) {
+		path := "open_2"
+		defer os.RemoveAll(path)
+		file := filepath.Join(path, "claim")
+
+		f, err := OpenExclusive(file, 0644)
+		if err != nil {
+			t.Fatalf("OpenExclusive failed: %v", err)
+		}
+		f.Close()
+
+		_, err = OpenExclusive(file, 0644)
+		if !errors.Is(err, os.ErrExist) {
+			t.Errorf("Expected os.ErrExist, got %v", err)
+		}
+	})
+
+	// Expect OpenRead to fail on missing files
+	t.Run("missing", func(t *testing.T) {
+		_, err := OpenRead("open_3/missing")
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected ErrNotExist, got %v", err)
+		}
+	})
+}
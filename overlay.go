@@ -0,0 +1,444 @@
+package fs_go
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+const (
+	// whiteoutPrefix marks a file in the upper layer that hides an entry of the base layer.
+	whiteoutPrefix = ".wh."
+	// opaqueMarker in an upper directory hides everything below it in the base layer.
+	opaqueMarker = whiteoutPrefix + whiteoutPrefix + ".opq"
+)
+
+// OverlayFS is a copy-on-write union of a read-only base layer and a writable upper layer.
+// Reads see the upper layer first and fall through to the base, while all writes go to
+// the upper layer, so the base is never modified.
+//
+// Deleting an entry that exists in the base leaves a whiteout file named ".wh.<name>" in
+// the upper layer, the same convention used by container image layers. Names starting
+// with ".wh." are therefore reserved.
+type OverlayFS struct {
+	base  fs.FS
+	upper FS
+}
+
+// NewOverlayFS returns an OverlayFS that reads through upper to base and writes to upper.
+//
+// Example:
+//
+//	fsys := NewOverlayFS(os.DirFS("vendor/lib"), DirFS("patches/lib"))
+//	err := fsys.WriteFile("config.go", patched, 0644)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func NewOverlayFS(base fs.FS, upper FS) *OverlayFS {
+	return &OverlayFS{base: base, upper: upper}
+}
+
+// check rejects names that aren't valid io/fs paths or that use the reserved whiteout prefix.
+func (o *OverlayFS) check(op, name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, whiteoutPrefix) {
+			return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+		}
+	}
+
+	return nil
+}
+
+// upperExists reports whether name exists in the upper layer.
+func (o *OverlayFS) upperExists(name string) bool {
+	_, err := o.upper.Stat(name)
+	return err == nil
+}
+
+// inBase returns the base layer entry for name, if it exists and isn't hidden by a
+// whiteout or an opaque directory in the upper layer.
+func (o *OverlayFS) inBase(name string) (fs.FileInfo, bool) {
+	for p := name; p != "."; p = path.Dir(p) {
+		if o.upperExists(whiteoutName(p)) {
+			return nil, false
+		}
+		if p != name && o.upperExists(path.Join(p, opaqueMarker)) {
+			return nil, false
+		}
+	}
+
+	info, err := fs.Stat(o.base, name)
+	if err != nil {
+		return nil, false
+	}
+
+	return info, true
+}
+
+// whiteoutName returns the name of the whiteout file that hides name.
+func whiteoutName(name string) string {
+	dir, base := path.Split(name)
+	return dir + whiteoutPrefix + base
+}
+
+func (o *OverlayFS) Open(name string) (fs.File, error) {
+	if err := o.check("open", name); err != nil {
+		return nil, err
+	}
+
+	info, err := o.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &overlayDir{fsys: o, name: name, info: info}, nil
+	}
+	if o.upperExists(name) {
+		return o.upper.Open(name)
+	}
+
+	return o.base.Open(name)
+}
+
+func (o *OverlayFS) Stat(name string) (fs.FileInfo, error) {
+	if err := o.check("stat", name); err != nil {
+		return nil, err
+	}
+
+	info, err := o.upper.Stat(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return info, err
+	}
+
+	info, ok := o.inBase(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return info, nil
+}
+
+// ReadDir lists the union of both layers, with upper entries replacing base entries of
+// the same name and whited out entries left out.
+func (o *OverlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err := o.check("readdir", name); err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]fs.DirEntry)
+	found := false
+
+	upperEntries, err := o.upper.ReadDir(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		found = true
+		for _, entry := range upperEntries {
+			if !strings.HasPrefix(entry.Name(), whiteoutPrefix) {
+				merged[entry.Name()] = entry
+			}
+		}
+	}
+
+	opaque := found && o.upperExists(path.Join(name, opaqueMarker))
+	if info, ok := o.inBase(name); ok && info.IsDir() && !opaque {
+		baseEntries, err := fs.ReadDir(o.base, name)
+		if err != nil && !found {
+			return nil, err
+		}
+		found = true
+		for _, entry := range baseEntries {
+			if _, ok := merged[entry.Name()]; ok {
+				continue
+			}
+			if o.upperExists(whiteoutName(path.Join(name, entry.Name()))) {
+				continue
+			}
+			merged[entry.Name()] = entry
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(merged))
+	for _, entry := range merged {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, nil
+}
+
+func (o *OverlayFS) ReadFile(name string) ([]byte, error) {
+	if err := o.check("readfile", name); err != nil {
+		return nil, err
+	}
+
+	content, err := o.upper.ReadFile(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return content, err
+	}
+	if _, ok := o.inBase(name); !ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return fs.ReadFile(o.base, name)
+}
+
+// WriteFile writes the file to the upper layer, copying up its parent directories.
+func (o *OverlayFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := o.check("writefile", name); err != nil {
+		return err
+	}
+	if err := o.prepare(name); err != nil {
+		return err
+	}
+
+	return o.upper.WriteFile(name, data, perm)
+}
+
+// Create creates the file in the upper layer, copying up its parent directories.
+func (o *OverlayFS) Create(name string) (io.WriteCloser, error) {
+	if err := o.check("create", name); err != nil {
+		return nil, err
+	}
+	if err := o.prepare(name); err != nil {
+		return nil, err
+	}
+
+	return o.upper.Create(name)
+}
+
+// MkdirAll creates the directory and its parents in the upper layer. Directories that
+// exist in the base are copied up with their base permissions.
+func (o *OverlayFS) MkdirAll(name string, perm fs.FileMode) error {
+	if err := o.check("mkdir", name); err != nil {
+		return err
+	}
+	if name == "." {
+		return nil
+	}
+	if err := o.MkdirAll(path.Dir(name), perm); err != nil {
+		return err
+	}
+
+	info, err := o.upper.Stat(name)
+	if err == nil {
+		if !info.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+		}
+		return nil
+	}
+
+	whitedOut := o.upperExists(whiteoutName(name))
+	mode := perm
+	if info, ok := o.inBase(name); ok {
+		if !info.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+		}
+		mode = info.Mode().Perm()
+	}
+
+	err = o.upper.MkdirAll(name, mode)
+	if err != nil {
+		return err
+	}
+	if !whitedOut {
+		return nil
+	}
+
+	// The directory replaces a deleted one, so the old base content must stay hidden
+	err = o.upper.WriteFile(path.Join(name, opaqueMarker), nil, 0644)
+	if err != nil {
+		return err
+	}
+	return o.removeWhiteout(name)
+}
+
+// Remove removes a file or empty directory, leaving a whiteout if it exists in the base.
+func (o *OverlayFS) Remove(name string) error {
+	if err := o.check("remove", name); err != nil {
+		return err
+	}
+
+	info, err := o.Stat(name)
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if info.IsDir() {
+		entries, err := o.ReadDir(name)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+		}
+	}
+
+	return o.remove(name)
+}
+
+// RemoveAll removes a file or directory and everything it contains, leaving a whiteout
+// if it exists in the base. It returns nil if name doesn't exist.
+func (o *OverlayFS) RemoveAll(name string) error {
+	if err := o.check("removeall", name); err != nil {
+		return err
+	}
+	if name == "." {
+		return &fs.PathError{Op: "removeall", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return o.remove(name)
+}
+
+// remove deletes name from the upper layer and hides it in the base.
+func (o *OverlayFS) remove(name string) error {
+	err := o.upper.RemoveAll(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := o.inBase(name); !ok {
+		return nil
+	}
+
+	err = o.MkdirAll(path.Dir(name), 0755)
+	if err != nil {
+		return err
+	}
+
+	return o.upper.WriteFile(whiteoutName(name), nil, 0644)
+}
+
+// Rename moves a file or directory. Entries that only exist in the upper layer are
+// renamed in place, while entries from the base are copied up to the new name.
+func (o *OverlayFS) Rename(oldname, newname string) error {
+	if err := o.check("rename", oldname); err != nil {
+		return err
+	}
+	if err := o.check("rename", newname); err != nil {
+		return err
+	}
+	if _, err := o.Stat(oldname); err != nil {
+		return err
+	}
+
+	if !o.baseHas(oldname) && !o.baseHas(newname) {
+		err := o.prepare(newname)
+		if err != nil {
+			return err
+		}
+		return o.upper.Rename(oldname, newname)
+	}
+
+	err := fs.WalkDir(o, oldname, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := newname + strings.TrimPrefix(name, oldname)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return o.MkdirAll(target, info.Mode().Perm())
+		}
+
+		content, err := o.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return o.WriteFile(target, content, info.Mode().Perm())
+	})
+	if err != nil {
+		return err
+	}
+
+	return o.RemoveAll(oldname)
+}
+
+// baseHas reports whether name exists in the base layer, whiteouts or not.
+func (o *OverlayFS) baseHas(name string) bool {
+	_, err := fs.Stat(o.base, name)
+	return err == nil
+}
+
+// prepare makes the parent directory of name exist in the upper layer and clears any
+// whiteout for name, so it can be written.
+func (o *OverlayFS) prepare(name string) error {
+	err := o.MkdirAll(path.Dir(name), 0755)
+	if err != nil {
+		return err
+	}
+
+	return o.removeWhiteout(name)
+}
+
+// removeWhiteout deletes the whiteout for name, if there is one.
+func (o *OverlayFS) removeWhiteout(name string) error {
+	err := o.upper.Remove(whiteoutName(name))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// overlayDir is a directory opened from an OverlayFS, listing the merged entries.
+type overlayDir struct {
+	fsys    *OverlayFS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	loaded  bool
+	offset  int
+}
+
+func (d *overlayDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *overlayDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: syscall.EISDIR}
+}
+
+func (d *overlayDir) Close() error {
+	return nil
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.loaded {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.loaded = true
+	}
+
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,152 @@
+package fs_go
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestOverlay(t *testing.T, path string) (*OverlayFS, fstest.MapFS) {
+	t.Helper()
+
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatalf("os.Mkdir failed: %v", err)
+	}
+	base := fstest.MapFS{
+		"README.md":        {Data: []byte("readme")},
+		"src/main.go":      {Data: []byte("package main")},
+		"src/util/util.go": {Data: []byte("package util")},
+	}
+
+	return NewOverlayFS(base, DirFS(path)), base
+}
+
+func TestOverlayFS(t *testing.T) {
+	// Expect reads to fall through to the base and writes to go to the upper layer
+	t.Run("read through", func(t *testing.T) {
+		path := "overlay_fs_1"
+		defer os.RemoveAll(path)
+		fsys, base := newTestOverlay(t, path)
+
+		err := fsys.WriteFile("src/main.go", []byte("package patched"), 0644)
+		if err != nil {
+			t.Errorf("WriteFile failed: %v", err)
+		}
+
+		content, err := fsys.ReadFile("src/main.go")
+		if err != nil || string(content) != "package patched" {
+			t.Errorf("Expected patched content, got %q (%v)", content, err)
+		}
+		if string(base["src/main.go"].Data) != "package main" {
+			t.Errorf("Expected base to be unchanged")
+		}
+
+		content, err = fsys.ReadFile("README.md")
+		if err != nil || string(content) != "readme" {
+			t.Errorf("Expected base content, got %q (%v)", content, err)
+		}
+
+		err = fstest.TestFS(fsys, "README.md", "src/main.go", "src/util/util.go")
+		if err != nil {
+			t.Errorf("fstest.TestFS failed: %v", err)
+		}
+	})
+
+	// Expect removed base entries to be hidden and listings to be merged
+	t.Run("remove", func(t *testing.T) {
+		path := "overlay_fs_2"
+		defer os.RemoveAll(path)
+		fsys, _ := newTestOverlay(t, path)
+
+		if err := fsys.Remove("README.md"); err != nil {
+			t.Errorf("Remove failed: %v", err)
+		}
+		if _, err := fsys.Stat("README.md"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected README.md to be removed, got %v", err)
+		}
+		if err := fsys.Remove("src"); err == nil {
+			t.Errorf("Expected removing a non-empty directory to fail")
+		}
+		if err := fsys.WriteFile("LICENSE", []byte("MIT"), 0644); err != nil {
+			t.Errorf("WriteFile failed: %v", err)
+		}
+
+		entries, err := fsys.ReadDir(".")
+		if err != nil {
+			t.Fatalf("ReadDir failed: %v", err)
+		}
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		if len(names) != 2 || names[0] != "LICENSE" || names[1] != "src" {
+			t.Errorf("Expected [LICENSE src], got %v", names)
+		}
+
+		if err := fsys.WriteFile("README.md", []byte("new"), 0644); err != nil {
+			t.Errorf("WriteFile failed: %v", err)
+		}
+		content, err := fsys.ReadFile("README.md")
+		if err != nil || string(content) != "new" {
+			t.Errorf("Expected recreated content, got %q (%v)", content, err)
+		}
+	})
+
+	// Expect a recreated directory to not show its deleted base content
+	t.Run("opaque", func(t *testing.T) {
+		path := "overlay_fs_3"
+		defer os.RemoveAll(path)
+		fsys, _ := newTestOverlay(t, path)
+
+		if err := fsys.RemoveAll("src"); err != nil {
+			t.Errorf("RemoveAll failed: %v", err)
+		}
+		if err := fsys.WriteFile("src/new.go", []byte("package src"), 0644); err != nil {
+			t.Errorf("WriteFile failed: %v", err)
+		}
+
+		entries, err := fsys.ReadDir("src")
+		if err != nil {
+			t.Fatalf("ReadDir failed: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Name() != "new.go" {
+			t.Errorf("Expected only new.go, got %v", entries)
+		}
+		if _, err := fsys.Stat("src/util/util.go"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected src/util/util.go to stay hidden, got %v", err)
+		}
+	})
+
+	// Expect renaming a base directory to copy it up
+	t.Run("rename", func(t *testing.T) {
+		path := "overlay_fs_4"
+		defer os.RemoveAll(path)
+		fsys, _ := newTestOverlay(t, path)
+
+		if err := fsys.Rename("src", "lib"); err != nil {
+			t.Fatalf("Rename failed: %v", err)
+		}
+		if _, err := fsys.Stat("src"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected src to be gone, got %v", err)
+		}
+
+		content, err := fsys.ReadFile("lib/util/util.go")
+		if err != nil || string(content) != "package util" {
+			t.Errorf("Expected copied content, got %q (%v)", content, err)
+		}
+	})
+
+	// Expect whiteout names to be reserved
+	t.Run("reserved", func(t *testing.T) {
+		path := "overlay_fs_5"
+		defer os.RemoveAll(path)
+		fsys, _ := newTestOverlay(t, path)
+
+		err := fsys.WriteFile(".wh.README.md", nil, 0644)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Expected fs.ErrInvalid, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package fs_go
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	// ErrOutOfBounds means an offset or region lies outside of a file.
+	ErrOutOfBounds = errors.New("out of bounds")
+	// ErrPreimageMismatch means the bytes a patch was about to overwrite weren't the expected ones.
+	ErrPreimageMismatch = errors.New("pre-image mismatch")
+)
+
+// Patch replaces a region of a file, starting at Offset, with Data.
+type Patch struct {
+	Offset int64
+	Data   []byte
+	// Expect, if set, is the content the region must have before patching, which guards
+	// against patching the wrong version of a file. It must be as long as Data.
+	Expect []byte
+}
+
+// PatchBytes overwrites the bytes of a file at offset with data, in place. Unlike
+// WriteBytesAt, it never grows the file, and fails with ErrOutOfBounds if the region
+// doesn't lie within it.
+//
+// Example:
+//
+//	err := PatchBytes("save.dat", 0x40, []byte{0xff, 0xff})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func PatchBytes(path string, offset int64, data []byte) error {
+	return applyPatches("PatchBytes", path, []Patch{{Offset: offset, Data: data}})
+}
+
+// ApplyPatches writes several patches to a file in place, in order. Every patch is checked
+// against the bounds of the file and its expected pre-image before anything is written,
+// so a file either gets all patches or none.
+//
+// Example:
+//
+//	err := ApplyPatches("firmware.bin", []Patch{
+//	    {Offset: 0x100, Data: []byte{0x01}, Expect: []byte{0x00}},
+//	    {Offset: 0x200, Data: newChecksum, Expect: oldChecksum},
+//	})
+//	if errors.Is(err, ErrPreimageMismatch) {
+//	    fmt.Println("unexpected firmware version")
+//	}
+func ApplyPatches(path string, patches []Patch) error {
+	return applyPatches("ApplyPatches", path, patches)
+}
+
+// applyPatches validates and then writes patches.
+func applyPatches(op, path string, patches []Patch) (err error) {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard(op, path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	var written int
+	defer func() { observeWrite(op, written, err) }()
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return pathError(op, path, fmt.Errorf("%s failed to open file: %w", op, err))
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return pathError(op, path, fmt.Errorf("%s failed to get file stat: %w", op, err))
+	}
+
+	for i, patch := range patches {
+		end := patch.Offset + int64(len(patch.Data))
+		if patch.Offset < 0 || end > info.Size() {
+			return pathError(op, path, fmt.Errorf("%s failed: patch %d at [%d, %d) of %d bytes: %w", op, i+1, patch.Offset, end, info.Size(), ErrOutOfBounds))
+		}
+		if patch.Expect == nil {
+			continue
+		}
+		if len(patch.Expect) != len(patch.Data) {
+			return pathError(op, path, fmt.Errorf("%s failed: patch %d expects %d bytes but writes %d", op, i+1, len(patch.Expect), len(patch.Data)))
+		}
+
+		current := make([]byte, len(patch.Expect))
+		_, err = file.ReadAt(current, patch.Offset)
+		if err != nil && err != io.EOF {
+			return pathError(op, path, fmt.Errorf("%s failed to read patch %d: %w", op, i+1, err))
+		}
+		if !bytes.Equal(current, patch.Expect) {
+			return pathError(op, path, fmt.Errorf("%s failed: patch %d at %d: %w", op, i+1, patch.Offset, ErrPreimageMismatch))
+		}
+	}
+
+	for i, patch := range patches {
+		n, err := file.WriteAt(patch.Data, patch.Offset)
+		written += n
+		if err != nil {
+			return pathError(op, path, fmt.Errorf("%s failed to write patch %d: %w", op, i+1, err))
+		}
+	}
+
+	err = file.Close()
+	if err != nil {
+		return pathError(op, path, fmt.Errorf("%s failed to close file: %w", op, err))
+	}
+
+	return nil
+}
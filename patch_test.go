@@ -0,0 +1,61 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestPatchBytes(t *testing.T) {
+	path := "patch_bytes.bin"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("0123456789"), 0644)
+
+	// Expect the region to be overwritten in place
+	err := PatchBytes(path, 2, []byte("ab"))
+	if err != nil {
+		t.Fatalf("PatchBytes failed: %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "01ab456789" {
+		t.Errorf("Expected 01ab456789, got %q", content)
+	}
+
+	// Expect writes past the end to fail without growing the file
+	err = PatchBytes(path, 9, []byte("xy"))
+	if !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("Expected ErrOutOfBounds, got %v", err)
+	}
+	if content, _ := os.ReadFile(path); len(content) != 10 {
+		t.Errorf("Expected 10 bytes, got %d", len(content))
+	}
+}
+
+func TestApplyPatches(t *testing.T) {
+	path := "apply_patches.bin"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("0123456789"), 0644)
+
+	// Expect all patches to be applied if their pre-images match
+	err := ApplyPatches(path, []Patch{
+		{Offset: 0, Data: []byte("A"), Expect: []byte("0")},
+		{Offset: 9, Data: []byte("Z")},
+	})
+	if err != nil {
+		t.Fatalf("ApplyPatches failed: %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "A12345678Z" {
+		t.Errorf("Expected A12345678Z, got %q", content)
+	}
+
+	// Expect a mismatching pre-image to prevent every patch
+	err = ApplyPatches(path, []Patch{
+		{Offset: 1, Data: []byte("B")},
+		{Offset: 0, Data: []byte("C"), Expect: []byte("0")},
+	})
+	if !errors.Is(err, ErrPreimageMismatch) {
+		t.Errorf("Expected ErrPreimageMismatch, got %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "A12345678Z" {
+		t.Errorf("Expected the file to be unchanged, got %q", content)
+	}
+}
@@ -0,0 +1,288 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// maxNameLength is the longest file name in bytes most file systems accept.
+const maxNameLength = 255
+
+// windowsReservedNames are the device names Windows reserves in every directory,
+// with or without an extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// ValidateFilename checks whether name can be used as a file name on the current OS.
+// It flags empty names, "." and "..", path separators, names that are too long, and on
+// Windows reserved device names such as CON or NUL, illegal characters, and trailing
+// dots or spaces.
+//
+// Example:
+//
+//	err := ValidateFilename(userInput)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ValidateFilename(name string) error {
+	err := validateFilename(name, runtime.GOOS)
+	if err != nil {
+		return fmt.Errorf("ValidateFilename failed: %w", err)
+	}
+
+	return nil
+}
+
+// validateFilename checks name against the rules of the given GOOS.
+func validateFilename(name, goos string) error {
+	if name == "" {
+		return fmt.Errorf("name is empty")
+	}
+	if name == "." || name == ".." {
+		return fmt.Errorf("%q is not a file name", name)
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("%q is longer than %d bytes", name, maxNameLength)
+	}
+	if i := strings.IndexAny(name, "/\x00"); i >= 0 {
+		return fmt.Errorf("%q contains illegal character %q", name, name[i])
+	}
+	if goos != "windows" {
+		return nil
+	}
+
+	for _, r := range name {
+		if r < 0x20 || strings.ContainsRune(`<>:"\|?*`, r) {
+			return fmt.Errorf("%q contains illegal character %q on windows", name, r)
+		}
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return fmt.Errorf("%q ends with a dot or space on windows", name)
+	}
+
+	// Reserved names stay reserved with an extension, and trailing spaces before it are ignored
+	base, _, _ := strings.Cut(name, ".")
+	if windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+		return fmt.Errorf("%q is a reserved name on windows", name)
+	}
+
+	return nil
+}
+
+// prefixLongPath returns an absolute, cleaned Windows path in its extended-length form,
+// which lifts the MAX_PATH limit of 260 characters.
+// Device paths and paths that already have the prefix are returned as is.
+func prefixLongPath(path string) string {
+	switch {
+	case strings.HasPrefix(path, `\\?\`), strings.HasPrefix(path, `\\.\`):
+		return path
+	case strings.HasPrefix(path, `\\`):
+		return `\\?\UNC\` + path[2:]
+	default:
+		return `\\?\` + path
+	}
+}
+
+// normalizePath prepares a path for the file system. A leading ~ is expanded to the home
+// directory, and on Windows long paths get the extended-length prefix.
+func normalizePath(path string) string {
+	if expanded, err := ExpandHome(path); err == nil {
+		path = expanded
+	}
+
+	return longPath(path)
+}
+
+// ExpandHome replaces a leading "~" in path with the home directory of the current user.
+// Other paths, including "~user" forms, are returned as is. Every function in this
+// package that takes a path expands it, so a file literally named "~" must be passed as "./~".
+//
+// Example:
+//
+//	path, err := ExpandHome("~/.config/app")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ExpandHome failed to find home directory: %w", err)
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// ExpandEnv replaces $VAR and ${VAR} in path with environment variables, then expands a
+// leading "~". Unset variables are replaced with an empty string.
+//
+// Example:
+//
+//	path, err := ExpandEnv("$XDG_STATE_HOME/app/state.json")
+func ExpandEnv(path string) (string, error) {
+	return ExpandHome(os.ExpandEnv(path))
+}
+
+// NormalizePath returns path expanded with ExpandHome, made absolute and cleaned.
+// Symlinks are not resolved.
+//
+// Example:
+//
+//	path, err := NormalizePath("~/projects/../notes.txt")
+//	// path is "/home/user/notes.txt"
+func NormalizePath(path string) (string, error) {
+	expanded, err := ExpandHome(path)
+	if err != nil {
+		return "", fmt.Errorf("NormalizePath failed: %w", err)
+	}
+
+	abs, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", fmt.Errorf("NormalizePath failed to make path absolute: %w", err)
+	}
+
+	return abs, nil
+}
+
+// IsSubPath reports whether child is parent itself or inside it. Both are normalized with
+// NormalizePath first, so relative paths and "~" work. The check is lexical and doesn't
+// resolve symlinks.
+//
+// Example:
+//
+//	inside, err := IsSubPath("/srv/uploads", requested)
+//	if err != nil || !inside {
+//	    return errForbidden
+//	}
+func IsSubPath(parent, child string) (bool, error) {
+	rel, err := relNormalized(parent, child)
+	if err != nil {
+		return false, fmt.Errorf("IsSubPath failed: %w", err)
+	}
+
+	return isLocalRel(rel), nil
+}
+
+// RelOrAbs returns path relative to base if it is inside base, and absolute otherwise.
+// It suits paths shown to users, which are shortest relative to a working directory.
+//
+// Example:
+//
+//	wd, _ := os.Getwd()
+//	display, err := RelOrAbs(wd, path)
+func RelOrAbs(base, path string) (string, error) {
+	rel, err := relNormalized(base, path)
+	if err != nil {
+		return "", fmt.Errorf("RelOrAbs failed: %w", err)
+	}
+	if isLocalRel(rel) {
+		return rel, nil
+	}
+
+	abs, err := NormalizePath(path)
+	if err != nil {
+		return "", fmt.Errorf("RelOrAbs failed: %w", err)
+	}
+
+	return abs, nil
+}
+
+// maxSymlinks limits the symlinks SecureJoin follows, like the kernel does for lookups.
+const maxSymlinks = 255
+
+// SecureJoin joins an untrusted path to root like a chroot would: ".." components and
+// symlinks are resolved one component at a time, and neither can lead outside root. A
+// ".." at the root stays at the root, and an absolute symlink target is taken relative
+// to root. Components that don't exist are joined as they are.
+//
+// The result is only safe as long as nobody replaces a component with a symlink before
+// it is used, so root must not be writable by the users the path comes from.
+//
+// Example:
+//
+//	path, err := SecureJoin("/srv/uploads", r.URL.Query().Get("file"))
+//	if err != nil {
+//	    return err
+//	}
+//	content, err := ReadBytes(path) // Always inside /srv/uploads
+func SecureJoin(root, untrusted string) (string, error) {
+	root = filepath.Clean(normalizePath(root))
+
+	var resolved string // Relative to root, and never above it
+	remaining := stripVolume(filepath.FromSlash(untrusted))
+	for links := 0; remaining != ""; {
+		var part string
+		part, remaining, _ = strings.Cut(remaining, string(filepath.Separator))
+
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if resolved = filepath.Dir(resolved); resolved == "." {
+				resolved = ""
+			}
+			continue
+		}
+
+		next := filepath.Join(resolved, part)
+		info, err := os.Lstat(filepath.Join(root, next))
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", pathError("SecureJoin", root, fmt.Errorf("SecureJoin failed to resolve %s: too many symlinks", untrusted))
+		}
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", pathError("SecureJoin", root, fmt.Errorf("SecureJoin failed to read symlink: %w", err))
+		}
+		target = stripVolume(filepath.FromSlash(target))
+		if strings.HasPrefix(target, string(filepath.Separator)) {
+			resolved = ""
+		}
+		remaining = target + string(filepath.Separator) + remaining
+	}
+
+	return filepath.Join(root, resolved), nil
+}
+
+// stripVolume removes the volume name of a Windows path, like "C:" or "\\server\share".
+func stripVolume(path string) string {
+	return path[len(filepath.VolumeName(path)):]
+}
+
+// relNormalized returns the relative path from base to path, after normalizing both.
+func relNormalized(base, path string) (string, error) {
+	base, err := NormalizePath(base)
+	if err != nil {
+		return "", err
+	}
+	path, err = NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Rel(base, path)
+}
+
+// isLocalRel reports whether a relative path stays inside its base.
+func isLocalRel(rel string) bool {
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package fs_go
+
+// longPath returns path as is. Long paths need no prefix outside Windows.
+func longPath(path string) string {
+	return path
+}
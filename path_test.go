@@ -0,0 +1,343 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestValidateFilename(t *testing.T) {
+	// Expect ordinary names to be valid everywhere
+	t.Run("valid names", func(t *testing.T) {
+		for _, goos := range []string{"linux", "darwin", "windows"} {
+			for _, name := range []string{"report.pdf", ".hidden", "CONSOLE.txt", "a b"} {
+				if err := validateFilename(name, goos); err != nil {
+					t.Errorf("Expected %q to be valid on %s, got %v", name, goos, err)
+				}
+			}
+		}
+	})
+
+	// Expect names that are invalid everywhere to be flagged
+	t.Run("invalid everywhere", func(t *testing.T) {
+		for _, name := range []string{"", ".", "..", "a/b", "a\x00b", strings.Repeat("a", 256)} {
+			if err := validateFilename(name, "linux"); err == nil {
+				t.Errorf("Expected %q to be invalid", name)
+			}
+		}
+	})
+
+	// Expect reserved names and illegal characters to be flagged only on windows
+	t.Run("windows rules", func(t *testing.T) {
+		for _, name := range []string{"CON", "nul.txt", "Com1.tar.gz", "LPT9", "a:b", "a?", "a*b", "a\\b", "trailing.", "trailing "} {
+			if err := validateFilename(name, "windows"); err == nil {
+				t.Errorf("Expected %q to be invalid on windows", name)
+			}
+			if err := validateFilename(name, "linux"); err != nil {
+				t.Errorf("Expected %q to be valid on linux, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestPrefixLongPath(t *testing.T) {
+	// Expect drive, UNC, and already prefixed paths to get the right prefix
+	t.Run("prefixes", func(t *testing.T) {
+		cases := map[string]string{
+			`C:\data\file.txt`:     `\\?\C:\data\file.txt`,
+			`\\server\share\file`:  `\\?\UNC\server\share\file`,
+			`\\?\C:\data\file.txt`: `\\?\C:\data\file.txt`,
+			`\\.\pipe\fs_go`:       `\\.\pipe\fs_go`,
+		}
+		for path, expected := range cases {
+			if actual := prefixLongPath(path); actual != expected {
+				t.Errorf("Expected %s to become %s, got %s", path, expected, actual)
+			}
+		}
+	})
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("No home directory: %v", err)
+	}
+
+	// Expect only a leading ~ to be expanded
+	t.Run("expand", func(t *testing.T) {
+		cases := map[string]string{
+			"~":           home,
+			"~/notes.txt": filepath.Join(home, "notes.txt"),
+			"~user/x":     "~user/x",
+			"a/~/b":       "a/~/b",
+			"./~":         "./~",
+		}
+		for path, expected := range cases {
+			if actual, err := ExpandHome(path); err != nil || actual != expected {
+				t.Errorf("Expected %s to become %s, got %s (%v)", path, expected, actual, err)
+			}
+		}
+	})
+
+	// Expect package functions to expand ~ themselves
+	t.Run("package functions", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("The home directory is not taken from HOME on Windows")
+		}
+		home, err := filepath.Abs("expand_home_1")
+		if err != nil {
+			t.Fatalf("Abs failed: %v", err)
+		}
+		defer os.RemoveAll(home)
+		os.Mkdir(home, 0755)
+		t.Setenv("HOME", home)
+
+		err = WriteText("~/file.txt", "content")
+		if err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+		if content, _ := os.ReadFile(filepath.Join(home, "file.txt")); string(content) != "content" {
+			t.Errorf("Expected file in home directory, got %q", content)
+		}
+	})
+
+	// Expect every entry point that takes a path to expand ~ before touching the disk
+	t.Run("entry points", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("The home directory is not taken from HOME on Windows")
+		}
+		home, err := filepath.Abs("expand_home_2")
+		if err != nil {
+			t.Fatalf("Abs failed: %v", err)
+		}
+		defer os.RemoveAll(home)
+		writeTree(t, home, map[string]string{"a.txt": "a", "b.txt": "a", "src/c.txt": "c"})
+		t.Setenv("HOME", home)
+		t.Setenv("XDG_DATA_HOME", "")
+
+		cases := map[string]func() error{
+			"Equal": func() error {
+				_, err := Equal("~/a.txt", "~/b.txt")
+				return err
+			},
+			"EqualContent": func() error {
+				_, err := EqualContent("~/a.txt", []byte("a"))
+				return err
+			},
+			"DiffDirs": func() error {
+				_, err := DiffDirs("~/src", "~/src")
+				return err
+			},
+			"Chunks": func() error {
+				it, err := Chunks("~/a.txt", 1)
+				if err == nil {
+					it.Close()
+				}
+				return err
+			},
+			"CopyDir": func() error {
+				return CopyDir("~/src", "~/copy")
+			},
+			"SyncDir": func() error {
+				_, err := SyncDir("~/src", "~/sync")
+				return err
+			},
+			"WriteListing": func() error {
+				return WriteListing("~/src", "~/listing.json", FormatJSON, []Field{FieldPath})
+			},
+			"SaveVersion": func() error {
+				version, err := SaveVersion("~/a.txt")
+				if err != nil {
+					return err
+				}
+				if _, err := ListVersions("~/a.txt"); err != nil {
+					return err
+				}
+				if err := RestoreVersion("~/a.txt", version.ID); err != nil {
+					return err
+				}
+				_, err = PruneVersions("~/a.txt", PrunePolicy{})
+				return err
+			},
+			"LockRange": func() error {
+				lock, err := LockRange("~/a.txt", 0, 1, false)
+				if err == nil {
+					lock.Unlock()
+				}
+				return err
+			},
+			"Mmap": func() error {
+				mapped, err := Mmap("~/a.txt")
+				if err == nil {
+					mapped.Unmap()
+				}
+				return err
+			},
+			"CaptureMeta": func() error {
+				meta, err := CaptureMeta("~/a.txt")
+				if err != nil {
+					return err
+				}
+				return ApplyMeta("~/b.txt", meta)
+			},
+			"Preallocate": func() error {
+				return Preallocate("~/prealloc.bin", 16)
+			},
+			"SparseRegions": func() error {
+				_, err := SparseRegions("~/a.txt")
+				return err
+			},
+			"Checkpointer": func() error {
+				c, err := NewCheckpointer("~/ckpt", func() int { return 1 }, CheckpointOptions{})
+				if err != nil {
+					return err
+				}
+				if err := c.Checkpoint(); err != nil {
+					return err
+				}
+				_, err = LoadLatestCheckpoint[int]("~/ckpt")
+				return err
+			},
+			"Verifier": func() error {
+				v, err := NewVerifier(map[string]string{"~/a.txt": ""}, VerifyOptions{})
+				if err != nil {
+					return err
+				}
+				mismatches, _, err := v.Step(1)
+				if err == nil && len(mismatches) == 1 && mismatches[0].Missing {
+					return fmt.Errorf("~/a.txt was reported missing")
+				}
+				return err
+			},
+			"Trash": func() error {
+				os.WriteFile(filepath.Join(home, "trashed.txt"), []byte("t"), 0644)
+				_, err := Trash("~/trashed.txt")
+				return err
+			},
+		}
+		for name, fn := range cases {
+			if err := fn(); err != nil {
+				t.Errorf("Expected %s to expand ~, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestExpandEnv(t *testing.T) {
+	// Expect variables to be replaced
+	t.Run("variables", func(t *testing.T) {
+		t.Setenv("FS_GO_TEST_DIR", "data")
+		path, err := ExpandEnv("$FS_GO_TEST_DIR/${FS_GO_TEST_DIR}.json")
+		if err != nil || path != "data/data.json" {
+			t.Errorf("Expected data/data.json, got %s (%v)", path, err)
+		}
+	})
+}
+
+func TestNormalizePath(t *testing.T) {
+	// Expect relative paths to become absolute and clean
+	t.Run("absolute", func(t *testing.T) {
+		wd, _ := os.Getwd()
+		path, err := NormalizePath("a/../b/./c")
+		if err != nil || path != filepath.Join(wd, "b", "c") {
+			t.Errorf("Expected %s, got %s (%v)", filepath.Join(wd, "b", "c"), path, err)
+		}
+	})
+}
+
+func TestIsSubPath(t *testing.T) {
+	// Expect paths inside the parent, and the parent itself, to be sub paths
+	t.Run("sub paths", func(t *testing.T) {
+		cases := []struct {
+			parent, child string
+			expected      bool
+		}{
+			{"data", "data/file", true},
+			{"data", "data", true},
+			{"data", "data/../data/x", true},
+			{"data", "data/../other", false},
+			{"data", "database", false},
+			{"data/sub", "data", false},
+			{"data", "data/..file", true},
+		}
+		for _, c := range cases {
+			actual, err := IsSubPath(c.parent, c.child)
+			if err != nil || actual != c.expected {
+				t.Errorf("Expected IsSubPath(%s, %s) to be %v, got %v (%v)", c.parent, c.child, c.expected, actual, err)
+			}
+		}
+	})
+}
+
+func TestRelOrAbs(t *testing.T) {
+	// Expect paths inside the base to be relative and others absolute
+	t.Run("display", func(t *testing.T) {
+		rel, err := RelOrAbs("data", "data/sub/file")
+		if err != nil || rel != filepath.Join("sub", "file") {
+			t.Errorf("Expected sub/file, got %s (%v)", rel, err)
+		}
+
+		abs, err := RelOrAbs("data", "other/file")
+		if err != nil || !filepath.IsAbs(abs) {
+			t.Errorf("Expected an absolute path, got %s (%v)", abs, err)
+		}
+	})
+}
+
+func TestSecureJoin(t *testing.T) {
+	// Expect ".." to stop at the root
+	t.Run("dot dot", func(t *testing.T) {
+		cases := map[string]string{
+			"a/b.txt":          filepath.Join("root", "a", "b.txt"),
+			"../../etc/passwd": filepath.Join("root", "etc", "passwd"),
+			"a/../../b":        filepath.Join("root", "b"),
+			"/etc/passwd":      filepath.Join("root", "etc", "passwd"),
+			"":                 "root",
+			"./a/./b/../c":     filepath.Join("root", "a", "c"),
+		}
+		for untrusted, expected := range cases {
+			actual, err := SecureJoin("root", untrusted)
+			if err != nil || actual != expected {
+				t.Errorf("Expected SecureJoin(root, %s) to be %s, got %s (%v)", untrusted, expected, actual, err)
+			}
+		}
+	})
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	root := "secure_join"
+	defer os.RemoveAll(root)
+	writeTree(t, root, map[string]string{"data/a.txt": "a"})
+	os.Symlink("../../..", filepath.Join(root, "data", "up"))
+	os.Symlink("/etc", filepath.Join(root, "abs"))
+	os.Symlink("data/a.txt", filepath.Join(root, "inside"))
+	os.Symlink("loop", filepath.Join(root, "loop"))
+
+	// Expect symlinks to be followed, but never outside the root
+	t.Run("symlinks", func(t *testing.T) {
+		cases := map[string]string{
+			"data/up/etc/passwd": filepath.Join(root, "etc", "passwd"),
+			"abs/passwd":         filepath.Join(root, "etc", "passwd"),
+			"inside":             filepath.Join(root, "data", "a.txt"),
+		}
+		for untrusted, expected := range cases {
+			actual, err := SecureJoin(root, untrusted)
+			if err != nil || actual != expected {
+				t.Errorf("Expected SecureJoin(%s, %s) to be %s, got %s (%v)", root, untrusted, expected, actual, err)
+			}
+		}
+	})
+
+	// Expect a symlink loop to fail
+	t.Run("loop", func(t *testing.T) {
+		_, err := SecureJoin(root, "loop/a")
+		if err == nil {
+			t.Errorf("Expected a symlink loop to fail")
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package fs_go
+
+import "path/filepath"
+
+// maxShortPath is the length from which paths get the extended-length prefix.
+// Directories are limited to MAX_PATH minus room for an 8.3 file name.
+const maxShortPath = 248
+
+// longPath prefixes long paths with \\?\ so they aren't limited to MAX_PATH.
+func longPath(path string) string {
+	if len(path) < maxShortPath {
+		return path
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	return prefixLongPath(abs)
+}
@@ -0,0 +1,195 @@
+package fs_go
+
+import (
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+// PathKind is the form of a path, as told by ClassifyPath.
+type PathKind int
+
+const (
+	RelativePath      PathKind = iota // Relative to the working directory, like "a/b"
+	AbsolutePath                      // Absolute, like "/a/b" or "C:\a\b"
+	UNCPath                           // A Windows network path, like `\\server\share\a`
+	DriveRelativePath                 // Relative to the working directory of a Windows drive, like "C:a"
+	RootRelativePath                  // Relative to the root of the current Windows drive, like `\a`
+	FileURLPath                       // A file URL, like "file:///a/b"
+	EmptyPath                         // The empty string, which isn't a path
+)
+
+func (k PathKind) String() string {
+	switch k {
+	case RelativePath:
+		return "relative"
+	case AbsolutePath:
+		return "absolute"
+	case UNCPath:
+		return "unc"
+	case DriveRelativePath:
+		return "drive-relative"
+	case RootRelativePath:
+		return "root-relative"
+	case FileURLPath:
+		return "file-url"
+	case EmptyPath:
+		return "empty"
+	default:
+		return fmt.Sprintf("PathKind(%d)", int(k))
+	}
+}
+
+// ClassifyPath returns the form of a path. Windows forms like drives and UNC paths are
+// only recognized on Windows, where they have a meaning; elsewhere "C:a" is a relative
+// path. File URLs are recognized everywhere and can be converted with FromFileURL.
+//
+// Example:
+//
+//	switch ClassifyPath(arg) {
+//	case FileURLPath:
+//	    arg, err = FromFileURL(arg)
+//	case DriveRelativePath, RootRelativePath:
+//	    return fmt.Errorf("%s depends on the current drive", arg)
+//	}
+func ClassifyPath(path string) PathKind {
+	return classifyPath(path, runtime.GOOS)
+}
+
+// classifyPath returns the form of a path on the given GOOS.
+func classifyPath(path, goos string) PathKind {
+	switch {
+	case path == "":
+		return EmptyPath
+	case len(path) >= 6 && strings.EqualFold(path[:6], "file:/"):
+		return FileURLPath
+	case goos != "windows":
+		if strings.HasPrefix(path, "/") {
+			return AbsolutePath
+		}
+		return RelativePath
+	}
+
+	switch {
+	case strings.HasPrefix(path, `\\?\UNC\`):
+		return UNCPath
+	case strings.HasPrefix(path, `\\?\`), strings.HasPrefix(path, `\\.\`):
+		return AbsolutePath // Device paths are always absolute
+	case len(path) >= 2 && isWindowsSeparator(path[0]) && isWindowsSeparator(path[1]):
+		return UNCPath
+	case isWindowsSeparator(path[0]):
+		return RootRelativePath
+	case len(path) >= 2 && isDriveLetter(path[0]) && path[1] == ':':
+		if len(path) >= 3 && isWindowsSeparator(path[2]) {
+			return AbsolutePath
+		}
+		return DriveRelativePath
+	default:
+		return RelativePath
+	}
+}
+
+// FromFileURL converts a file URL like "file:///home/user/a%20b.txt" to a path. On
+// Windows, "file:///C:/a" becomes `C:\a` and "file://server/share/a" becomes the UNC path
+// `\\server\share\a`. Elsewhere, URLs with a host other than "localhost" fail.
+//
+// Example:
+//
+//	path, err := FromFileURL("file:///srv/data/report.csv")
+//	// path is "/srv/data/report.csv"
+func FromFileURL(rawURL string) (string, error) {
+	path, err := fromFileURL(rawURL, runtime.GOOS)
+	if err != nil {
+		return "", fmt.Errorf("FromFileURL failed: %w", err)
+	}
+
+	return path, nil
+}
+
+// fromFileURL converts a file URL to a path on the given GOOS.
+func fromFileURL(rawURL, goos string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("%q is not a file URL", rawURL)
+	}
+	if u.Opaque != "" || u.Path == "" {
+		return "", fmt.Errorf("%q has no absolute path", rawURL)
+	}
+
+	host := u.Host
+	if strings.EqualFold(host, "localhost") {
+		host = ""
+	}
+	if goos != "windows" {
+		if host != "" {
+			return "", fmt.Errorf("%q is on another host", rawURL)
+		}
+		return u.Path, nil
+	}
+
+	path := strings.ReplaceAll(u.Path, "/", `\`)
+	if host != "" {
+		return `\\` + host + path, nil
+	}
+	// "/C:/a" is the path of a drive
+	if len(path) >= 3 && isDriveLetter(path[1]) && path[2] == ':' {
+		return path[1:], nil
+	}
+
+	return "", fmt.Errorf("%q has no drive", rawURL)
+}
+
+// ToFileURL converts a path to a file URL, after making it absolute with NormalizePath.
+// Characters that aren't allowed in URLs are escaped.
+//
+// Example:
+//
+//	u, err := ToFileURL("reports/q1 summary.pdf")
+//	// u is "file:///home/user/reports/q1%20summary.pdf"
+func ToFileURL(path string) (string, error) {
+	abs, err := NormalizePath(path)
+	if err != nil {
+		return "", fmt.Errorf("ToFileURL failed: %w", err)
+	}
+
+	return toFileURL(abs, runtime.GOOS), nil
+}
+
+// toFileURL converts an absolute path to a file URL on the given GOOS.
+func toFileURL(abs, goos string) string {
+	u := url.URL{Scheme: "file", Path: abs}
+	if goos != "windows" {
+		return u.String()
+	}
+
+	switch {
+	case strings.HasPrefix(abs, `\\?\UNC\`):
+		abs = `\\` + abs[len(`\\?\UNC\`):]
+	case strings.HasPrefix(abs, `\\?\`):
+		abs = abs[len(`\\?\`):]
+	}
+
+	abs = strings.ReplaceAll(abs, `\`, "/")
+	if strings.HasPrefix(abs, "//") {
+		host, rest, _ := strings.Cut(strings.TrimPrefix(abs, "//"), "/")
+		u.Host, u.Path = host, "/"+rest
+	} else {
+		u.Path = "/" + abs
+	}
+
+	return u.String()
+}
+
+// isWindowsSeparator reports whether c separates path components on Windows.
+func isWindowsSeparator(c byte) bool {
+	return c == '\\' || c == '/'
+}
+
+// isDriveLetter reports whether c can name a Windows drive.
+func isDriveLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,107 @@
+package fs_go
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClassifyPath(t *testing.T) {
+	// Expect Windows forms to be recognized on Windows
+	t.Run("windows", func(t *testing.T) {
+		cases := map[string]PathKind{
+			`C:\a\b`:               AbsolutePath,
+			`c:/a`:                 AbsolutePath,
+			`C:a`:                  DriveRelativePath,
+			`\a`:                   RootRelativePath,
+			`\\server\share\a`:     UNCPath,
+			`\\?\UNC\server\share`: UNCPath,
+			`\\?\C:\a`:             AbsolutePath,
+			`a\b`:                  RelativePath,
+			"file:///C:/a":         FileURLPath,
+			"":                     EmptyPath,
+		}
+		for path, expected := range cases {
+			if kind := classifyPath(path, "windows"); kind != expected {
+				t.Errorf("Expected %s to be %v, got %v", path, expected, kind)
+			}
+		}
+	})
+
+	// Expect only absolute and relative paths and URLs elsewhere
+	t.Run("unix", func(t *testing.T) {
+		cases := map[string]PathKind{
+			"/a/b":           AbsolutePath,
+			"a/b":            RelativePath,
+			`C:a`:            RelativePath,
+			"file:notes":     RelativePath,
+			"FILE:///a/b":    FileURLPath,
+			`\\server\share`: RelativePath,
+		}
+		for path, expected := range cases {
+			if kind := classifyPath(path, "linux"); kind != expected {
+				t.Errorf("Expected %s to be %v, got %v", path, expected, kind)
+			}
+		}
+	})
+}
+
+func TestFileURL(t *testing.T) {
+	// Expect file URLs to be converted to paths
+	t.Run("from", func(t *testing.T) {
+		cases := []struct {
+			url, goos, expected string
+		}{
+			{"file:///srv/a%20b.txt", "linux", "/srv/a b.txt"},
+			{"file://localhost/srv/a", "linux", "/srv/a"},
+			{"file:///C:/a/b", "windows", `C:\a\b`},
+			{"file://server/share/a", "windows", `\\server\share\a`},
+		}
+		for _, c := range cases {
+			path, err := fromFileURL(c.url, c.goos)
+			if err != nil || path != c.expected {
+				t.Errorf("Expected %s to be %s on %s, got %s (%v)", c.url, c.expected, c.goos, path, err)
+			}
+		}
+
+		for _, url := range []string{"https://example.com/a", "file://server/share/a", "file:relative"} {
+			if _, err := fromFileURL(url, "linux"); err == nil {
+				t.Errorf("Expected %s to fail", url)
+			}
+		}
+	})
+
+	// Expect paths to be converted to escaped file URLs
+	t.Run("to", func(t *testing.T) {
+		cases := []struct {
+			path, goos, expected string
+		}{
+			{"/srv/a b.txt", "linux", "file:///srv/a%20b.txt"},
+			{`C:\a\b c`, "windows", "file:///C:/a/b%20c"},
+			{`\\server\share\a`, "windows", "file://server/share/a"},
+			{`\\?\C:\a`, "windows", "file:///C:/a"},
+		}
+		for _, c := range cases {
+			if url := toFileURL(c.path, c.goos); url != c.expected {
+				t.Errorf("Expected %s to be %s on %s, got %s", c.path, c.expected, c.goos, url)
+			}
+		}
+	})
+
+	// Expect a relative path to round-trip as an absolute path
+	t.Run("round trip", func(t *testing.T) {
+		url, err := ToFileURL("dir/file name.txt")
+		if err != nil || !strings.HasPrefix(url, "file://") {
+			t.Fatalf("Expected a file URL, got %s (%v)", url, err)
+		}
+
+		path, err := FromFileURL(url)
+		abs, _ := filepath.Abs("dir/file name.txt")
+		if err != nil || path != abs {
+			t.Errorf("Expected %s, got %s (%v)", abs, path, err)
+		}
+		if ClassifyPath(path) != AbsolutePath {
+			t.Errorf("Expected an absolute path, got %v", ClassifyPath(path))
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// PermPolicy describes the permissions files in a tree must have.
+type PermPolicy struct {
+	// NoWorldWritable reports files and directories anyone can write to.
+	NoWorldWritable bool
+	// NoSetuid reports setuid and setgid bits.
+	NoSetuid bool
+	// FileMode is the most permissive mode files may have, such as 0644.
+	// Permission bits outside it are violations. Zero allows any.
+	FileMode os.FileMode
+	// DirMode is the most permissive mode directories may have, such as 0755.
+	// Permission bits outside it are violations. Zero allows any.
+	DirMode os.FileMode
+	// CheckOwner reports files not owned by UID and GID. Only supported on Unix.
+	CheckOwner bool
+	// UID is the required owner user ID, or -1 for any.
+	UID int
+	// GID is the required owner group ID, or -1 for any.
+	GID int
+}
+
+// PermViolation is a file whose permissions don't match a PermPolicy.
+type PermViolation struct {
+	Path     string
+	Mode     os.FileMode // Current mode, including setuid, setgid and sticky bits
+	WantMode os.FileMode // Mode that satisfies the policy
+	UID      int         // Current owner user ID, -1 if unknown
+	GID      int         // Current owner group ID, -1 if unknown
+	Problems []string    // Human-readable descriptions, such as "world-writable"
+}
+
+// CheckPerms reports every file and directory under root that violates the policy,
+// in lexical order. Symlinks are skipped, since their permissions are not used.
+// Permission bits are mostly meaningless on Windows.
+//
+// Example:
+//
+//	violations, err := CheckPerms("/srv/app", PermPolicy{
+//	    NoWorldWritable: true,
+//	    NoSetuid:        true,
+//	    FileMode:        0644,
+//	    DirMode:         0755,
+//	})
+//	for _, violation := range violations {
+//	    fmt.Println(violation.Path, violation.Problems)
+//	}
+func CheckPerms(root string, policy PermPolicy) ([]PermViolation, error) {
+	var violations []PermViolation
+	err := filepath.WalkDir(normalizePath(root), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		violation, ok := checkPerm(path, info, policy)
+		if ok {
+			violations = append(violations, violation)
+		}
+		return nil
+	})
+	if err != nil {
+		return violations, fmt.Errorf("CheckPerms failed to walk directory: %w", err)
+	}
+
+	return violations, nil
+}
+
+// FixPerms corrects the permissions of every file reported by CheckPerms, removing
+// offending mode bits and changing owners, which usually requires privileges.
+// It returns the violations it fixed.
+//
+// Example:
+//
+//	fixed, err := FixPerms("/srv/app", policy)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println("fixed", len(fixed), "files")
+func FixPerms(root string, policy PermPolicy) ([]PermViolation, error) {
+	violations, err := CheckPerms(root, policy)
+	if err != nil {
+		return nil, fmt.Errorf("FixPerms failed: %w", err)
+	}
+
+	var fixed []PermViolation
+	for _, violation := range violations {
+		if skip, err := writeGuard("FixPerms", violation.Path, ""); skip {
+			if err != nil {
+				return fixed, err
+			}
+			fixed = append(fixed, violation)
+			continue
+		}
+		defer invalidateStats(violation.Path)
+
+		// The owner is changed first, since that can clear setuid and setgid bits
+		if policy.CheckOwner && violation.UID >= 0 && ownerMismatch(violation, policy) {
+			err := os.Lchown(violation.Path, policy.UID, policy.GID)
+			if err != nil {
+				return fixed, pathError("FixPerms", violation.Path, fmt.Errorf("FixPerms failed to change owner: %w", err))
+			}
+		}
+		if violation.Mode != violation.WantMode {
+			err := os.Chmod(violation.Path, violation.WantMode)
+			if err != nil {
+				return fixed, pathError("FixPerms", violation.Path, fmt.Errorf("FixPerms failed to change mode: %w", err))
+			}
+		}
+		fixed = append(fixed, violation)
+	}
+
+	return fixed, nil
+}
+
+// checkPerm checks a single file against the policy.
+func checkPerm(path string, info os.FileInfo, policy PermPolicy) (PermViolation, bool) {
+	meta := metaFromInfo(path, info)
+	violation := PermViolation{Path: path, Mode: meta.Mode, WantMode: meta.Mode, UID: meta.UID, GID: meta.GID}
+
+	if policy.NoWorldWritable && meta.Mode&0002 != 0 {
+		violation.WantMode &^= 0002
+		violation.Problems = append(violation.Problems, "world-writable")
+	}
+	if policy.NoSetuid && meta.Mode&os.ModeSetuid != 0 {
+		violation.WantMode &^= os.ModeSetuid
+		violation.Problems = append(violation.Problems, "setuid")
+	}
+	if policy.NoSetuid && meta.Mode&os.ModeSetgid != 0 {
+		violation.WantMode &^= os.ModeSetgid
+		violation.Problems = append(violation.Problems, "setgid")
+	}
+
+	limit := policy.FileMode
+	if info.IsDir() {
+		limit = policy.DirMode
+	}
+	if limit != 0 && meta.Mode.Perm()&^limit.Perm() != 0 {
+		violation.WantMode &^= os.ModePerm &^ limit.Perm()
+		violation.Problems = append(violation.Problems, fmt.Sprintf("mode %#o exceeds %#o", meta.Mode.Perm(), limit.Perm()))
+	}
+
+	if policy.CheckOwner && meta.UID >= 0 && ownerMismatch(violation, policy) {
+		violation.Problems = append(violation.Problems, fmt.Sprintf("owner %d:%d, want %d:%d", meta.UID, meta.GID, policy.UID, policy.GID))
+	}
+
+	return violation, len(violation.Problems) > 0
+}
+
+// ownerMismatch reports whether a file's owner differs from the policy.
+func ownerMismatch(violation PermViolation, policy PermPolicy) bool {
+	return (policy.UID >= 0 && violation.UID != policy.UID) || (policy.GID >= 0 && violation.GID != policy.GID)
+}
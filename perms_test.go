@@ -0,0 +1,114 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCheckPerms(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Permission bits are not supported on Windows")
+	}
+
+	// Expect violations to be reported with their problems and the mode that fixes them
+	t.Run("violations", func(t *testing.T) {
+		root := "check_perms_1"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{"ok.txt": "", "open.txt": "", "wide.txt": ""})
+		os.Chmod(filepath.Join(root, "ok.txt"), 0644)
+		os.Chmod(filepath.Join(root, "open.txt"), 0666)
+		os.Chmod(filepath.Join(root, "wide.txt"), 0754)
+		os.Chmod(root, 0755)
+
+		violations, err := CheckPerms(root, PermPolicy{NoWorldWritable: true, FileMode: 0644, DirMode: 0755})
+		if err != nil {
+			t.Fatalf("CheckPerms failed: %v", err)
+		}
+		if len(violations) != 2 {
+			t.Fatalf("Expected 2 violations, got %+v", violations)
+		}
+
+		open := violations[0]
+		if open.Path != filepath.Join(root, "open.txt") || open.WantMode != 0644 || len(open.Problems) != 2 {
+			t.Errorf("Unexpected violation for open.txt: %+v", open)
+		}
+		wide := violations[1]
+		if wide.Path != filepath.Join(root, "wide.txt") || wide.WantMode != 0644 {
+			t.Errorf("Unexpected violation for wide.txt: %+v", wide)
+		}
+	})
+
+	// Expect setuid bits to be reported
+	t.Run("setuid", func(t *testing.T) {
+		root := "check_perms_2"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{"tool": ""})
+		if err := os.Chmod(filepath.Join(root, "tool"), 0755|os.ModeSetuid); err != nil {
+			t.Skipf("Setting setuid not supported: %v", err)
+		}
+
+		violations, err := CheckPerms(root, PermPolicy{NoSetuid: true})
+		if err != nil {
+			t.Fatalf("CheckPerms failed: %v", err)
+		}
+		if len(violations) != 1 || violations[0].WantMode != 0755 {
+			t.Errorf("Expected setuid violation, got %+v", violations)
+		}
+	})
+}
+
+func TestFixPerms(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Permission bits are not supported on Windows")
+	}
+
+	// Expect violations to be fixed, so a second check finds none
+	t.Run("fix", func(t *testing.T) {
+		root := "fix_perms_1"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{"open.txt": "", "sub/open.txt": ""})
+		os.Chmod(filepath.Join(root, "open.txt"), 0666)
+		os.Chmod(filepath.Join(root, "sub"), 0777)
+
+		policy := PermPolicy{NoWorldWritable: true}
+		fixed, err := FixPerms(root, policy)
+		if err != nil {
+			t.Fatalf("FixPerms failed: %v", err)
+		}
+		if len(fixed) != 2 {
+			t.Errorf("Expected 2 fixes, got %+v", fixed)
+		}
+
+		violations, _ := CheckPerms(root, policy)
+		if len(violations) != 0 {
+			t.Errorf("Expected no violations after fixing, got %+v", violations)
+		}
+		if info, _ := os.Stat(filepath.Join(root, "open.txt")); info.Mode().Perm() != 0664 {
+			t.Errorf("Expected mode 0664, got %v", info.Mode().Perm())
+		}
+	})
+
+	// Expect nothing to change in dry-run mode
+	t.Run("dry run", func(t *testing.T) {
+		root := "fix_perms_2"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{"open.txt": ""})
+		os.Chmod(filepath.Join(root, "open.txt"), 0666)
+
+		plan, err := DryRun(func() error {
+			_, err := FixPerms(root, PermPolicy{NoWorldWritable: true})
+			return err
+		})
+		if err != nil {
+			t.Fatalf("FixPerms failed: %v", err)
+		}
+		if len(plan) != 1 {
+			t.Errorf("Expected 1 action, got %v", plan)
+		}
+		if info, _ := os.Stat(filepath.Join(root, "open.txt")); info.Mode().Perm() != 0666 {
+			t.Errorf("Expected mode to be unchanged, got %v", info.Mode().Perm())
+		}
+	})
+}
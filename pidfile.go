@@ -0,0 +1,248 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrLocked means a lock file is held by another process that is still running.
+var ErrLocked = errors.New("locked by another process")
+
+// PidFileOptions configures AcquirePidFileWithOptions.
+type PidFileOptions struct {
+	// MaxAge makes a lock file older than this stale even if its process is still running,
+	// which protects against process IDs that were reused. Zero means locks don't expire.
+	MaxAge time.Duration
+	// Mode is the file mode of the lock file. Defaults to 0644.
+	Mode os.FileMode
+}
+
+// PidFile is a lock file holding the process ID of its owner, created by AcquirePidFile.
+type PidFile struct {
+	path string
+	pid  int
+}
+
+// heldPidFiles are the lock files held by this process, so a lock file holding its own
+// process ID can be told apart from one left by a previous run.
+var heldPidFiles struct {
+	sync.Mutex
+	files map[string]*PidFile
+}
+
+// AcquirePidFile takes the lock file at path for the current process. See
+// AcquirePidFileWithOptions.
+//
+// Example:
+//
+//	lock, err := AcquirePidFile("/run/app.pid")
+//	if errors.Is(err, ErrLocked) {
+//	    fmt.Println("app is already running")
+//	    return
+//	}
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer lock.Release()
+func AcquirePidFile(path string) (*PidFile, error) {
+	return AcquirePidFileWithOptions(path, PidFileOptions{})
+}
+
+// AcquirePidFileWithOptions takes the lock file at path for the current process, writing
+// its process ID to it. If the file exists and its process is still running, it fails with
+// an error matching ErrLocked. A lock file left behind by a process that is gone, or one
+// older than MaxAge, is stale and broken. A lock file holding the ID of the current process
+// is locked while it is held through another PidFile, and stale otherwise, since it must
+// then be from a previous run that had the same ID.
+//
+// The process ID is written to a temporary file that is then linked into place, so the
+// lock file is created atomically with its content, and of several processes acquiring it
+// at once, exactly one succeeds. The file system must support hard links.
+//
+// Example:
+//
+//	lock, err := AcquirePidFileWithOptions("/run/app.pid", PidFileOptions{MaxAge: 24 * time.Hour})
+func AcquirePidFileWithOptions(path string, opts PidFileOptions) (*PidFile, error) {
+	path = normalizePath(path)
+	lock := &PidFile{path: path, pid: os.Getpid()}
+
+	if skip, err := writeGuard("AcquirePidFile", path, ""); skip {
+		if err != nil {
+			return nil, err
+		}
+		return lock, nil
+	}
+	defer invalidateStats(path)
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, pathError("AcquirePidFile", path, fmt.Errorf("AcquirePidFile failed to create temporary file: %w", err))
+	}
+	tmp := file.Name()
+	defer os.Remove(tmp)
+
+	err = writeAndClose(file, []byte(strconv.Itoa(lock.pid)+"\n"), opts.Mode, true)
+	if err != nil {
+		return nil, pathError("AcquirePidFile", path, fmt.Errorf("AcquirePidFile failed to write process ID: %w", err))
+	}
+
+	// Held while linking, so other goroutines see the lock as held as soon as it exists
+	heldPidFiles.Lock()
+	defer heldPidFiles.Unlock()
+
+	// A stale lock is broken at most once, so two processes can't keep breaking each other's
+	for broken := false; ; broken = true {
+		err = os.Link(tmp, path)
+		if err == nil {
+			if heldPidFiles.files == nil {
+				heldPidFiles.files = make(map[string]*PidFile)
+			}
+			heldPidFiles.files[path] = lock
+			return lock, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, pathError("AcquirePidFile", path, fmt.Errorf("AcquirePidFile failed to create lock file: %w", err))
+		}
+
+		pid, stale, err := staleLock(path, opts.MaxAge)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // Released in the meantime
+		}
+		if err != nil {
+			return nil, pathError("AcquirePidFile", path, fmt.Errorf("AcquirePidFile failed to read lock file: %w", err))
+		}
+		if !stale || broken {
+			return nil, pathError("AcquirePidFile", path, fmt.Errorf("AcquirePidFile failed: %w: process %d", ErrLocked, pid))
+		}
+
+		err = breakLock(path, pid)
+		if err != nil {
+			return nil, pathError("AcquirePidFile", path, fmt.Errorf("AcquirePidFile failed to break stale lock of process %d: %w", pid, err))
+		}
+	}
+}
+
+// ReadPidFile returns the process ID in the lock file at path.
+func ReadPidFile(path string) (int, error) {
+	path = normalizePath(path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, pathError("ReadPidFile", path, fmt.Errorf("ReadPidFile failed to read file: %w", err))
+	}
+
+	pid, err := parsePid(content)
+	if err != nil {
+		return 0, pathError("ReadPidFile", path, fmt.Errorf("ReadPidFile failed: %w", err))
+	}
+
+	return pid, nil
+}
+
+// Path returns the path of the lock file.
+func (p *PidFile) Path() string {
+	return p.path
+}
+
+// Release removes the lock file. If another process broke the lock and took it over in the
+// meantime, the file is left to its new owner and Release fails with ErrLocked.
+func (p *PidFile) Release() error {
+	if skip, err := writeGuard("Release", p.path, ""); skip {
+		return err
+	}
+	defer invalidateStats(p.path)
+
+	heldPidFiles.Lock()
+	defer heldPidFiles.Unlock()
+	if heldPidFiles.files[p.path] == p {
+		delete(heldPidFiles.files, p.path)
+	}
+
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return pathError("Release", p.path, fmt.Errorf("Release failed to read lock file: %w", err))
+	}
+	if pid, err := parsePid(content); err != nil || pid != p.pid {
+		return pathError("Release", p.path, fmt.Errorf("Release failed: %w: lock file was taken over", ErrLocked))
+	}
+
+	err = os.Remove(p.path)
+	if err != nil {
+		return pathError("Release", p.path, fmt.Errorf("Release failed to remove lock file: %w", err))
+	}
+
+	return nil
+}
+
+// staleLock returns the process ID in a lock file and whether the lock is stale.
+// heldPidFiles must be locked.
+func staleLock(path string, maxAge time.Duration) (int, bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false, err
+	}
+
+	pid, err := parsePid(content)
+	if err != nil {
+		return 0, false, err
+	}
+	if pid == os.Getpid() {
+		// Unless held in this process, a lock of its own ID was left by a previous run that had
+		// the same ID, which is common in containers, where the main process always has ID 1
+		_, held := heldPidFiles.files[path]
+		return pid, !held, nil
+	}
+	if maxAge > 0 && time.Since(info.ModTime()) > maxAge {
+		return pid, true, nil
+	}
+
+	return pid, !processAlive(pid), nil
+}
+
+// breakLock removes a stale lock file of pid. The file is renamed aside before it is
+// removed, and put back if another process replaced it with a fresh lock in between.
+func breakLock(path string, pid int) error {
+	aside := fmt.Sprintf("%s.stale%d", path, os.Getpid())
+	err := os.Rename(path, aside)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer os.Remove(aside)
+
+	content, err := os.ReadFile(aside)
+	if err != nil {
+		return err
+	}
+	if current, err := parsePid(content); err != nil || current != pid {
+		os.Link(aside, path)
+	}
+
+	return nil
+}
+
+// parsePid parses the content of a lock file.
+func parsePid(content []byte) (int, error) {
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid process ID %q", strings.TrimSpace(string(content)))
+	}
+
+	return pid, nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package fs_go
+
+// processAlive reports whether a process with pid is running. Processes can't be inspected
+// on this platform, so only locks that expire with MaxAge are ever stale.
+func processAlive(pid int) bool {
+	return true
+}
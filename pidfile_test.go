@@ -0,0 +1,137 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// deadPid is a process ID no running process has.
+const deadPid = 1 << 30
+
+func TestAcquirePidFile(t *testing.T) {
+	// Expect the lock file to hold the process ID until it is released
+	t.Run("acquire and release", func(t *testing.T) {
+		path := "pidfile_1.pid"
+		defer os.Remove(path)
+
+		lock, err := AcquirePidFile(path)
+		if err != nil {
+			t.Fatalf("AcquirePidFile failed: %v", err)
+		}
+
+		pid, err := ReadPidFile(path)
+		if err != nil || pid != os.Getpid() {
+			t.Errorf("Expected process ID %d, got %d (%v)", os.Getpid(), pid, err)
+		}
+
+		if err := lock.Release(); err != nil {
+			t.Errorf("Release failed: %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected the lock file to be removed, got %v", err)
+		}
+	})
+
+	// Expect ErrLocked while a running process holds the lock
+	t.Run("held", func(t *testing.T) {
+		path := "pidfile_2.pid"
+		defer os.Remove(path)
+
+		// The parent process of the test is running
+		os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())+"\n"), 0644)
+
+		_, err := AcquirePidFile(path)
+		if !errors.Is(err, ErrLocked) {
+			t.Errorf("Expected ErrLocked, got %v", err)
+		}
+	})
+
+	// Expect the lock of a process that is gone to be broken
+	t.Run("dead process", func(t *testing.T) {
+		path := "pidfile_3.pid"
+		defer os.Remove(path)
+		os.WriteFile(path, []byte(strconv.Itoa(deadPid)+"\n"), 0644)
+
+		lock, err := AcquirePidFile(path)
+		if err != nil {
+			t.Fatalf("AcquirePidFile failed: %v", err)
+		}
+		defer lock.Release()
+
+		if pid, _ := ReadPidFile(path); pid != os.Getpid() {
+			t.Errorf("Expected process ID %d, got %d", os.Getpid(), pid)
+		}
+	})
+
+	// Expect a lock with the ID of the current process to be left from a previous run
+	t.Run("own process ID", func(t *testing.T) {
+		path := "pidfile_5.pid"
+		defer os.Remove(path)
+		os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+
+		lock, err := AcquirePidFile(path)
+		if err != nil {
+			t.Fatalf("AcquirePidFile failed: %v", err)
+		}
+		if err := lock.Release(); err != nil {
+			t.Errorf("Release failed: %v", err)
+		}
+	})
+
+	// Expect a lock held through another PidFile of this process not to be broken
+	t.Run("held in process", func(t *testing.T) {
+		path := "pidfile_6.pid"
+		defer os.Remove(path)
+
+		lock, err := AcquirePidFile(path)
+		if err != nil {
+			t.Fatalf("AcquirePidFile failed: %v", err)
+		}
+
+		_, err = AcquirePidFileWithOptions(path, PidFileOptions{MaxAge: time.Nanosecond})
+		if !errors.Is(err, ErrLocked) {
+			t.Errorf("Expected ErrLocked, got %v", err)
+		}
+
+		if err := lock.Release(); err != nil {
+			t.Fatalf("Release failed: %v", err)
+		}
+		lock, err = AcquirePidFile(path)
+		if err != nil {
+			t.Fatalf("AcquirePidFile failed after Release: %v", err)
+		}
+		lock.Release()
+	})
+
+	// Expect a lock older than MaxAge to be broken even if its process is running, and
+	// Release of the previous owner to leave it alone
+	t.Run("expired", func(t *testing.T) {
+		path := "pidfile_4.pid"
+		defer os.Remove(path)
+
+		old, err := AcquirePidFile(path)
+		if err != nil {
+			t.Fatalf("AcquirePidFile failed: %v", err)
+		}
+		os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())+"\n"), 0644)
+		old.pid = os.Getppid()
+		past := time.Now().Add(-2 * time.Hour)
+		os.Chtimes(path, past, past)
+
+		_, err = AcquirePidFileWithOptions(path, PidFileOptions{MaxAge: time.Minute})
+		if err != nil {
+			t.Fatalf("AcquirePidFileWithOptions failed: %v", err)
+		}
+
+		err = old.Release()
+		if !errors.Is(err, ErrLocked) {
+			t.Errorf("Expected ErrLocked, got %v", err)
+		}
+		if pid, _ := ReadPidFile(path); pid != os.Getpid() {
+			t.Errorf("Expected process ID %d, got %d", os.Getpid(), pid)
+		}
+	})
+}
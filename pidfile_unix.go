@@ -0,0 +1,15 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether a process with pid is running. A process that exists but
+// belongs to another user can't be signaled, and is running too.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,50 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+)
+
+// Preallocate reserves disk space for a file of the given size, so later writes up to
+// that size don't fail for lack of space. The file is created with mode 0644 if it doesn't
+// exist and grows to size, filled with zeros. Files that are already large enough are left alone.
+//
+// Space is reserved with fallocate on Linux, F_PREALLOCATE on macOS and SetEndOfFile on Windows.
+// Where the file system doesn't support reservation, the file is only extended.
+//
+// Example:
+//
+//	err := Preallocate("download.part", contentLength)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func Preallocate(path string, size int64) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("Preallocate", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("Preallocate failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("Preallocate failed to get file stat: %w", err)
+	}
+	if info.Size() >= size {
+		return nil
+	}
+
+	err = preallocate(file, info.Size(), size)
+	if err != nil {
+		return fmt.Errorf("Preallocate failed to allocate space: %w", err)
+	}
+
+	return nil
+}
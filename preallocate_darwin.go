@@ -0,0 +1,28 @@
+package fs_go
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func preallocate(file *os.File, current, size int64) error {
+	store := unix.Fstore_t{
+		Flags:   unix.F_ALLOCATECONTIG,
+		Posmode: unix.F_PEOFPOSMODE,
+		Length:  size - current,
+	}
+
+	// Fall back to a fragmented allocation if no contiguous space is available
+	err := unix.FcntlFstore(file.Fd(), unix.F_PREALLOCATE, &store)
+	if err != nil {
+		store.Flags = unix.F_ALLOCATEALL
+		err = unix.FcntlFstore(file.Fd(), unix.F_PREALLOCATE, &store)
+	}
+	if err != nil && err != unix.ENOTSUP {
+		return err
+	}
+
+	// F_PREALLOCATE reserves blocks but doesn't change the file size
+	return file.Truncate(size)
+}
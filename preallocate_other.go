@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package fs_go
+
+import "os"
+
+func preallocate(file *os.File, current, size int64) error {
+	return file.Truncate(size)
+}
@@ -0,0 +1,57 @@
+package fs_go
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPreallocate(t *testing.T) {
+	// Expect a new file to be created with the requested size
+	t.Run("new file", func(t *testing.T) {
+		path := "preallocate_1.bin"
+		defer os.Remove(path)
+
+		err := Preallocate(path, 1<<20)
+		if err != nil {
+			t.Errorf("Preallocate failed: %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if info.Size() != 1<<20 {
+			t.Errorf("Expected size to be %d, got %d", 1<<20, info.Size())
+		}
+	})
+
+	// Expect existing content to be kept and larger files to not shrink
+	t.Run("existing file", func(t *testing.T) {
+		path := "preallocate_2.bin"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := Preallocate(path, 4)
+		if err != nil {
+			t.Errorf("Preallocate failed: %v", err)
+		}
+
+		err = Preallocate(path, 16)
+		if err != nil {
+			t.Errorf("Preallocate failed: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "test content\x00\x00\x00\x00" {
+			t.Errorf("Expected content to be kept and zero-extended, got %q", content)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package fs_go
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func preallocate(file *os.File, current, size int64) error {
+	_, err := file.Seek(size, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	return windows.SetEndOfFile(windows.Handle(file.Fd()))
+}
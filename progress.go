@@ -0,0 +1,153 @@
+package fs_go
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Progress receives reports from long operations, like CopyDirWithOptions and
+// DownloadWithOptions, so any progress bar can be plugged in. Work is measured in bytes.
+// Calls are never made concurrently, so implementations don't need to synchronize, but they
+// are made from the goroutines doing the work and must not block for long.
+//
+// Example:
+//
+//	type bar struct{ started time.Time }
+//
+//	func (b *bar) Start(total int64) { b.started = time.Now() }
+//	func (b *bar) Update(done, total int64) {
+//	    fmt.Printf("\r%d/%d bytes, %v left", done, total, EstimateRemaining(time.Since(b.started), done, total))
+//	}
+//	func (b *bar) Finish(err error) { fmt.Println() }
+//
+//	err := CopyDirWithOptions("photos", "/mnt/backup/photos", CopyDirOptions{Progress: &bar{}})
+type Progress interface {
+	// Start is called once when the work begins, with its total, or -1 if it isn't known yet.
+	Start(total int64)
+	// Update is called as work is done, with the work done so far and the total, which is
+	// -1 while it isn't known.
+	Update(done, total int64)
+	// Finish is called once after Start when the operation ends, with its error if it failed.
+	Finish(err error)
+}
+
+// EstimateRemaining estimates how long the rest of the work will take, assuming it goes
+// on at the pace of the work done in elapsed. It returns -1 if there is nothing to go by yet
+// or the total isn't known.
+func EstimateRemaining(elapsed time.Duration, done, total int64) time.Duration {
+	if done <= 0 || total < 0 {
+		return -1
+	}
+	if done >= total {
+		return 0
+	}
+
+	return time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+}
+
+// progressCounter counts work towards a Progress and reports it, one call at a time.
+// A nil progressCounter reports nothing.
+type progressCounter struct {
+	mu       sync.Mutex
+	progress Progress
+	done     int64
+	total    int64
+}
+
+// startProgress calls Start on progress and returns a counter for it, or nil if progress is nil.
+func startProgress(progress Progress, done, total int64) *progressCounter {
+	if progress == nil {
+		return nil
+	}
+
+	progress.Start(total)
+	if done > 0 {
+		progress.Update(done, total)
+	}
+	return &progressCounter{progress: progress, done: done, total: total}
+}
+
+// add counts n more work as done.
+func (c *progressCounter) add(n int64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done += n
+	c.progress.Update(c.done, c.total)
+}
+
+// setTotal sets the total once it is known.
+func (c *progressCounter) setTotal(total int64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total = total
+	c.progress.Update(c.done, c.total)
+}
+
+// finish calls Finish with the error the operation ended with.
+func (c *progressCounter) finish(err error) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Finish(err)
+}
+
+// writer returns w counting the bytes written as done, or w itself if c is nil.
+func (c *progressCounter) writer(w io.Writer) io.Writer {
+	if c == nil {
+		return w
+	}
+
+	return &progressWriter{w: w, counter: c}
+}
+
+// progressWriter is a writer that counts the bytes written through it.
+type progressWriter struct {
+	w       io.Writer
+	counter *progressCounter
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.counter.add(int64(n))
+	return n, err
+}
+
+// track makes each job count its size as done once it succeeds.
+func (c *progressCounter) track(jobs []Job) {
+	if c == nil {
+		return
+	}
+
+	for i := range jobs {
+		run, size := jobs[i].Run, jobs[i].Size
+		jobs[i].Run = func() error {
+			err := run()
+			if err == nil {
+				c.add(size)
+			}
+			return err
+		}
+	}
+}
+
+// jobsSize returns the total size of the jobs.
+func jobsSize(jobs []Job) int64 {
+	var size int64
+	for _, job := range jobs {
+		size += job.Size
+	}
+
+	return size
+}
@@ -0,0 +1,126 @@
+package fs_go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordedProgress records the reports it receives.
+type recordedProgress struct {
+	started  int
+	finished int
+	total    int64
+	done     int64
+	err      error
+}
+
+func (p *recordedProgress) Start(total int64) {
+	p.started++
+	p.total = total
+}
+
+func (p *recordedProgress) Update(done, total int64) {
+	p.done, p.total = done, total
+}
+
+func (p *recordedProgress) Finish(err error) {
+	p.finished++
+	p.err = err
+}
+
+// check fails unless the progress was started, finished and completed with total bytes.
+func (p *recordedProgress) check(t *testing.T, total int64) {
+	t.Helper()
+	if p.started != 1 || p.finished != 1 {
+		t.Errorf("Expected one Start and one Finish, got %d and %d", p.started, p.finished)
+	}
+	if p.done != total || p.total != total {
+		t.Errorf("Expected %d of %d bytes, got %d of %d", total, total, p.done, p.total)
+	}
+	if p.err != nil {
+		t.Errorf("Expected no error, got %v", p.err)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	path := "progress_1"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"src/a.txt": "12345", "src/sub/b.txt": "1234567890"})
+	src := filepath.Join(path, "src")
+
+	// Expect the bytes of all copied files to be reported
+	t.Run("copy dir", func(t *testing.T) {
+		progress := &recordedProgress{}
+		err := CopyDirWithOptions(src, filepath.Join(path, "copy"), CopyDirOptions{Progress: progress})
+		if err != nil {
+			t.Fatalf("CopyDirWithOptions failed: %v", err)
+		}
+		progress.check(t, 15)
+	})
+
+	// Expect only the changed files of a sync to count
+	t.Run("sync dir", func(t *testing.T) {
+		dst := filepath.Join(path, "sync")
+		_, err := SyncDir(src, dst)
+		if err != nil {
+			t.Fatalf("SyncDir failed: %v", err)
+		}
+		os.WriteFile(filepath.Join(src, "a.txt"), []byte("123456"), 0644)
+
+		progress := &recordedProgress{}
+		_, err = SyncDirWithOptions(src, dst, SyncOptions{Progress: progress})
+		if err != nil {
+			t.Fatalf("SyncDirWithOptions failed: %v", err)
+		}
+		progress.check(t, 6)
+	})
+
+	// Expect the hashed bytes to be reported, and the total once the walk is done
+	t.Run("hash files", func(t *testing.T) {
+		progress := &recordedProgress{}
+		_, err := HashFilesWithOptions(src, HashFilesOptions{Progress: progress})
+		if err != nil {
+			t.Fatalf("HashFilesWithOptions failed: %v", err)
+		}
+		progress.check(t, 16)
+	})
+
+	// Expect the downloaded bytes to be reported against the content length
+	t.Run("download", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(strings.Repeat("x", 1000)))
+		}))
+		defer server.Close()
+
+		progress := &recordedProgress{}
+		err := DownloadWithOptions(context.Background(), server.URL, filepath.Join(path, "download"), DownloadOptions{Progress: progress})
+		if err != nil {
+			t.Fatalf("DownloadWithOptions failed: %v", err)
+		}
+		progress.check(t, 1000)
+	})
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	// Expect the remaining work to take as long per byte as the work done
+	if remaining := EstimateRemaining(10*time.Second, 25, 100); remaining != 30*time.Second {
+		t.Errorf("Expected 30s, got %v", remaining)
+	}
+
+	// Expect -1 without anything to go by, and 0 when done
+	if remaining := EstimateRemaining(time.Second, 0, 100); remaining != -1 {
+		t.Errorf("Expected -1, got %v", remaining)
+	}
+	if remaining := EstimateRemaining(time.Second, 10, -1); remaining != -1 {
+		t.Errorf("Expected -1, got %v", remaining)
+	}
+	if remaining := EstimateRemaining(time.Second, 100, 100); remaining != 0 {
+		t.Errorf("Expected 0, got %v", remaining)
+	}
+}
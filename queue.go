@@ -0,0 +1,252 @@
+package fs_go
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueEmpty is returned by Queue.Dequeue when there is nothing to claim.
+var ErrQueueEmpty = errors.New("queue is empty")
+
+// Queue is a first-in, first-out job spool kept in a directory, maildir-style.
+//
+// Payloads are written to "tmp" and renamed into "new" once complete, so consumers never
+// see partial items. Consumers claim an item by renaming it into "cur", which only one
+// of them can win, so any number of goroutines and processes can consume the same queue.
+// Claimed items are removed with Ack or returned with Nack, and Recover returns items
+// whose consumer crashed.
+type Queue struct {
+	root string
+}
+
+// QueueItem is a claimed queue entry.
+type QueueItem struct {
+	ID      string // Unique ID, which sorts in enqueue order
+	Payload []byte
+	queue   *Queue
+}
+
+// queueSeq orders items enqueued in the same nanosecond by this process.
+var queueSeq atomic.Uint32
+
+// OpenQueue returns the queue in root, creating its directories if needed.
+//
+// Example:
+//
+//	queue, err := OpenQueue("spool/emails")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	_, err = queue.Enqueue(message)
+func OpenQueue(root string) (*Queue, error) {
+	for _, dir := range []string{"tmp", "new", "cur"} {
+		err := EnsureDir(filepath.Join(root, dir))
+		if err != nil {
+			return nil, fmt.Errorf("OpenQueue failed to create %s: %w", dir, err)
+		}
+	}
+
+	return &Queue{root: root}, nil
+}
+
+// Enqueue adds a payload to the end of the queue and returns its ID.
+func (q *Queue) Enqueue(payload []byte) (string, error) {
+	id, err := newQueueID()
+	if err != nil {
+		return "", fmt.Errorf("Enqueue failed to generate ID: %w", err)
+	}
+
+	tmp := filepath.Join(q.root, "tmp", id)
+	dst := filepath.Join(q.root, "new", id)
+	if skip, err := writeGuard("Enqueue", dst, ""); skip {
+		return id, err
+	}
+	defer invalidateStats(dst)
+
+	err = os.WriteFile(normalizePath(tmp), payload, 0644)
+	if err != nil {
+		os.Remove(normalizePath(tmp))
+		return "", fmt.Errorf("Enqueue failed to write payload: %w", err)
+	}
+	err = os.Rename(normalizePath(tmp), normalizePath(dst))
+	if err != nil {
+		os.Remove(normalizePath(tmp))
+		return "", fmt.Errorf("Enqueue failed to publish payload: %w", err)
+	}
+
+	return id, nil
+}
+
+// Dequeue claims the oldest item in the queue. It returns ErrQueueEmpty if there is none.
+// The item must be passed to Ack once processed, or to Nack to retry it later.
+//
+// Example:
+//
+//	item, err := queue.Dequeue()
+//	if errors.Is(err, ErrQueueEmpty) {
+//	    time.Sleep(time.Second)
+//	    continue
+//	}
+//	if err != nil {
+//	    return err
+//	}
+//	if err := send(item.Payload); err != nil {
+//	    item.Nack()
+//	    continue
+//	}
+//	item.Ack()
+func (q *Queue) Dequeue() (*QueueItem, error) {
+	ids, err := q.list("new")
+	if err != nil {
+		return nil, fmt.Errorf("Dequeue failed to list queue: %w", err)
+	}
+
+	for _, id := range ids {
+		src := filepath.Join(q.root, "new", id)
+		dst := filepath.Join(q.root, "cur", id)
+		if skip, err := writeGuard("Dequeue", src, dst); skip {
+			// Nothing is claimed in dry-run mode, so there is nothing to process
+			if err == nil {
+				err = ErrQueueEmpty
+			}
+			return nil, err
+		}
+		defer invalidateStats(src, dst)
+
+		err := os.Rename(normalizePath(src), normalizePath(dst))
+		if errors.Is(err, os.ErrNotExist) {
+			// Claimed by another consumer first
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Dequeue failed to claim %s: %w", id, err)
+		}
+
+		// Mark the claim time, so Recover can tell abandoned claims apart
+		now := time.Now()
+		os.Chtimes(normalizePath(dst), now, now)
+
+		payload, err := os.ReadFile(normalizePath(dst))
+		if err != nil {
+			return nil, fmt.Errorf("Dequeue failed to read %s: %w", id, err)
+		}
+
+		return &QueueItem{ID: id, Payload: payload, queue: q}, nil
+	}
+
+	return nil, ErrQueueEmpty
+}
+
+// Ack removes a processed item from the queue.
+func (i *QueueItem) Ack() error {
+	err := Remove(filepath.Join(i.queue.root, "cur", i.ID))
+	if err != nil {
+		return fmt.Errorf("Ack failed to remove %s: %w", i.ID, err)
+	}
+
+	return nil
+}
+
+// Nack returns an item to the queue. It keeps its place, so it is claimed again next.
+func (i *QueueItem) Nack() error {
+	err := Move(filepath.Join(i.queue.root, "cur", i.ID), filepath.Join(i.queue.root, "new", i.ID))
+	if err != nil {
+		return fmt.Errorf("Nack failed to return %s: %w", i.ID, err)
+	}
+
+	return nil
+}
+
+// Len returns the number of items waiting to be claimed.
+func (q *Queue) Len() (int, error) {
+	ids, err := q.list("new")
+	if err != nil {
+		return 0, fmt.Errorf("Len failed to list queue: %w", err)
+	}
+
+	return len(ids), nil
+}
+
+// Recover returns items claimed longer than olderThan ago to the queue, and removes
+// payloads that were never completely written. Run it on startup, or periodically with
+// a threshold well above the longest processing time. It returns the number of items
+// returned to the queue.
+func (q *Queue) Recover(olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	claimed, err := q.list("cur")
+	if err != nil {
+		return 0, fmt.Errorf("Recover failed to list claimed items: %w", err)
+	}
+
+	recovered := 0
+	for _, id := range claimed {
+		path := filepath.Join(q.root, "cur", id)
+		info, err := os.Stat(normalizePath(path))
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+
+		err = Move(path, filepath.Join(q.root, "new", id))
+		if err != nil && !errors.Is(err, ErrNotExist) {
+			return recovered, fmt.Errorf("Recover failed to return %s: %w", id, err)
+		}
+		if err == nil {
+			recovered++
+		}
+	}
+
+	partial, err := q.list("tmp")
+	if err != nil {
+		return recovered, fmt.Errorf("Recover failed to list partial items: %w", err)
+	}
+	for _, id := range partial {
+		path := filepath.Join(q.root, "tmp", id)
+		info, err := os.Stat(normalizePath(path))
+		if err == nil && info.ModTime().Before(cutoff) {
+			Remove(path)
+		}
+	}
+
+	return recovered, nil
+}
+
+// list returns the item IDs in a queue directory, oldest first.
+func (q *Queue) list(dir string) ([]string, error) {
+	entries, err := os.ReadDir(normalizePath(filepath.Join(q.root, dir)))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// newQueueID returns an ID that sorts by creation time: the time in nanoseconds and a
+// sequence number in fixed-width hex, followed by random bytes to keep IDs from
+// different processes apart.
+func newQueueID() (string, error) {
+	random := make([]byte, 4)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%016x.%08x.%s", time.Now().UnixNano(), queueSeq.Add(1), hex.EncodeToString(random)), nil
+}
@@ -0,0 +1,126 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	// Expect items to be dequeued in order and removed once acknowledged
+	t.Run("order", func(t *testing.T) {
+		path := "queue_1"
+		defer os.RemoveAll(path)
+		queue, err := OpenQueue(path)
+		if err != nil {
+			t.Fatalf("OpenQueue failed: %v", err)
+		}
+
+		for _, payload := range []string{"a", "b", "c"} {
+			if _, err := queue.Enqueue([]byte(payload)); err != nil {
+				t.Fatalf("Enqueue failed: %v", err)
+			}
+		}
+
+		first, err := queue.Dequeue()
+		if err != nil || string(first.Payload) != "a" {
+			t.Fatalf("Expected a, got %v (%v)", first, err)
+		}
+		if err := first.Nack(); err != nil {
+			t.Errorf("Nack failed: %v", err)
+		}
+
+		for _, expected := range []string{"a", "b", "c"} {
+			item, err := queue.Dequeue()
+			if err != nil || string(item.Payload) != expected {
+				t.Fatalf("Expected %s, got %v (%v)", expected, item, err)
+			}
+			if err := item.Ack(); err != nil {
+				t.Errorf("Ack failed: %v", err)
+			}
+		}
+
+		_, err = queue.Dequeue()
+		if !errors.Is(err, ErrQueueEmpty) {
+			t.Errorf("Expected ErrQueueEmpty, got %v", err)
+		}
+	})
+
+	// Expect concurrent consumers to never claim the same item
+	t.Run("consumers", func(t *testing.T) {
+		path := "queue_2"
+		defer os.RemoveAll(path)
+		queue, err := OpenQueue(path)
+		if err != nil {
+			t.Fatalf("OpenQueue failed: %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			queue.Enqueue([]byte{byte(i)})
+		}
+
+		var mu sync.Mutex
+		seen := make(map[string]bool)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					item, err := queue.Dequeue()
+					if err != nil {
+						return
+					}
+					mu.Lock()
+					if seen[item.ID] {
+						t.Errorf("Expected %s to be claimed once", item.ID)
+					}
+					seen[item.ID] = true
+					mu.Unlock()
+					item.Ack()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(seen) != 50 {
+			t.Errorf("Expected 50 items to be processed, got %d", len(seen))
+		}
+	})
+
+	// Expect abandoned claims and partial payloads to be recovered
+	t.Run("recover", func(t *testing.T) {
+		path := "queue_3"
+		defer os.RemoveAll(path)
+		queue, err := OpenQueue(path)
+		if err != nil {
+			t.Fatalf("OpenQueue failed: %v", err)
+		}
+		queue.Enqueue([]byte("job"))
+		if _, err := queue.Dequeue(); err != nil {
+			t.Fatalf("Dequeue failed: %v", err)
+		}
+		partial := filepath.Join(path, "tmp", "partial")
+		os.WriteFile(partial, []byte("half"), 0644)
+
+		old := time.Now().Add(-2 * time.Hour)
+		entries, _ := os.ReadDir(filepath.Join(path, "cur"))
+		for _, entry := range entries {
+			os.Chtimes(filepath.Join(path, "cur", entry.Name()), old, old)
+		}
+		os.Chtimes(partial, old, old)
+
+		recovered, err := queue.Recover(time.Hour)
+		if err != nil || recovered != 1 {
+			t.Errorf("Expected 1 recovered item, got %d (%v)", recovered, err)
+		}
+		if n, _ := queue.Len(); n != 1 {
+			t.Errorf("Expected 1 item waiting, got %d", n)
+		}
+		if _, err := os.Stat(partial); !os.IsNotExist(err) {
+			t.Errorf("Expected partial payload to be removed")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package fs_go
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// rateLimiter paces I/O to a number of bytes per second. It is shared by all copies of an
+// operation, like the concurrent copies of a sync, so their combined bandwidth is limited.
+// A nil rateLimiter doesn't limit anything.
+type rateLimiter struct {
+	mu   sync.Mutex
+	rate float64   // Bytes per second
+	next time.Time // When the next bytes may be written
+}
+
+// newRateLimiter returns a limiter for bytesPerSecond, or nil if it isn't positive.
+func newRateLimiter(bytesPerSecond int64) *rateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	return &rateLimiter{rate: float64(bytesPerSecond)}
+}
+
+// wait reserves n bytes of bandwidth and blocks until they may be written, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writer returns w limited to the rate of l, or w itself if l is nil.
+func (l *rateLimiter) writer(ctx context.Context, w io.Writer) io.Writer {
+	if l == nil {
+		return w
+	}
+
+	// Write in slices of a tenth of a second, so the pace stays smooth for large writes
+	chunk := max(1, min(32<<10, int(l.rate/10)))
+	return &limitedWriter{ctx: ctx, w: w, limiter: l, chunk: chunk}
+}
+
+// limitedWriter is a writer limited by a rateLimiter.
+type limitedWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter *rateLimiter
+	chunk   int
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		n := min(len(p), lw.chunk)
+		err := lw.limiter.wait(lw.ctx, n)
+		if err != nil {
+			return written, err
+		}
+
+		n, err = lw.w.Write(p[:n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+
+	return written, nil
+}
@@ -0,0 +1,80 @@
+package fs_go
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	// Expect a limited copy to take as long as its rate requires
+	t.Run("copy", func(t *testing.T) {
+		path := "rate_limit_1"
+		defer os.RemoveAll(path)
+		content := bytes.Repeat([]byte("x"), 20000)
+		writeTree(t, path, map[string]string{"src": string(content)})
+
+		start := time.Now()
+		err := CopyFileWithOptions(filepath.Join(path, "src"), filepath.Join(path, "dst"), CopyOptions{BytesPerSecond: 40000})
+		if err != nil {
+			t.Fatalf("CopyFileWithOptions failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+			t.Errorf("Expected the copy to take about 400ms, took %v", elapsed)
+		}
+
+		copied, _ := os.ReadFile(filepath.Join(path, "dst"))
+		if !bytes.Equal(copied, content) {
+			t.Errorf("Expected %d identical bytes, got %d", len(content), len(copied))
+		}
+	})
+
+	// Expect the copies of a sync to share the limit
+	t.Run("sync", func(t *testing.T) {
+		path := "rate_limit_2"
+		defer os.RemoveAll(path)
+		content := string(bytes.Repeat([]byte("x"), 10000))
+		writeTree(t, path, map[string]string{"src/a": content, "src/b": content})
+
+		start := time.Now()
+		_, err := SyncDirWithOptions(filepath.Join(path, "src"), filepath.Join(path, "dst"), SyncOptions{BytesPerSecond: 40000})
+		if err != nil {
+			t.Fatalf("SyncDirWithOptions failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+			t.Errorf("Expected the sync to take about 400ms, took %v", elapsed)
+		}
+
+		diff, err := DiffDirs(filepath.Join(path, "src"), filepath.Join(path, "dst"))
+		if err != nil || !diff.Empty() {
+			t.Errorf("Expected identical trees, got %+v, %v", diff, err)
+		}
+	})
+
+	// Expect a limited download to stop when its context is canceled
+	t.Run("download canceled", func(t *testing.T) {
+		path := "rate_limit_3"
+		defer os.RemoveAll(path)
+		os.Mkdir(path, 0755)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(bytes.Repeat([]byte("x"), 100000))
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := DownloadWithOptions(ctx, server.URL, filepath.Join(path, "file"), DownloadOptions{BytesPerSecond: 10000})
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Expected the download to stop early, took %v", elapsed)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// PathForm decides how paths below a root are returned.
+type PathForm int
+
+const (
+	PathJoined   PathForm = iota // Joined with the root as given, like filepath.Walk
+	PathRelative                 // Relative to the root
+	PathAbsolute                 // Absolute
+)
+
+// ReadDirRecOptions configures ReadDirRecWithOptions and IterDirRec.
+type ReadDirRecOptions struct {
+	// Paths is the form of the returned paths. Defaults to PathJoined.
+	Paths PathForm
+	// IncludeDirs returns directories below the root too, before their content.
+	IncludeDirs bool
+	// IncludeSymlinks returns symlinks, which are never followed.
+	IncludeSymlinks bool
+	// ExcludeSpecial skips named pipes, sockets and devices, which are returned like
+	// regular files otherwise. DirRecIterator.Kind tells them apart.
+	ExcludeSpecial bool
+	// Ignore skips the files and directories it matches, relative to the root. Ignored
+	// directories aren't read.
+	Ignore *Ignorer
+}
+
+// ReadDirRecWithOptions reads the content of a directory recursively and returns the paths
+// selected by the options, in lexical order.
+//
+// Example:
+//
+//	files, err := ReadDirRecWithOptions("assets", ReadDirRecOptions{Paths: PathRelative})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadDirRecWithOptions(path string, opts ReadDirRecOptions) ([]string, error) {
+	it, err := IterDirRec(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for it.Next() {
+		paths = append(paths, it.Path())
+	}
+
+	err = it.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// DirRecIterator iterates over a directory tree in lexical order, one directory read at
+// a time, so huge trees never have to fit in memory. It is created by IterDirRec.
+type DirRecIterator struct {
+	root    string
+	absRoot string
+	opts    ReadDirRecOptions
+	stack   []dirFrame
+	pending fs.DirEntry              // A root that isn't a directory, returned on its own
+	archive map[string][]fs.DirEntry // Directories by path relative to the root, inside archives
+
+	path  string
+	entry fs.DirEntry
+	err   error
+}
+
+// dirFrame is a directory being iterated over.
+type dirFrame struct {
+	path    string // Path joined with the root
+	rel     string // Path relative to the root
+	entries []fs.DirEntry
+	read    bool // Whether entries were read, which waits until the frame is reached
+	next    int
+}
+
+// IterDirRec starts iterating over a directory tree. If path is a file rather than a
+// directory, it is the only result. Directories inside archives, like "bundle.zip!/assets",
+// are read up front, and the returned paths are archive paths.
+//
+// Example:
+//
+//	it, err := IterDirRec("/data", ReadDirRecOptions{IncludeDirs: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for it.Next() {
+//	    if it.Entry().IsDir() && it.Entry().Name() == ".git" {
+//	        it.SkipDir()
+//	        continue
+//	    }
+//	    process(it.Path())
+//	}
+//	if err := it.Err(); err != nil {
+//	    fmt.Println(err)
+//	}
+func IterDirRec(path string, opts ReadDirRecOptions) (*DirRecIterator, error) {
+	path = normalizePath(path)
+
+	it := &DirRecIterator{root: path, opts: opts}
+	if opts.Paths == PathAbsolute {
+		absRoot, err := filepath.Abs(path)
+		if err != nil {
+			return nil, pathError("ReadDirRec", path, fmt.Errorf("ReadDirRec failed to resolve absolute path: %w", err))
+		}
+		it.absRoot = absRoot
+	}
+
+	if archive, name, ok := splitArchivePath(path); ok {
+		info, dirs, err := readArchiveTree(archive, name)
+		if err != nil {
+			return nil, pathError("ReadDirRec", path, fmt.Errorf("ReadDirRec failed to read archive: %w", err))
+		}
+		if !info.IsDir() {
+			it.pending = fs.FileInfoToDirEntry(info)
+			return it, nil
+		}
+		it.archive = dirs
+		it.stack = append(it.stack, dirFrame{path: path, rel: "."})
+		return it, nil
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, pathError("ReadDirRec", path, fmt.Errorf("ReadDirRec failed to get root stat: %w", err))
+	}
+	if !info.IsDir() {
+		it.pending = fs.FileInfoToDirEntry(info)
+		return it, nil
+	}
+
+	it.stack = append(it.stack, dirFrame{path: path, rel: "."})
+	return it, nil
+}
+
+// Next advances to the next path and reports whether there was one.
+// It returns false at the end of the tree or on error; check Err to tell them apart.
+func (it *DirRecIterator) Next() bool {
+	if it.pending != nil {
+		it.entry, it.pending = it.pending, nil
+		if it.included(it.entry) {
+			it.path = it.form(it.root, ".")
+			return true
+		}
+	}
+
+	for it.err == nil && len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if !top.read {
+			entries, err := it.readDir(top)
+			if err != nil {
+				it.err = pathError("ReadDirRec", top.path, fmt.Errorf("ReadDirRec failed to read directory: %w", err))
+				return false
+			}
+			top.entries, top.read = entries, true
+		}
+		if top.next >= len(top.entries) {
+			it.stack = it.stack[:len(it.stack)-1]
+			continue
+		}
+
+		entry := top.entries[top.next]
+		top.next++
+		path := filepath.Join(top.path, entry.Name())
+		rel := filepath.Join(top.rel, entry.Name())
+		if it.opts.Ignore.Match(rel, entry.IsDir()) {
+			continue
+		}
+
+		switch {
+		case entry.IsDir():
+			it.stack = append(it.stack, dirFrame{path: path, rel: rel})
+			if !it.opts.IncludeDirs {
+				continue
+			}
+		case !it.included(entry):
+			continue
+		}
+
+		it.path = it.form(path, rel)
+		it.entry = entry
+		return true
+	}
+
+	return false
+}
+
+// Path returns the current path, in the form selected by the options.
+func (it *DirRecIterator) Path() string {
+	return it.path
+}
+
+// Entry returns the directory entry of the current path.
+func (it *DirRecIterator) Entry() fs.DirEntry {
+	return it.entry
+}
+
+// Kind returns the kind of the current path, without following symlinks.
+func (it *DirRecIterator) Kind() FileKind {
+	return KindOf(it.entry.Type())
+}
+
+// SkipDir skips the content of the current path, if it is a directory, without reading it.
+func (it *DirRecIterator) SkipDir() {
+	if it.entry == nil || !it.entry.IsDir() || len(it.stack) == 0 {
+		return
+	}
+
+	// The directory was pushed when it was returned, and hasn't been read since
+	if top := it.stack[len(it.stack)-1]; !top.read && top.rel != "." {
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+}
+
+// Err returns the first error encountered while reading, if any.
+func (it *DirRecIterator) Err() error {
+	return it.err
+}
+
+// readDir reads the entries of a directory frame.
+func (it *DirRecIterator) readDir(frame *dirFrame) ([]fs.DirEntry, error) {
+	if it.archive != nil {
+		return it.archive[frame.rel], nil
+	}
+
+	return os.ReadDir(frame.path)
+}
+
+// included reports whether a file that isn't a directory is returned.
+func (it *DirRecIterator) included(entry fs.DirEntry) bool {
+	kind := KindOf(entry.Type())
+	switch {
+	case kind == KindSymlink:
+		return it.opts.IncludeSymlinks
+	case kind.Special():
+		return !it.opts.ExcludeSpecial
+	default:
+		return true
+	}
+}
+
+// form returns a path below the root in the form selected by the options.
+func (it *DirRecIterator) form(path, rel string) string {
+	switch it.opts.Paths {
+	case PathRelative:
+		return rel
+	case PathAbsolute:
+		return filepath.Join(it.absRoot, rel)
+	default:
+		return path
+	}
+}
@@ -0,0 +1,102 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestReadDirRecWithOptions(t *testing.T) {
+	path := "read_dir_rec_options"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{
+		"a.txt":          "a",
+		"nested/b.txt":   "b",
+		"nested/c/d.txt": "d",
+	})
+	hasSymlink := runtime.GOOS != "windows" && os.Symlink("a.txt", filepath.Join(path, "link.txt")) == nil
+
+	// Expect relative paths of files only, in lexical order
+	files, err := ReadDirRecWithOptions(path, ReadDirRecOptions{Paths: PathRelative})
+	if err != nil {
+		t.Fatalf("ReadDirRecWithOptions failed: %v", err)
+	}
+	expected := []string{"a.txt", filepath.Join("nested", "b.txt"), filepath.Join("nested", "c", "d.txt")}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+
+	// Expect directories before their content, and symlinks when requested
+	files, err = ReadDirRecWithOptions(path, ReadDirRecOptions{Paths: PathRelative, IncludeDirs: true, IncludeSymlinks: true})
+	if err != nil {
+		t.Fatalf("ReadDirRecWithOptions failed: %v", err)
+	}
+	expected = []string{"a.txt"}
+	if hasSymlink {
+		expected = append(expected, "link.txt")
+	}
+	expected = append(expected, "nested", filepath.Join("nested", "b.txt"), filepath.Join("nested", "c"), filepath.Join("nested", "c", "d.txt"))
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+
+	// Expect ignored files and directories to be skipped, relative to the root
+	files, err = ReadDirRecWithOptions(path, ReadDirRecOptions{Paths: PathRelative, IncludeDirs: true, Ignore: NewIgnorer("c/", "a.txt")})
+	if err != nil {
+		t.Fatalf("ReadDirRecWithOptions failed: %v", err)
+	}
+	expected = []string{"nested", filepath.Join("nested", "b.txt")}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+
+	// Expect absolute paths to be rooted at the absolute root
+	files, err = ReadDirRecWithOptions(path, ReadDirRecOptions{Paths: PathAbsolute})
+	if err != nil {
+		t.Fatalf("ReadDirRecWithOptions failed: %v", err)
+	}
+	abs, _ := filepath.Abs(filepath.Join(path, "a.txt"))
+	if len(files) != 3 || files[0] != abs {
+		t.Errorf("Expected %s first, got %v", abs, files)
+	}
+}
+
+func TestIterDirRec(t *testing.T) {
+	path := "iter_dir_rec"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{
+		".git/config":  "",
+		"a.txt":        "a",
+		"nested/b.txt": "b",
+	})
+
+	it, err := IterDirRec(path, ReadDirRecOptions{IncludeDirs: true})
+	if err != nil {
+		t.Fatalf("IterDirRec failed: %v", err)
+	}
+
+	// Expect skipped directories not to be descended into
+	var files []string
+	for it.Next() {
+		if it.Entry().Name() == ".git" {
+			it.SkipDir()
+			continue
+		}
+		files = append(files, it.Path())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+
+	expected := []string{filepath.Join(path, "a.txt"), filepath.Join(path, "nested"), filepath.Join(path, "nested", "b.txt")}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+
+	// Expect a missing root to fail
+	if _, err := IterDirRec("missing", ReadDirRecOptions{}); err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -0,0 +1,88 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ReadOptions configures OpenReader and ReadBytesWithOptions.
+type ReadOptions struct {
+	// NoCache reads around the page cache, so reading large files, like in backups, doesn't
+	// evict the data other programs keep hot. It uses O_DIRECT on Linux, falling back to
+	// dropping the read pages from the cache where the file system doesn't support it,
+	// and F_NOCACHE on macOS. Elsewhere it has no effect.
+	NoCache bool
+	// Advice tells the operating system how the file is going to be read. AdviceDontNeed
+	// drops the read pages from the cache when the reader is closed. Ignored with NoCache.
+	Advice Advice
+}
+
+// OpenReader opens a file for reading as selected by the options. Unlike a file opened with
+// O_DIRECT directly, the reader accepts buffers of any size and alignment. The caller must
+// close it.
+//
+// Example:
+//
+//	reader, err := OpenReader("/var/lib/db/data.bin", ReadOptions{NoCache: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer reader.Close()
+//	_, err = io.Copy(backup, reader)
+func OpenReader(path string, opts ReadOptions) (io.ReadCloser, error) {
+	path = normalizePath(path)
+
+	if opts.NoCache {
+		reader, err := openNoCache(path)
+		if err != nil {
+			return nil, pathError("OpenReader", path, fmt.Errorf("OpenReader failed to open file: %w", err))
+		}
+		return reader, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("OpenReader", path, fmt.Errorf("OpenReader failed to open file: %w", err))
+	}
+	adviseRead(file, opts.Advice)
+	if opts.Advice == AdviceDontNeed {
+		return advisedFile{File: file, advice: opts.Advice}, nil
+	}
+
+	return file, nil
+}
+
+// ReadBytesWithOptions reads the content of a file as selected by the options and returns
+// it as a byte slice.
+//
+// Example:
+//
+//	content, err := ReadBytesWithOptions("backup.tar", ReadOptions{NoCache: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReadBytesWithOptions(path string, opts ReadOptions) (content []byte, err error) {
+	defer func() { observe("ReadBytes", int64(len(content)), 0, err) }()
+
+	reader, err := OpenReader(path, opts)
+	if err != nil {
+		return nil, pathError("ReadBytes", path, fmt.Errorf("ReadBytes failed to open file: %w", err))
+	}
+	defer reader.Close()
+
+	var buf bytes.Buffer
+	if info, err := os.Stat(normalizePath(path)); err == nil && info.Mode().IsRegular() {
+		buf.Grow(int(info.Size()) + bytes.MinRead)
+	}
+
+	_, err = buf.ReadFrom(reader)
+	if err != nil {
+		return nil, pathError("ReadBytes", path, fmt.Errorf("ReadBytes failed to read file: %w", err))
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,23 @@
+package fs_go
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func openNoCache(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = unix.FcntlInt(file.Fd(), unix.F_NOCACHE, 1)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
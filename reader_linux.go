@@ -0,0 +1,116 @@
+package fs_go
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// directBufferSize is the size of the aligned buffer O_DIRECT reads go through.
+const directBufferSize = 1 << 20
+
+func openNoCache(path string) (io.ReadCloser, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY|unix.O_DIRECT, 0)
+	if errors.Is(err, unix.EINVAL) {
+		// The file system doesn't support O_DIRECT
+		file, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return &directReader{file: file}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// O_DIRECT needs buffers aligned to the block size, which anonymous mappings are
+	buf, err := unix.Mmap(-1, 0, directBufferSize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &directReader{file: file, buf: buf, direct: true}, nil
+}
+
+// directReader reads a file opened with O_DIRECT through an aligned buffer, so callers can
+// read into buffers of any size. Where O_DIRECT turns out not to be supported, it reads
+// normally and drops the read pages from the page cache on Close instead.
+type directReader struct {
+	file   *os.File
+	buf    []byte // Aligned buffer, nil unless direct
+	data   []byte // Unread part of buf
+	direct bool
+	eof    bool
+}
+
+func (r *directReader) Read(p []byte) (int, error) {
+	if !r.direct {
+		return r.file.Read(p)
+	}
+
+	if len(r.data) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+
+		n, err := r.file.Read(r.buf)
+		if errors.Is(err, unix.EINVAL) && !r.started() {
+			// Some file systems accept O_DIRECT when opening, but not when reading
+			err = r.fallback()
+			if err != nil {
+				return 0, err
+			}
+			return r.file.Read(p)
+		}
+		if err == io.EOF || n < len(r.buf) {
+			// Short reads only happen at the end, and leave the offset unaligned
+			r.eof = true
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if n == 0 {
+			return 0, io.EOF
+		}
+		r.data = r.buf[:n]
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// started reports whether anything was read yet.
+func (r *directReader) started() bool {
+	offset, err := r.file.Seek(0, io.SeekCurrent)
+	return err != nil || offset != 0
+}
+
+// fallback switches to normal reads.
+func (r *directReader) fallback() error {
+	flags, err := unix.FcntlInt(r.file.Fd(), unix.F_GETFL, 0)
+	if err != nil {
+		return err
+	}
+	_, err = unix.FcntlInt(r.file.Fd(), unix.F_SETFL, flags&^unix.O_DIRECT)
+	if err != nil {
+		return err
+	}
+
+	r.direct = false
+	return unix.Munmap(r.buf)
+}
+
+func (r *directReader) Close() error {
+	if r.direct {
+		unix.Munmap(r.buf)
+	} else {
+		// Drop whatever was read from the cache, as O_DIRECT would have avoided caching it
+		unix.Fadvise(int(r.file.Fd()), 0, 0, unix.FADV_DONTNEED)
+	}
+
+	return r.file.Close()
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package fs_go
+
+import (
+	"io"
+	"os"
+)
+
+func openNoCache(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
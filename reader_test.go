@@ -0,0 +1,63 @@
+package fs_go
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadBytesWithOptions(t *testing.T) {
+	path := "read_options_1"
+	defer os.RemoveAll(path)
+
+	// Sizes around the buffer of O_DIRECT reads, which must all read back whole
+	sizes := []int{0, 1, 4095, 4096, 1<<20 + 17, 3 << 20}
+	files := map[string]string{}
+	for _, size := range sizes {
+		files[filepath.Join("sizes", string(rune('a'+len(files))))] = string(bytes.Repeat([]byte{byte(size)}, size))
+	}
+	writeTree(t, path, files)
+
+	// Expect the content to be read whole, with or without the page cache
+	for name, content := range files {
+		for _, opts := range []ReadOptions{{}, {NoCache: true}} {
+			read, err := ReadBytesWithOptions(filepath.Join(path, name), opts)
+			if err != nil {
+				t.Fatalf("ReadBytesWithOptions failed: %v", err)
+			}
+			if !bytes.Equal(read, []byte(content)) {
+				t.Errorf("Expected %d identical bytes with %+v, got %d", len(content), opts, len(read))
+			}
+		}
+	}
+
+	// Expect small, unaligned reads to work with NoCache
+	t.Run("small reads", func(t *testing.T) {
+		name := filepath.Join(path, "sizes", "e")
+		reader, err := OpenReader(name, ReadOptions{NoCache: true})
+		if err != nil {
+			t.Fatalf("OpenReader failed: %v", err)
+		}
+		defer reader.Close()
+
+		var read bytes.Buffer
+		_, err = io.CopyBuffer(&read, struct{ io.Reader }{reader}, make([]byte, 7))
+		if err != nil {
+			t.Fatalf("io.CopyBuffer failed: %v", err)
+		}
+		if read.String() != files[filepath.Join("sizes", "e")] {
+			t.Errorf("Expected %d identical bytes, got %d", len(files[filepath.Join("sizes", "e")]), read.Len())
+		}
+	})
+
+	// Expect missing files to fail
+	t.Run("missing", func(t *testing.T) {
+		_, err := ReadBytesWithOptions(filepath.Join(path, "missing"), ReadOptions{NoCache: true})
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected ErrNotExist, got %v", err)
+		}
+	})
+}
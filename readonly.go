@@ -0,0 +1,71 @@
+package fs_go
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+var readOnly atomic.Bool
+
+// SetReadOnly enables or disables read-only mode for the whole package. While enabled,
+// functions that would modify the file system fail with an error wrapping ErrReadOnly
+// instead, which makes a global --read-only flag safe to implement. Reads are unaffected.
+// Read-only mode takes precedence over dry-run mode.
+//
+// To turn writes into logged no-ops instead of errors, use SetDryRun.
+//
+// Example:
+//
+//	if *readOnlyFlag {
+//	    SetReadOnly(true)
+//	}
+func SetReadOnly(enabled bool) {
+	readOnly.Store(enabled)
+}
+
+// IsReadOnly reports whether read-only mode is enabled.
+func IsReadOnly() bool {
+	return readOnly.Load()
+}
+
+// checkWritable returns an error wrapping ErrReadOnly if read-only mode is enabled.
+func checkWritable(op, path string) error {
+	if !IsReadOnly() {
+		return nil
+	}
+
+	return pathError(op, path, fmt.Errorf("%s failed: %w", op, ErrReadOnly))
+}
+
+// WriteGuard lets packages building on this one honor SetReadOnly and SetDryRun for their
+// own writes. It reports true if the caller must not perform the operation op on path,
+// and dest if the operation has one, along with the error to return, which wraps
+// ErrReadOnly in read-only mode and is nil in dry-run mode, where the action is recorded.
+//
+// Example:
+//
+//	if skip, err := fs_go.WriteGuard("Publish", path, ""); skip {
+//	    return err
+//	}
+func WriteGuard(op, path, dest string) (bool, error) {
+	return writeGuard(op, path, dest)
+}
+
+// writeGuard must be called before every mutating operation. It reports true if the
+// caller must not perform the operation, along with the error to return, which is nil
+// in dry-run mode. Otherwise, the paths are invalidated in the stat cache, and the caller
+// must invalidate them again once the operation is done, usually with a deferred call to
+// invalidateStats, since a stat taken in between would cache the old state.
+func writeGuard(op, path, dest string) (bool, error) {
+	err := checkWritable(op, path)
+	if err != nil {
+		return true, err
+	}
+
+	if dryRunSkip(op, path, dest) {
+		return true, nil
+	}
+	invalidateStats(path, dest)
+
+	return false, nil
+}
@@ -0,0 +1,92 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetReadOnly(t *testing.T) {
+	// Expect writes to fail with ErrReadOnly and leave the file system untouched
+	t.Run("writes fail", func(t *testing.T) {
+		path := "set_read_only_1.txt"
+		defer os.Remove(path)
+
+		SetReadOnly(true)
+		defer SetReadOnly(false)
+
+		err := WriteText(path, "content")
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Expected error to match ErrReadOnly, got %v", err)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected file to not be created")
+		}
+	})
+
+	// Expect reads and existing directories to be unaffected
+	t.Run("reads succeed", func(t *testing.T) {
+		path := "set_read_only_2"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{"a.txt": "a"})
+
+		SetReadOnly(true)
+		defer SetReadOnly(false)
+
+		content, err := ReadText(filepath.Join(path, "a.txt"))
+		if err != nil {
+			t.Errorf("ReadText failed: %v", err)
+		}
+
+		if content != "a" {
+			t.Errorf("Expected content to be %q, got %q", "a", content)
+		}
+
+		err = EnsureDir(path)
+		if err != nil {
+			t.Errorf("EnsureDir failed for an existing directory: %v", err)
+		}
+	})
+
+	// Expect bulk operations to fail before touching the destination
+	t.Run("sync fails", func(t *testing.T) {
+		path := "set_read_only_3"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{"a.txt": "a"})
+
+		SetReadOnly(true)
+		defer SetReadOnly(false)
+
+		_, err := SyncDir(src, dst)
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Expected error to match ErrReadOnly, got %v", err)
+		}
+
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("Expected destination to not be created")
+		}
+	})
+
+	// Expect read-only mode to take precedence over dry-run mode
+	t.Run("precedence over dry run", func(t *testing.T) {
+		SetReadOnly(true)
+		defer SetReadOnly(false)
+
+		plan, err := DryRun(func() error {
+			return Remove("set_read_only_4.txt")
+		})
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Expected error to match ErrReadOnly, got %v", err)
+		}
+
+		if len(plan) != 0 {
+			t.Errorf("Expected no actions to be recorded, got %v", plan)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package fs_go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RebuildOptions configures NeedsRebuildWithOptions and MarkBuilt.
+type RebuildOptions struct {
+	// ByContent compares the content of the sources against the stamp written by MarkBuilt
+	// instead of comparing modification times, so sources that are touched without being
+	// changed, like after a checkout, don't cause a rebuild.
+	ByContent bool
+	// StampPath is the file that records the digest of the sources in ByContent mode.
+	// Defaults to the target path with ".stamp" appended.
+	StampPath string
+}
+
+// NeedsRebuild reports whether target is missing or older than any of the sources,
+// like the rules of make. Missing sources are an error.
+//
+// Example:
+//
+//	rebuild, err := NeedsRebuild("dist/app.css", "src/app.scss", "src/theme.scss")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if rebuild {
+//	    compileStyles()
+//	}
+func NeedsRebuild(target string, sources ...string) (bool, error) {
+	return NeedsRebuildWithOptions(target, sources, RebuildOptions{})
+}
+
+// NeedsRebuildWithOptions reports whether target needs to be rebuilt from sources. In
+// ByContent mode, it does if the target or its stamp is missing, or the sources changed
+// since MarkBuilt was last called.
+//
+// Example:
+//
+//	opts := RebuildOptions{ByContent: true}
+//	rebuild, err := NeedsRebuildWithOptions("gen/api.go", []string{"api.yaml"}, opts)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if rebuild {
+//	    generate()
+//	    err = MarkBuilt("gen/api.go", []string{"api.yaml"}, opts)
+//	}
+func NeedsRebuildWithOptions(target string, sources []string, opts RebuildOptions) (bool, error) {
+	target = normalizePath(target)
+
+	info, err := os.Stat(target)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, pathError("NeedsRebuild", target, fmt.Errorf("NeedsRebuild failed to get target stat: %w", err))
+	}
+
+	if opts.ByContent {
+		stamp, err := os.ReadFile(stampPath(target, opts))
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, pathError("NeedsRebuild", target, fmt.Errorf("NeedsRebuild failed to read stamp: %w", err))
+		}
+
+		digest, err := sourcesDigest(sources)
+		if err != nil {
+			return false, pathError("NeedsRebuild", target, fmt.Errorf("NeedsRebuild failed to hash sources: %w", err))
+		}
+
+		return strings.TrimSpace(string(stamp)) != digest, nil
+	}
+
+	for _, source := range sources {
+		modTime, err := ModTime(source)
+		if err != nil {
+			return false, pathError("NeedsRebuild", source, fmt.Errorf("NeedsRebuild failed to get source stat: %w", err))
+		}
+		if modTime.After(info.ModTime()) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// MarkBuilt records the current content of the sources in the stamp of target, for
+// NeedsRebuildWithOptions in ByContent mode. Call it after target was rebuilt successfully.
+func MarkBuilt(target string, sources []string, opts RebuildOptions) error {
+	target = normalizePath(target)
+
+	digest, err := sourcesDigest(sources)
+	if err != nil {
+		return pathError("MarkBuilt", target, fmt.Errorf("MarkBuilt failed to hash sources: %w", err))
+	}
+
+	err = writeAtomic(stampPath(target, opts), []byte(digest+"\n"), 0644, false)
+	if err != nil {
+		return pathError("MarkBuilt", target, fmt.Errorf("MarkBuilt failed to write stamp: %w", err))
+	}
+
+	return nil
+}
+
+// stampPath returns the stamp file of target.
+func stampPath(target string, opts RebuildOptions) string {
+	if opts.StampPath != "" {
+		return normalizePath(opts.StampPath)
+	}
+
+	return target + ".stamp"
+}
+
+// sourcesDigest returns a digest covering the paths and content of the sources, in order.
+func sourcesDigest(sources []string) (string, error) {
+	hash := sha256.New()
+	for _, source := range sources {
+		digest, err := hashFile(normalizePath(source))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%s\n", source, digest)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
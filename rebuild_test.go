@@ -0,0 +1,75 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNeedsRebuild(t *testing.T) {
+	path := "needs_rebuild"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"a.src": "a", "b.src": "b"})
+	a, b := filepath.Join(path, "a.src"), filepath.Join(path, "b.src")
+	target := filepath.Join(path, "out")
+
+	// Expect a missing target to need a rebuild
+	if rebuild, err := NeedsRebuild(target, a, b); err != nil || !rebuild {
+		t.Errorf("Expected a rebuild, got %v, %v", rebuild, err)
+	}
+
+	// Expect a target newer than its sources to be up to date
+	os.WriteFile(target, []byte("out"), 0644)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(a, past, past)
+	os.Chtimes(b, past, past)
+	if rebuild, err := NeedsRebuild(target, a, b); err != nil || rebuild {
+		t.Errorf("Expected no rebuild, got %v, %v", rebuild, err)
+	}
+
+	// Expect a source newer than the target to need a rebuild
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(b, future, future)
+	if rebuild, err := NeedsRebuild(target, a, b); err != nil || !rebuild {
+		t.Errorf("Expected a rebuild, got %v, %v", rebuild, err)
+	}
+
+	// Expect missing sources to fail
+	if _, err := NeedsRebuild(target, filepath.Join(path, "missing")); !errors.Is(err, ErrNotExist) {
+		t.Errorf("Expected ErrNotExist, got %v", err)
+	}
+}
+
+func TestNeedsRebuildByContent(t *testing.T) {
+	path := "needs_rebuild_content"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"a.src": "a", "out": "out"})
+	sources := []string{filepath.Join(path, "a.src")}
+	target := filepath.Join(path, "out")
+	opts := RebuildOptions{ByContent: true}
+
+	// Expect a target without a stamp to need a rebuild
+	if rebuild, err := NeedsRebuildWithOptions(target, sources, opts); err != nil || !rebuild {
+		t.Errorf("Expected a rebuild, got %v, %v", rebuild, err)
+	}
+
+	err := MarkBuilt(target, sources, opts)
+	if err != nil {
+		t.Fatalf("MarkBuilt failed: %v", err)
+	}
+
+	// Expect touching a source without changing it to keep the target up to date
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(sources[0], future, future)
+	if rebuild, err := NeedsRebuildWithOptions(target, sources, opts); err != nil || rebuild {
+		t.Errorf("Expected no rebuild, got %v, %v", rebuild, err)
+	}
+
+	// Expect changed content to need a rebuild
+	os.WriteFile(sources[0], []byte("changed"), 0644)
+	if rebuild, err := NeedsRebuildWithOptions(target, sources, opts); err != nil || !rebuild {
+		t.Errorf("Expected a rebuild, got %v, %v", rebuild, err)
+	}
+}
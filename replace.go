@@ -0,0 +1,186 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// ReplaceOptions configures ReplaceRegexpInFileWithOptions and ReplaceInTree.
+type ReplaceOptions struct {
+	// BackupSuffix, if set, keeps the original content of every changed file next to it,
+	// at its path with the suffix appended, like ".bak".
+	BackupSuffix string
+	// Include limits ReplaceInTree to files matching any of these patterns, in the syntax
+	// of path.Match. A pattern matches the slash-separated path relative to the root or
+	// the base name. All files are included if empty.
+	Include []string
+	// Ignore skips the files and directories it matches in ReplaceInTree, relative to the root.
+	Ignore *Ignorer
+}
+
+// ReplaceInFile replaces all occurrences of old with new in a file and returns the number
+// of replacements. Files are rewritten atomically with their mode kept, and only if
+// something was replaced.
+//
+// Example:
+//
+//	n, err := ReplaceInFile("go.mod", "example.com/old", "example.com/new")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReplaceInFile(path, old, new string) (int, error) {
+	return replaceInFile("ReplaceInFile", path, ReplaceOptions{}, func(content []byte) ([]byte, int) {
+		if old == "" {
+			return content, 0
+		}
+		return bytes.ReplaceAll(content, []byte(old), []byte(new)), bytes.Count(content, []byte(old))
+	})
+}
+
+// ReplaceRegexpInFile replaces all matches of re in a file with repl, which can refer to
+// submatches like Regexp.Expand, and returns the number of replacements.
+//
+// Example:
+//
+//	re := regexp.MustCompile(`version = "[^"]*"`)
+//	n, err := ReplaceRegexpInFile("Cargo.toml", re, `version = "2.0.0"`)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReplaceRegexpInFile(path string, re *regexp.Regexp, repl string) (int, error) {
+	return ReplaceRegexpInFileWithOptions(path, re, repl, ReplaceOptions{})
+}
+
+// ReplaceRegexpInFileWithOptions replaces all matches of re in a file with repl, keeping
+// a backup of the original if requested.
+func ReplaceRegexpInFileWithOptions(path string, re *regexp.Regexp, repl string, opts ReplaceOptions) (int, error) {
+	return replaceInFile("ReplaceRegexpInFile", path, opts, regexpReplacer(re, repl))
+}
+
+// ReplaceInTree replaces all matches of re with repl in the regular files below root that
+// pass the options' filters, and returns the slash-separated paths, relative to root, of
+// the files that changed. Files are processed one at a time, so an error can leave some
+// of them changed; the returned paths are accurate either way.
+//
+// Example:
+//
+//	re := regexp.MustCompile(`\bOldName\b`)
+//	changed, err := ReplaceInTree("src", re, "NewName", ReplaceOptions{
+//	    Include: []string{"*.go"},
+//	    Ignore:  NewIgnorer("vendor/"),
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func ReplaceInTree(root string, re *regexp.Regexp, repl string, opts ReplaceOptions) ([]string, error) {
+	root = normalizePath(root)
+
+	var changed []string
+	err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && opts.Ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !matchesInclude(rel, opts.Include) {
+			return nil
+		}
+
+		n, err := replaceInFile("ReplaceInTree", file, opts, regexpReplacer(re, repl))
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			changed = append(changed, rel)
+		}
+		return nil
+	})
+	sort.Strings(changed)
+	if err != nil {
+		return changed, pathError("ReplaceInTree", root, fmt.Errorf("ReplaceInTree failed: %w", err))
+	}
+
+	return changed, nil
+}
+
+// replaceInFile rewrites a file with the content returned by replace, if it made any replacements.
+func replaceInFile(op, file string, opts ReplaceOptions, replace func([]byte) ([]byte, int)) (int, error) {
+	file = normalizePath(file)
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0, pathError(op, file, fmt.Errorf("%s failed to get file stat: %w", op, err))
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return 0, pathError(op, file, fmt.Errorf("%s failed to read file: %w", op, err))
+	}
+
+	replaced, n := replace(content)
+	if n == 0 {
+		return 0, nil
+	}
+
+	if opts.BackupSuffix != "" {
+		err = writeAtomic(file+opts.BackupSuffix, content, info.Mode().Perm(), false)
+		if err != nil {
+			return 0, pathError(op, file, fmt.Errorf("%s failed to write backup: %w", op, err))
+		}
+	}
+
+	err = writeAtomic(file, replaced, info.Mode().Perm(), false)
+	if err != nil {
+		return 0, pathError(op, file, fmt.Errorf("%s failed to write file: %w", op, err))
+	}
+
+	return n, nil
+}
+
+// regexpReplacer returns a replace function for replaceInFile that expands repl for every match of re.
+func regexpReplacer(re *regexp.Regexp, repl string) func([]byte) ([]byte, int) {
+	return func(content []byte) ([]byte, int) {
+		n := len(re.FindAllIndex(content, -1))
+		if n == 0 {
+			return content, 0
+		}
+		return re.ReplaceAll(content, []byte(repl)), n
+	}
+}
+
+// matchesInclude reports whether a slash-separated relative path or its base name matches
+// any of the patterns, or whether there are no patterns.
+func matchesInclude(rel string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"testing"
+)
+
+func TestReplaceInFile(t *testing.T) {
+	path := "replace_in_file.txt"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("foo bar foo"), 0600)
+
+	// Expect every occurrence to be replaced and the mode kept
+	n, err := ReplaceInFile(path, "foo", "baz")
+	if err != nil {
+		t.Fatalf("ReplaceInFile failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 replacements, got %d", n)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "baz bar baz" {
+		t.Errorf("Expected baz bar baz, got %q", content)
+	}
+	if info, _ := os.Stat(path); runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %#o", info.Mode().Perm())
+	}
+
+	// Expect no replacements to leave the file alone
+	n, err = ReplaceInFile(path, "missing", "x")
+	if err != nil || n != 0 {
+		t.Errorf("Expected 0 replacements, got %d, %v", n, err)
+	}
+}
+
+func TestReplaceRegexpInFile(t *testing.T) {
+	path := "replace_regexp_in_file.toml"
+	defer os.Remove(path)
+	defer os.Remove(path + ".bak")
+	os.WriteFile(path, []byte(`version = "1.2.3"`), 0644)
+
+	// Expect submatches to be expanded and a backup to be kept
+	re := regexp.MustCompile(`version = "(\d+)\.\d+\.\d+"`)
+	n, err := ReplaceRegexpInFileWithOptions(path, re, `version = "${1}.9.0"`, ReplaceOptions{BackupSuffix: ".bak"})
+	if err != nil {
+		t.Fatalf("ReplaceRegexpInFileWithOptions failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 replacement, got %d", n)
+	}
+	if content, _ := os.ReadFile(path); string(content) != `version = "1.9.0"` {
+		t.Errorf("Expected version 1.9.0, got %q", content)
+	}
+	if content, _ := os.ReadFile(path + ".bak"); string(content) != `version = "1.2.3"` {
+		t.Errorf("Expected backup with version 1.2.3, got %q", content)
+	}
+}
+
+func TestReplaceInTree(t *testing.T) {
+	path := "replace_in_tree"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{
+		"main.go":           "OldName()",
+		"pkg/util.go":       "OldName and OldName",
+		"pkg/README.md":     "OldName",
+		"vendor/dep/dep.go": "OldName",
+	})
+
+	// Expect only included, non-ignored files to change
+	changed, err := ReplaceInTree(path, regexp.MustCompile(`\bOldName\b`), "NewName", ReplaceOptions{
+		Include: []string{"*.go"},
+		Ignore:  NewIgnorer("vendor/"),
+	})
+	if err != nil {
+		t.Fatalf("ReplaceInTree failed: %v", err)
+	}
+	if !reflect.DeepEqual(changed, []string{"main.go", "pkg/util.go"}) {
+		t.Errorf("Expected main.go and pkg/util.go to change, got %v", changed)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(path, "pkg", "util.go")); string(content) != "NewName and NewName" {
+		t.Errorf("Expected both names replaced, got %q", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(path, "vendor", "dep", "dep.go")); string(content) != "OldName" {
+		t.Errorf("Expected vendor to be untouched, got %q", content)
+	}
+}
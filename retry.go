@@ -0,0 +1,163 @@
+package fs_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
+)
+
+// RetryPolicy configures how a Retrier retries failed operations.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. Defaults to 5.
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry. Defaults to 100ms.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts. Defaults to 5s.
+	MaxDelay time.Duration
+	// Multiplier is the factor the delay grows by after every retry. Defaults to 2.
+	Multiplier float64
+	// Jitter is the fraction of every delay that is randomized, so clients that failed
+	// together don't retry together. Defaults to 0.2, and negative values disable it.
+	Jitter float64
+	// Retryable reports whether an error is worth retrying. Defaults to IsTransient.
+	Retryable func(error) bool
+}
+
+// Retrier retries idempotent file operations with exponential backoff, for file systems
+// that fail transiently, such as NFS or SMB shares.
+//
+// Only operations that are safe to repeat are offered. Use Do for anything else that is.
+type Retrier struct {
+	policy RetryPolicy
+}
+
+// NewRetrier creates a Retrier with the given policy.
+//
+// Example:
+//
+//	retrier := NewRetrier(RetryPolicy{MaxAttempts: 10})
+//	content, err := retrier.ReadBytes("/mnt/nfs/data.bin")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func NewRetrier(policy RetryPolicy) *Retrier {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 5
+	}
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = 100 * time.Millisecond
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = 5 * time.Second
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 2
+	}
+	if policy.Jitter == 0 {
+		policy.Jitter = 0.2
+	}
+	policy.Jitter = min(max(policy.Jitter, 0), 1)
+	if policy.Retryable == nil {
+		policy.Retryable = IsTransient
+	}
+
+	return &Retrier{policy: policy}
+}
+
+// IsTransient reports whether an error is likely to go away on its own, such as a busy
+// or stale file handle on a network file system, an interrupted call, or a timeout.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, os.ErrDeadlineExceeded) || isTransientErrno(err)
+}
+
+// Do calls fn until it succeeds, returns an error that isn't retryable, or runs out of attempts.
+// fn must be safe to call more than once.
+func (r *Retrier) Do(fn func() error) error {
+	return r.DoContext(context.Background(), fn)
+}
+
+// DoContext is like Do, but stops waiting for the next attempt once ctx is done.
+func (r *Retrier) DoContext(ctx context.Context, fn func() error) error {
+	delay := r.policy.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if attempt >= r.policy.MaxAttempts || !r.policy.Retryable(err) {
+			return fmt.Errorf("Retry failed after %d attempts: %w", attempt, err)
+		}
+
+		// Spread the delay evenly around its nominal value
+		jittered := time.Duration(float64(delay) * (1 + r.policy.Jitter*(2*rand.Float64()-1)))
+		timer := time.NewTimer(jittered)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("Retry failed after %d attempts: %w", attempt, errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+
+		delay = min(time.Duration(float64(delay)*r.policy.Multiplier), r.policy.MaxDelay)
+	}
+}
+
+// ReadBytes reads a file with retries. See ReadBytes.
+func (r *Retrier) ReadBytes(path string) ([]byte, error) {
+	var content []byte
+	err := r.Do(func() error {
+		var err error
+		content, err = ReadBytes(path)
+		return err
+	})
+
+	return content, err
+}
+
+// ReadText reads a file as a string with retries. See ReadText.
+func (r *Retrier) ReadText(path string) (string, error) {
+	content, err := r.ReadBytes(path)
+	return string(content), err
+}
+
+// WriteBytes writes a file with retries. See WriteBytes.
+func (r *Retrier) WriteBytes(path string, content []byte) error {
+	return r.Do(func() error {
+		return WriteBytes(path, content)
+	})
+}
+
+// WriteText writes a string to a file with retries. See WriteText.
+func (r *Retrier) WriteText(path, content string) error {
+	return r.WriteBytes(path, []byte(content))
+}
+
+// CopyFile copies a file with retries. See CopyFile.
+func (r *Retrier) CopyFile(src, dst string) error {
+	return r.Do(func() error {
+		return CopyFile(src, dst)
+	})
+}
+
+// EnsureDir creates a directory with retries. See EnsureDir.
+func (r *Retrier) EnsureDir(path string) error {
+	return r.Do(func() error {
+		return EnsureDir(path)
+	})
+}
+
+// RemoveAll removes a path and everything it contains with retries. See RemoveAll.
+func (r *Retrier) RemoveAll(path string) error {
+	return r.Do(func() error {
+		return RemoveAll(path)
+	})
+}
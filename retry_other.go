@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package fs_go
+
+// isTransientErrno reports whether an error is a system error that is usually temporary.
+func isTransientErrno(err error) bool {
+	return false
+}
@@ -0,0 +1,120 @@
+package fs_go
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRetrier(t *testing.T) {
+	errTransient := errors.New("transient")
+	policy := RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		Retryable:    func(err error) bool { return errors.Is(err, errTransient) },
+	}
+
+	// Expect transient errors to be retried until the operation succeeds
+	t.Run("retries transient errors", func(t *testing.T) {
+		attempts := 0
+		err := NewRetrier(policy).Do(func() error {
+			attempts++
+			if attempts < 3 {
+				return errTransient
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Do failed: %v", err)
+		}
+
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	// Expect other errors to be returned immediately
+	t.Run("doesn't retry other errors", func(t *testing.T) {
+		errPermanent := errors.New("permanent")
+		attempts := 0
+		err := NewRetrier(policy).Do(func() error {
+			attempts++
+			return errPermanent
+		})
+		if !errors.Is(err, errPermanent) {
+			t.Errorf("Expected error to wrap the permanent error, got %v", err)
+		}
+
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	// Expect the last error after running out of attempts
+	t.Run("gives up", func(t *testing.T) {
+		attempts := 0
+		err := NewRetrier(policy).Do(func() error {
+			attempts++
+			return errTransient
+		})
+		if !errors.Is(err, errTransient) {
+			t.Errorf("Expected error to wrap the transient error, got %v", err)
+		}
+
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	// Expect waiting to stop once the context is done
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		slow := policy
+		slow.InitialDelay = time.Hour
+		err := NewRetrier(slow).DoContext(ctx, func() error {
+			return errTransient
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected error to wrap context.Canceled, got %v", err)
+		}
+	})
+
+	// Expect file operations to work through the retrier
+	t.Run("file operations", func(t *testing.T) {
+		path := "retrier_1.txt"
+		defer os.Remove(path)
+
+		retrier := NewRetrier(policy)
+		err := retrier.WriteText(path, "content")
+		if err != nil {
+			t.Errorf("WriteText failed: %v", err)
+		}
+
+		content, err := retrier.ReadText(path)
+		if err != nil {
+			t.Errorf("ReadText failed: %v", err)
+		}
+
+		if content != "content" {
+			t.Errorf("Expected content to be %q, got %q", "content", content)
+		}
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	// Expect timeouts to be transient and missing files to not be
+	t.Run("classification", func(t *testing.T) {
+		if !IsTransient(&os.PathError{Op: "read", Path: "f", Err: os.ErrDeadlineExceeded}) {
+			t.Errorf("Expected a timeout to be transient")
+		}
+
+		_, err := ReadBytes("is_transient_missing.txt")
+		if IsTransient(err) {
+			t.Errorf("Expected a missing file to not be transient")
+		}
+	})
+}
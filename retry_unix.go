@@ -0,0 +1,19 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isTransientErrno reports whether an error is a system error that is usually temporary.
+func isTransientErrno(err error) bool {
+	for _, errno := range []syscall.Errno{syscall.EBUSY, syscall.ESTALE, syscall.EAGAIN, syscall.EINTR, syscall.ETIMEDOUT} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,24 @@
+package fs_go
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isTransientErrno reports whether an error is a system error that is usually temporary.
+func isTransientErrno(err error) bool {
+	for _, errno := range []windows.Errno{
+		windows.ERROR_SHARING_VIOLATION,
+		windows.ERROR_LOCK_VIOLATION,
+		windows.ERROR_NETNAME_DELETED,
+		windows.ERROR_SEM_TIMEOUT,
+		windows.ERROR_BUSY,
+	} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,302 @@
+package fs_go
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOptions configures a RotatingWriter.
+type RotateOptions struct {
+	// MaxSize rotates the file before a write would make it larger than this many bytes.
+	// Zero disables size-based rotation.
+	MaxSize int64
+	// MaxAge rotates the file once it has been written to for this long, counted from
+	// when it was opened or last rotated. Zero disables age-based rotation.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of them.
+	MaxBackups int
+	// Compress gzips rotated files.
+	Compress bool
+	// Mode is the file mode of new files. Defaults to 0644.
+	Mode os.FileMode
+}
+
+// backupTimeFormat names rotated files so they sort by age.
+const backupTimeFormat = "20060102T150405.000000000"
+
+// RotatingWriter is an io.Writer that appends to a file and rotates it by size or age.
+// Rotated files are renamed to "<path>.<timestamp>", optionally gzipped, and pruned to
+// MaxBackups. It is safe for concurrent use.
+//
+// In dry-run mode, writes are discarded.
+type RotatingWriter struct {
+	mu     sync.Mutex
+	path   string
+	opts   RotateOptions
+	file   *os.File
+	size   int64
+	opened time.Time
+	dryRun bool
+}
+
+// NewRotatingWriter opens path for appending and returns a writer that rotates it.
+//
+// Example:
+//
+//	writer, err := NewRotatingWriter("logs/app.log", RotateOptions{
+//	    MaxSize:    10 << 20,
+//	    MaxBackups: 5,
+//	    Compress:   true,
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer writer.Close()
+//	log.SetOutput(writer)
+func NewRotatingWriter(path string, opts RotateOptions) (*RotatingWriter, error) {
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+
+	w := &RotatingWriter{path: normalizePath(path), opts: opts}
+	if skip, err := writeGuard("AppendBytes", w.path, ""); skip {
+		if err != nil {
+			return nil, err
+		}
+		w.dryRun = true
+		return w, nil
+	}
+	defer invalidateStats(w.path)
+
+	err := w.open()
+	if err != nil {
+		return nil, fmt.Errorf("NewRotatingWriter failed to open file: %w", err)
+	}
+
+	return w, nil
+}
+
+// Write appends p to the file, rotating it first if needed.
+func (w *RotatingWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.dryRun {
+		return len(p), nil
+	}
+	if w.file == nil {
+		return 0, fmt.Errorf("RotatingWriter failed to write: %w", os.ErrClosed)
+	}
+	defer func() { observeWrite("AppendBytes", n, err) }()
+
+	if w.shouldRotate(int64(len(p))) {
+		err = w.rotate()
+		if err != nil {
+			return 0, fmt.Errorf("RotatingWriter failed to rotate: %w", err)
+		}
+	}
+
+	n, err = w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file now, for example when the process receives SIGHUP.
+//
+// Example:
+//
+//	hup := make(chan os.Signal, 1)
+//	signal.Notify(hup, syscall.SIGHUP)
+//	go func() {
+//	    for range hup {
+//	        writer.Rotate()
+//	    }
+//	}()
+func (w *RotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.dryRun {
+		return nil
+	}
+	if w.file == nil {
+		return fmt.Errorf("Rotate failed: %w", os.ErrClosed)
+	}
+
+	err := w.rotate()
+	if err != nil {
+		return fmt.Errorf("Rotate failed: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the file. Later writes fail.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// shouldRotate reports whether writing n more bytes calls for a rotation first.
+// A file is never rotated while empty, so writes larger than MaxSize still succeed.
+func (w *RotatingWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.opts.MaxSize > 0 && w.size+n > w.opts.MaxSize {
+		return true
+	}
+
+	return w.opts.MaxAge > 0 && time.Since(w.opened) >= w.opts.MaxAge
+}
+
+// open opens the file for appending.
+func (w *RotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, w.opts.Mode)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file, w.size, w.opened = file, info.Size(), time.Now()
+	return nil
+}
+
+// rotate moves the current file aside, opens a new one and prunes old backups.
+func (w *RotatingWriter) rotate() error {
+	// Backups are created and pruned next to the file
+	defer invalidateStats(filepath.Dir(w.path))
+
+	err := w.file.Close()
+	w.file = nil
+	if err != nil {
+		return err
+	}
+
+	backup := w.path + "." + time.Now().Format(backupTimeFormat)
+	err = os.Rename(w.path, backup)
+	if err != nil {
+		// Keep writing to the old file rather than losing data
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+
+	err = w.open()
+	if err != nil {
+		return err
+	}
+
+	if w.opts.Compress {
+		err = gzipFile(backup)
+		if err != nil {
+			return fmt.Errorf("failed to compress backup: %w", err)
+		}
+	}
+
+	return w.prune()
+}
+
+// prune removes the oldest backups beyond MaxBackups.
+func (w *RotatingWriter) prune() error {
+	if w.opts.MaxBackups <= 0 {
+		return nil
+	}
+
+	dir, base := filepath.Split(w.path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		if isBackupName(entry.Name(), base) && entry.Type().IsRegular() {
+			backups = append(backups, entry.Name())
+		}
+	}
+	sort.Strings(backups)
+
+	for len(backups) > w.opts.MaxBackups {
+		err = os.Remove(filepath.Join(dir, backups[0]))
+		if err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+
+	return nil
+}
+
+// isBackupName reports whether name is a backup of the file named base, which is
+// "<base>.<timestamp>" or "<base>.<timestamp>.gz", so other files sharing the prefix, like
+// "app.conf" next to "app", are never pruned.
+func isBackupName(name, base string) bool {
+	stamp, ok := strings.CutPrefix(name, base+".")
+	if !ok {
+		return false
+	}
+
+	_, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ".gz"))
+	return err == nil
+}
+
+// gzipFile compresses a file to "<path>.gz" and removes the original.
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,175 @@
+package fs_go
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rotateBackups returns the rotated files of path, oldest first.
+func rotateBackups(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	return matches
+}
+
+func TestRotatingWriter(t *testing.T) {
+	// Expect the file to be rotated before a write would exceed MaxSize
+	t.Run("size", func(t *testing.T) {
+		dir := "rotate_1"
+		defer os.RemoveAll(dir)
+		os.Mkdir(dir, 0755)
+		path := filepath.Join(dir, "app.log")
+
+		writer, err := NewRotatingWriter(path, RotateOptions{MaxSize: 10})
+		if err != nil {
+			t.Fatalf("NewRotatingWriter failed: %v", err)
+		}
+		defer writer.Close()
+
+		for _, line := range []string{"first\n", "second\n", "third\n"} {
+			if _, err := writer.Write([]byte(line)); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "third\n" {
+			t.Errorf("Expected third in current file, got %q", content)
+		}
+		backups := rotateBackups(t, path)
+		if len(backups) != 2 {
+			t.Fatalf("Expected 2 backups, got %v", backups)
+		}
+		oldest, _ := os.ReadFile(backups[0])
+		if string(oldest) != "first\n" {
+			t.Errorf("Expected first in oldest backup, got %q", oldest)
+		}
+	})
+
+	// Expect only MaxBackups backups to be kept, gzipped when Compress is set
+	t.Run("backups", func(t *testing.T) {
+		dir := "rotate_2"
+		defer os.RemoveAll(dir)
+		os.Mkdir(dir, 0755)
+		path := filepath.Join(dir, "app.log")
+
+		writer, err := NewRotatingWriter(path, RotateOptions{MaxBackups: 2, Compress: true})
+		if err != nil {
+			t.Fatalf("NewRotatingWriter failed: %v", err)
+		}
+		defer writer.Close()
+
+		for _, line := range []string{"1", "2", "3", "4"} {
+			writer.Write([]byte(line))
+			if err := writer.Rotate(); err != nil {
+				t.Fatalf("Rotate failed: %v", err)
+			}
+		}
+
+		backups := rotateBackups(t, path)
+		if len(backups) != 2 {
+			t.Fatalf("Expected 2 backups, got %v", backups)
+		}
+		if !strings.HasSuffix(backups[1], ".gz") {
+			t.Fatalf("Expected a gzipped backup, got %s", backups[1])
+		}
+
+		file, err := os.Open(backups[1])
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		defer file.Close()
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("gzip.NewReader failed: %v", err)
+		}
+		content, _ := io.ReadAll(reader)
+		if string(content) != "4" {
+			t.Errorf("Expected 4 in newest backup, got %q", content)
+		}
+	})
+
+	// Expect the file to be rotated once it is older than MaxAge
+	t.Run("age", func(t *testing.T) {
+		dir := "rotate_3"
+		defer os.RemoveAll(dir)
+		os.Mkdir(dir, 0755)
+		path := filepath.Join(dir, "app.log")
+
+		writer, err := NewRotatingWriter(path, RotateOptions{MaxAge: 20 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("NewRotatingWriter failed: %v", err)
+		}
+		defer writer.Close()
+
+		writer.Write([]byte("old"))
+		time.Sleep(30 * time.Millisecond)
+		writer.Write([]byte("new"))
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "new" {
+			t.Errorf("Expected new in current file, got %q", content)
+		}
+		if backups := rotateBackups(t, path); len(backups) != 1 {
+			t.Errorf("Expected 1 backup, got %v", backups)
+		}
+	})
+
+	// Expect writes after Close to fail
+	t.Run("closed", func(t *testing.T) {
+		dir := "rotate_4"
+		defer os.RemoveAll(dir)
+		os.Mkdir(dir, 0755)
+
+		writer, err := NewRotatingWriter(filepath.Join(dir, "app.log"), RotateOptions{})
+		if err != nil {
+			t.Fatalf("NewRotatingWriter failed: %v", err)
+		}
+		writer.Close()
+
+		if _, err := writer.Write([]byte("x")); err == nil {
+			t.Error("Expected an error writing to a closed writer")
+		}
+	})
+
+	// Expect files that only share the prefix of the log to survive pruning
+	t.Run("unrelated files", func(t *testing.T) {
+		dir := "rotate_5"
+		defer os.RemoveAll(dir)
+		os.Mkdir(dir, 0755)
+		path := filepath.Join(dir, "app.log")
+		for _, name := range []string{"app.log.old", "app.log.conf", "app.log.2020.gz"} {
+			os.WriteFile(filepath.Join(dir, name), []byte(name), 0644)
+		}
+
+		writer, err := NewRotatingWriter(path, RotateOptions{MaxBackups: 1})
+		if err != nil {
+			t.Fatalf("NewRotatingWriter failed: %v", err)
+		}
+		defer writer.Close()
+
+		for _, line := range []string{"1", "2", "3"} {
+			writer.Write([]byte(line))
+			if err := writer.Rotate(); err != nil {
+				t.Fatalf("Rotate failed: %v", err)
+			}
+		}
+
+		for _, name := range []string{"app.log.old", "app.log.conf", "app.log.2020.gz"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				t.Errorf("Expected %s to be kept, got %v", name, err)
+			}
+		}
+		if backups := rotateBackups(t, path); len(backups) != 4 {
+			t.Errorf("Expected 1 backup next to the unrelated files, got %v", backups)
+		}
+	})
+}
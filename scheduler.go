@@ -0,0 +1,169 @@
+package fs_go
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Scheduler runs bulk file operations concurrently, bucketing them by file size.
+// Many small files are processed with high concurrency, since their cost is dominated
+// by per-file latency, while large files get few workers so they don't compete for
+// disk bandwidth. The zero value uses sensible defaults.
+type Scheduler struct {
+	// SmallFileThreshold is the largest size in bytes of a small file. Defaults to 1 MiB.
+	SmallFileThreshold int64
+	// SmallWorkers is the number of small files processed concurrently. Defaults to 16.
+	SmallWorkers int
+	// LargeWorkers is the number of large files processed concurrently. Defaults to 2.
+	LargeWorkers int
+	// ChunkSize enables chunked parallelism for copies of files larger than it,
+	// copying ChunkWorkers chunks of a file at a time. Disabled if 0.
+	ChunkSize int64
+	// ChunkWorkers is the number of chunks of a single file copied concurrently. Defaults to 4.
+	ChunkWorkers int
+}
+
+// Job is a unit of work for a Scheduler, usually an operation on a single file.
+type Job struct {
+	Size int64        // Size of the file the job operates on, used for bucketing
+	Run  func() error // The work to do
+}
+
+// withDefaults returns a copy of the scheduler with unset fields filled in.
+func (s *Scheduler) withDefaults() Scheduler {
+	var scheduler Scheduler
+	if s != nil {
+		scheduler = *s
+	}
+	if scheduler.SmallFileThreshold <= 0 {
+		scheduler.SmallFileThreshold = 1 << 20
+	}
+	if scheduler.SmallWorkers <= 0 {
+		scheduler.SmallWorkers = 16
+	}
+	if scheduler.LargeWorkers <= 0 {
+		scheduler.LargeWorkers = 2
+	}
+	if scheduler.ChunkWorkers <= 0 {
+		scheduler.ChunkWorkers = 4
+	}
+
+	return scheduler
+}
+
+// Run runs the jobs, small and large ones in separate worker pools, and waits for them to finish.
+// Large jobs are started biggest first. After the first failure no new jobs are started,
+// and the first error is returned.
+func (s *Scheduler) Run(jobs []Job) error {
+	scheduler := s.withDefaults()
+
+	var small, large []Job
+	for _, job := range jobs {
+		if job.Size <= scheduler.SmallFileThreshold {
+			small = append(small, job)
+		} else {
+			large = append(large, job)
+		}
+	}
+	sort.SliceStable(large, func(i, j int) bool { return large[i].Size > large[j].Size })
+
+	var mu sync.Mutex
+	var firstErr error
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	var wg sync.WaitGroup
+	pool := func(jobs []Job, workers int) {
+		queue := make(chan Job)
+		for i := 0; i < workers && i < len(jobs); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range queue {
+					if failed() {
+						continue
+					}
+					if err := job.Run(); err != nil {
+						fail(err)
+					}
+				}
+			}()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(queue)
+			for _, job := range jobs {
+				if failed() {
+					return
+				}
+				queue <- job
+			}
+		}()
+	}
+	pool(small, scheduler.SmallWorkers)
+	pool(large, scheduler.LargeWorkers)
+	wg.Wait()
+
+	return firstErr
+}
+
+// copyFile copies a regular file, splitting it into concurrently copied chunks if it is
+// larger than the scheduler's chunk size. The copy is limited by limiter if it isn't nil.
+// size is the size the file had when it was listed, and only decides whether it is chunked.
+func (s *Scheduler) copyFile(src, dst string, size int64, limiter *rateLimiter) error {
+	src, dst = normalizePath(src), normalizePath(dst)
+
+	scheduler := s.withDefaults()
+	if scheduler.ChunkSize <= 0 || size <= scheduler.ChunkSize {
+		return copyFile(src, dst, limiter, nil)
+	}
+
+	return copyFile(src, dst, limiter, func(source, destination *os.File) (int64, error) {
+		// The file may have changed since size was taken
+		info, err := source.Stat()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get source stat: %w", err)
+		}
+		size := info.Size()
+
+		err = destination.Truncate(size)
+		if err != nil {
+			return 0, fmt.Errorf("failed to size destination file: %w", err)
+		}
+
+		var jobs []Job
+		for offset := int64(0); offset < size; offset += scheduler.ChunkSize {
+			length := min(scheduler.ChunkSize, size-offset)
+			section := io.NewSectionReader(source, offset, length)
+			writer := limiter.writer(context.Background(), io.NewOffsetWriter(destination, offset))
+			jobs = append(jobs, Job{Run: func() error {
+				_, err := io.Copy(writer, section)
+				return err
+			}})
+		}
+
+		chunks := Scheduler{SmallWorkers: scheduler.ChunkWorkers, SmallFileThreshold: 1}
+		err = chunks.Run(jobs)
+		if err != nil {
+			return 0, err
+		}
+
+		return size, nil
+	})
+}
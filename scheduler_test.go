@@ -0,0 +1,145 @@
+package fs_go
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScheduler(t *testing.T) {
+	// Expect every job to run, with concurrency bounded per size bucket
+	t.Run("bucketed concurrency", func(t *testing.T) {
+		var running, maxLarge, ran atomic.Int64
+		var jobs []Job
+		for i := 0; i < 20; i++ {
+			jobs = append(jobs, Job{Size: 10, Run: func() error {
+				ran.Add(1)
+				return nil
+			}})
+		}
+		for i := 0; i < 6; i++ {
+			jobs = append(jobs, Job{Size: 1000, Run: func() error {
+				current := running.Add(1)
+				defer running.Add(-1)
+				for {
+					seen := maxLarge.Load()
+					if current <= seen || maxLarge.CompareAndSwap(seen, current) {
+						break
+					}
+				}
+				ran.Add(1)
+				return nil
+			}})
+		}
+
+		scheduler := &Scheduler{SmallFileThreshold: 100, LargeWorkers: 2}
+		err := scheduler.Run(jobs)
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+
+		if ran.Load() != 26 {
+			t.Errorf("Expected 26 jobs to run, got %d", ran.Load())
+		}
+
+		if maxLarge.Load() > 2 {
+			t.Errorf("Expected at most 2 concurrent large jobs, got %d", maxLarge.Load())
+		}
+	})
+
+	// Expect the first error to be returned
+	t.Run("error", func(t *testing.T) {
+		failure := errors.New("failure")
+		var scheduler *Scheduler
+		err := scheduler.Run([]Job{
+			{Size: 1, Run: func() error { return failure }},
+		})
+		if !errors.Is(err, failure) {
+			t.Errorf("Expected the job error, got %v", err)
+		}
+	})
+
+	// Expect large files to be copied in parallel chunks
+	t.Run("chunked copy", func(t *testing.T) {
+		path := "scheduler_chunked"
+		defer os.RemoveAll(path)
+
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+		content := bytes.Repeat([]byte("0123456789"), 1000)
+		src := filepath.Join(path, "src.bin")
+		dst := filepath.Join(path, "dst.bin")
+		if err := os.WriteFile(src, content, 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		scheduler := &Scheduler{ChunkSize: 999}
+		err := scheduler.copyFile(src, dst, int64(len(content)), nil)
+		if err != nil {
+			t.Errorf("copyFile failed: %v", err)
+		}
+
+		copied, err := os.ReadFile(dst)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if !bytes.Equal(copied, content) {
+			t.Errorf("Expected copied content to match the source")
+		}
+	})
+	// Expect a chunked copy onto itself to fail without truncating the source
+	t.Run("chunked copy onto itself", func(t *testing.T) {
+		path := "scheduler_chunked_same"
+		defer os.RemoveAll(path)
+
+		content := bytes.Repeat([]byte("0123456789"), 1000)
+		writeTree(t, path, map[string]string{"src.bin": string(content)})
+		src := filepath.Join(path, "src.bin")
+
+		scheduler := &Scheduler{ChunkSize: 999}
+		err := scheduler.copyFile(src, src, int64(len(content)), nil)
+		if err == nil {
+			t.Errorf("Expected copying %s onto itself to fail", src)
+		}
+
+		kept, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if !bytes.Equal(kept, content) {
+			t.Errorf("Expected the source to be left intact, got %d bytes", len(kept))
+		}
+	})
+	// Expect a file that changed size since it was listed to be copied whole
+	t.Run("chunked copy of a changed file", func(t *testing.T) {
+		path := "scheduler_chunked_changed"
+		defer os.RemoveAll(path)
+
+		content := bytes.Repeat([]byte("0123456789"), 1000)
+		writeTree(t, path, map[string]string{"src.bin": string(content)})
+		src, dst := filepath.Join(path, "src.bin"), filepath.Join(path, "dst.bin")
+
+		// A limiter keeps the kernel from copying the file in one go
+		scheduler := &Scheduler{ChunkSize: 999}
+		for _, size := range []int64{5000, 20000} {
+			err := scheduler.copyFile(src, dst, size, newRateLimiter(1<<40))
+			if err != nil {
+				t.Errorf("copyFile failed: %v", err)
+			}
+
+			copied, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatalf("os.ReadFile failed: %v", err)
+			}
+			if !bytes.Equal(copied, content) {
+				t.Errorf("Expected the whole file to be copied when listed with size %d, got %d bytes", size, len(copied))
+			}
+		}
+	})
+}
@@ -0,0 +1,154 @@
+//go:build go1.23
+
+package fs_go
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+)
+
+// The functions in this file return iterators for range-over-func loops. Each value is
+// paired with an error; after a non-nil error, iteration stops. Breaking out of a loop
+// early releases the resources held by the iterator.
+
+// WalkSeq iterates over a directory tree like IterDirRec, yielding the path of every entry
+// selected by the options.
+//
+// Example:
+//
+//	for path, err := range WalkSeq("logs", ReadDirRecOptions{}) {
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    if strings.HasSuffix(path, ".core") {
+//	        fmt.Println("found a core dump:", path)
+//	        break
+//	    }
+//	}
+func WalkSeq(root string, opts ReadDirRecOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		it, err := IterDirRec(root, opts)
+		if err != nil {
+			yield("", err)
+			return
+		}
+
+		for it.Next() {
+			if !yield(it.Path(), nil) {
+				return
+			}
+		}
+
+		if err := it.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+// GlobSeq yields the paths matching pattern like GlobWithOptions, in lexical order, reading
+// directories only as far as the loop gets.
+//
+// Example:
+//
+//	for path, err := range GlobSeq("backups/*/manifest.json", GlobOptions{}) {
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    fmt.Println(path)
+//	}
+func GlobSeq(pattern string, opts GlobOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		pattern := normalizePath(pattern)
+
+		err := globWalk(pattern, opts, func(match string) bool {
+			return yield(match, nil)
+		})
+		if err != nil {
+			yield("", pathError("Glob", pattern, fmt.Errorf("Glob failed: %w", err)))
+		}
+	}
+}
+
+// LinesSeq yields the lines of a file without their line endings, reading no more of the
+// file than the loop consumes. Lines may be of any length.
+//
+// Example:
+//
+//	for line, err := range LinesSeq("access.log") {
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    if strings.Contains(line, " 500 ") {
+//	        fmt.Println(line)
+//	    }
+//	}
+func LinesSeq(path string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		path := normalizePath(path)
+
+		file, err := os.Open(path)
+		if err != nil {
+			yield("", pathError("LinesSeq", path, fmt.Errorf("LinesSeq failed to open file: %w", err)))
+			return
+		}
+		defer file.Close()
+
+		reader := bufio.NewReader(file)
+		for {
+			line, err := reader.ReadString('\n')
+			if line != "" {
+				line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+				if !yield(line, nil) {
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF {
+					yield("", pathError("LinesSeq", path, fmt.Errorf("LinesSeq failed to read line: %w", err)))
+				}
+				return
+			}
+		}
+	}
+}
+
+// ChunksSeq yields a file in chunks of chunkSize bytes like Chunks. The chunk slice is
+// reused between iterations, so the loop must copy it to keep it.
+//
+// Example:
+//
+//	hash := sha256.New()
+//	for chunk, err := range ChunksSeq("disk.img", 1<<20) {
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    hash.Write(chunk)
+//	}
+func ChunksSeq(path string, chunkSize int) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		chunks, err := Chunks(path, chunkSize)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer chunks.Close()
+
+		for chunks.Next() {
+			if !yield(chunks.Chunk(), nil) {
+				return
+			}
+		}
+
+		if err := chunks.Err(); err != nil {
+			yield(nil, fmt.Errorf("ChunksSeq failed to read file: %w", err))
+		}
+	}
+}
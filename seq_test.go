@@ -0,0 +1,103 @@
+//go:build go1.23
+
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWalkSeq(t *testing.T) {
+	path := "walk_seq"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"a.txt": "a", "b.txt": "b", "nested/c.txt": "c"})
+
+	// Expect paths in lexical order
+	var files []string
+	for file, err := range WalkSeq(path, ReadDirRecOptions{Paths: PathRelative}) {
+		if err != nil {
+			t.Fatalf("WalkSeq failed: %v", err)
+		}
+		files = append(files, file)
+	}
+	expected := []string{"a.txt", "b.txt", filepath.Join("nested", "c.txt")}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+
+	// Expect ignored paths not to be yielded
+	files = nil
+	for file, err := range WalkSeq(path, ReadDirRecOptions{Paths: PathRelative, Ignore: NewIgnorer("nested/", "a.*")}) {
+		if err != nil {
+			t.Fatalf("WalkSeq failed: %v", err)
+		}
+		files = append(files, file)
+	}
+	if !reflect.DeepEqual(files, []string{"b.txt"}) {
+		t.Errorf("Expected only b.txt, got %v", files)
+	}
+
+	// Expect a missing root to yield an error
+	for _, err := range WalkSeq("missing", ReadDirRecOptions{}) {
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	}
+}
+
+func TestGlobSeq(t *testing.T) {
+	path := "glob_seq"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{"a/x.log": "", "b/y.log": "", "c/z.txt": ""})
+
+	// Expect the loop to stop early
+	var matches []string
+	for match, err := range GlobSeq(filepath.Join(path, "*", "*.log"), GlobOptions{}) {
+		if err != nil {
+			t.Fatalf("GlobSeq failed: %v", err)
+		}
+		matches = append(matches, match)
+		break
+	}
+	if !reflect.DeepEqual(matches, []string{filepath.Join(path, "a", "x.log")}) {
+		t.Errorf("Expected only x.log, got %v", matches)
+	}
+}
+
+func TestLinesSeq(t *testing.T) {
+	path := "lines_seq.txt"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("one\r\ntwo\n\nthree"), 0644)
+
+	// Expect lines without their endings, including empty and unterminated ones
+	var lines []string
+	for line, err := range LinesSeq(path) {
+		if err != nil {
+			t.Fatalf("LinesSeq failed: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	if !reflect.DeepEqual(lines, []string{"one", "two", "", "three"}) {
+		t.Errorf("Expected 4 lines, got %q", lines)
+	}
+}
+
+func TestChunksSeq(t *testing.T) {
+	path := "chunks_seq.bin"
+	defer os.Remove(path)
+	os.WriteFile(path, []byte("0123456789"), 0644)
+
+	// Expect fixed-size chunks with a shorter last one
+	var chunks []string
+	for chunk, err := range ChunksSeq(path, 4) {
+		if err != nil {
+			t.Fatalf("ChunksSeq failed: %v", err)
+		}
+		chunks = append(chunks, string(chunk))
+	}
+	if !reflect.DeepEqual(chunks, []string{"0123", "4567", "89"}) {
+		t.Errorf("Expected 3 chunks, got %q", chunks)
+	}
+}
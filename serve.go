@@ -0,0 +1,227 @@
+package fs_go
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ServeOptions configures ServeDir and ServeFS.
+type ServeOptions struct {
+	// ShowHidden serves files and directories whose names start with a dot.
+	// By default they are left out of listings and answered with 404.
+	ShowHidden bool
+	// NoListing disables directory listings. Directories without an index file are
+	// answered with 404.
+	NoListing bool
+	// Index is the file served for a directory if it exists. Defaults to "index.html".
+	Index string
+}
+
+// ServeDir returns an http.Handler serving the files below root. See ServeFS.
+//
+// Example:
+//
+//	http.ListenAndServe(":8080", ServeDir("public", ServeOptions{}))
+func ServeDir(root string, opts ServeOptions) http.Handler {
+	return ServeFS(DirFS(root), opts)
+}
+
+// ServeFS returns an http.Handler serving the files of a file system, like an
+// OverlayFS or an ArchiveFS. Only GET and HEAD requests are allowed.
+//
+// Files are served with range request support and an ETag built from their modification
+// time and size, so clients can resume downloads and revalidate cached copies.
+// Directories are answered with their index file or an HTML listing.
+func ServeFS(fsys fs.FS, opts ServeOptions) http.Handler {
+	if opts.Index == "" {
+		opts.Index = "index.html"
+	}
+
+	return &fileServer{fsys: fsys, opts: opts}
+}
+
+// fileServer is the handler returned by ServeFS.
+type fileServer struct {
+	fsys fs.FS
+	opts ServeOptions
+}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+	if !s.opts.ShowHidden && isHiddenName(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	info, err := fs.Stat(s.fsys, name)
+	if err != nil {
+		serveError(w, r, err)
+		return
+	}
+
+	if !info.IsDir() {
+		s.serveFile(w, r, name, info)
+		return
+	}
+
+	if base := dirBase(r); !strings.HasSuffix(r.URL.Path, "/") && base != "" {
+		// Relative, so a path like "//host/dir" can't redirect to another host
+		target := url.PathEscape(base) + "/"
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		w.Header().Set("Location", target)
+		w.WriteHeader(http.StatusMovedPermanently)
+		return
+	}
+
+	index := path.Join(name, s.opts.Index)
+	if info, err := fs.Stat(s.fsys, index); err == nil && info.Mode().IsRegular() {
+		s.serveFile(w, r, index, info)
+		return
+	}
+	if s.opts.NoListing {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.serveListing(w, r, name)
+}
+
+// serveFile writes a file with ETag and range support.
+func (s *fileServer) serveFile(w http.ResponseWriter, r *http.Request, name string, info fs.FileInfo) {
+	file, err := s.fsys.Open(name)
+	if err != nil {
+		serveError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		// Range requests need seeking, so buffer files of file systems that can't seek
+		buffered, err := io.ReadAll(file)
+		if err != nil {
+			serveError(w, r, err)
+			return
+		}
+		content = bytes.NewReader(buffered)
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	http.ServeContent(w, r, info.Name(), info.ModTime(), content)
+}
+
+// listingTemplate renders a directory listing.
+var listingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<ul>
+{{- if ne .Path "/"}}
+<li><a href="../">../</a></li>
+{{- end}}
+{{- range .Entries}}
+<li><a href="./{{.Href}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// serveListing writes an HTML listing of a directory, directories first.
+func (s *fileServer) serveListing(w http.ResponseWriter, r *http.Request, name string) {
+	entries, err := fs.ReadDir(s.fsys, name)
+	if err != nil {
+		serveError(w, r, err)
+		return
+	}
+
+	// Names are escaped in links, so names with characters like "#" or "?" still work
+	type listingEntry struct{ Name, Href string }
+	var dirs, files []listingEntry
+	for _, entry := range entries {
+		if !s.opts.ShowHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if entry.IsDir() {
+			dirs = append(dirs, listingEntry{Name: entry.Name() + "/", Href: url.PathEscape(entry.Name()) + "/"})
+		} else {
+			files = append(files, listingEntry{Name: entry.Name(), Href: url.PathEscape(entry.Name())})
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Name < dirs[j].Name })
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	listingTemplate.Execute(w, struct {
+		Path    string
+		Entries []listingEntry
+	}{
+		Path:    r.URL.Path,
+		Entries: append(dirs, files...),
+	})
+}
+
+// dirBase returns the last element of the requested directory path, or "" for the root.
+// Behind http.StripPrefix the root directory has an empty path, so its name is taken
+// from the original request URI.
+func dirBase(r *http.Request) string {
+	requestPath := r.URL.Path
+	if requestPath == "" {
+		u, err := url.ParseRequestURI(r.RequestURI)
+		if err != nil {
+			return ""
+		}
+		requestPath = u.Path
+	}
+
+	base := path.Base(requestPath)
+	if base == "." || base == "/" {
+		return ""
+	}
+
+	return base
+}
+
+// isHiddenName reports whether any component of a slash-separated name starts with a dot.
+func isHiddenName(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serveError answers with the status matching a file system error.
+func serveError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		http.NotFound(w, r)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,159 @@
+package fs_go
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func serveRequest(handler http.Handler, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestServeDir(t *testing.T) {
+	path := "serve_dir_1"
+	defer os.RemoveAll(path)
+	writeTree(t, path, map[string]string{
+		"a.txt":           "0123456789",
+		".env":            "SECRET=1",
+		"docs/guide.md":   "guide",
+		"site/index.html": "<h1>home</h1>",
+	})
+	handler := ServeDir(path, ServeOptions{})
+
+	// Expect files to be served with an ETag and conditional requests to be honored
+	t.Run("file", func(t *testing.T) {
+		resp := serveRequest(handler, http.MethodGet, "/a.txt", nil)
+		if resp.Code != http.StatusOK || resp.Body.String() != "0123456789" {
+			t.Fatalf("Expected file content, got %d %q", resp.Code, resp.Body.String())
+		}
+
+		etag := resp.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("Expected an ETag")
+		}
+		resp = serveRequest(handler, http.MethodGet, "/a.txt", map[string]string{"If-None-Match": etag})
+		if resp.Code != http.StatusNotModified {
+			t.Errorf("Expected 304, got %d", resp.Code)
+		}
+	})
+
+	// Expect range requests to return partial content
+	t.Run("range", func(t *testing.T) {
+		resp := serveRequest(handler, http.MethodGet, "/a.txt", map[string]string{"Range": "bytes=2-4"})
+		if resp.Code != http.StatusPartialContent || resp.Body.String() != "234" {
+			t.Errorf("Expected partial content 234, got %d %q", resp.Code, resp.Body.String())
+		}
+	})
+
+	// Expect directories to be listed without hidden files, or served by their index
+	t.Run("directory", func(t *testing.T) {
+		resp := serveRequest(handler, http.MethodGet, "/", nil)
+		body := resp.Body.String()
+		if resp.Code != http.StatusOK || !strings.Contains(body, `href="./docs/"`) || !strings.Contains(body, `href="./a.txt"`) {
+			t.Errorf("Expected a listing, got %d %q", resp.Code, body)
+		}
+		if strings.Contains(body, ".env") {
+			t.Errorf("Expected hidden files to be left out of the listing")
+		}
+
+		resp = serveRequest(handler, http.MethodGet, "/site/", nil)
+		if resp.Body.String() != "<h1>home</h1>" {
+			t.Errorf("Expected the index file, got %q", resp.Body.String())
+		}
+
+		resp = serveRequest(handler, http.MethodGet, "/docs", nil)
+		if resp.Code != http.StatusMovedPermanently || resp.Header().Get("Location") != "docs/" {
+			t.Errorf("Expected a redirect to docs/, got %d %s", resp.Code, resp.Header().Get("Location"))
+		}
+
+		// A protocol-relative redirect would lead to another host
+		resp = serveRequest(handler, http.MethodGet, "//docs", nil)
+		if resp.Code != http.StatusMovedPermanently || resp.Header().Get("Location") != "docs/" {
+			t.Errorf("Expected a redirect to docs/, got %d %s", resp.Code, resp.Header().Get("Location"))
+		}
+	})
+
+	// Expect hidden files, missing files and other methods to be refused
+	t.Run("refused", func(t *testing.T) {
+		if resp := serveRequest(handler, http.MethodGet, "/.env", nil); resp.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for a hidden file, got %d", resp.Code)
+		}
+		if resp := serveRequest(handler, http.MethodGet, "/missing.txt", nil); resp.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for a missing file, got %d", resp.Code)
+		}
+		if resp := serveRequest(handler, http.MethodPost, "/a.txt", nil); resp.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected 405, got %d", resp.Code)
+		}
+
+		noListing := ServeDir(path, ServeOptions{NoListing: true, ShowHidden: true})
+		if resp := serveRequest(noListing, http.MethodGet, "/docs/", nil); resp.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 without listings, got %d", resp.Code)
+		}
+		if resp := serveRequest(noListing, http.MethodGet, "/.env", nil); resp.Code != http.StatusOK {
+			t.Errorf("Expected hidden files to be served with ShowHidden, got %d", resp.Code)
+		}
+	})
+}
+
+func TestServeFS(t *testing.T) {
+	// Expect file systems that can't seek to still support ranges
+	t.Run("no seek", func(t *testing.T) {
+		path := "serve_fs_1"
+		defer os.RemoveAll(path)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+
+		fsys := NewErrorFS(NewOverlayFS(fstest.MapFS{"a.txt": {Data: []byte("0123456789")}}, DirFS(path)))
+		handler := ServeFS(fsys.ShortReads(3), ServeOptions{})
+
+		resp := serveRequest(handler, http.MethodGet, "/a.txt", map[string]string{"Range": "bytes=5-"})
+		if resp.Code != http.StatusPartialContent || resp.Body.String() != "56789" {
+			t.Errorf("Expected partial content 56789, got %d %q", resp.Code, resp.Body.String())
+		}
+	})
+	// Expect the root behind http.StripPrefix to redirect to itself with a trailing slash
+	t.Run("strip prefix", func(t *testing.T) {
+		handler := http.StripPrefix("/static", ServeFS(fstest.MapFS{"a.txt": {Data: []byte("a")}}, ServeOptions{}))
+
+		resp := serveRequest(handler, http.MethodGet, "/static", nil)
+		if resp.Code != http.StatusMovedPermanently || resp.Header().Get("Location") != "static/" {
+			t.Errorf("Expected a redirect to static/, got %d %s", resp.Code, resp.Header().Get("Location"))
+		}
+
+		resp = serveRequest(handler, http.MethodGet, "/static/", nil)
+		if resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), `href="./a.txt"`) {
+			t.Errorf("Expected a listing, got %d %q", resp.Code, resp.Body.String())
+		}
+	})
+
+	// Expect names with special characters to be escaped in listing links
+	t.Run("escaped names", func(t *testing.T) {
+		handler := ServeFS(fstest.MapFS{
+			"notes #1?.txt": {Data: []byte("notes")},
+			"50% off/a.txt": {Data: []byte("a")},
+		}, ServeOptions{})
+
+		resp := serveRequest(handler, http.MethodGet, "/", nil)
+		body := resp.Body.String()
+		if !strings.Contains(body, `href="./notes%20%231%3F.txt"`) || !strings.Contains(body, `href="./50%25%20off/"`) {
+			t.Errorf("Expected escaped links, got %q", body)
+		}
+
+		resp = serveRequest(handler, http.MethodGet, "/notes%20%231%3F.txt", nil)
+		if resp.Code != http.StatusOK || resp.Body.String() != "notes" {
+			t.Errorf("Expected the escaped link to be served, got %d %q", resp.Code, resp.Body.String())
+		}
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/frodi-karlsson/fs_go/sftpfs
+
+go 1.22.1
+
+require (
+	github.com/frodi-karlsson/fs_go v0.0.0-20261015145408-2ab4ba615403
+	github.com/pkg/sftp v1.13.7
+	golang.org/x/crypto v0.33.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.4.0 // indirect
+	github.com/kr/fs v0.1.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
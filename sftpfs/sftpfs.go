@@ -0,0 +1,466 @@
+// Package sftpfs implements fs_go.FS on a remote host over SFTP.
+//
+// Deployment tools can use the same code for local and remote trees, and copy whole
+// directories in either direction with Upload and Download.
+//
+// Example:
+//
+//	remote, err := sftpfs.Dial("deploy.example.com:22", sshConfig, "/srv/www")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer remote.Close()
+//	err = remote.Upload("build", "releases/v2", sftpfs.TransferOptions{})
+package sftpfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	fs_go "github.com/frodi-karlsson/fs_go"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPFS is an fs_go.FS rooted at a directory on a remote host.
+type SFTPFS struct {
+	client *sftp.Client
+	root   string
+	conn   *ssh.Client
+}
+
+var _ fs_go.FS = (*SFTPFS)(nil)
+
+// New returns a file system rooted at root that uses an existing SFTP client.
+// Closing the file system doesn't close the client.
+func New(client *sftp.Client, root string) *SFTPFS {
+	return &SFTPFS{client: client, root: root}
+}
+
+// Dial connects to an SSH server and returns a file system rooted at root.
+// Close it to close the connection.
+func Dial(addr string, config *ssh.ClientConfig, root string) (*SFTPFS, error) {
+	conn, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, fmt.Errorf("Dial failed to connect to %s: %w", addr, err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Dial failed to start SFTP session: %w", err)
+	}
+
+	return &SFTPFS{client: client, root: root, conn: conn}, nil
+}
+
+// Close ends the SFTP session and the SSH connection if they were opened by Dial.
+func (s *SFTPFS) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.client.Close()
+	if closeErr := s.conn.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// join returns the remote path of a name, rejecting names that aren't valid io/fs paths.
+func (s *SFTPFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if s.root == "" {
+		return name, nil
+	}
+
+	return path.Join(s.root, name), nil
+}
+
+func (s *SFTPFS) Open(name string) (fs.File, error) {
+	remote, err := s.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.client.Stat(remote)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if info.IsDir() {
+		entries, err := s.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &dirFile{info: renamedInfo{info, path.Base(name)}, entries: entries}, nil
+	}
+
+	file, err := s.client.Open(remote)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return file, nil
+}
+
+func (s *SFTPFS) Stat(name string) (fs.FileInfo, error) {
+	remote, err := s.join("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.client.Stat(remote)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	return renamedInfo{info, path.Base(name)}, nil
+}
+
+func (s *SFTPFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	remote, err := s.join("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := s.client.ReadDir(remote)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, nil
+}
+
+func (s *SFTPFS) ReadFile(name string) ([]byte, error) {
+	file, err := s.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+func (s *SFTPFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	remote, err := s.join("writefile", name)
+	if err != nil {
+		return err
+	}
+
+	file, err := s.client.OpenFile(remote, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return &fs.PathError{Op: "writefile", Path: name, Err: err}
+	}
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = s.client.Chmod(remote, perm)
+	}
+	if err != nil {
+		return &fs.PathError{Op: "writefile", Path: name, Err: err}
+	}
+
+	return nil
+}
+
+func (s *SFTPFS) Create(name string) (io.WriteCloser, error) {
+	remote, err := s.join("create", name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.client.Create(remote)
+	if err != nil {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: err}
+	}
+
+	return file, nil
+}
+
+func (s *SFTPFS) MkdirAll(name string, perm fs.FileMode) error {
+	remote, err := s.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.MkdirAll(remote)
+	if err != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+
+	return nil
+}
+
+// Chmod changes the mode of a remote file or directory.
+func (s *SFTPFS) Chmod(name string, mode fs.FileMode) error {
+	remote, err := s.join("chmod", name)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.Chmod(remote, mode)
+	if err != nil {
+		return &fs.PathError{Op: "chmod", Path: name, Err: err}
+	}
+
+	return nil
+}
+
+func (s *SFTPFS) Remove(name string) error {
+	remote, err := s.join("remove", name)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.Remove(remote)
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: err}
+	}
+
+	return nil
+}
+
+// RemoveAll removes a file or directory and everything it contains.
+// It returns nil if name doesn't exist.
+func (s *SFTPFS) RemoveAll(name string) error {
+	remote, err := s.join("removeall", name)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.RemoveAll(remote)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return &fs.PathError{Op: "removeall", Path: name, Err: err}
+	}
+
+	return nil
+}
+
+// Rename moves a file or directory, replacing an existing file at newname if the server
+// supports the posix-rename extension.
+func (s *SFTPFS) Rename(oldname, newname string) error {
+	oldpath, err := s.join("rename", oldname)
+	if err != nil {
+		return err
+	}
+	newpath, err := s.join("rename", newname)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s.client.HasExtension("posix-rename@openssh.com"); ok {
+		err = s.client.PosixRename(oldpath, newpath)
+	} else {
+		err = s.client.Rename(oldpath, newpath)
+	}
+	if err != nil {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: err}
+	}
+
+	return nil
+}
+
+// TransferOptions configures Upload and Download.
+type TransferOptions struct {
+	// Progress receives the bytes transferred across all files, after every chunk written.
+	Progress fs_go.Progress
+}
+
+// Upload copies a local file or directory tree to name on the remote host, keeping
+// file permissions. Directories get the default permissions of the server.
+//
+// Example:
+//
+//	// bar implements fs_go.Progress
+//	err := remote.Upload("build", "releases/v2", sftpfs.TransferOptions{Progress: &bar{}})
+func (s *SFTPFS) Upload(local, name string, opts TransferOptions) error {
+	src := fs_go.DirFS(filepath.Dir(local))
+	err := transfer(src, filepath.Base(local), s, name, opts)
+	if err != nil {
+		return fmt.Errorf("Upload failed to upload %s: %w", local, err)
+	}
+
+	return nil
+}
+
+// Download copies a remote file or directory tree to a local path.
+func (s *SFTPFS) Download(name, local string, opts TransferOptions) error {
+	dst := fs_go.DirFS(filepath.Dir(local))
+	err := transfer(s, name, dst, filepath.Base(local), opts)
+	if err != nil {
+		return fmt.Errorf("Download failed to download %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// transfer copies srcName from src to dstName in dst, reporting progress.
+func transfer(src fs_go.FS, srcName string, dst fs_go.FS, dstName string, opts TransferOptions) (err error) {
+	var total int64
+	err = fs.WalkDir(src, srcName, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	progress := &progressWriter{progress: opts.Progress, total: total}
+	if opts.Progress != nil {
+		opts.Progress.Start(total)
+		defer func() { opts.Progress.Finish(err) }()
+	}
+
+	return fs.WalkDir(src, srcName, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := dstName
+		if name != srcName {
+			target = path.Join(dstName, strings.TrimPrefix(name, srcName+"/"))
+			if srcName == "." {
+				target = path.Join(dstName, name)
+			}
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return dst.MkdirAll(target, info.Mode().Perm())
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		err = copyFile(src, name, dst, target, progress)
+		if err != nil {
+			return err
+		}
+
+		if chmod, ok := dst.(interface{ Chmod(string, fs.FileMode) error }); ok {
+			return chmod.Chmod(target, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// copyFile streams a single file between file systems.
+func copyFile(src fs_go.FS, srcName string, dst fs_go.FS, dstName string, progress *progressWriter) error {
+	in, err := src.Open(srcName)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := dst.Create(dstName)
+	if err != nil {
+		return err
+	}
+
+	progress.Writer = out
+	_, err = io.Copy(progress, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// progressWriter counts the bytes written through it and reports them to progress, if
+// it isn't nil.
+type progressWriter struct {
+	io.Writer
+	progress fs_go.Progress
+	done     int64
+	total    int64
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.done += int64(n)
+	if w.progress != nil {
+		w.progress.Update(w.done, w.total)
+	}
+
+	return n, err
+}
+
+// renamedInfo reports a name that matches the requested one, since servers may return
+// the full path or nothing at all.
+type renamedInfo struct {
+	fs.FileInfo
+	name string
+}
+
+func (i renamedInfo) Name() string {
+	return i.name
+}
+
+// dirFile is an open remote directory.
+type dirFile struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: syscall.EISDIR}
+}
+
+func (d *dirFile) Close() error {
+	return nil
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,128 @@
+package sftpfs
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pkg/sftp"
+)
+
+// newTestFS connects to an in-memory SFTP server and returns a file system rooted at /.
+func newTestFS(t *testing.T) *SFTPFS {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	server := sftp.NewRequestServer(serverConn, sftp.InMemHandler())
+	go server.Serve()
+
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	if err != nil {
+		t.Fatalf("sftp.NewClientPipe failed: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	return New(client, "/")
+}
+
+func TestSFTPFS(t *testing.T) {
+	// Expect written files to form a valid file system
+	t.Run("read", func(t *testing.T) {
+		fsys := newTestFS(t)
+
+		if err := fsys.MkdirAll("dir/sub", 0755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		for name, content := range map[string]string{"a.txt": "a", "dir/b.txt": "b", "dir/sub/c.txt": "c"} {
+			if err := fsys.WriteFile(name, []byte(content), 0644); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+		}
+
+		err := fstest.TestFS(fsys, "a.txt", "dir/b.txt", "dir/sub/c.txt")
+		if err != nil {
+			t.Errorf("fstest.TestFS failed: %v", err)
+		}
+	})
+
+	// Expect files to be renamed and removed recursively
+	t.Run("rename and remove", func(t *testing.T) {
+		fsys := newTestFS(t)
+		fsys.MkdirAll("dir", 0755)
+		fsys.WriteFile("dir/a.txt", []byte("a"), 0644)
+
+		if err := fsys.Rename("dir/a.txt", "dir/b.txt"); err != nil {
+			t.Errorf("Rename failed: %v", err)
+		}
+		content, err := fsys.ReadFile("dir/b.txt")
+		if err != nil || string(content) != "a" {
+			t.Errorf("Expected renamed content, got %q (%v)", content, err)
+		}
+
+		if err := fsys.RemoveAll("dir"); err != nil {
+			t.Errorf("RemoveAll failed: %v", err)
+		}
+		if _, err := fsys.Stat("dir"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected dir to be removed, got %v", err)
+		}
+		if err := fsys.RemoveAll("dir"); err != nil {
+			t.Errorf("Expected RemoveAll of a missing path to succeed, got %v", err)
+		}
+	})
+
+	// Expect a directory tree to round-trip through Upload and Download with progress
+	t.Run("transfer", func(t *testing.T) {
+		fsys := newTestFS(t)
+		local := "sftp_transfer_1"
+		downloaded := "sftp_transfer_2"
+		defer os.RemoveAll(local)
+		defer os.RemoveAll(downloaded)
+
+		if err := os.MkdirAll(filepath.Join(local, "sub"), 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed: %v", err)
+		}
+		os.WriteFile(filepath.Join(local, "a.txt"), []byte("hello"), 0644)
+		os.WriteFile(filepath.Join(local, "sub", "b.txt"), []byte("world!"), 0644)
+
+		progress := &recordedProgress{}
+		err := fsys.Upload(local, "releases/v1", TransferOptions{Progress: progress})
+		if err != nil {
+			t.Fatalf("Upload failed: %v", err)
+		}
+		if progress.total != 11 || progress.done != 11 || !progress.finished || progress.err != nil {
+			t.Errorf("Expected progress to reach 11 of 11 bytes and finish, got %+v", progress)
+		}
+
+		content, err := fsys.ReadFile("releases/v1/sub/b.txt")
+		if err != nil || string(content) != "world!" {
+			t.Errorf("Expected uploaded content, got %q (%v)", content, err)
+		}
+
+		err = fsys.Download("releases/v1", downloaded, TransferOptions{})
+		if err != nil {
+			t.Fatalf("Download failed: %v", err)
+		}
+		content, err = os.ReadFile(filepath.Join(downloaded, "sub", "b.txt"))
+		if err != nil || string(content) != "world!" {
+			t.Errorf("Expected downloaded content, got %q (%v)", content, err)
+		}
+	})
+}
+
+// recordedProgress remembers the last reports of a transfer.
+type recordedProgress struct {
+	total, done int64
+	finished    bool
+	err         error
+}
+
+func (p *recordedProgress) Start(total int64)        { p.total = total }
+func (p *recordedProgress) Update(done, total int64) { p.done, p.total = done, total }
+func (p *recordedProgress) Finish(err error)         { p.finished, p.err = true, err }
@@ -0,0 +1,122 @@
+package fs_go
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Shred overwrites a file with random data passes times, syncing after every pass,
+// then truncates and removes it. Symlinks are removed without touching their target.
+// passes defaults to 1 if not positive.
+//
+// Shredding is best effort. It can't reach copies the system keeps elsewhere: SSDs
+// remap writes to fresh blocks through wear leveling, copy-on-write filesystems like
+// Btrfs, ZFS and APFS write new blocks instead of overwriting, journals may hold old
+// content, and snapshots and backups keep theirs. Where that matters, prefer full-disk
+// encryption, or WriteEncrypted and destroying the key.
+//
+// Example:
+//
+//	err := Shred("credentials.json", 3)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func Shred(path string, passes int) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("Shred", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err := shredFile(path, passes)
+	observe("Shred", 0, 0, err)
+	if err != nil {
+		return pathError("Shred", path, fmt.Errorf("Shred failed: %w", err))
+	}
+
+	return nil
+}
+
+// ShredDir shreds every file in a directory tree, then removes the tree.
+// The same caveats as for Shred apply.
+func ShredDir(root string, passes int) error {
+	root = normalizePath(root)
+
+	if skip, err := writeGuard("ShredDir", root, ""); skip {
+		return err
+	}
+	defer invalidateStats(root)
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			return shredFile(path, passes)
+		}
+		return nil
+	})
+	observe("ShredDir", 0, 0, err)
+	if err != nil {
+		return pathError("ShredDir", root, fmt.Errorf("ShredDir failed to shred file: %w", err))
+	}
+
+	err = os.RemoveAll(root)
+	if err != nil {
+		return pathError("ShredDir", root, fmt.Errorf("ShredDir failed to remove directory: %w", err))
+	}
+
+	return nil
+}
+
+// shredFile overwrites, truncates and removes a single file.
+func shredFile(path string, passes int) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return ErrIsDirectory
+	}
+	if !info.Mode().IsRegular() {
+		return os.Remove(path)
+	}
+	if passes <= 0 {
+		passes = 1
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for i := 0; i < passes; i++ {
+		_, err = file.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		_, err = io.CopyN(file, rand.Reader, info.Size())
+		if err != nil {
+			return fmt.Errorf("failed to overwrite: %w", err)
+		}
+		err = file.Sync()
+		if err != nil {
+			return fmt.Errorf("failed to sync: %w", err)
+		}
+	}
+
+	err = file.Truncate(0)
+	if err != nil {
+		return err
+	}
+	file.Close()
+
+	return os.Remove(path)
+}
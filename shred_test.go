@@ -0,0 +1,91 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestShred(t *testing.T) {
+	// Expect the file to be removed
+	t.Run("file", func(t *testing.T) {
+		path := "shred_1"
+		defer os.Remove(path)
+		os.WriteFile(path, []byte("password"), 0600)
+
+		err := Shred(path, 3)
+		if err != nil {
+			t.Fatalf("Shred failed: %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected file to be removed, got %v", err)
+		}
+	})
+
+	// Expect a directory to be refused
+	t.Run("directory", func(t *testing.T) {
+		path := "shred_2"
+		defer os.RemoveAll(path)
+		os.Mkdir(path, 0755)
+
+		err := Shred(path, 1)
+		if !errors.Is(err, ErrIsDirectory) {
+			t.Errorf("Expected ErrIsDirectory, got %v", err)
+		}
+	})
+
+	// Expect a symlink to be removed without touching its target
+	t.Run("symlink", func(t *testing.T) {
+		target := "shred_3_target"
+		link := "shred_3_link"
+		defer os.Remove(target)
+		defer os.Remove(link)
+		os.WriteFile(target, []byte("keep"), 0644)
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+
+		err := Shred(link, 1)
+		if err != nil {
+			t.Fatalf("Shred failed: %v", err)
+		}
+		if content, _ := os.ReadFile(target); string(content) != "keep" {
+			t.Errorf("Expected target to be untouched, got %q", content)
+		}
+		if _, err := os.Lstat(link); !os.IsNotExist(err) {
+			t.Errorf("Expected link to be removed, got %v", err)
+		}
+	})
+
+	// Expect a missing file to fail
+	t.Run("missing", func(t *testing.T) {
+		err := Shred("shred_4", 1)
+		if !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected ErrNotExist, got %v", err)
+		}
+	})
+}
+
+func TestShredDir(t *testing.T) {
+	// Expect the whole tree to be removed
+	t.Run("tree", func(t *testing.T) {
+		root := "shred_dir_1"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{
+			"a.txt":     "secret",
+			"sub/b.txt": "secret",
+		})
+
+		err := ShredDir(root, 1)
+		if err != nil {
+			t.Fatalf("ShredDir failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "sub")); !os.IsNotExist(err) {
+			t.Errorf("Expected tree to be removed, got %v", err)
+		}
+		if _, err := os.Stat(root); !os.IsNotExist(err) {
+			t.Errorf("Expected root to be removed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Snapshot is a copy of a directory saved by SnapshotDir.
+type Snapshot struct {
+	Path   string    // Directory holding the copy
+	Source string    // Directory the copy was taken from
+	Time   time.Time // When the snapshot was taken
+}
+
+// SnapshotDir copies a directory tree, with file permissions and times, into a new
+// temporary directory, so it can be put back with RestoreDir if a risky operation on it
+// goes wrong. Call Remove on the snapshot once it is no longer needed.
+//
+// Example:
+//
+//	snapshot, err := SnapshotDir("data")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer snapshot.Remove()
+//	err = migrate("data")
+//	if err != nil {
+//	    err = RestoreDir(snapshot, "data")
+//	}
+func SnapshotDir(dir string) (Snapshot, error) {
+	dir = normalizePath(dir)
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return Snapshot{}, pathError("SnapshotDir", dir, fmt.Errorf("SnapshotDir failed to get directory stat: %w", err))
+	}
+	if !info.IsDir() {
+		return Snapshot{}, pathError("SnapshotDir", dir, fmt.Errorf("SnapshotDir failed: %s: %w", dir, ErrNotDirectory))
+	}
+
+	path, err := os.MkdirTemp("", "fs_go-snapshot-*")
+	if err != nil {
+		return Snapshot{}, pathError("SnapshotDir", dir, fmt.Errorf("SnapshotDir failed to create snapshot directory: %w", err))
+	}
+
+	err = copySnapshot(dir, path, info.Mode())
+	if err != nil {
+		os.RemoveAll(path)
+		return Snapshot{}, pathError("SnapshotDir", dir, fmt.Errorf("SnapshotDir failed to copy directory: %w", err))
+	}
+
+	return Snapshot{Path: path, Source: dir, Time: time.Now()}, nil
+}
+
+// RestoreDir replaces dir with the content of a snapshot. The snapshot is copied next to
+// dir first and then swapped in with renames, so dir is never left half restored, and
+// the snapshot itself is kept for further restores. dir doesn't need to exist.
+func RestoreDir(snapshot Snapshot, dir string) error {
+	dir = normalizePath(dir)
+
+	if skip, err := writeGuard("RestoreDir", snapshot.Path, dir); skip {
+		return err
+	}
+	defer invalidateStats(snapshot.Path, dir)
+
+	info, err := os.Stat(snapshot.Path)
+	if err != nil {
+		return pathError("RestoreDir", dir, fmt.Errorf("RestoreDir failed to get snapshot stat: %w", err))
+	}
+
+	err = EnsureDir(filepath.Dir(dir))
+	if err != nil {
+		return pathError("RestoreDir", dir, fmt.Errorf("RestoreDir failed to ensure parent directory: %w", err))
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+".restore*")
+	if err != nil {
+		return pathError("RestoreDir", dir, fmt.Errorf("RestoreDir failed to create staging directory: %w", err))
+	}
+	defer os.RemoveAll(staging)
+
+	err = copySnapshot(snapshot.Path, staging, info.Mode())
+	if err != nil {
+		return pathError("RestoreDir", dir, fmt.Errorf("RestoreDir failed to copy snapshot: %w", err))
+	}
+
+	aside, step, err := moveAside(dir)
+	if err != nil {
+		return pathError("RestoreDir", dir, fmt.Errorf("RestoreDir failed to move directory aside: %w", err))
+	}
+
+	err = os.Rename(staging, dir)
+	if err != nil {
+		undoTxSteps([]txStep{step})
+		return pathError("RestoreDir", dir, fmt.Errorf("RestoreDir failed to swap in snapshot: %w", err))
+	}
+
+	if aside != "" {
+		os.RemoveAll(step.cleanup)
+	}
+
+	return nil
+}
+
+// Remove deletes the snapshot.
+func (s Snapshot) Remove() error {
+	err := os.RemoveAll(s.Path)
+	if err != nil {
+		return pathError("RemoveSnapshot", s.Path, fmt.Errorf("RemoveSnapshot failed to remove snapshot: %w", err))
+	}
+
+	return nil
+}
+
+// copySnapshot copies the tree at src into the existing directory dst, giving dst the mode
+// and times of src.
+func copySnapshot(src, dst string, mode os.FileMode) error {
+	err := CopyDirWithOptions(src, dst, CopyDirOptions{PreserveTimes: true})
+	if err != nil {
+		return err
+	}
+
+	return os.Chmod(dst, mode.Perm())
+}
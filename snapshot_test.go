@@ -0,0 +1,93 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotDir(t *testing.T) {
+	// Expect a restore to undo changes made after the snapshot
+	t.Run("restore", func(t *testing.T) {
+		path := "snapshot_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"a.txt":        "a",
+			"nested/b.txt": "b",
+		})
+
+		snapshot, err := SnapshotDir(path)
+		if err != nil {
+			t.Fatalf("SnapshotDir failed: %v", err)
+		}
+		defer snapshot.Remove()
+
+		os.WriteFile(filepath.Join(path, "a.txt"), []byte("changed"), 0644)
+		os.RemoveAll(filepath.Join(path, "nested"))
+		os.WriteFile(filepath.Join(path, "extra.txt"), []byte("extra"), 0644)
+
+		err = RestoreDir(snapshot, path)
+		if err != nil {
+			t.Fatalf("RestoreDir failed: %v", err)
+		}
+
+		diff, err := DiffDirs(snapshot.Path, path)
+		if err != nil {
+			t.Fatalf("DiffDirs failed: %v", err)
+		}
+		if !diff.Empty() {
+			t.Errorf("Expected restored tree to equal the snapshot, got %+v", diff)
+		}
+
+		entries, _ := os.ReadDir(".")
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), "."+path+".") {
+				t.Errorf("Expected no leftover staging directories, got %s", entry.Name())
+			}
+		}
+	})
+
+	// Expect a snapshot to be restorable to a directory that no longer exists
+	t.Run("restore removed", func(t *testing.T) {
+		path := "snapshot_2"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{"a.txt": "a"})
+
+		snapshot, err := SnapshotDir(path)
+		if err != nil {
+			t.Fatalf("SnapshotDir failed: %v", err)
+		}
+		os.RemoveAll(path)
+
+		err = RestoreDir(snapshot, path)
+		if err != nil {
+			t.Fatalf("RestoreDir failed: %v", err)
+		}
+		if content, _ := os.ReadFile(filepath.Join(path, "a.txt")); string(content) != "a" {
+			t.Errorf("Expected a, got %q", content)
+		}
+
+		err = snapshot.Remove()
+		if err != nil {
+			t.Fatalf("Remove failed: %v", err)
+		}
+		if _, err := os.Stat(snapshot.Path); !os.IsNotExist(err) {
+			t.Errorf("Expected snapshot to be removed, got %v", err)
+		}
+	})
+
+	// Expect snapshots of files to fail
+	t.Run("not a directory", func(t *testing.T) {
+		path := "snapshot_3.txt"
+		defer os.RemoveAll(path)
+		os.WriteFile(path, []byte("a"), 0644)
+
+		_, err := SnapshotDir(path)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// ErrSocketInUse means a Unix domain socket has a listener that accepts connections.
+var ErrSocketInUse = errors.New("socket is in use")
+
+// socketDialTimeout limits how long RemoveStaleSocket waits for a listener to answer.
+const socketDialTimeout = time.Second
+
+// SocketPath joins a directory and the name of a Unix domain socket, and fails if the path
+// is too long to bind to. Socket addresses are limited to 108 bytes on Linux and Windows,
+// and to 104 on BSDs and macOS, so deep directories like those of tests can be too long.
+//
+// Example:
+//
+//	path, err := SocketPath(runtimeDir, "app.sock")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func SocketPath(dir, name string) (string, error) {
+	path := filepath.Join(normalizePath(dir), name)
+
+	limit := 108
+	switch runtime.GOOS {
+	case "darwin", "ios", "freebsd", "netbsd", "openbsd", "dragonfly":
+		limit = 104
+	}
+	// The address must also fit a terminating NUL byte
+	if len(path) >= limit {
+		return "", pathError("SocketPath", path, fmt.Errorf("SocketPath failed: %s is %d bytes, over the socket address limit of %d", path, len(path), limit-1))
+	}
+
+	return path, nil
+}
+
+// EnsureSocketDir creates a directory for Unix domain sockets with mode 0700, so only the
+// current user can connect to the sockets in it, and any missing parents. An existing
+// directory is kept as is, but it fails if other users could replace its sockets, which
+// is when it is writable by them without the sticky bit, like /tmp has.
+//
+// Example:
+//
+//	err := EnsureSocketDir("/run/user/1000/app")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func EnsureSocketDir(dir string) error {
+	dir = normalizePath(dir)
+
+	err := EnsureDirWithMode(dir, 0700)
+	if err != nil {
+		return pathError("EnsureSocketDir", dir, fmt.Errorf("EnsureSocketDir failed to ensure directory: %w", err))
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		if IsDryRun() && os.IsNotExist(err) {
+			return nil
+		}
+		return pathError("EnsureSocketDir", dir, fmt.Errorf("EnsureSocketDir failed to get directory stat: %w", err))
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0022 != 0 && info.Mode()&os.ModeSticky == 0 {
+		return pathError("EnsureSocketDir", dir, fmt.Errorf("EnsureSocketDir failed: %s is writable by other users (mode %v)", dir, info.Mode().Perm()))
+	}
+
+	return nil
+}
+
+// RemoveStaleSocket removes a Unix domain socket that was left behind by a process that
+// exited without cleaning up, which makes listening on it fail with "address already in
+// use". The socket is only removed if connecting to it is refused. If a listener accepts
+// the connection, it fails with ErrSocketInUse. It returns whether a socket was removed,
+// and a missing socket is not an error.
+//
+// Example:
+//
+//	_, err := RemoveStaleSocket(path)
+//	if err != nil {
+//	    fmt.Println(err) // Another instance is running
+//	    return
+//	}
+//	listener, err := net.Listen("unix", path)
+func RemoveStaleSocket(path string) (bool, error) {
+	path = normalizePath(path)
+
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, pathError("RemoveStaleSocket", path, fmt.Errorf("RemoveStaleSocket failed to get file stat: %w", err))
+	}
+	if KindOf(info.Mode()) != KindSocket {
+		return false, pathError("RemoveStaleSocket", path, fmt.Errorf("RemoveStaleSocket failed: %s is a %s, not a socket", path, KindOf(info.Mode())))
+	}
+
+	conn, err := net.DialTimeout("unix", path, socketDialTimeout)
+	if err == nil {
+		conn.Close()
+		return false, pathError("RemoveStaleSocket", path, fmt.Errorf("RemoveStaleSocket failed: %w", ErrSocketInUse))
+	}
+	if !connRefused(err) {
+		return false, pathError("RemoveStaleSocket", path, fmt.Errorf("RemoveStaleSocket failed to check for a listener: %w", err))
+	}
+
+	if skip, err := writeGuard("RemoveStaleSocket", path, ""); skip {
+		return err == nil, err
+	}
+	defer invalidateStats(path)
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, pathError("RemoveStaleSocket", path, fmt.Errorf("RemoveStaleSocket failed to remove socket: %w", err))
+	}
+
+	return true, nil
+}
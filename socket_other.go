@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package fs_go
+
+// connRefused reports whether dialing failed because nothing listens on the address.
+// Unix domain sockets aren't supported on this platform, so no socket is ever stale.
+func connRefused(err error) bool {
+	return false
+}
@@ -0,0 +1,91 @@
+package fs_go
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSocketPath(t *testing.T) {
+	// Expect short paths to be joined and long ones to fail
+	path, err := SocketPath("run", "app.sock")
+	if err != nil || path != filepath.Join("run", "app.sock") {
+		t.Errorf("Expected run/app.sock, got %q (%v)", path, err)
+	}
+
+	_, err = SocketPath(strings.Repeat("d", 120), "app.sock")
+	if err == nil {
+		t.Errorf("Expected a path over the limit to fail")
+	}
+}
+
+func TestEnsureSocketDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directory permissions are not checked on Windows")
+	}
+
+	// Expect a private directory to be created
+	t.Run("create", func(t *testing.T) {
+		dir := "socket_dir_1"
+		defer os.RemoveAll(dir)
+
+		if err := EnsureSocketDir(dir); err != nil {
+			t.Fatalf("EnsureSocketDir failed: %v", err)
+		}
+
+		info, _ := os.Stat(dir)
+		if info.Mode().Perm() != 0700 {
+			t.Errorf("Expected mode 0700, got %#o", info.Mode().Perm())
+		}
+	})
+
+	// Expect a directory other users can write to to be refused
+	t.Run("writable by others", func(t *testing.T) {
+		dir := "socket_dir_2"
+		defer os.RemoveAll(dir)
+		os.Mkdir(dir, 0755)
+		os.Chmod(dir, 0777)
+
+		if err := EnsureSocketDir(dir); err == nil {
+			t.Errorf("Expected EnsureSocketDir to fail")
+		}
+	})
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	// Expect a socket with a listener to be kept, and one without to be removed
+	path := "stale.sock"
+	defer os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("net.Listen failed: %v", err)
+	}
+	// Keep the socket file after closing, like a process that crashed
+	if unix, ok := listener.(interface{ SetUnlinkOnClose(bool) }); ok {
+		unix.SetUnlinkOnClose(false)
+	}
+
+	removed, err := RemoveStaleSocket(path)
+	if removed || !errors.Is(err, ErrSocketInUse) {
+		t.Errorf("Expected ErrSocketInUse, got %v (%v)", removed, err)
+	}
+
+	listener.Close()
+	removed, err = RemoveStaleSocket(path)
+	if !removed || err != nil {
+		t.Fatalf("Expected the socket to be removed, got %v (%v)", removed, err)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the socket to be gone, got %v", err)
+	}
+
+	// Expect a missing socket not to be an error
+	removed, err = RemoveStaleSocket(path)
+	if removed || err != nil {
+		t.Errorf("Expected nothing to be removed, got %v (%v)", removed, err)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"errors"
+	"syscall"
+)
+
+// connRefused reports whether dialing failed because nothing listens on the address.
+func connRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
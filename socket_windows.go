@@ -0,0 +1,12 @@
+package fs_go
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// connRefused reports whether dialing failed because nothing listens on the address.
+func connRefused(err error) bool {
+	return errors.Is(err, windows.WSAECONNREFUSED)
+}
@@ -0,0 +1,105 @@
+package fs_go
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Region is a contiguous byte range of a file.
+type Region struct {
+	Offset int64
+	Length int64
+}
+
+// SparseRegions returns the regions of a file that contain data, in order.
+// The gaps between them are holes, which read as zeros but take up no disk space.
+//
+// Holes are found with SEEK_DATA and SEEK_HOLE on Linux, macOS and FreeBSD.
+// Elsewhere, or on file systems without hole support, the whole file is a single region.
+func SparseRegions(path string) ([]Region, error) {
+	path = normalizePath(path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("SparseRegions failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("SparseRegions failed to get file stat: %w", err)
+	}
+
+	regions, err := dataRegions(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("SparseRegions failed to find data regions: %w", err)
+	}
+
+	return regions, nil
+}
+
+// IsSparse reports whether a file has holes.
+func IsSparse(path string) (bool, error) {
+	regions, err := SparseRegions(path)
+	if err != nil {
+		return false, fmt.Errorf("IsSparse failed to find data regions: %w", err)
+	}
+
+	size, err := GetSize(path)
+	if err != nil {
+		return false, fmt.Errorf("IsSparse failed to get file size: %w", err)
+	}
+
+	return regionsLength(regions) < size, nil
+}
+
+// regionsLength returns the total length of the regions.
+func regionsLength(regions []Region) int64 {
+	var length int64
+	for _, region := range regions {
+		length += region.Length
+	}
+
+	return length
+}
+
+// copySparse copies only the data regions of source to destination and sizes the
+// destination to match, so holes stay holes instead of being written out as zeros.
+// It reports the number of bytes copied and whether the source had holes, and copies
+// nothing if it didn't.
+func copySparse(source, destination *os.File) (int64, bool, error) {
+	info, err := source.Stat()
+	if err != nil {
+		return 0, false, err
+	}
+
+	regions, err := dataRegions(source, info.Size())
+	if err != nil {
+		return 0, false, err
+	}
+	if regionsLength(regions) == info.Size() {
+		// Finding the regions moved the offset, so rewind for a regular copy
+		_, err = source.Seek(0, io.SeekStart)
+		return 0, false, err
+	}
+
+	var copied int64
+	for _, region := range regions {
+		_, err = source.Seek(region.Offset, io.SeekStart)
+		if err != nil {
+			return copied, true, err
+		}
+		_, err = destination.Seek(region.Offset, io.SeekStart)
+		if err != nil {
+			return copied, true, err
+		}
+		n, err := io.CopyN(destination, source, region.Length)
+		copied += n
+		if err != nil {
+			return copied, true, err
+		}
+	}
+
+	return copied, true, destination.Truncate(info.Size())
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd
+
+package fs_go
+
+import "os"
+
+func dataRegions(file *os.File, size int64) ([]Region, error) {
+	if size == 0 {
+		return nil, nil
+	}
+
+	return []Region{{Offset: 0, Length: size}}, nil
+}
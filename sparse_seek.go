@@ -0,0 +1,38 @@
+//go:build linux || darwin || freebsd
+
+package fs_go
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func dataRegions(file *os.File, size int64) ([]Region, error) {
+	var regions []Region
+	for offset := int64(0); offset < size; {
+		data, err := file.Seek(offset, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			// No data after offset, the rest of the file is a hole
+			break
+		}
+		if errors.Is(err, unix.EINVAL) && offset == 0 {
+			// The file system doesn't support finding holes
+			return []Region{{Offset: 0, Length: size}}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		hole, err := file.Seek(data, unix.SEEK_HOLE)
+		if err != nil {
+			return nil, err
+		}
+
+		regions = append(regions, Region{Offset: data, Length: hole - data})
+		offset = hole
+	}
+
+	return regions, nil
+}
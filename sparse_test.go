@@ -0,0 +1,129 @@
+package fs_go
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// writeSparse writes data at the start and end of a file with a large hole between.
+// It reports false if the file system doesn't create holes.
+func writeSparse(t *testing.T, path string, size int64) bool {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("os.Create failed: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteAt([]byte("start"), 0); err != nil {
+		t.Fatalf("WriteAt failed: %v", err)
+	}
+	if _, err := file.WriteAt([]byte("end"), size-3); err != nil {
+		t.Fatalf("WriteAt failed: %v", err)
+	}
+
+	regions, err := dataRegions(file, size)
+	if err != nil {
+		t.Fatalf("dataRegions failed: %v", err)
+	}
+
+	return regionsLength(regions) < size
+}
+
+func TestSparseRegions(t *testing.T) {
+	// Expect a dense file to be a single region
+	t.Run("dense file", func(t *testing.T) {
+		path := "sparse_regions_dense.bin"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		regions, err := SparseRegions(path)
+		if err != nil {
+			t.Errorf("SparseRegions failed: %v", err)
+		}
+
+		if len(regions) != 1 || regions[0] != (Region{Offset: 0, Length: 12}) {
+			t.Errorf("Expected a single region, got %+v", regions)
+		}
+
+		sparse, err := IsSparse(path)
+		if err != nil {
+			t.Errorf("IsSparse failed: %v", err)
+		}
+
+		if sparse {
+			t.Errorf("Expected file to not be sparse")
+		}
+	})
+
+	// Expect holes to be skipped
+	t.Run("sparse file", func(t *testing.T) {
+		path := "sparse_regions_sparse.bin"
+		defer os.Remove(path)
+
+		if !writeSparse(t, path, 64<<20) {
+			t.Skip("file system doesn't support holes")
+		}
+
+		regions, err := SparseRegions(path)
+		if err != nil {
+			t.Errorf("SparseRegions failed: %v", err)
+		}
+
+		if len(regions) != 2 || regions[0].Offset != 0 || regions[1].Offset+regions[1].Length != 64<<20 {
+			t.Errorf("Expected a region at each end, got %+v", regions)
+		}
+
+		sparse, err := IsSparse(path)
+		if err != nil {
+			t.Errorf("IsSparse failed: %v", err)
+		}
+
+		if !sparse {
+			t.Errorf("Expected file to be sparse")
+		}
+	})
+}
+
+func TestCopyFileSparse(t *testing.T) {
+	// Expect CopyFile to keep holes and content
+	t.Run("copy sparse file", func(t *testing.T) {
+		src := "copy_file_sparse_src.bin"
+		dst := "copy_file_sparse_dst.bin"
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		size := int64(64 << 20)
+		if !writeSparse(t, src, size) {
+			t.Skip("file system doesn't support holes")
+		}
+
+		err := CopyFile(src, dst)
+		if err != nil {
+			t.Errorf("CopyFile failed: %v", err)
+		}
+
+		sparse, err := IsSparse(dst)
+		if err != nil {
+			t.Errorf("IsSparse failed: %v", err)
+		}
+
+		if !sparse {
+			t.Errorf("Expected copy to be sparse")
+		}
+
+		content, err := os.ReadFile(dst)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if int64(len(content)) != size || !bytes.HasPrefix(content, []byte("start")) || !bytes.HasSuffix(content, []byte("end")) {
+			t.Errorf("Expected copy to have the same size and content")
+		}
+	})
+}
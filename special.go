@@ -0,0 +1,143 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileKind is the kind of a file system entry.
+type FileKind int
+
+const (
+	KindRegular   FileKind = iota // Regular file
+	KindDir                       // Directory
+	KindSymlink                   // Symbolic link
+	KindFifo                      // Named pipe
+	KindSocket                    // Unix domain socket
+	KindDevice                    // Block or character device
+	KindIrregular                 // Anything else, like a Windows reparse point
+)
+
+func (k FileKind) String() string {
+	switch k {
+	case KindRegular:
+		return "regular"
+	case KindDir:
+		return "directory"
+	case KindSymlink:
+		return "symlink"
+	case KindFifo:
+		return "fifo"
+	case KindSocket:
+		return "socket"
+	case KindDevice:
+		return "device"
+	case KindIrregular:
+		return "irregular"
+	default:
+		return fmt.Sprintf("FileKind(%d)", int(k))
+	}
+}
+
+// Special reports whether the kind is a named pipe, socket or device. Reading from these
+// can block forever or never end, so they shouldn't be treated as regular files.
+func (k FileKind) Special() bool {
+	return k == KindFifo || k == KindSocket || k == KindDevice
+}
+
+// KindOf returns the kind of a file with mode.
+func KindOf(mode fs.FileMode) FileKind {
+	switch {
+	case mode.IsRegular():
+		return KindRegular
+	case mode.IsDir():
+		return KindDir
+	case mode&fs.ModeSymlink != 0:
+		return KindSymlink
+	case mode&fs.ModeNamedPipe != 0:
+		return KindFifo
+	case mode&fs.ModeSocket != 0:
+		return KindSocket
+	case mode&fs.ModeDevice != 0:
+		return KindDevice
+	default:
+		return KindIrregular
+	}
+}
+
+// IsFifo reports whether path is a named pipe, following symlinks. A missing file is not
+// a named pipe and not an error.
+func IsFifo(path string) (bool, error) {
+	return isKind("IsFifo", path, KindFifo)
+}
+
+// IsSocket reports whether path is a Unix domain socket, following symlinks. A missing file
+// is not a socket and not an error.
+func IsSocket(path string) (bool, error) {
+	return isKind("IsSocket", path, KindSocket)
+}
+
+// IsDevice reports whether path is a block or character device, following symlinks. A
+// missing file is not a device and not an error.
+func IsDevice(path string) (bool, error) {
+	return isKind("IsDevice", path, KindDevice)
+}
+
+// isKind reports whether path is of kind.
+func isKind(op, path string, kind FileKind) (bool, error) {
+	info, err := os.Stat(normalizePath(path))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s failed to get file stat: %w", op, err)
+	}
+
+	return KindOf(info.Mode()) == kind, nil
+}
+
+// EnsureFifo creates a named pipe with mode if it doesn't exist, and any missing parent
+// directories. It fails if something other than a named pipe exists at path. Named pipes
+// are only supported on Unix.
+//
+// Example:
+//
+//	err := EnsureFifo("/run/app/control", 0600)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	control, err := OpenRead("/run/app/control") // Blocks until a writer opens it
+func EnsureFifo(path string, mode os.FileMode) error {
+	path = normalizePath(path)
+
+	info, err := os.Stat(path)
+	if err == nil {
+		if KindOf(info.Mode()) != KindFifo {
+			return pathError("EnsureFifo", path, fmt.Errorf("EnsureFifo failed: %s is a %s: %w", path, KindOf(info.Mode()), ErrExist))
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return pathError("EnsureFifo", path, fmt.Errorf("EnsureFifo failed to check file existence: %w", err))
+	}
+
+	err = EnsureDirWithMode(filepath.Dir(path), 0755)
+	if err != nil {
+		return pathError("EnsureFifo", path, fmt.Errorf("EnsureFifo failed to ensure directory: %w", err))
+	}
+
+	if skip, err := writeGuard("EnsureFifo", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err = mkfifo(path, mode)
+	if err != nil && !os.IsExist(err) {
+		return pathError("EnsureFifo", path, fmt.Errorf("EnsureFifo failed to create named pipe: %w", err))
+	}
+
+	return nil
+}
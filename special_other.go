@@ -0,0 +1,12 @@
+//go:build !unix
+
+package fs_go
+
+import (
+	"errors"
+	"os"
+)
+
+func mkfifo(path string, mode os.FileMode) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,134 @@
+package fs_go
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	// Expect each file type to be classified
+	cases := map[fs.FileMode]FileKind{
+		0644:                              KindRegular,
+		fs.ModeDir | 0755:                 KindDir,
+		fs.ModeSymlink | 0777:             KindSymlink,
+		fs.ModeNamedPipe | 0644:           KindFifo,
+		fs.ModeSocket | 0755:              KindSocket,
+		fs.ModeDevice | 0660:              KindDevice,
+		fs.ModeDevice | fs.ModeCharDevice: KindDevice,
+		fs.ModeIrregular:                  KindIrregular,
+	}
+	for mode, expected := range cases {
+		if kind := KindOf(mode); kind != expected {
+			t.Errorf("Expected %v for %v, got %v", expected, mode, kind)
+		}
+	}
+}
+
+func TestEnsureFifo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes are only supported on Unix")
+	}
+
+	// Expect a named pipe to be created, and to be left alone if it exists
+	t.Run("create", func(t *testing.T) {
+		root := "ensure_fifo_1"
+		defer os.RemoveAll(root)
+		path := filepath.Join(root, "nested", "control")
+
+		for i := 0; i < 2; i++ {
+			if err := EnsureFifo(path, 0600); err != nil {
+				t.Fatalf("EnsureFifo failed: %v", err)
+			}
+		}
+
+		if ok, err := IsFifo(path); !ok || err != nil {
+			t.Errorf("Expected a named pipe, got %v (%v)", ok, err)
+		}
+		if ok, _ := IsSocket(path); ok {
+			t.Errorf("Expected a named pipe not to be a socket")
+		}
+	})
+
+	// Expect ErrExist if a regular file is in the way
+	t.Run("file in the way", func(t *testing.T) {
+		path := "ensure_fifo_2"
+		defer os.Remove(path)
+		os.WriteFile(path, []byte("content"), 0644)
+
+		err := EnsureFifo(path, 0600)
+		if !errors.Is(err, ErrExist) {
+			t.Errorf("Expected ErrExist, got %v", err)
+		}
+	})
+}
+
+func TestIsSpecial(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("special files are only supported on Unix")
+	}
+
+	// Expect a socket and a device to be recognized, and a missing file to be neither
+	if ok, err := IsDevice(os.DevNull); !ok || err != nil {
+		t.Errorf("Expected %s to be a device, got %v (%v)", os.DevNull, ok, err)
+	}
+	if ok, err := IsDevice("is_special_missing"); ok || err != nil {
+		t.Errorf("Expected a missing file not to be a device, got %v (%v)", ok, err)
+	}
+
+	path := "is_special.sock"
+	defer os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("net.Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	if ok, err := IsSocket(path); !ok || err != nil {
+		t.Errorf("Expected a socket, got %v (%v)", ok, err)
+	}
+	if ok, _ := IsFifo(path); ok {
+		t.Errorf("Expected a socket not to be a named pipe")
+	}
+}
+
+func TestReadDirRecSpecial(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes are only supported on Unix")
+	}
+
+	root := "read_dir_rec_special"
+	defer os.RemoveAll(root)
+	writeTree(t, root, map[string]string{"a.txt": "a"})
+	if err := EnsureFifo(filepath.Join(root, "pipe"), 0600); err != nil {
+		t.Fatalf("EnsureFifo failed: %v", err)
+	}
+
+	// Expect special files to be listed and classified by default
+	it, err := IterDirRec(root, ReadDirRecOptions{Paths: PathRelative})
+	if err != nil {
+		t.Fatalf("IterDirRec failed: %v", err)
+	}
+	kinds := map[string]FileKind{}
+	for it.Next() {
+		kinds[it.Path()] = it.Kind()
+	}
+	expected := map[string]FileKind{"a.txt": KindRegular, "pipe": KindFifo}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("Expected %v, got %v", expected, kinds)
+	}
+
+	// Expect special files to be skipped with ExcludeSpecial
+	files, err := ReadDirRecWithOptions(root, ReadDirRecOptions{Paths: PathRelative, ExcludeSpecial: true})
+	if err != nil {
+		t.Fatalf("ReadDirRecWithOptions failed: %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{"a.txt"}) {
+		t.Errorf("Expected only a.txt, got %v", files)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package fs_go
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func mkfifo(path string, mode os.FileMode) error {
+	return unix.Mkfifo(path, uint32(mode.Perm()))
+}
@@ -0,0 +1,210 @@
+package fs_go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// splitChecksumExt is the extension of the checksum file Split writes next to the parts.
+const splitChecksumExt = ".sha256"
+
+// Split cuts a file into parts of chunkSize bytes, named like "backup.tar.001",
+// "backup.tar.002" and so on next to the file, and returns their paths in order. The last
+// part may be shorter. The SHA-256 checksum of the whole file is written next to the parts,
+// as "backup.tar.sha256" in the format of sha256sum, so Join can verify the result.
+//
+// Example:
+//
+//	parts, err := Split("backup.tar", 25<<20)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	// Later, after transferring the parts and the checksum file
+//	err = Join(parts, "backup.tar")
+func Split(path string, chunkSize int64) (parts []string, err error) {
+	path = normalizePath(path)
+
+	if chunkSize <= 0 {
+		return nil, pathError("Split", path, fmt.Errorf("Split failed: chunk size must be positive, got %d", chunkSize))
+	}
+	if skip, err := writeGuard("Split", path, path+".001"); skip {
+		return nil, err
+	}
+	defer invalidateStats(path, filepath.Dir(path))
+
+	source, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("Split", path, fmt.Errorf("Split failed to open file: %w", err))
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return nil, pathError("Split", path, fmt.Errorf("Split failed to get file stat: %w", err))
+	}
+
+	defer func() {
+		if err != nil {
+			for _, part := range parts {
+				os.Remove(part)
+			}
+		}
+	}()
+
+	hash := sha256.New()
+	reader := io.TeeReader(source, hash)
+	for i := 1; ; i++ {
+		part := fmt.Sprintf("%s.%03d", path, i)
+		written, err := writePart(part, io.LimitReader(reader, chunkSize), info.Mode().Perm())
+		if err != nil {
+			return parts, pathError("Split", path, fmt.Errorf("Split failed to write part %s: %w", part, err))
+		}
+		// An empty file still gets a single, empty part
+		if written == 0 && i > 1 {
+			os.Remove(part)
+			break
+		}
+
+		parts = append(parts, part)
+		if written < chunkSize {
+			break
+		}
+	}
+
+	line := hex.EncodeToString(hash.Sum(nil)) + "  " + filepath.Base(path) + "\n"
+	err = writeAtomic(path+splitChecksumExt, []byte(line), 0644, false)
+	if err != nil {
+		return parts, pathError("Split", path, fmt.Errorf("Split failed to write checksum: %w", err))
+	}
+
+	return parts, nil
+}
+
+// Join concatenates parts, in the given order, into dst. If the checksum file written by
+// Split exists next to the first part, the joined content is verified against it, and
+// Join fails with ErrChecksumMismatch without touching dst if they differ.
+// dst is replaced atomically.
+func Join(parts []string, dst string) error {
+	dst = normalizePath(dst)
+
+	if len(parts) == 0 {
+		return pathError("Join", dst, fmt.Errorf("Join failed: no parts given"))
+	}
+	if skip, err := writeGuard("Join", parts[0], dst); skip {
+		return err
+	}
+	defer invalidateStats(parts[0], dst)
+
+	expected, err := readSplitChecksum(normalizePath(parts[0]))
+	if err != nil {
+		return pathError("Join", dst, fmt.Errorf("Join failed to read checksum: %w", err))
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return pathError("Join", dst, fmt.Errorf("Join failed to create temporary file: %w", err))
+	}
+	tmp := file.Name()
+	defer os.Remove(tmp)
+
+	hash := sha256.New()
+	writer := io.MultiWriter(file, hash)
+	var mode os.FileMode = 0644
+	for i, part := range parts {
+		m, err := appendPart(writer, normalizePath(part))
+		if err != nil {
+			file.Close()
+			return pathError("Join", dst, fmt.Errorf("Join failed to append part %s: %w", part, err))
+		}
+		if i == 0 {
+			mode = m
+		}
+	}
+
+	err = file.Chmod(mode)
+	if err != nil {
+		file.Close()
+		return pathError("Join", dst, fmt.Errorf("Join failed to set file mode: %w", err))
+	}
+	err = file.Close()
+	if err != nil {
+		return pathError("Join", dst, fmt.Errorf("Join failed to close file: %w", err))
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if expected != "" && actual != expected {
+		return pathError("Join", dst, fmt.Errorf("Join failed: %w: expected %s, got %s", ErrChecksumMismatch, expected, actual))
+	}
+
+	err = os.Rename(tmp, dst)
+	if err != nil {
+		return pathError("Join", dst, fmt.Errorf("Join failed to rename temporary file: %w", err))
+	}
+
+	return nil
+}
+
+// writePart copies reader into a new file at path and returns the number of bytes written.
+func writePart(path string, reader io.Reader, mode os.FileMode) (int64, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(file, reader)
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return 0, err
+	}
+
+	return written, file.Close()
+}
+
+// appendPart copies the file at path to writer and returns its mode.
+func appendPart(writer io.Writer, path string) (os.FileMode, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = io.Copy(writer, file)
+	return info.Mode().Perm(), err
+}
+
+// readSplitChecksum returns the checksum Split wrote for the file the first part belongs to,
+// or "" if there is none.
+func readSplitChecksum(firstPart string) (string, error) {
+	ext := filepath.Ext(firstPart)
+	if ext == "" {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(strings.TrimSuffix(firstPart, ext) + splitChecksumExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("checksum file is empty")
+	}
+
+	return strings.ToLower(fields[0]), nil
+}
@@ -0,0 +1,85 @@
+package fs_go
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	// Expect parts of the chunk size that join back into the original file
+	t.Run("split and join", func(t *testing.T) {
+		path := "split_1"
+		defer os.RemoveAll(path)
+		os.Mkdir(path, 0755)
+
+		content := bytes.Repeat([]byte("0123456789"), 25)
+		source := filepath.Join(path, "data.bin")
+		os.WriteFile(source, content, 0644)
+
+		parts, err := Split(source, 100)
+		if err != nil {
+			t.Fatalf("Split failed: %v", err)
+		}
+		if len(parts) != 3 || parts[0] != source+".001" {
+			t.Fatalf("Expected 3 parts starting with data.bin.001, got %v", parts)
+		}
+		if info, _ := os.Stat(parts[2]); info.Size() != 50 {
+			t.Errorf("Expected the last part to have 50 bytes, got %d", info.Size())
+		}
+
+		dst := filepath.Join(path, "joined.bin")
+		err = Join(parts, dst)
+		if err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		if joined, _ := os.ReadFile(dst); !bytes.Equal(joined, content) {
+			t.Errorf("Expected joined content to equal the original")
+		}
+	})
+
+	// Expect exact multiples of the chunk size not to produce an empty part
+	t.Run("exact multiple", func(t *testing.T) {
+		path := "split_2.bin"
+		defer os.Remove(path)
+		defer os.Remove(path + ".sha256")
+		os.WriteFile(path, make([]byte, 200), 0644)
+
+		parts, err := Split(path, 100)
+		for _, part := range parts {
+			defer os.Remove(part)
+		}
+		if err != nil {
+			t.Fatalf("Split failed: %v", err)
+		}
+		if len(parts) != 2 {
+			t.Errorf("Expected 2 parts, got %v", parts)
+		}
+	})
+
+	// Expect a corrupted or missing part to fail verification and leave dst alone
+	t.Run("checksum mismatch", func(t *testing.T) {
+		path := "split_3"
+		defer os.RemoveAll(path)
+		os.Mkdir(path, 0755)
+
+		source := filepath.Join(path, "data.bin")
+		os.WriteFile(source, bytes.Repeat([]byte("x"), 250), 0644)
+
+		parts, err := Split(source, 100)
+		if err != nil {
+			t.Fatalf("Split failed: %v", err)
+		}
+
+		dst := filepath.Join(path, "joined.bin")
+		err = Join(parts[:2], dst)
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+		}
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("Expected dst not to be written, got %v", err)
+		}
+	})
+}
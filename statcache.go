@@ -0,0 +1,220 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var statCache atomic.Pointer[StatCache]
+
+// SetStatCache makes Exists, GetSize and ReadDirRec answer from c for the whole package,
+// so loops that probe the same paths over and over, like watchers or dependency
+// resolvers, don't repeat the same system calls. Passing nil disables the cache.
+//
+// Writes through the package invalidate the paths they touch, but changes made by other
+// processes, or directly with the os package, are only seen once entries expire.
+//
+// Example:
+//
+//	SetStatCache(NewStatCache(2 * time.Second))
+//	defer SetStatCache(nil)
+//	for _, dep := range deps {
+//	    ok, err := Exists(dep) // Probed at most once every 2 seconds
+//	}
+func SetStatCache(c *StatCache) {
+	statCache.Store(c)
+}
+
+// activeStatCache returns the cache set with SetStatCache, or nil if there is none.
+func activeStatCache() *StatCache {
+	return statCache.Load()
+}
+
+// StatCache remembers file stats and directory listings for a fixed time. It is safe for
+// concurrent use, and is created by NewStatCache.
+//
+// Entries are keyed by absolute paths, made absolute with the working directory at the
+// time the cache was created, so it must be cleared after changing directories.
+type StatCache struct {
+	ttl time.Duration
+	wd  string
+
+	mu      sync.Mutex
+	stats   map[string]statCacheEntry
+	listing map[string]listingCacheEntry
+	swept   time.Time
+}
+
+// statCacheEntry is a cached stat. A nil info means the path doesn't exist.
+type statCacheEntry struct {
+	info os.FileInfo
+	at   time.Time
+}
+
+// listingCacheEntry is a cached recursive directory listing.
+type listingCacheEntry struct {
+	files []string
+	at    time.Time
+}
+
+// NewStatCache returns an empty StatCache whose entries expire after ttl.
+func NewStatCache(ttl time.Duration) *StatCache {
+	wd, _ := os.Getwd()
+
+	return &StatCache{
+		ttl:     ttl,
+		wd:      wd,
+		stats:   make(map[string]statCacheEntry),
+		listing: make(map[string]listingCacheEntry),
+		swept:   time.Now(),
+	}
+}
+
+// Invalidate forgets path and everything below it, the stat of its parent directory, and
+// the listings of directories containing it.
+func (c *StatCache) Invalidate(path string) {
+	key := c.key(normalizePath(path))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.stats, filepath.Dir(key))
+	for cached := range c.stats {
+		if isPathWithin(cached, key) {
+			delete(c.stats, cached)
+		}
+	}
+	for root := range c.listing {
+		if isPathWithin(root, key) || isPathWithin(key, root) {
+			delete(c.listing, root)
+		}
+	}
+}
+
+// Clear forgets everything.
+func (c *StatCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.stats)
+	clear(c.listing)
+}
+
+// stat returns the stat of a normalized path following symlinks, from the cache if it is
+// fresh. A path that doesn't exist is cached too, and returns an error matching ErrNotExist.
+func (c *StatCache) stat(path string) (os.FileInfo, error) {
+	key := c.key(path)
+
+	c.mu.Lock()
+	entry, ok := c.stats[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.at) < c.ttl {
+		if entry.info == nil {
+			return nil, &os.PathError{Op: "stat", Path: path, Err: os.ErrNotExist}
+		}
+		return entry.info, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.stats[key] = statCacheEntry{info: info, at: time.Now()}
+	c.sweep()
+	c.mu.Unlock()
+
+	return info, err
+}
+
+// readDirRec returns the recursive listing of a normalized directory from the cache if it
+// is fresh, and otherwise calls list and caches its result.
+func (c *StatCache) readDirRec(path string, list func() ([]string, error)) ([]string, error) {
+	key := c.key(path)
+
+	c.mu.Lock()
+	entry, ok := c.listing[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.at) < c.ttl {
+		return append([]string(nil), entry.files...), nil
+	}
+
+	files, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.listing[key] = listingCacheEntry{files: append([]string(nil), files...), at: time.Now()}
+	c.sweep()
+	c.mu.Unlock()
+
+	return files, nil
+}
+
+// sweep removes expired entries, at most once per ttl so the cost stays amortized.
+// It must be called with mu held.
+func (c *StatCache) sweep() {
+	now := time.Now()
+	if now.Sub(c.swept) < c.ttl {
+		return
+	}
+	c.swept = now
+
+	for key, entry := range c.stats {
+		if now.Sub(entry.at) >= c.ttl {
+			delete(c.stats, key)
+		}
+	}
+	for key, entry := range c.listing {
+		if now.Sub(entry.at) >= c.ttl {
+			delete(c.listing, key)
+		}
+	}
+}
+
+// key returns the absolute, cleaned form of a path used to look it up.
+func (c *StatCache) key(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(c.wd, path)
+}
+
+// invalidateStats forgets the paths a write changes, if a cache is set.
+func invalidateStats(paths ...string) {
+	c := activeStatCache()
+	if c == nil {
+		return
+	}
+
+	for _, path := range paths {
+		if path != "" {
+			c.Invalidate(path)
+		}
+	}
+}
+
+// cachedStat returns the stat of a normalized path, through the cache if one is set.
+func cachedStat(path string) (os.FileInfo, error) {
+	if c := activeStatCache(); c != nil {
+		return c.stat(path)
+	}
+
+	return os.Stat(path)
+}
+
+// isPathWithin reports whether the cleaned, absolute path is dir or inside it.
+func isPathWithin(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -0,0 +1,133 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestStatCache(t *testing.T) {
+	// Expect answers from the cache while fresh, even if the file changed behind its back
+	t.Run("cached", func(t *testing.T) {
+		path := "stat_cache_1.txt"
+		defer os.Remove(path)
+		SetStatCache(NewStatCache(time.Hour))
+		defer SetStatCache(nil)
+
+		if ok, _ := Exists(path); ok {
+			t.Fatalf("Expected %s not to exist yet", path)
+		}
+		os.WriteFile(path, []byte("content"), 0644)
+
+		if ok, _ := Exists(path); ok {
+			t.Errorf("Expected the cached answer that %s doesn't exist", path)
+		}
+	})
+
+	// Expect entries to expire after the ttl
+	t.Run("expired", func(t *testing.T) {
+		path := "stat_cache_2.txt"
+		defer os.Remove(path)
+		SetStatCache(NewStatCache(10 * time.Millisecond))
+		defer SetStatCache(nil)
+
+		Exists(path)
+		os.WriteFile(path, []byte("content"), 0644)
+		time.Sleep(20 * time.Millisecond)
+
+		if ok, _ := Exists(path); !ok {
+			t.Errorf("Expected %s to exist after the entry expired", path)
+		}
+	})
+
+	// Expect writes through the package to invalidate stats and listings
+	t.Run("invalidated by writes", func(t *testing.T) {
+		root := "stat_cache_3"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{"a.txt": "a"})
+		SetStatCache(NewStatCache(time.Hour))
+		defer SetStatCache(nil)
+
+		path := filepath.Join(root, "a.txt")
+		if size, _ := GetSize(path); size != 1 {
+			t.Fatalf("Expected size 1, got %d", size)
+		}
+		ReadDirRec(root)
+
+		if err := WriteText(path, "longer"); err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+		if err := EnsureDir(filepath.Join(root, "nested")); err != nil {
+			t.Fatalf("EnsureDir failed: %v", err)
+		}
+		if err := WriteText(filepath.Join(root, "nested", "b.txt"), "b"); err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+
+		if size, _ := GetSize(path); size != 6 {
+			t.Errorf("Expected size 6 after writing, got %d", size)
+		}
+		files, _ := ReadDirRec(root)
+		sort.Strings(files)
+		expected := []string{path, filepath.Join(root, "nested", "b.txt")}
+		if len(files) != 2 || files[0] != expected[0] || files[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, files)
+		}
+
+		if err := RemoveAll(root); err != nil {
+			t.Fatalf("RemoveAll failed: %v", err)
+		}
+		if ok, _ := Exists(path); ok {
+			t.Errorf("Expected %s not to exist after removing its directory", path)
+		}
+	})
+
+	// Expect a stat taken while a FileWriter is open not to outlive Close
+	t.Run("invalidated after writes", func(t *testing.T) {
+		path := "stat_cache_4.txt"
+		defer os.Remove(path)
+		SetStatCache(NewStatCache(time.Hour))
+		defer SetStatCache(nil)
+
+		writer, err := NewFileWriter(path, FileWriterOptions{})
+		if err != nil {
+			t.Fatalf("NewFileWriter failed: %v", err)
+		}
+		writer.Write([]byte("content"))
+		if ok, _ := Exists(path); ok {
+			t.Fatalf("Expected %s not to exist before Close", path)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		if ok, _ := Exists(path); !ok {
+			t.Errorf("Expected %s to exist after Close", path)
+		}
+	})
+
+	// Expect files deleted by SyncDir not to be reported as existing
+	t.Run("invalidated by sync", func(t *testing.T) {
+		src, dst := "stat_cache_5_src", "stat_cache_5_dst"
+		defer os.RemoveAll(src)
+		defer os.RemoveAll(dst)
+		writeTree(t, src, map[string]string{"a.txt": "a"})
+		writeTree(t, dst, map[string]string{"a.txt": "a", "stale.txt": "stale"})
+		SetStatCache(NewStatCache(time.Hour))
+		defer SetStatCache(nil)
+
+		stale := filepath.Join(dst, "stale.txt")
+		if ok, _ := Exists(stale); !ok {
+			t.Fatalf("Expected %s to exist before syncing", stale)
+		}
+		if _, err := SyncDirWithOptions(src, dst, SyncOptions{Delete: true}); err != nil {
+			t.Fatalf("SyncDirWithOptions failed: %v", err)
+		}
+
+		if ok, _ := Exists(stale); ok {
+			t.Errorf("Expected %s not to exist after syncing", stale)
+		}
+	})
+}
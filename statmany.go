@@ -0,0 +1,103 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// StatResult is the stat of one of the paths of StatMany.
+type StatResult struct {
+	Info os.FileInfo // nil if Err is set
+	Err  error       // Matches ErrNotExist if the path doesn't exist
+}
+
+// StatMany stats paths concurrently, following symlinks, and returns the results by path
+// as given. Each path is stat'ed once, however often it is listed. Like Exists, it answers
+// from the stat cache, if one was set with SetStatCache.
+//
+// Example:
+//
+//	for path, result := range StatMany(sources) {
+//	    if result.Err == nil && result.Info.ModTime().After(built) {
+//	        fmt.Println(path, "changed")
+//	    }
+//	}
+func StatMany(paths []string) map[string]StatResult {
+	results := make(map[string]StatResult, len(paths))
+	var unique []string
+	for _, path := range paths {
+		if _, ok := results[path]; !ok {
+			results[path] = StatResult{}
+			unique = append(unique, path)
+		}
+	}
+
+	// Stats mostly wait on the disk or network, so there are more workers than CPUs
+	workers := min(len(unique), 4*runtime.NumCPU())
+	queue := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range queue {
+				normalized := normalizePath(path)
+				info, err := cachedStat(normalized)
+				result := StatResult{Info: info}
+				if err != nil {
+					result = StatResult{Err: pathError("StatMany", normalized, fmt.Errorf("StatMany failed to get file stat: %w", err))}
+				}
+
+				mu.Lock()
+				results[path] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, path := range unique {
+		queue <- path
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// ExistsAll checks concurrently which paths exist, and returns the answers by path as
+// given. Errors other than a path not existing are joined, and their paths are left out.
+//
+// Example:
+//
+//	exists, err := ExistsAll(deps...)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, dep := range deps {
+//	    if !exists[dep] {
+//	        fmt.Println("missing", dep)
+//	    }
+//	}
+func ExistsAll(paths ...string) (map[string]bool, error) {
+	exists := make(map[string]bool, len(paths))
+	var errs []error
+	for path, result := range StatMany(paths) {
+		switch {
+		case result.Err == nil:
+			exists[path] = true
+		case errors.Is(result.Err, ErrNotExist):
+			exists[path] = false
+		default:
+			errs = append(errs, result.Err)
+		}
+	}
+	if len(errs) > 0 {
+		return exists, fmt.Errorf("ExistsAll failed: %w", errors.Join(errs...))
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,62 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatMany(t *testing.T) {
+	root := "stat_many"
+	defer os.RemoveAll(root)
+	files := map[string]string{}
+	for i := 0; i < 100; i++ {
+		files[fmt.Sprintf("f%d.txt", i)] = fmt.Sprint(i)
+	}
+	writeTree(t, root, files)
+
+	// Expect a result for every path, including duplicates and missing ones
+	t.Run("stat", func(t *testing.T) {
+		var paths []string
+		for name := range files {
+			paths = append(paths, filepath.Join(root, name))
+		}
+		missing := filepath.Join(root, "missing.txt")
+		paths = append(paths, missing, paths[0])
+
+		results := StatMany(paths)
+		if len(results) != 101 {
+			t.Fatalf("Expected 101 results, got %d", len(results))
+		}
+		if info := results[filepath.Join(root, "f42.txt")].Info; info == nil || info.Size() != 2 {
+			t.Errorf("Expected f42.txt to have size 2, got %v", info)
+		}
+		if err := results[missing].Err; !errors.Is(err, ErrNotExist) {
+			t.Errorf("Expected ErrNotExist for %s, got %v", missing, err)
+		}
+	})
+
+	// Expect existence of every path
+	t.Run("exists all", func(t *testing.T) {
+		exists, err := ExistsAll(filepath.Join(root, "f1.txt"), filepath.Join(root, "missing.txt"), root)
+		if err != nil {
+			t.Fatalf("ExistsAll failed: %v", err)
+		}
+
+		expected := map[string]bool{filepath.Join(root, "f1.txt"): true, filepath.Join(root, "missing.txt"): false, root: true}
+		for path, ok := range expected {
+			if exists[path] != ok {
+				t.Errorf("Expected %s to exist: %v, got %v", path, ok, exists[path])
+			}
+		}
+	})
+
+	// Expect no paths to give no results
+	t.Run("empty", func(t *testing.T) {
+		if results := StatMany(nil); len(results) != 0 {
+			t.Errorf("Expected no results, got %v", results)
+		}
+	})
+}
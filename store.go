@@ -0,0 +1,313 @@
+package fs_go
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StoreEncoding selects how a Store encodes values.
+type StoreEncoding int
+
+const (
+	StoreJSON StoreEncoding = iota // Human-readable, the default
+	StoreGob                       // Compact and Go-specific
+)
+
+// StoreOptions configures a Store.
+type StoreOptions struct {
+	// Encoding of the values. Defaults to StoreJSON.
+	Encoding StoreEncoding
+	// Dir stores every key in its own file inside the directory at the store path,
+	// instead of all keys in a single file. Prefer it for many or large values, since
+	// a single-file store rewrites the whole file on every change.
+	Dir bool
+	// Cache keeps values in memory after they are first read, so reads don't touch the
+	// disk. Only enable it if nothing else modifies the store.
+	Cache bool
+}
+
+// Store is a small persistent map for tools that need durable state without a database.
+// Every change is written atomically, so a crash never leaves a half-written value.
+// It is safe for concurrent use within a process.
+type Store[V any] struct {
+	path  string
+	opts  StoreOptions
+	mu    sync.Mutex
+	cache map[string]V
+	// loaded is set once a single-file store has been read into the cache
+	loaded bool
+}
+
+// NewStore returns a Store at path. Nothing is created until the first Set.
+//
+// Example:
+//
+//	store, err := NewStore[time.Time]("state/last-run.json", StoreOptions{})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = store.Set("backup", time.Now())
+func NewStore[V any](path string, opts StoreOptions) (*Store[V], error) {
+	path = normalizePath(path)
+
+	if opts.Encoding != StoreJSON && opts.Encoding != StoreGob {
+		return nil, fmt.Errorf("NewStore failed: unknown encoding %d", opts.Encoding)
+	}
+
+	store := &Store[V]{path: path, opts: opts}
+	if opts.Cache {
+		store.cache = make(map[string]V)
+	}
+
+	return store, nil
+}
+
+// Get returns the value for key and whether it exists.
+func (s *Store[V]) Get(key string) (V, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var zero V
+	if s.opts.Dir {
+		if value, ok := s.cache[key]; ok {
+			return value, true, nil
+		}
+
+		value, ok, err := s.readKey(key)
+		if err != nil {
+			return zero, false, fmt.Errorf("Get failed to read %q: %w", key, err)
+		}
+		if ok && s.cache != nil {
+			s.cache[key] = value
+		}
+		return value, ok, nil
+	}
+
+	values, err := s.readAll()
+	if err != nil {
+		return zero, false, fmt.Errorf("Get failed to read store: %w", err)
+	}
+	value, ok := values[key]
+
+	return value, ok, nil
+}
+
+// Set stores a value for key, replacing any existing value.
+func (s *Store[V]) Set(key string, value V) error {
+	if key == "" {
+		return fmt.Errorf("Set failed: key is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.Dir {
+		content, err := s.encode(value)
+		if err != nil {
+			return fmt.Errorf("Set failed to encode %q: %w", key, err)
+		}
+		err = EnsureDir(s.path)
+		if err != nil {
+			return fmt.Errorf("Set failed to create store: %w", err)
+		}
+		err = Locked(s.keyPath(key)).Do(func(path string) error {
+			return writeAtomic(path, content, 0644, false)
+		})
+		if err != nil {
+			return fmt.Errorf("Set failed to write %q: %w", key, err)
+		}
+		if s.cache != nil {
+			s.cache[key] = value
+		}
+		return nil
+	}
+
+	err := s.update(func(values map[string]V) {
+		values[key] = value
+	})
+	if err != nil {
+		return fmt.Errorf("Set failed to write store: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes key from the store. Deleting a missing key succeeds.
+func (s *Store[V]) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.Dir {
+		err := Locked(s.keyPath(key)).Do(func(path string) error {
+			err := Remove(path)
+			if errors.Is(err, ErrNotExist) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Delete failed to remove %q: %w", key, err)
+		}
+		delete(s.cache, key)
+		return nil
+	}
+
+	err := s.update(func(values map[string]V) {
+		delete(values, key)
+	})
+	if err != nil {
+		return fmt.Errorf("Delete failed to write store: %w", err)
+	}
+
+	return nil
+}
+
+// Keys returns all keys in the store, sorted.
+func (s *Store[V]) Keys() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	if s.opts.Dir {
+		entries, err := os.ReadDir(normalizePath(s.path))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("Keys failed to read store: %w", err)
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), s.extension())
+			if !ok || entry.IsDir() {
+				continue
+			}
+			key, err := url.QueryUnescape(name)
+			if err == nil {
+				keys = append(keys, key)
+			}
+		}
+	} else {
+		values, err := s.readAll()
+		if err != nil {
+			return nil, fmt.Errorf("Keys failed to read store: %w", err)
+		}
+		for key := range values {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// extension returns the file extension for the encoding.
+func (s *Store[V]) extension() string {
+	if s.opts.Encoding == StoreGob {
+		return ".gob"
+	}
+
+	return ".json"
+}
+
+// keyPath returns the file of a key in a directory store. Keys are escaped, so any
+// string is a valid key on every platform.
+func (s *Store[V]) keyPath(key string) string {
+	return filepath.Join(s.path, url.QueryEscape(key)+s.extension())
+}
+
+// readKey reads a single key of a directory store.
+func (s *Store[V]) readKey(key string) (V, bool, error) {
+	var value V
+	content, err := os.ReadFile(normalizePath(s.keyPath(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return value, false, nil
+	}
+	if err != nil {
+		return value, false, err
+	}
+
+	err = s.decode(content, &value)
+	return value, err == nil, err
+}
+
+// readAll reads every value of a single-file store. A missing file is an empty store.
+func (s *Store[V]) readAll() (map[string]V, error) {
+	if s.loaded {
+		return s.cache, nil
+	}
+
+	values := make(map[string]V)
+	content, err := os.ReadFile(normalizePath(s.path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(content) > 0 {
+		err = s.decode(content, &values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cache != nil {
+		s.cache = values
+		s.loaded = true
+	}
+
+	return values, nil
+}
+
+// update applies a change to a single-file store and writes it back atomically.
+func (s *Store[V]) update(change func(values map[string]V)) error {
+	return Locked(s.path).Do(func(path string) error {
+		values, err := s.readAll()
+		if err != nil {
+			return err
+		}
+
+		// Change a copy, so the cache stays intact if the write fails
+		updated := make(map[string]V, len(values)+1)
+		for key, value := range values {
+			updated[key] = value
+		}
+		change(updated)
+
+		content, err := s.encode(updated)
+		if err != nil {
+			return err
+		}
+		err = writeAtomic(path, content, 0644, false)
+		if err != nil {
+			return err
+		}
+
+		if s.loaded {
+			s.cache = updated
+		}
+		return nil
+	})
+}
+
+func (s *Store[V]) encode(v any) ([]byte, error) {
+	if s.opts.Encoding == StoreGob {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(v)
+		return buf.Bytes(), err
+	}
+
+	return json.MarshalIndent(v, "", "  ")
+}
+
+func (s *Store[V]) decode(content []byte, v any) error {
+	if s.opts.Encoding == StoreGob {
+		return gob.NewDecoder(bytes.NewReader(content)).Decode(v)
+	}
+
+	return json.Unmarshal(content, v)
+}
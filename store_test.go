@@ -0,0 +1,117 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type storeValue struct {
+	Name  string
+	Count int
+}
+
+func TestStore(t *testing.T) {
+	// Expect values to persist in a single JSON file across stores
+	t.Run("file", func(t *testing.T) {
+		path := "store_1.json"
+		defer os.Remove(path)
+
+		store, err := NewStore[storeValue](path, StoreOptions{})
+		if err != nil {
+			t.Fatalf("NewStore failed: %v", err)
+		}
+		if err := store.Set("a", storeValue{Name: "a", Count: 1}); err != nil {
+			t.Errorf("Set failed: %v", err)
+		}
+		if err := store.Set("b", storeValue{Name: "b", Count: 2}); err != nil {
+			t.Errorf("Set failed: %v", err)
+		}
+		if err := store.Delete("a"); err != nil {
+			t.Errorf("Delete failed: %v", err)
+		}
+
+		reopened, _ := NewStore[storeValue](path, StoreOptions{})
+		value, ok, err := reopened.Get("b")
+		if err != nil || !ok || value.Count != 2 {
+			t.Errorf("Expected b to persist, got %+v %v (%v)", value, ok, err)
+		}
+		_, ok, _ = reopened.Get("a")
+		if ok {
+			t.Errorf("Expected a to be deleted")
+		}
+
+		keys, err := reopened.Keys()
+		if err != nil || strings.Join(keys, ",") != "b" {
+			t.Errorf("Expected keys [b], got %v (%v)", keys, err)
+		}
+	})
+
+	// Expect a directory store to keep each key in its own escaped file
+	t.Run("dir", func(t *testing.T) {
+		path := "store_2"
+		defer os.RemoveAll(path)
+
+		store, err := NewStore[int](path, StoreOptions{Dir: true, Encoding: StoreGob})
+		if err != nil {
+			t.Fatalf("NewStore failed: %v", err)
+		}
+		if err := store.Set("jobs/nightly:1", 42); err != nil {
+			t.Errorf("Set failed: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(path, "jobs%2Fnightly%3A1.gob")); err != nil {
+			t.Errorf("Expected an escaped key file: %v", err)
+		}
+
+		value, ok, err := store.Get("jobs/nightly:1")
+		if err != nil || !ok || value != 42 {
+			t.Errorf("Expected 42, got %d %v (%v)", value, ok, err)
+		}
+
+		keys, err := store.Keys()
+		if err != nil || len(keys) != 1 || keys[0] != "jobs/nightly:1" {
+			t.Errorf("Expected the unescaped key, got %v (%v)", keys, err)
+		}
+
+		if err := store.Delete("jobs/nightly:1"); err != nil {
+			t.Errorf("Delete failed: %v", err)
+		}
+		if err := store.Delete("missing"); err != nil {
+			t.Errorf("Expected deleting a missing key to succeed, got %v", err)
+		}
+	})
+
+	// Expect a cached store to serve reads from memory
+	t.Run("cache", func(t *testing.T) {
+		path := "store_3.json"
+		defer os.Remove(path)
+
+		store, _ := NewStore[string](path, StoreOptions{Cache: true})
+		if err := store.Set("key", "value"); err != nil {
+			t.Errorf("Set failed: %v", err)
+		}
+		if err := os.Remove(path); err != nil {
+			t.Fatalf("os.Remove failed: %v", err)
+		}
+
+		value, ok, err := store.Get("key")
+		if err != nil || !ok || value != "value" {
+			t.Errorf("Expected cached value, got %q %v (%v)", value, ok, err)
+		}
+	})
+
+	// Expect changes to be refused in read-only mode
+	t.Run("read-only", func(t *testing.T) {
+		path := "store_4.json"
+		defer os.Remove(path)
+		SetReadOnly(true)
+		defer SetReadOnly(false)
+
+		store, _ := NewStore[string](path, StoreOptions{})
+		if err := store.Set("key", "value"); err == nil {
+			t.Errorf("Expected Set to fail in read-only mode")
+		}
+	})
+}
@@ -0,0 +1,318 @@
+package fs_go
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SyncOptions configures SyncDirWithOptions.
+type SyncOptions struct {
+	// Delete removes files and directories in the destination that don't exist in the source.
+	Delete bool
+	// Checksum compares file contents to detect changes instead of size and modification time.
+	Checksum bool
+	// DryRun reports what would be done without touching the destination.
+	DryRun bool
+	// Exclude is a list of filepath.Match patterns. A pattern is matched against both the
+	// slash-separated path relative to the root and the base name. Excluded paths are
+	// neither copied nor deleted.
+	Exclude []string
+	// Ignore skips the paths it matches, like Exclude, relative to the roots.
+	Ignore *Ignorer
+	// Scheduler controls how changed files are copied concurrently. Defaults are used if nil.
+	Scheduler *Scheduler
+	// BytesPerSecond limits the combined bandwidth of all copies, if positive.
+	BytesPerSecond int64
+	// Progress receives the bytes of the changed files copied, once the trees were compared.
+	Progress Progress
+}
+
+// SyncResult describes the changes made (or, in dry-run mode, planned) by a sync.
+// All paths are relative to the synced roots.
+type SyncResult struct {
+	Copied  []string // Files and symlinks copied from the source
+	Deleted []string // Files and directories deleted from the destination
+}
+
+// SyncDir makes dst a mirror of src, copying only files that changed by size or
+// modification time. Extra files in dst are kept.
+func SyncDir(src, dst string) (SyncResult, error) {
+	return SyncDirWithOptions(src, dst, SyncOptions{})
+}
+
+// SyncDirWithOptions makes dst a mirror of src, rsync-style.
+// Only files that changed are copied, and copied files keep the permissions and
+// modification time of their source so unchanged files are skipped on the next sync.
+//
+// Example:
+//
+//	result, err := SyncDirWithOptions("build", "/srv/www", SyncOptions{
+//	    Delete:  true,
+//	    Exclude: []string{"*.tmp", ".git"},
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(result.Copied)
+func SyncDirWithOptions(src, dst string, opts SyncOptions) (SyncResult, error) {
+	src, dst = normalizePath(src), normalizePath(dst)
+
+	// In package-wide dry-run mode, plan the sync and record the planned actions
+	if !opts.DryRun && IsDryRun() {
+		opts.DryRun = true
+		result, err := SyncDirWithOptions(src, dst, opts)
+		for _, rel := range result.Copied {
+			dryRunSkip("CopyFile", filepath.Join(src, rel), filepath.Join(dst, rel))
+		}
+		for _, rel := range result.Deleted {
+			dryRunSkip("RemoveAll", filepath.Join(dst, rel), "")
+		}
+		return result, err
+	}
+
+	var result SyncResult
+
+	if !opts.DryRun {
+		err := checkWritable("SyncDir", dst)
+		if err != nil {
+			return result, err
+		}
+		// Everything below dst may be removed or replaced
+		invalidateStats(dst)
+		defer invalidateStats(dst)
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return result, fmt.Errorf("SyncDir failed to get source stat: %w", err)
+	}
+	if !info.IsDir() {
+		return result, fmt.Errorf("SyncDir failed: %s is not a directory", src)
+	}
+
+	if !opts.DryRun {
+		err = EnsureDirWithMode(dst, info.Mode().Perm())
+		if err != nil {
+			return result, fmt.Errorf("SyncDir failed to ensure destination: %w", err)
+		}
+	}
+
+	var j *Journal
+	var id int64
+	if !opts.DryRun {
+		j = activeJournal()
+		id, err = j.begin(journalRecord{Op: "SyncDir", Src: src, Dst: dst, Sync: journalSyncOptions(opts)})
+		if err != nil {
+			return result, fmt.Errorf("SyncDir failed: %w", err)
+		}
+	}
+
+	limiter := newRateLimiter(opts.BytesPerSecond)
+	seen := make(map[string]bool)
+	var jobs []Job
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if matchAny(opts.Exclude, rel) || opts.Ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		seen[rel] = true
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		copied, job, err := syncEntry(path, filepath.Join(dst, rel), info, opts, limiter)
+		if err != nil {
+			return err
+		}
+		if copied {
+			result.Copied = append(result.Copied, rel)
+		}
+		if job != nil {
+			jobs = append(jobs, *job)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("SyncDir failed to sync %s: %w", src, err)
+	}
+
+	progress := startProgress(opts.Progress, 0, jobsSize(jobs))
+	progress.track(jobs)
+	err = opts.Scheduler.Run(jobs)
+	progress.finish(err)
+	if err != nil {
+		return result, fmt.Errorf("SyncDir failed to copy files: %w", err)
+	}
+
+	if !opts.Delete {
+		err = j.done(id)
+		if err != nil {
+			return result, fmt.Errorf("SyncDir failed: %w", err)
+		}
+		return result, nil
+	}
+
+	err = filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && opts.DryRun {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(dst, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." || seen[rel] {
+			return nil
+		}
+		if matchAny(opts.Exclude, rel) || opts.Ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		result.Deleted = append(result.Deleted, rel)
+		if !opts.DryRun {
+			err = os.RemoveAll(path)
+			if err != nil {
+				return err
+			}
+		}
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("SyncDir failed to delete extraneous files: %w", err)
+	}
+
+	err = j.done(id)
+	if err != nil {
+		return result, fmt.Errorf("SyncDir failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// syncEntry brings a single destination entry in line with its source.
+// It reports whether a file or symlink needs to be copied, and returns a job that
+// performs the copy, limited by limiter, if it can't be done immediately.
+func syncEntry(src, dst string, info os.FileInfo, opts SyncOptions, limiter *rateLimiter) (bool, *Job, error) {
+	dstInfo, err := os.Lstat(dst)
+	if err != nil && !os.IsNotExist(err) {
+		return false, nil, err
+	}
+	exists := err == nil
+
+	switch {
+	case info.IsDir():
+		if exists && dstInfo.IsDir() {
+			return false, nil, nil
+		}
+		if opts.DryRun {
+			return false, nil, nil
+		}
+		if exists {
+			err = os.RemoveAll(dst)
+			if err != nil {
+				return false, nil, err
+			}
+		}
+		return false, nil, os.Mkdir(dst, info.Mode().Perm())
+
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return false, nil, err
+		}
+		if exists && dstInfo.Mode()&os.ModeSymlink != 0 {
+			current, err := os.Readlink(dst)
+			if err == nil && current == target {
+				return false, nil, nil
+			}
+		}
+		if opts.DryRun {
+			return true, nil, nil
+		}
+		return true, nil, copySymlink(src, dst)
+
+	case info.Mode().IsRegular():
+		changed, err := syncChanged(src, dst, info, dstInfo, opts.Checksum)
+		if err != nil {
+			return false, nil, err
+		}
+		if !changed || opts.DryRun {
+			return changed, nil, nil
+		}
+		job := &Job{Size: info.Size(), Run: func() error {
+			if exists && !dstInfo.Mode().IsRegular() {
+				err := os.RemoveAll(dst)
+				if err != nil {
+					return err
+				}
+			}
+			return copyRegular(opts.Scheduler, src, dst, info, true, false, limiter)
+		}}
+		return true, job, nil
+
+	default:
+		return false, nil, fmt.Errorf("%s has unsupported file type %s", src, info.Mode().Type())
+	}
+}
+
+// syncChanged reports whether a regular file needs to be copied over its destination.
+// dstInfo is nil if the destination doesn't exist.
+func syncChanged(src, dst string, info, dstInfo os.FileInfo, checksum bool) (bool, error) {
+	if dstInfo == nil || !dstInfo.Mode().IsRegular() {
+		return true, nil
+	}
+	if info.Size() != dstInfo.Size() {
+		return true, nil
+	}
+	if checksum {
+		equal, err := Equal(src, dst)
+		return !equal, err
+	}
+
+	return !info.ModTime().Equal(dstInfo.ModTime()), nil
+}
+
+// matchAny reports whether the relative path or its base name matches any of the patterns.
+func matchAny(patterns []string, rel string) bool {
+	slashed := filepath.ToSlash(rel)
+	base := filepath.Base(rel)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, slashed); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,190 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeTree writes files relative to root, creating parent directories as needed.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	// Expect dst to mirror src after the first sync
+	t.Run("initial sync", func(t *testing.T) {
+		path := "sync_dir_1"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{
+			"a.txt":        "a",
+			"nested/b.txt": "b",
+		})
+
+		result, err := SyncDir(src, dst)
+		if err != nil {
+			t.Errorf("SyncDir failed: %v", err)
+		}
+
+		expected := []string{"a.txt", filepath.Join("nested", "b.txt")}
+		if !reflect.DeepEqual(result.Copied, expected) {
+			t.Errorf("Expected copied files to be %v, got %v", expected, result.Copied)
+		}
+
+		diff, err := DiffDirs(src, dst)
+		if err != nil {
+			t.Errorf("DiffDirs failed: %v", err)
+		}
+
+		if !diff.Empty() {
+			t.Errorf("Expected trees to be equal, got %+v", diff)
+		}
+	})
+
+	// Expect only changed files to be copied on subsequent syncs
+	t.Run("incremental sync", func(t *testing.T) {
+		path := "sync_dir_2"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{
+			"a.txt": "a",
+			"b.txt": "b",
+		})
+
+		_, err := SyncDir(src, dst)
+		if err != nil {
+			t.Fatalf("SyncDir failed: %v", err)
+		}
+
+		err = os.WriteFile(filepath.Join(src, "b.txt"), []byte("changed"), 0644)
+		if err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		result, err := SyncDir(src, dst)
+		if err != nil {
+			t.Errorf("SyncDir failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Copied, []string{"b.txt"}) {
+			t.Errorf("Expected only b.txt to be copied, got %v", result.Copied)
+		}
+	})
+}
+
+func TestSyncDirWithOptions(t *testing.T) {
+	// Expect extraneous files to be deleted, except excluded ones
+	t.Run("delete and exclude", func(t *testing.T) {
+		path := "sync_dir_options_1"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{
+			"a.txt":     "a",
+			"skip.tmp":  "skip",
+			"keep/b.go": "b",
+		})
+		writeTree(t, dst, map[string]string{
+			"extra.txt":     "extra",
+			"old/c.txt":     "c",
+			"protected.tmp": "protected",
+		})
+
+		result, err := SyncDirWithOptions(src, dst, SyncOptions{
+			Delete:  true,
+			Exclude: []string{"*.tmp"},
+		})
+		if err != nil {
+			t.Errorf("SyncDirWithOptions failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Deleted, []string{"extra.txt", "old"}) {
+			t.Errorf("Expected extra.txt and old to be deleted, got %v", result.Deleted)
+		}
+
+		if _, err := os.Stat(filepath.Join(dst, "skip.tmp")); !os.IsNotExist(err) {
+			t.Errorf("Expected excluded file to not be copied")
+		}
+
+		if _, err := os.Stat(filepath.Join(dst, "protected.tmp")); err != nil {
+			t.Errorf("Expected excluded file to not be deleted: %v", err)
+		}
+	})
+
+	// Expect a dry run to report changes without touching dst
+	t.Run("dry run", func(t *testing.T) {
+		path := "sync_dir_options_2"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{"a.txt": "a"})
+
+		result, err := SyncDirWithOptions(src, dst, SyncOptions{DryRun: true, Delete: true})
+		if err != nil {
+			t.Errorf("SyncDirWithOptions failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Copied, []string{"a.txt"}) {
+			t.Errorf("Expected a.txt to be reported, got %v", result.Copied)
+		}
+
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("Expected destination to not be created")
+		}
+	})
+
+	// Expect checksum mode to detect changes that keep size and modification time
+	t.Run("checksum", func(t *testing.T) {
+		path := "sync_dir_options_3"
+		defer os.RemoveAll(path)
+
+		src := filepath.Join(path, "src")
+		dst := filepath.Join(path, "dst")
+		writeTree(t, src, map[string]string{"a.txt": "aaaa"})
+		writeTree(t, dst, map[string]string{"a.txt": "bbbb"})
+
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		for _, root := range []string{src, dst} {
+			if err := os.Chtimes(filepath.Join(root, "a.txt"), mtime, mtime); err != nil {
+				t.Fatalf("os.Chtimes failed: %v", err)
+			}
+		}
+
+		result, err := SyncDir(src, dst)
+		if err != nil {
+			t.Errorf("SyncDir failed: %v", err)
+		}
+
+		if len(result.Copied) != 0 {
+			t.Errorf("Expected no files to be copied without checksum, got %v", result.Copied)
+		}
+
+		result, err = SyncDirWithOptions(src, dst, SyncOptions{Checksum: true})
+		if err != nil {
+			t.Errorf("SyncDirWithOptions failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Copied, []string{"a.txt"}) {
+			t.Errorf("Expected a.txt to be copied with checksum, got %v", result.Copied)
+		}
+	})
+}
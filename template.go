@@ -0,0 +1,102 @@
+package fs_go
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// TemplateOptions configures WriteTemplateWithOptions and WriteTemplateFile.
+type TemplateOptions struct {
+	// HTML renders with html/template, which escapes data for safe use in HTML.
+	HTML bool
+	// Funcs are functions made available to the template.
+	Funcs map[string]any
+	// Mode is the file mode of the written file. Defaults to 0644.
+	Mode os.FileMode
+}
+
+// WriteTemplate renders a text/template with data and writes the result to a file.
+// The file is replaced atomically, so it is never left half-rendered if rendering fails.
+//
+// Example:
+//
+//	err := WriteTemplate("cmd/main.go", "package main\n\n// {{.Name}} is generated\n", project)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func WriteTemplate(path, src string, data any) error {
+	return WriteTemplateWithOptions(path, src, data, TemplateOptions{})
+}
+
+// WriteTemplateWithOptions renders a template with data and writes the result to a file atomically.
+//
+// Example:
+//
+//	err := WriteTemplateWithOptions("public/index.html", page, data, TemplateOptions{
+//	    HTML:  true,
+//	    Funcs: map[string]any{"upper": strings.ToUpper},
+//	})
+func WriteTemplateWithOptions(path, src string, data any, opts TemplateOptions) error {
+	err := writeTemplate(path, filepath.Base(path), src, data, opts)
+	if err != nil {
+		return fmt.Errorf("WriteTemplate failed: %w", err)
+	}
+
+	return nil
+}
+
+// WriteTemplateFile renders the template in templatePath with data and writes the result
+// to a file atomically.
+func WriteTemplateFile(path, templatePath string, data any, opts TemplateOptions) error {
+	src, err := ReadText(templatePath)
+	if err != nil {
+		return fmt.Errorf("WriteTemplateFile failed to read template: %w", err)
+	}
+
+	err = writeTemplate(path, filepath.Base(templatePath), src, data, opts)
+	if err != nil {
+		return fmt.Errorf("WriteTemplateFile failed: %w", err)
+	}
+
+	return nil
+}
+
+// writeTemplate parses and renders a template, then writes the result to path atomically.
+func writeTemplate(path, name, src string, data any, opts TemplateOptions) error {
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+
+	var buf bytes.Buffer
+	if opts.HTML {
+		tmpl, err := htmltemplate.New(name).Funcs(opts.Funcs).Parse(src)
+		if err != nil {
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+	} else {
+		tmpl, err := template.New(name).Funcs(opts.Funcs).Parse(src)
+		if err != nil {
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+	}
+
+	err := writeAtomic(normalizePath(path), buf.Bytes(), opts.Mode, false)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
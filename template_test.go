@@ -0,0 +1,115 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	// Expect the rendered template to be written
+	t.Run("render", func(t *testing.T) {
+		path := "write_template_1.txt"
+		defer os.Remove(path)
+
+		err := WriteTemplate(path, "Hello, {{.}}!", "world")
+		if err != nil {
+			t.Errorf("WriteTemplate failed: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "Hello, world!" {
+			t.Errorf("Expected %q, got %q", "Hello, world!", content)
+		}
+	})
+
+	// Expect an existing file to be left untouched if rendering fails
+	t.Run("render error", func(t *testing.T) {
+		path := "write_template_2.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("original"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := WriteTemplate(path, "{{.Missing}}", struct{}{})
+		if err == nil {
+			t.Errorf("Expected WriteTemplate to fail")
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "original" {
+			t.Errorf("Expected file to be unchanged, got %q", content)
+		}
+	})
+}
+
+func TestWriteTemplateWithOptions(t *testing.T) {
+	// Expect html escaping, functions and mode to be applied
+	t.Run("options", func(t *testing.T) {
+		path := "write_template_options_1.html"
+		defer os.Remove(path)
+
+		err := WriteTemplateWithOptions(path, "<p>{{upper .}}</p>", "<b>hi</b>", TemplateOptions{
+			HTML:  true,
+			Funcs: map[string]any{"upper": strings.ToUpper},
+			Mode:  0600,
+		})
+		if err != nil {
+			t.Errorf("WriteTemplateWithOptions failed: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "<p>&lt;B&gt;HI&lt;/B&gt;</p>" {
+			t.Errorf("Expected escaped content, got %q", content)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("Expected file mode to be 0600, got %#o", info.Mode().Perm())
+		}
+	})
+}
+
+func TestWriteTemplateFile(t *testing.T) {
+	// Expect a template to be read from a file and rendered
+	t.Run("render", func(t *testing.T) {
+		path := "write_template_file_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{"readme.tmpl": "# {{.Name}}\n"})
+
+		out := filepath.Join(path, "README.md")
+		err := WriteTemplateFile(out, filepath.Join(path, "readme.tmpl"), map[string]string{"Name": "fs_go"}, TemplateOptions{})
+		if err != nil {
+			t.Errorf("WriteTemplateFile failed: %v", err)
+		}
+
+		content, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "# fs_go\n" {
+			t.Errorf("Expected %q, got %q", "# fs_go\n", content)
+		}
+	})
+}
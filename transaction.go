@@ -0,0 +1,327 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrTransactionDone means a Transaction was used after it was committed or rolled back.
+var ErrTransactionDone = errors.New("transaction already committed or rolled back")
+
+// Transaction stages writes, renames and deletes of several files and applies them
+// together with Commit. New content is written to temporary files next to its targets
+// before anything is changed, and every file that is replaced or deleted is kept aside
+// until all operations succeed, so a failing Commit restores the previous state.
+//
+// Operations are applied with renames, which are atomic one by one but not as a group:
+// a crash in the middle of Commit can leave some operations applied and the moved-aside
+// files behind, in hidden ".<name>.txn*" directories next to their original paths.
+// In journal mode, Commit records its steps so such a crash can be recovered from with
+// Journal.Resume or Journal.RollbackIncomplete.
+type Transaction struct {
+	ops  []txOp
+	done bool
+}
+
+// txOp is a single staged operation of a Transaction.
+type txOp struct {
+	name    string
+	path    string
+	dest    string
+	content []byte
+	mode    os.FileMode
+}
+
+// txStep records how to undo an applied operation.
+type txStep struct {
+	undo    func() error
+	cleanup string // Directory holding moved-aside files, removed once the transaction is done
+}
+
+// NewTransaction returns an empty Transaction.
+//
+// Example:
+//
+//	tx := NewTransaction()
+//	tx.Write("config/app.yaml", appConfig)
+//	tx.Write("config/db.yaml", dbConfig)
+//	tx.Delete("config/legacy.ini")
+//	err := tx.Commit()
+//	if err != nil {
+//	    fmt.Println(err) // The config directory is as it was before
+//	    return
+//	}
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Write stages writing content to path with mode 0644, replacing the file if it exists.
+func (t *Transaction) Write(path string, content []byte) *Transaction {
+	return t.WriteWithMode(path, content, 0644)
+}
+
+// WriteWithMode stages writing content to path with a specific file mode.
+func (t *Transaction) WriteWithMode(path string, content []byte, mode os.FileMode) *Transaction {
+	t.ops = append(t.ops, txOp{name: "Write", path: path, content: content, mode: mode})
+	return t
+}
+
+// Rename stages moving src to dst, replacing dst if it exists.
+// Both paths must be on the same file system.
+func (t *Transaction) Rename(src, dst string) *Transaction {
+	t.ops = append(t.ops, txOp{name: "Rename", path: src, dest: dst})
+	return t
+}
+
+// Delete stages removing a file or directory.
+func (t *Transaction) Delete(path string) *Transaction {
+	t.ops = append(t.ops, txOp{name: "Delete", path: path})
+	return t
+}
+
+// Len returns the number of staged operations.
+func (t *Transaction) Len() int {
+	return len(t.ops)
+}
+
+// Commit applies the staged operations in order. If one fails, the operations applied
+// before it are undone in reverse order and the returned error describes the failure,
+// joined with any error from undoing. A Transaction can only be committed once.
+func (t *Transaction) Commit() error {
+	if t.done {
+		return fmt.Errorf("Commit failed: %w", ErrTransactionDone)
+	}
+	t.done = true
+
+	for _, op := range t.ops {
+		if skip, err := writeGuard(op.name, normalizePath(op.path), op.dest); skip {
+			if err != nil {
+				return pathError("Commit", op.path, fmt.Errorf("Commit failed to %s %s: %w", op.name, op.path, err))
+			}
+		}
+		defer invalidateStats(normalizePath(op.path), op.dest)
+	}
+	if IsDryRun() {
+		return nil
+	}
+
+	// Write all new content first, so nothing is changed if any of it fails
+	staged := make([]string, len(t.ops))
+	defer func() {
+		for _, tmp := range staged {
+			if tmp != "" {
+				os.Remove(tmp)
+			}
+		}
+	}()
+	for i, op := range t.ops {
+		if op.name != "Write" {
+			continue
+		}
+
+		tmp, err := stageWrite(normalizePath(op.path), op.content, op.mode)
+		if err != nil {
+			return pathError("Commit", op.path, fmt.Errorf("Commit failed to stage write of %s: %w", op.path, err))
+		}
+		staged[i] = tmp
+	}
+
+	j := activeJournal()
+	id, err := j.begin(journalRecord{Op: "Commit", TxOps: journalTxOps(t.ops, staged)})
+	if err != nil {
+		return fmt.Errorf("Commit failed: %w", err)
+	}
+
+	var steps []txStep
+	undo := func() error {
+		err := undoTxSteps(steps)
+		if err != nil {
+			return err // Left incomplete in the journal, for RollbackIncomplete
+		}
+		return j.done(id)
+	}
+	for i, op := range t.ops {
+		step, err := applyTxOp(op, staged[i], func(aside string) error {
+			return j.step(id, journalRecord{State: "aside", Index: i, Aside: aside})
+		})
+		if err != nil {
+			err = pathError("Commit", op.path, fmt.Errorf("Commit failed to %s %s: %w", op.name, op.path, err))
+			return errors.Join(err, undo())
+		}
+		staged[i] = ""
+		steps = append(steps, step)
+
+		err = j.step(id, journalRecord{State: "applied", Index: i})
+		if err != nil {
+			err = pathError("Commit", op.path, fmt.Errorf("Commit failed: %w", err))
+			return errors.Join(err, undo())
+		}
+	}
+
+	for _, step := range steps {
+		if step.cleanup != "" {
+			os.RemoveAll(step.cleanup)
+		}
+	}
+
+	err = j.done(id)
+	if err != nil {
+		return fmt.Errorf("Commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// Rollback discards the staged operations without applying them.
+// Commit undoes its own changes when it fails, so Rollback is only needed to abandon
+// a transaction that won't be committed.
+func (t *Transaction) Rollback() error {
+	if t.done {
+		return fmt.Errorf("Rollback failed: %w", ErrTransactionDone)
+	}
+	t.done = true
+	t.ops = nil
+
+	return nil
+}
+
+// stageWrite writes content to a temporary file next to path and returns its name.
+func stageWrite(path string, content []byte, mode os.FileMode) (string, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	err = writeAndClose(file, content, mode, false)
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// applyTxOp applies a single operation. For writes, tmp is the staged content. If the
+// operation moves an existing file aside, onAside is called with its new location first,
+// unless it is nil, and the operation is abandoned if it fails.
+func applyTxOp(op txOp, tmp string, onAside func(aside string) error) (txStep, error) {
+	path := normalizePath(op.path)
+
+	switch op.name {
+	case "Write":
+		aside, step, err := moveAsideFor(path, onAside)
+		if err != nil {
+			return txStep{}, err
+		}
+
+		err = os.Rename(tmp, path)
+		if err != nil {
+			return txStep{}, errors.Join(err, undoTxSteps([]txStep{step}))
+		}
+
+		return txStep{cleanup: step.cleanup, undo: func() error {
+			if aside == "" {
+				return os.Remove(path)
+			}
+			return os.Rename(aside, path)
+		}}, nil
+	case "Rename":
+		dest := normalizePath(op.dest)
+		aside, step, err := moveAsideFor(dest, onAside)
+		if err != nil {
+			return txStep{}, err
+		}
+
+		err = os.Rename(path, dest)
+		if err != nil {
+			return txStep{}, errors.Join(err, undoTxSteps([]txStep{step}))
+		}
+
+		return txStep{cleanup: step.cleanup, undo: func() error {
+			err := os.Rename(dest, path)
+			if err != nil || aside == "" {
+				return err
+			}
+			return os.Rename(aside, dest)
+		}}, nil
+	default:
+		aside, step, err := moveAsideFor(path, onAside)
+		if err != nil {
+			return txStep{}, err
+		}
+		if aside == "" {
+			return txStep{}, fmt.Errorf("%s: %w", path, ErrNotExist)
+		}
+
+		return step, nil
+	}
+}
+
+// moveAside moves whatever exists at path into a hidden directory next to it, so it can be
+// restored later. It returns the new location, or "" if nothing exists at path, and a step
+// that moves it back.
+func moveAside(path string) (string, txStep, error) {
+	_, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return "", txStep{undo: func() error { return nil }}, nil
+	}
+	if err != nil {
+		return "", txStep{}, err
+	}
+
+	dir, err := os.MkdirTemp(filepath.Dir(path), "."+filepath.Base(path)+".txn*")
+	if err != nil {
+		return "", txStep{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	aside := filepath.Join(dir, filepath.Base(path))
+	err = os.Rename(path, aside)
+	if err != nil {
+		os.Remove(dir)
+		return "", txStep{}, fmt.Errorf("failed to move existing file aside: %w", err)
+	}
+
+	return aside, txStep{cleanup: dir, undo: func() error {
+		err := os.Rename(aside, path)
+		if err == nil {
+			os.Remove(dir)
+		}
+		return err
+	}}, nil
+}
+
+// moveAsideFor is moveAside, calling onAside with the new location if it isn't nil. If
+// onAside fails, the file is moved back.
+func moveAsideFor(path string, onAside func(aside string) error) (string, txStep, error) {
+	aside, step, err := moveAside(path)
+	if err != nil || aside == "" || onAside == nil {
+		return aside, step, err
+	}
+
+	err = onAside(aside)
+	if err != nil {
+		return "", txStep{}, errors.Join(err, undoTxSteps([]txStep{step}))
+	}
+
+	return aside, step, nil
+}
+
+// undoTxSteps undoes applied steps in reverse order, removing the backup directories
+// that were emptied by undoing.
+func undoTxSteps(steps []txStep) error {
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		err := steps[i].undo()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to undo: %w", err))
+			continue
+		}
+		if steps[i].cleanup != "" {
+			os.Remove(steps[i].cleanup)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	// Expect all staged operations to be applied and no backups left behind
+	t.Run("commit", func(t *testing.T) {
+		root := "transaction_1"
+		defer os.RemoveAll(root)
+		os.Mkdir(root, 0755)
+		os.WriteFile(filepath.Join(root, "a.txt"), []byte("old a"), 0644)
+		os.WriteFile(filepath.Join(root, "b.txt"), []byte("b"), 0644)
+		os.WriteFile(filepath.Join(root, "c.txt"), []byte("c"), 0644)
+
+		err := NewTransaction().
+			Write(filepath.Join(root, "a.txt"), []byte("new a")).
+			Write(filepath.Join(root, "d.txt"), []byte("d")).
+			Rename(filepath.Join(root, "b.txt"), filepath.Join(root, "e.txt")).
+			Delete(filepath.Join(root, "c.txt")).
+			Commit()
+		if err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		if content, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(content) != "new a" {
+			t.Errorf("Expected new a, got %q", content)
+		}
+		if content, _ := os.ReadFile(filepath.Join(root, "e.txt")); string(content) != "b" {
+			t.Errorf("Expected b in e.txt, got %q", content)
+		}
+		entries, _ := os.ReadDir(root)
+		if len(entries) != 3 {
+			t.Errorf("Expected a.txt, d.txt and e.txt, got %v", entries)
+		}
+	})
+
+	// Expect a failing operation to restore the previous state
+	t.Run("failure restores", func(t *testing.T) {
+		root := "transaction_2"
+		defer os.RemoveAll(root)
+		os.Mkdir(root, 0755)
+		os.WriteFile(filepath.Join(root, "a.txt"), []byte("old a"), 0644)
+		os.WriteFile(filepath.Join(root, "b.txt"), []byte("b"), 0644)
+
+		err := NewTransaction().
+			Write(filepath.Join(root, "a.txt"), []byte("new a")).
+			Delete(filepath.Join(root, "b.txt")).
+			Rename(filepath.Join(root, "missing"), filepath.Join(root, "x")).
+			Commit()
+		if !errors.Is(err, ErrNotExist) {
+			t.Fatalf("Expected ErrNotExist, got %v", err)
+		}
+
+		if content, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(content) != "old a" {
+			t.Errorf("Expected old a, got %q", content)
+		}
+		if content, _ := os.ReadFile(filepath.Join(root, "b.txt")); string(content) != "b" {
+			t.Errorf("Expected b.txt to be restored, got %q", content)
+		}
+		entries, _ := os.ReadDir(root)
+		if len(entries) != 2 {
+			t.Errorf("Expected only a.txt and b.txt, got %v", entries)
+		}
+	})
+
+	// Expect a failing staged write to change nothing
+	t.Run("stage failure", func(t *testing.T) {
+		root := "transaction_3"
+		defer os.RemoveAll(root)
+		os.Mkdir(root, 0755)
+		os.WriteFile(filepath.Join(root, "a.txt"), []byte("a"), 0644)
+
+		err := NewTransaction().
+			Delete(filepath.Join(root, "a.txt")).
+			Write(filepath.Join(root, "missing", "b.txt"), []byte("b")).
+			Commit()
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+
+		if _, err := os.Stat(filepath.Join(root, "a.txt")); err != nil {
+			t.Errorf("Expected a.txt to remain, got %v", err)
+		}
+	})
+
+	// Expect a transaction to be usable only once
+	t.Run("done", func(t *testing.T) {
+		tx := NewTransaction()
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback failed: %v", err)
+		}
+		if err := tx.Commit(); !errors.Is(err, ErrTransactionDone) {
+			t.Errorf("Expected ErrTransactionDone, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TrashItem is a file or directory that was moved to the trash.
+type TrashItem struct {
+	OriginalPath string    // Absolute path the item was trashed from, if known
+	TrashPath    string    // Current location of the item inside the trash, if known
+	DeletedAt    time.Time // When the item was trashed, if known
+}
+
+// Trash moves a file or directory to the trash of the current user instead of deleting it.
+//
+// On Linux and other Unix systems the FreeDesktop.org trash specification is followed,
+// using the home trash in $XDG_DATA_HOME/Trash, or for files on other file systems a trash
+// at the top of their file system, "$topdir/.Trash/$uid" or "$topdir/.Trash-$uid".
+// On macOS items are moved to ~/.Trash. On 64-bit Windows items are sent to the Recycle
+// Bin. Other platforms are not supported.
+func Trash(path string) (TrashItem, error) {
+	path = normalizePath(path)
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return TrashItem{}, fmt.Errorf("Trash failed to resolve path: %w", err)
+	}
+	err = checkWritable("Trash", abs)
+	if err != nil {
+		return TrashItem{}, err
+	}
+	if dryRunSkip("Trash", abs, "") {
+		return TrashItem{OriginalPath: abs, DeletedAt: time.Now()}, nil
+	}
+
+	item, err := trash(abs)
+	if err != nil {
+		return TrashItem{}, fmt.Errorf("Trash failed to trash %s: %w", path, err)
+	}
+
+	return item, nil
+}
+
+// ListTrash returns the items in the trash of the current user. On Unix systems other than
+// macOS, only the home trash is listed. Not supported on Windows. On macOS the original
+// paths are unknown, since Finder keeps them in a private format.
+func ListTrash() ([]TrashItem, error) {
+	items, err := listTrash()
+	if err != nil {
+		return nil, fmt.Errorf("ListTrash failed to list trash: %w", err)
+	}
+
+	return items, nil
+}
+
+// Restore moves a trashed item back to its original path.
+// It fails if something already exists at the original path.
+// Not supported on Windows.
+func Restore(item TrashItem) error {
+	if item.OriginalPath == "" || item.TrashPath == "" {
+		return fmt.Errorf("Restore failed: original and trash paths of the item must be known")
+	}
+	item.TrashPath, item.OriginalPath = normalizePath(item.TrashPath), normalizePath(item.OriginalPath)
+	if skip, err := writeGuard("Restore", item.TrashPath, item.OriginalPath); skip {
+		return err
+	}
+	defer invalidateStats(item.TrashPath, item.OriginalPath)
+
+	exists, err := Exists(item.OriginalPath)
+	if err != nil {
+		return fmt.Errorf("Restore failed to check original path: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("Restore failed: %s already exists", item.OriginalPath)
+	}
+
+	err = EnsureDir(filepath.Dir(item.OriginalPath))
+	if err != nil {
+		return fmt.Errorf("Restore failed to ensure parent directory: %w", err)
+	}
+
+	err = restore(item)
+	if err != nil {
+		return fmt.Errorf("Restore failed to restore %s: %w", item.OriginalPath, err)
+	}
+
+	return nil
+}
+
+// EmptyTrash permanently deletes everything in the trash of the current user. On Unix
+// systems other than macOS, only the home trash is emptied.
+func EmptyTrash() error {
+	if skip, err := writeGuard("EmptyTrash", "", ""); skip {
+		return err
+	}
+
+	err := emptyTrash()
+	if err != nil {
+		return fmt.Errorf("EmptyTrash failed to empty trash: %w", err)
+	}
+
+	return nil
+}
+
+// removeContents removes everything inside a directory, but not the directory itself.
+// A missing directory is considered empty.
+func removeContents(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		err = os.RemoveAll(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// macTrashDir returns the trash directory of the current user.
+func macTrashDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".Trash"), nil
+}
+
+func trash(path string) (TrashItem, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return TrashItem{}, err
+	}
+
+	dir, err := macTrashDir()
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	// Mimic Finder by appending a counter before the extension on collisions
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(filepath.Base(path), ext)
+	trashPath := filepath.Join(dir, stem+ext)
+	for i := 2; ; i++ {
+		_, err = os.Lstat(trashPath)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return TrashItem{}, err
+		}
+		trashPath = filepath.Join(dir, stem+" "+strconv.Itoa(i)+ext)
+	}
+
+	err = Move(path, trashPath)
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	return TrashItem{OriginalPath: path, TrashPath: trashPath, DeletedAt: time.Now()}, nil
+}
+
+func listTrash() ([]TrashItem, error) {
+	dir, err := macTrashDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []TrashItem
+	for _, entry := range entries {
+		if entry.Name() == ".DS_Store" {
+			continue
+		}
+		items = append(items, TrashItem{TrashPath: filepath.Join(dir, entry.Name())})
+	}
+
+	return items, nil
+}
+
+func restore(item TrashItem) error {
+	return Move(item.TrashPath, item.OriginalPath)
+}
+
+func emptyTrash() error {
+	dir, err := macTrashDir()
+	if err != nil {
+		return err
+	}
+
+	return removeContents(dir)
+}
@@ -0,0 +1,21 @@
+//go:build !unix && !(windows && (amd64 || arm64))
+
+package fs_go
+
+import "errors"
+
+func trash(path string) (TrashItem, error) {
+	return TrashItem{}, errors.ErrUnsupported
+}
+
+func listTrash() ([]TrashItem, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func restore(item TrashItem) error {
+	return errors.ErrUnsupported
+}
+
+func emptyTrash() error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,165 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTrash(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("only the XDG trash can be redirected for tests")
+	}
+
+	// Expect a trashed file to be listed, restored, and the trash to be emptied
+	t.Run("trash and restore", func(t *testing.T) {
+		path := "trash_1"
+		defer os.RemoveAll(path)
+
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			t.Fatalf("filepath.Abs failed: %v", err)
+		}
+		t.Setenv("XDG_DATA_HOME", filepath.Join(abs, "data"))
+
+		file := filepath.Join(path, "file with spaces.txt")
+		writeTree(t, path, map[string]string{"file with spaces.txt": "test content"})
+
+		item, err := Trash(file)
+		if err != nil {
+			t.Fatalf("Trash failed: %v", err)
+		}
+
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("Expected file to be moved to the trash")
+		}
+
+		items, err := ListTrash()
+		if err != nil {
+			t.Errorf("ListTrash failed: %v", err)
+		}
+
+		if len(items) != 1 || items[0].OriginalPath != filepath.Join(abs, "file with spaces.txt") || items[0].TrashPath != item.TrashPath {
+			t.Errorf("Expected trashed file to be listed, got %+v", items)
+		}
+
+		err = Restore(items[0])
+		if err != nil {
+			t.Errorf("Restore failed: %v", err)
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "test content" {
+			t.Errorf("Expected content to be 'test content', got '%s'", content)
+		}
+
+		items, err = ListTrash()
+		if err != nil {
+			t.Errorf("ListTrash failed: %v", err)
+		}
+
+		if len(items) != 0 {
+			t.Errorf("Expected trash to be empty after restore, got %+v", items)
+		}
+	})
+
+	// Expect files with the same name to get unique trash names, and EmptyTrash to remove them
+	t.Run("name collisions", func(t *testing.T) {
+		path := "trash_2"
+		defer os.RemoveAll(path)
+
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			t.Fatalf("filepath.Abs failed: %v", err)
+		}
+		t.Setenv("XDG_DATA_HOME", filepath.Join(abs, "data"))
+
+		for _, dir := range []string{"a", "b"} {
+			writeTree(t, path, map[string]string{filepath.Join(dir, "file.txt"): dir})
+			if _, err := Trash(filepath.Join(path, dir, "file.txt")); err != nil {
+				t.Fatalf("Trash failed: %v", err)
+			}
+		}
+
+		items, err := ListTrash()
+		if err != nil {
+			t.Errorf("ListTrash failed: %v", err)
+		}
+
+		if len(items) != 2 || items[0].TrashPath == items[1].TrashPath {
+			t.Errorf("Expected two distinct trashed files, got %+v", items)
+		}
+
+		err = EmptyTrash()
+		if err != nil {
+			t.Errorf("EmptyTrash failed: %v", err)
+		}
+
+		items, err = ListTrash()
+		if err != nil {
+			t.Errorf("ListTrash failed: %v", err)
+		}
+
+		if len(items) != 0 {
+			t.Errorf("Expected trash to be empty, got %+v", items)
+		}
+	})
+
+	// Expect a directory on another file system to go to the trash at the top of it
+	t.Run("other file system", func(t *testing.T) {
+		path := "trash_3"
+		defer os.RemoveAll(path)
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			t.Fatalf("filepath.Abs failed: %v", err)
+		}
+		// The home trash doesn't exist yet, as on a fresh account
+		t.Setenv("XDG_DATA_HOME", filepath.Join(abs, "data", "share"))
+		os.MkdirAll(abs, 0755)
+
+		// /dev/shm is a separate tmpfs on most Linux systems
+		topdir := "/dev/shm"
+		same, err := IsOnSameFilesystem(topdir, abs)
+		if err != nil || same {
+			t.Skip("no other file system to trash from")
+		}
+		trashDir := filepath.Join(topdir, ".Trash-"+strconv.Itoa(os.Getuid()))
+		if _, err := os.Lstat(trashDir); err == nil {
+			t.Skipf("%s is already in use", trashDir)
+		}
+		defer os.RemoveAll(trashDir)
+
+		dir, err := os.MkdirTemp(topdir, "trash_3_*")
+		if err != nil {
+			t.Skipf("os.MkdirTemp failed: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		writeTree(t, dir, map[string]string{"sub/file.txt": "content"})
+
+		item, err := Trash(filepath.Join(dir, "sub"))
+		if err != nil {
+			t.Fatalf("Trash failed: %v", err)
+		}
+		if filepath.Dir(filepath.Dir(item.TrashPath)) != trashDir {
+			t.Errorf("Expected the item in %s, got %s", trashDir, item.TrashPath)
+		}
+		info, err := os.ReadFile(filepath.Join(trashDir, "info", "sub.trashinfo"))
+		if err != nil || !strings.Contains(string(info), "Path="+filepath.Base(dir)+"/sub\n") {
+			t.Errorf("Expected a path relative to %s, got %q (%v)", topdir, info, err)
+		}
+
+		if err := Restore(item); err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if content, err := os.ReadFile(filepath.Join(dir, "sub", "file.txt")); err != nil || string(content) != "content" {
+			t.Errorf("Expected the directory to be restored, got %q (%v)", content, err)
+		}
+	})
+}
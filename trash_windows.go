@@ -0,0 +1,86 @@
+//go:build windows && (amd64 || arm64)
+
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	shell32                = windows.NewLazySystemDLL("shell32.dll")
+	procSHFileOperationW   = shell32.NewProc("SHFileOperationW")
+	procSHEmptyRecycleBinW = shell32.NewProc("SHEmptyRecycleBinW")
+)
+
+const (
+	foDelete          = 0x3
+	fofSilent         = 0x4
+	fofNoConfirmation = 0x10
+	fofAllowUndo      = 0x40
+	fofNoErrorUI      = 0x400
+
+	sherbNoConfirmation = 0x1
+	sherbNoProgressUI   = 0x2
+	sherbNoSound        = 0x4
+
+	// SHEmptyRecycleBinW reports E_UNEXPECTED when the Recycle Bin is already empty
+	eUnexpected = 0x8000FFFF
+)
+
+// shFileOpStruct mirrors SHFILEOPSTRUCTW, which is naturally aligned on 64-bit Windows.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+func trash(path string) (TrashItem, error) {
+	// pFrom is a list of paths terminated by an additional null character
+	from, err := windows.UTF16FromString(path)
+	if err != nil {
+		return TrashItem{}, err
+	}
+	from = append(from, 0)
+
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &from[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofSilent | fofNoErrorUI,
+	}
+	ret, _, _ := procSHFileOperationW.Call(uintptr(unsafe.Pointer(&op)))
+	if ret != 0 {
+		return TrashItem{}, fmt.Errorf("SHFileOperation failed with code %#x", ret)
+	}
+	if op.fAnyOperationsAborted != 0 {
+		return TrashItem{}, errors.New("SHFileOperation was aborted")
+	}
+
+	return TrashItem{OriginalPath: path, DeletedAt: time.Now()}, nil
+}
+
+func listTrash() ([]TrashItem, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func restore(item TrashItem) error {
+	return errors.ErrUnsupported
+}
+
+func emptyTrash() error {
+	ret, _, _ := procSHEmptyRecycleBinW.Call(0, 0, sherbNoConfirmation|sherbNoProgressUI|sherbNoSound)
+	if ret != 0 && ret != eUnexpected {
+		return fmt.Errorf("SHEmptyRecycleBin failed with code %#x", ret)
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+//go:build unix && !darwin
+
+package fs_go
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	trashInfoExt        = ".trashinfo"
+	trashDeletionLayout = "2006-01-02T15:04:05"
+)
+
+// xdgTrashDir returns the home trash directory as defined by the FreeDesktop.org trash specification.
+func xdgTrashDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dataHome, "Trash"), nil
+}
+
+// topTrashDir returns the trash directory for files below topdir, the top of a file system
+// other than the one of the home trash: "$topdir/.Trash/$uid" if an administrator set up a
+// shared trash, and otherwise "$topdir/.Trash-$uid", which is created if needed.
+func topTrashDir(topdir string) (string, error) {
+	uid := strconv.Itoa(os.Getuid())
+
+	// The shared trash must be a sticky directory, not a symlink, so users can't remove
+	// each other's trash
+	shared := filepath.Join(topdir, ".Trash")
+	if info, err := os.Lstat(shared); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		dir := filepath.Join(shared, uid)
+		if EnsureDirWithMode(dir, 0700) == nil {
+			return dir, nil
+		}
+	}
+
+	dir := filepath.Join(topdir, ".Trash-"+uid)
+	err := EnsureDirWithMode(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); !info.IsDir() || !ok || int(stat.Uid) != os.Getuid() {
+		return "", fmt.Errorf("%s is not a directory owned by the current user", dir)
+	}
+
+	return dir, nil
+}
+
+// mountPoint returns the top directory of the file system dir is on.
+func mountPoint(dir string) (string, error) {
+	id, err := fileID(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, nil
+		}
+
+		parentID, err := fileID(parent)
+		if err != nil {
+			return "", err
+		}
+		if parentID.Device != id.Device {
+			return dir, nil
+		}
+		dir = parent
+	}
+}
+
+// trashDirFor returns the trash directory for an absolute path, and the directory the paths
+// in its info files are relative to, which is empty for the home trash. Files on another
+// file system than the home trash go to a trash at the top of their own file system, as the
+// specification requires, so trashing them never copies them.
+func trashDirFor(path string) (string, string, error) {
+	home, err := xdgTrashDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	// The home trash may not exist yet on a fresh account, so compare with its closest
+	// existing parent, where it will be created
+	parent := filepath.Dir(path)
+	same, err := IsOnSameFilesystem(parent, closestExisting(home))
+	if err != nil {
+		return "", "", err
+	}
+	if same {
+		return home, "", nil
+	}
+
+	topdir, err := mountPoint(parent)
+	if err != nil {
+		return "", "", err
+	}
+	dir, err := topTrashDir(topdir)
+	if err != nil {
+		return "", "", err
+	}
+
+	return dir, topdir, nil
+}
+
+func trash(path string) (TrashItem, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return TrashItem{}, err
+	}
+
+	dir, topdir, err := trashDirFor(path)
+	if err != nil {
+		return TrashItem{}, err
+	}
+	// Paths in the trash of a file system are relative to its top, so it can be mounted elsewhere
+	infoPath := path
+	if topdir != "" {
+		infoPath, err = filepath.Rel(topdir, path)
+		if err != nil {
+			return TrashItem{}, err
+		}
+	}
+	filesDir := filepath.Join(dir, "files")
+	infoDir := filepath.Join(dir, "info")
+	for _, d := range []string{filesDir, infoDir} {
+		err = EnsureDirWithMode(d, 0700)
+		if err != nil {
+			return TrashItem{}, err
+		}
+	}
+
+	// Claim a unique name by exclusively creating its info file, as the specification requires
+	now := time.Now()
+	base := filepath.Base(path)
+	name := base
+	var info *os.File
+	for i := 2; ; i++ {
+		info, err = os.OpenFile(filepath.Join(infoDir, name+trashInfoExt), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+		name = base + "." + strconv.Itoa(i)
+	}
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	content := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n", (&url.URL{Path: infoPath}).EscapedPath(), now.Format(trashDeletionLayout))
+	_, err = info.WriteString(content)
+	closeErr := info.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(info.Name())
+		return TrashItem{}, err
+	}
+
+	trashPath := filepath.Join(filesDir, name)
+	err = Move(path, trashPath)
+	if err != nil {
+		os.Remove(info.Name())
+		return TrashItem{}, err
+	}
+
+	return TrashItem{OriginalPath: path, TrashPath: trashPath, DeletedAt: now.Truncate(time.Second)}, nil
+}
+
+func listTrash() ([]TrashItem, error) {
+	dir, err := xdgTrashDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "info"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []TrashItem
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), trashInfoExt) {
+			continue
+		}
+
+		item, err := readTrashInfo(filepath.Join(dir, "info", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		item.TrashPath = filepath.Join(dir, "files", strings.TrimSuffix(entry.Name(), trashInfoExt))
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// readTrashInfo parses a .trashinfo file.
+func readTrashInfo(path string) (TrashItem, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return TrashItem{}, err
+	}
+	defer file.Close()
+
+	var item TrashItem
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Path":
+			original, err := url.PathUnescape(value)
+			if err != nil {
+				return TrashItem{}, fmt.Errorf("invalid path in %s: %w", path, err)
+			}
+			item.OriginalPath = original
+		case "DeletionDate":
+			deleted, err := time.ParseInLocation(trashDeletionLayout, value, time.Local)
+			if err == nil {
+				item.DeletedAt = deleted
+			}
+		}
+	}
+
+	return item, scanner.Err()
+}
+
+func restore(item TrashItem) error {
+	err := Move(item.TrashPath, item.OriginalPath)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(item.TrashPath)
+	info := filepath.Join(filepath.Dir(filepath.Dir(item.TrashPath)), "info", name+trashInfoExt)
+	err = os.Remove(info)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func emptyTrash() error {
+	dir, err := xdgTrashDir()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range []string{"files", "info"} {
+		err = removeContents(filepath.Join(dir, sub))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
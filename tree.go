@@ -0,0 +1,193 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TreeFile is a file in a CreateTree spec with an explicit mode.
+type TreeFile struct {
+	Content string
+	Mode    os.FileMode
+}
+
+// CreateTree creates a directory tree under root from a declarative spec, which is handy
+// for project generators and test fixtures. Existing files in the way are overwritten.
+//
+// Keys are names relative to their parent and may contain slashes to create intermediate
+// directories. Values describe the entry:
+//
+//   - a string or []byte is a file with that content and mode 0644
+//   - a TreeFile is a file with a mode
+//   - a map[string]any is a directory with those entries, and nil is an empty directory
+//
+// In directory maps, the reserved key "$mode" sets the mode of the directory, and a map
+// with a "$content" key is a file instead, optionally with a "$mode". Modes are numbers,
+// or strings in octal like "0755". This lets the spec come from JSON or YAML, see
+// CreateTreeFromFile.
+//
+// Example:
+//
+//	err := CreateTree("project", map[string]any{
+//	    "go.mod":      "module example.com/project\n",
+//	    "cmd/main.go": "package main\n",
+//	    "scripts": map[string]any{
+//	        "build.sh": TreeFile{Content: "#!/bin/sh\n", Mode: 0755},
+//	    },
+//	    "tmp": nil,
+//	})
+func CreateTree(root string, spec map[string]any) error {
+	err := EnsureDir(root)
+	if err != nil {
+		return fmt.Errorf("CreateTree failed to ensure root: %w", err)
+	}
+
+	err = createTree(root, spec)
+	if err != nil {
+		return fmt.Errorf("CreateTree failed: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTreeFromFile creates a directory tree under root from a JSON or YAML spec file,
+// following the rules of CreateTree.
+//
+// Example spec:
+//
+//	go.mod: "module example.com/project\n"
+//	scripts:
+//	  build.sh:
+//	    $content: "#!/bin/sh\n"
+//	    $mode: "0755"
+func CreateTreeFromFile(root, specPath string) error {
+	var spec map[string]any
+	err := ReadAuto(specPath, &spec)
+	if err != nil {
+		return fmt.Errorf("CreateTreeFromFile failed to read spec: %w", err)
+	}
+
+	err = CreateTree(root, spec)
+	if err != nil {
+		return fmt.Errorf("CreateTreeFromFile failed: %w", err)
+	}
+
+	return nil
+}
+
+// createTree creates the entries of a directory spec inside dir.
+func createTree(dir string, spec map[string]any) error {
+	names := make([]string, 0, len(spec))
+	for name := range spec {
+		if name != "$mode" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if rel, err := filepath.Rel(dir, path); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%q escapes its directory", name)
+		}
+
+		err := EnsureDir(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+
+		err = createTreeEntry(path, spec[name])
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// createTreeEntry creates a single file or directory from its spec.
+func createTreeEntry(path string, entry any) error {
+	switch entry := entry.(type) {
+	case string:
+		return writeTreeFile(path, []byte(entry), 0644)
+	case []byte:
+		return writeTreeFile(path, entry, 0644)
+	case TreeFile:
+		mode := entry.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		return writeTreeFile(path, []byte(entry.Content), mode)
+	case nil:
+		return EnsureDir(path)
+	case map[string]any:
+		if content, ok := entry["$content"]; ok {
+			text, ok := content.(string)
+			if !ok {
+				return fmt.Errorf("$content must be a string, got %T", content)
+			}
+			mode, err := treeMode(entry["$mode"], 0644)
+			if err != nil {
+				return err
+			}
+			return writeTreeFile(path, []byte(text), mode)
+		}
+
+		mode, err := treeMode(entry["$mode"], 0755)
+		if err != nil {
+			return err
+		}
+		err = EnsureDir(path)
+		if err != nil {
+			return err
+		}
+		err = createTree(path, entry)
+		if err != nil {
+			return err
+		}
+		// Set the mode last, in case it doesn't allow creating the entries
+		return applyMeta(path, FileMeta{Mode: mode}, true, false, false)
+	default:
+		return fmt.Errorf("unsupported spec type %T", entry)
+	}
+}
+
+// writeTreeFile writes a file and sets its mode exactly, regardless of the umask.
+func writeTreeFile(path string, content []byte, mode os.FileMode) error {
+	err := WriteBytesWithMode(path, content, mode)
+	if err != nil {
+		return err
+	}
+
+	return applyMeta(path, FileMeta{Mode: mode}, true, false, false)
+}
+
+// treeMode parses a mode from a spec, which is a number or an octal string.
+func treeMode(value any, fallback os.FileMode) (os.FileMode, error) {
+	switch value := value.(type) {
+	case nil:
+		return fallback, nil
+	case int:
+		return os.FileMode(value), nil
+	case int64:
+		return os.FileMode(value), nil
+	case uint64:
+		return os.FileMode(value), nil
+	case float64:
+		return os.FileMode(value), nil
+	case os.FileMode:
+		return value, nil
+	case string:
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid mode %q: %w", value, err)
+		}
+		return os.FileMode(mode), nil
+	default:
+		return 0, fmt.Errorf("invalid mode of type %T", value)
+	}
+}
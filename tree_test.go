@@ -0,0 +1,121 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCreateTree(t *testing.T) {
+	// Expect files, nested directories and modes to be created from the spec
+	t.Run("create", func(t *testing.T) {
+		path := "create_tree_1"
+		defer os.RemoveAll(path)
+
+		err := CreateTree(path, map[string]any{
+			"go.mod":      "module example.com/project\n",
+			"cmd/main.go": "package main\n",
+			"scripts": map[string]any{
+				"build.sh": TreeFile{Content: "#!/bin/sh\n", Mode: 0755},
+			},
+			"tmp": nil,
+		})
+		if err != nil {
+			t.Errorf("CreateTree failed: %v", err)
+		}
+
+		for name, expected := range map[string]string{
+			"go.mod":           "module example.com/project\n",
+			"cmd/main.go":      "package main\n",
+			"scripts/build.sh": "#!/bin/sh\n",
+		} {
+			content, err := os.ReadFile(filepath.Join(path, name))
+			if err != nil {
+				t.Errorf("os.ReadFile failed: %v", err)
+			}
+
+			if string(content) != expected {
+				t.Errorf("Expected %s to contain %q, got %q", name, expected, content)
+			}
+		}
+
+		info, err := os.Stat(filepath.Join(path, "tmp"))
+		if err != nil || !info.IsDir() {
+			t.Errorf("Expected tmp to be a directory: %v", err)
+		}
+
+		info, err = os.Stat(filepath.Join(path, "scripts", "build.sh"))
+		if err != nil {
+			t.Fatalf("os.Stat failed: %v", err)
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0755 {
+			t.Errorf("Expected build.sh mode to be 0755, got %#o", info.Mode().Perm())
+		}
+	})
+
+	// Expect names that escape their directory to be rejected
+	t.Run("escape", func(t *testing.T) {
+		path := "create_tree_2"
+		defer os.RemoveAll(path)
+
+		err := CreateTree(path, map[string]any{"../outside.txt": "content"})
+		if err == nil {
+			t.Errorf("Expected CreateTree to fail")
+		}
+
+		if _, err := os.Stat("outside.txt"); !os.IsNotExist(err) {
+			t.Errorf("Expected file outside the root to not be created")
+		}
+	})
+}
+
+func TestCreateTreeFromFile(t *testing.T) {
+	// Expect a YAML spec with reserved keys to be applied
+	t.Run("yaml", func(t *testing.T) {
+		path := "create_tree_from_file_1"
+		spec := "create_tree_from_file_1.yaml"
+		defer os.RemoveAll(path)
+		defer os.Remove(spec)
+
+		content := "readme.md: \"# Project\\n\"\n" +
+			"private:\n" +
+			"  $mode: \"0700\"\n" +
+			"  key.pem:\n" +
+			"    $content: secret\n" +
+			"    $mode: \"0600\"\n"
+		if err := os.WriteFile(spec, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := CreateTreeFromFile(path, spec)
+		if err != nil {
+			t.Errorf("CreateTreeFromFile failed: %v", err)
+		}
+
+		read, err := os.ReadFile(filepath.Join(path, "private", "key.pem"))
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(read) != "secret" {
+			t.Errorf("Expected key.pem to contain %q, got %q", "secret", read)
+		}
+
+		if runtime.GOOS == "windows" {
+			return
+		}
+
+		for name, expected := range map[string]os.FileMode{"private": 0700, "private/key.pem": 0600} {
+			info, err := os.Stat(filepath.Join(path, name))
+			if err != nil {
+				t.Fatalf("os.Stat failed: %v", err)
+			}
+
+			if info.Mode().Perm() != expected {
+				t.Errorf("Expected %s mode to be %#o, got %#o", name, expected, info.Mode().Perm())
+			}
+		}
+	})
+}
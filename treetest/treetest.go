@@ -0,0 +1,208 @@
+// Package treetest provides helpers for testing code that writes directory trees.
+//
+// A tree is captured as a Manifest with Snapshot, and two manifests are compared with
+// AssertTreeEqual, which reports missing, extra and changed entries one per line.
+//
+// Example:
+//
+//	func TestGenerate(t *testing.T) {
+//	    err := Generate("out")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//
+//	    treetest.AssertTreeEqual(t, treetest.MustSnapshot(t, "testdata/expected"), treetest.MustSnapshot(t, "out"))
+//	}
+package treetest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Entry is a single file, directory or symlink in a Manifest.
+type Entry struct {
+	Path string      // Slash-separated path relative to the snapshot root
+	Mode fs.FileMode // Type and permission bits
+	Hash string      // Hex-encoded SHA-256 of the content for files, "-> target" for symlinks, empty for directories
+}
+
+// String formats the entry as a single manifest line.
+func (e Entry) String() string {
+	hash := e.Hash
+	if hash == "" {
+		hash = "-"
+	}
+
+	return fmt.Sprintf("%s %s %s", e.Mode, hash, e.Path)
+}
+
+// Manifest is a canonical description of a directory tree, sorted by path.
+type Manifest []Entry
+
+// String formats the manifest with one entry per line.
+func (m Manifest) String() string {
+	var builder strings.Builder
+	for _, entry := range m {
+		builder.WriteString(entry.String())
+		builder.WriteByte('\n')
+	}
+
+	return builder.String()
+}
+
+// Snapshot walks dir and returns a manifest of everything below it.
+// The root itself is not included. Symlinks are recorded, not followed.
+//
+// Example:
+//
+//	manifest, err := treetest.Snapshot("out")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Print(manifest)
+func Snapshot(dir string) (Manifest, error) {
+	var manifest Manifest
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		entry := Entry{Path: filepath.ToSlash(rel), Mode: info.Mode()}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			entry.Hash = "-> " + filepath.ToSlash(target)
+		case info.Mode().IsRegular():
+			entry.Hash, err = hashFile(path)
+			if err != nil {
+				return err
+			}
+		}
+		manifest = append(manifest, entry)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Snapshot failed to walk %s: %w", dir, err)
+	}
+
+	sort.Slice(manifest, func(i, j int) bool {
+		return manifest[i].Path < manifest[j].Path
+	})
+
+	return manifest, nil
+}
+
+// MustSnapshot is like Snapshot, but fails the test if the tree can't be read.
+func MustSnapshot(t testing.TB, dir string) Manifest {
+	t.Helper()
+
+	manifest, err := Snapshot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return manifest
+}
+
+// AssertTreeEqual fails the test if the manifests differ, listing every difference.
+// Missing entries are prefixed with "-", extra entries with "+" and changed entries with "~".
+//
+// Example:
+//
+//	treetest.AssertTreeEqual(t, want, treetest.MustSnapshot(t, "out"))
+func AssertTreeEqual(t testing.TB, want, got Manifest) {
+	t.Helper()
+
+	diff := Diff(want, got)
+	if len(diff) > 0 {
+		t.Errorf("Trees differ:\n%s", strings.Join(diff, "\n"))
+	}
+}
+
+// Diff returns the differences between two manifests, one line per entry, in path order.
+// It returns nil if the manifests are equal.
+func Diff(want, got Manifest) []string {
+	wanted := make(map[string]Entry, len(want))
+	paths := make([]string, 0, len(want)+len(got))
+	for _, entry := range want {
+		wanted[entry.Path] = entry
+		paths = append(paths, entry.Path)
+	}
+	gotten := make(map[string]Entry, len(got))
+	for _, entry := range got {
+		gotten[entry.Path] = entry
+		if _, ok := wanted[entry.Path]; !ok {
+			paths = append(paths, entry.Path)
+		}
+	}
+	sort.Strings(paths)
+
+	var diff []string
+	for _, path := range paths {
+		entry, inWant := wanted[path]
+		other, inGot := gotten[path]
+		switch {
+		case !inGot:
+			diff = append(diff, "- "+entry.String())
+		case !inWant:
+			diff = append(diff, "+ "+other.String())
+		default:
+			var changes []string
+			if entry.Mode != other.Mode {
+				changes = append(changes, fmt.Sprintf("mode %s != %s", entry.Mode, other.Mode))
+			}
+			if entry.Hash != other.Hash {
+				changes = append(changes, "content differs")
+			}
+			if len(changes) > 0 {
+				diff = append(diff, fmt.Sprintf("~ %s: %s", path, strings.Join(changes, ", ")))
+			}
+		}
+	}
+
+	return diff
+}
+
+// hashFile returns the hex-encoded SHA-256 digest of a file's content.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
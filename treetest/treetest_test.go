@@ -0,0 +1,132 @@
+package treetest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// recorder captures test failures so assertions can be tested themselves.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	// Expect a sorted manifest of files and directories, excluding the root
+	t.Run("manifest", func(t *testing.T) {
+		path := "snapshot_1"
+		defer os.RemoveAll(path)
+		writeFiles(t, path, map[string]string{"b.txt": "b", "a/c.txt": "c"})
+
+		manifest, err := Snapshot(path)
+		if err != nil {
+			t.Fatalf("Snapshot failed: %v", err)
+		}
+
+		var paths []string
+		for _, entry := range manifest {
+			paths = append(paths, entry.Path)
+		}
+		if strings.Join(paths, ",") != "a,a/c.txt,b.txt" {
+			t.Errorf("Expected paths a,a/c.txt,b.txt, got %v", paths)
+		}
+
+		if !manifest[0].Mode.IsDir() || manifest[0].Hash != "" {
+			t.Errorf("Expected a to be a directory without hash, got %s", manifest[0])
+		}
+
+		// SHA-256 of "b"
+		expected := "3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d"
+		if manifest[2].Hash != expected {
+			t.Errorf("Expected hash %s, got %s", expected, manifest[2].Hash)
+		}
+	})
+
+	// Expect an error for a missing directory
+	t.Run("missing", func(t *testing.T) {
+		_, err := Snapshot("snapshot_2")
+		if err == nil {
+			t.Errorf("Expected Snapshot to fail")
+		}
+	})
+}
+
+func TestAssertTreeEqual(t *testing.T) {
+	// Expect identical trees to pass
+	t.Run("equal", func(t *testing.T) {
+		want := "assert_tree_equal_1"
+		got := "assert_tree_equal_2"
+		defer os.RemoveAll(want)
+		defer os.RemoveAll(got)
+		files := map[string]string{"a.txt": "a", "dir/b.txt": "b"}
+		writeFiles(t, want, files)
+		writeFiles(t, got, files)
+
+		r := &recorder{TB: t}
+		AssertTreeEqual(r, MustSnapshot(t, want), MustSnapshot(t, got))
+		if len(r.errors) != 0 {
+			t.Errorf("Expected no failures, got %v", r.errors)
+		}
+	})
+
+	// Expect missing, extra and changed entries to be reported in path order
+	t.Run("diff", func(t *testing.T) {
+		want := "assert_tree_equal_3"
+		got := "assert_tree_equal_4"
+		defer os.RemoveAll(want)
+		defer os.RemoveAll(got)
+		writeFiles(t, want, map[string]string{"a.txt": "a", "b.txt": "b", "run.sh": "x"})
+		writeFiles(t, got, map[string]string{"b.txt": "changed", "c.txt": "c", "run.sh": "x"})
+		if runtime.GOOS != "windows" {
+			if err := os.Chmod(filepath.Join(got, "run.sh"), 0755); err != nil {
+				t.Fatalf("os.Chmod failed: %v", err)
+			}
+		}
+
+		diff := Diff(MustSnapshot(t, want), MustSnapshot(t, got))
+		expected := []string{"- ", "~ b.txt: content differs", "+ "}
+		if runtime.GOOS != "windows" {
+			expected = append(expected, "~ run.sh: mode -rw-r--r-- != -rwxr-xr-x")
+		}
+		if len(diff) != len(expected) {
+			t.Fatalf("Expected %d differences, got %v", len(expected), diff)
+		}
+		for i, prefix := range expected {
+			if !strings.HasPrefix(diff[i], prefix) {
+				t.Errorf("Expected difference %d to start with %q, got %q", i, prefix, diff[i])
+			}
+		}
+		if !strings.HasSuffix(diff[0], " a.txt") || !strings.HasSuffix(diff[2], " c.txt") {
+			t.Errorf("Expected a.txt to be missing and c.txt to be extra, got %v", diff)
+		}
+
+		r := &recorder{TB: t}
+		AssertTreeEqual(r, MustSnapshot(t, want), MustSnapshot(t, got))
+		if len(r.errors) != 1 {
+			t.Errorf("Expected one failure, got %v", r.errors)
+		}
+	})
+}
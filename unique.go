@@ -0,0 +1,103 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// UniquePathOptions configures UniquePathWithOptions.
+type UniquePathOptions struct {
+	// Pattern builds the candidate names after the original one is taken.
+	// {name} is replaced with the base name without extension, {n} with the attempt number,
+	// and {ext} with the extension including the dot. Defaults to "{name} ({n}){ext}".
+	Pattern string
+	// MaxAttempts is the number of numbered candidates tried before giving up. Defaults to 10000.
+	MaxAttempts int
+	// Mode is the file mode of the reserved file. Defaults to 0644.
+	Mode os.FileMode
+}
+
+// UniquePath reserves a path that doesn't exist yet by creating an empty file there.
+// If path is taken, "report (1).pdf", "report (2).pdf" and so on are tried.
+// The file is created exclusively, so concurrent callers never get the same path.
+//
+// Example:
+//
+//	path, err := UniquePath("downloads/report.pdf")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = WriteBytes(path, content)
+func UniquePath(path string) (string, error) {
+	return UniquePathWithOptions(path, UniquePathOptions{})
+}
+
+// UniquePathWithOptions reserves a path that doesn't exist yet, numbering candidates with
+// a custom pattern.
+//
+// Example:
+//
+//	path, err := UniquePathWithOptions("export.csv", UniquePathOptions{
+//	    Pattern: "{name}_{n}{ext}",
+//	})
+func UniquePathWithOptions(path string, opts UniquePathOptions) (string, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = "{name} ({n}){ext}"
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10000
+	}
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+	if !strings.Contains(opts.Pattern, "{n}") {
+		return "", fmt.Errorf("UniquePath failed: pattern %q doesn't contain {n}", opts.Pattern)
+	}
+	err := checkWritable("UniquePath", path)
+	if err != nil {
+		return "", err
+	}
+
+	dir, base := filepath.Split(path)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	if name == "" {
+		// Dotfiles like .env have no extension
+		name, ext = base, ""
+	}
+
+	for n := 0; n <= opts.MaxAttempts; n++ {
+		candidate := path
+		if n > 0 {
+			replacer := strings.NewReplacer("{name}", name, "{n}", strconv.Itoa(n), "{ext}", ext)
+			candidate = dir + replacer.Replace(opts.Pattern)
+		}
+
+		if IsDryRun() {
+			_, err := os.Lstat(normalizePath(candidate))
+			if errors.Is(err, os.ErrNotExist) {
+				dryRunSkip("UniquePath", candidate, "")
+				return candidate, nil
+			}
+			if err != nil {
+				return "", fmt.Errorf("UniquePath failed to check %s: %w", candidate, err)
+			}
+			continue
+		}
+
+		file, err := os.OpenFile(normalizePath(candidate), os.O_CREATE|os.O_EXCL|os.O_WRONLY, opts.Mode)
+		if err == nil {
+			return candidate, file.Close()
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("UniquePath failed to create %s: %w", candidate, err)
+		}
+	}
+
+	return "", fmt.Errorf("UniquePath failed: no free path for %s after %d attempts", path, opts.MaxAttempts)
+}
@@ -0,0 +1,122 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestUniquePath(t *testing.T) {
+	// Expect the original path to be used while it is free
+	t.Run("free path", func(t *testing.T) {
+		path := "unique_path_1"
+		defer os.RemoveAll(path)
+
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+
+		actual, err := UniquePath(filepath.Join(path, "report.pdf"))
+		if err != nil {
+			t.Errorf("UniquePath failed: %v", err)
+		}
+
+		if expected := filepath.Join(path, "report.pdf"); actual != expected {
+			t.Errorf("Expected %s, got %s", expected, actual)
+		}
+
+		if _, err := os.Stat(actual); err != nil {
+			t.Errorf("Expected file to be created: %v", err)
+		}
+	})
+
+	// Expect numbered names once the original is taken
+	t.Run("taken path", func(t *testing.T) {
+		path := "unique_path_2"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"report.pdf":     "taken",
+			"report (1).pdf": "taken",
+		})
+
+		actual, err := UniquePath(filepath.Join(path, "report.pdf"))
+		if err != nil {
+			t.Errorf("UniquePath failed: %v", err)
+		}
+
+		if expected := filepath.Join(path, "report (2).pdf"); actual != expected {
+			t.Errorf("Expected %s, got %s", expected, actual)
+		}
+	})
+
+	// Expect concurrent callers to get distinct paths
+	t.Run("concurrent", func(t *testing.T) {
+		path := "unique_path_3"
+		defer os.RemoveAll(path)
+
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		seen := make(map[string]bool)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				actual, err := UniquePath(filepath.Join(path, "file.txt"))
+				if err != nil {
+					t.Errorf("UniquePath failed: %v", err)
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if seen[actual] {
+					t.Errorf("Expected %s to be returned once", actual)
+				}
+				seen[actual] = true
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestUniquePathWithOptions(t *testing.T) {
+	// Expect a custom pattern to be used for numbered names
+	t.Run("custom pattern", func(t *testing.T) {
+		path := "unique_path_options_1"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{"export.csv": "taken"})
+
+		actual, err := UniquePathWithOptions(filepath.Join(path, "export.csv"), UniquePathOptions{
+			Pattern: "{name}_{n}{ext}",
+		})
+		if err != nil {
+			t.Errorf("UniquePathWithOptions failed: %v", err)
+		}
+
+		if expected := filepath.Join(path, "export_1.csv"); actual != expected {
+			t.Errorf("Expected %s, got %s", expected, actual)
+		}
+	})
+
+	// Expect an error once all attempts are taken
+	t.Run("max attempts", func(t *testing.T) {
+		path := "unique_path_options_2"
+		defer os.RemoveAll(path)
+
+		writeTree(t, path, map[string]string{
+			"a.txt":     "taken",
+			"a (1).txt": "taken",
+		})
+
+		_, err := UniquePathWithOptions(filepath.Join(path, "a.txt"), UniquePathOptions{MaxAttempts: 1})
+		if err == nil {
+			t.Errorf("Expected UniquePathWithOptions to fail")
+		}
+	})
+}
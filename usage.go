@@ -0,0 +1,216 @@
+package fs_go
+
+import (
+	"container/heap"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UsageOptions configures UsageWithOptions.
+type UsageOptions struct {
+	// TopN is the number of largest files to report. Defaults to 10.
+	TopN int
+	// Workers is the number of directories read concurrently. Defaults to 16.
+	Workers int
+}
+
+// UsageReport summarizes the disk usage of a directory tree. Sizes are apparent sizes,
+// and only regular files are counted. Paths are relative to the root.
+type UsageReport struct {
+	Size  int64 // Total size of all files
+	Files int   // Number of files
+	Dirs  int   // Number of directories, including the root
+	// DirSizes is the cumulative size of every directory, keyed by path, with "." for the root.
+	DirSizes map[string]int64
+	// Largest are the largest files, biggest first.
+	Largest []FileSize
+	// Extensions breaks files down by lowercase extension, such as ".log", with "" for
+	// files without one.
+	Extensions map[string]ExtensionUsage
+}
+
+// FileSize is a file and its size.
+type FileSize struct {
+	Path string
+	Size int64
+}
+
+// ExtensionUsage is the number and total size of files with an extension.
+type ExtensionUsage struct {
+	Files int
+	Size  int64
+}
+
+// Usage reports the disk usage of a directory tree, like du. See UsageWithOptions.
+//
+// Example:
+//
+//	report, err := Usage("/var/log")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, file := range report.Largest {
+//	    fmt.Println(file.Size, file.Path)
+//	}
+func Usage(root string) (UsageReport, error) {
+	return UsageWithOptions(root, UsageOptions{})
+}
+
+// UsageWithOptions reports the disk usage of a directory tree, reading directories
+// concurrently, which is much faster on big trees and network file systems.
+// Symlinks are not followed.
+func UsageWithOptions(root string, opts UsageOptions) (UsageReport, error) {
+	if opts.TopN <= 0 {
+		opts.TopN = 10
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 16
+	}
+	root = normalizePath(root)
+
+	report := UsageReport{
+		DirSizes:   map[string]int64{".": 0},
+		Extensions: make(map[string]ExtensionUsage),
+		Dirs:       1,
+	}
+	var largest fileSizeHeap
+	var mu sync.Mutex
+
+	err := walkParallel(root, opts.Workers, func(path string, entry fs.DirEntry) error {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			mu.Lock()
+			report.Dirs++
+			if _, ok := report.DirSizes[rel]; !ok {
+				report.DirSizes[rel] = 0
+			}
+			mu.Unlock()
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size := info.Size()
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+
+		mu.Lock()
+		defer mu.Unlock()
+		report.Size += size
+		report.Files++
+		usage := report.Extensions[ext]
+		report.Extensions[ext] = ExtensionUsage{Files: usage.Files + 1, Size: usage.Size + size}
+		for dir := filepath.Dir(rel); ; dir = filepath.Dir(dir) {
+			report.DirSizes[dir] += size
+			if dir == "." {
+				break
+			}
+		}
+		if largest.Len() < opts.TopN {
+			heap.Push(&largest, FileSize{Path: rel, Size: size})
+		} else if size > largest[0].Size {
+			largest[0] = FileSize{Path: rel, Size: size}
+			heap.Fix(&largest, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return UsageReport{}, fmt.Errorf("Usage failed to walk directory: %w", err)
+	}
+
+	report.Largest = largest
+	sort.Slice(report.Largest, func(i, j int) bool {
+		if report.Largest[i].Size != report.Largest[j].Size {
+			return report.Largest[i].Size > report.Largest[j].Size
+		}
+		return report.Largest[i].Path < report.Largest[j].Path
+	})
+
+	return report, nil
+}
+
+// walkParallel calls fn for every entry below root, reading up to workers directories
+// concurrently. fn is called concurrently and in no particular order. After the first
+// error no new directories are read, and the error is returned.
+func walkParallel(root string, workers int, fn func(path string, entry fs.DirEntry) error) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	slots := make(chan struct{}, workers)
+	var walk func(dir string)
+	walk = func(dir string) {
+		defer wg.Done()
+		if failed() {
+			return
+		}
+
+		// Only reading is limited, so waiting for a slot never blocks a reader
+		slots <- struct{}{}
+		entries, err := os.ReadDir(dir)
+		<-slots
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			err := fn(path, entry)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if entry.IsDir() {
+				wg.Add(1)
+				go walk(path)
+			}
+		}
+	}
+
+	wg.Add(1)
+	walk(root)
+	wg.Wait()
+
+	return firstErr
+}
+
+// fileSizeHeap is a min-heap of files by size, to keep the largest ones.
+type fileSizeHeap []FileSize
+
+func (h fileSizeHeap) Len() int           { return len(h) }
+func (h fileSizeHeap) Less(i, j int) bool { return h[i].Size < h[j].Size }
+func (h fileSizeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *fileSizeHeap) Push(x any)        { *h = append(*h, x.(FileSize)) }
+func (h *fileSizeHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
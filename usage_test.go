@@ -0,0 +1,56 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUsage(t *testing.T) {
+	// Expect sizes to add up per directory, extension and in total
+	t.Run("report", func(t *testing.T) {
+		root := "usage_1"
+		defer os.RemoveAll(root)
+		writeTree(t, root, map[string]string{
+			"a.log":         strings.Repeat("a", 10),
+			"b.LOG":         strings.Repeat("b", 20),
+			"sub/c.txt":     strings.Repeat("c", 30),
+			"sub/deep/d":    strings.Repeat("d", 40),
+			"empty/.keep":   "",
+			"sub/deep/e.go": strings.Repeat("e", 5),
+		})
+
+		report, err := UsageWithOptions(root, UsageOptions{TopN: 2, Workers: 2})
+		if err != nil {
+			t.Fatalf("Usage failed: %v", err)
+		}
+
+		if report.Size != 105 || report.Files != 6 || report.Dirs != 4 {
+			t.Errorf("Expected 105 bytes in 6 files and 4 directories, got %d, %d, %d", report.Size, report.Files, report.Dirs)
+		}
+		expectedDirs := map[string]int64{".": 105, "sub": 75, filepath.Join("sub", "deep"): 45, "empty": 0}
+		for dir, size := range expectedDirs {
+			if report.DirSizes[dir] != size {
+				t.Errorf("Expected %s to use %d bytes, got %d", dir, size, report.DirSizes[dir])
+			}
+		}
+		if len(report.Largest) != 2 || report.Largest[0].Path != filepath.Join("sub", "deep", "d") || report.Largest[1].Size != 30 {
+			t.Errorf("Unexpected largest files: %v", report.Largest)
+		}
+		if logs := report.Extensions[".log"]; logs.Files != 2 || logs.Size != 30 {
+			t.Errorf("Expected 2 log files of 30 bytes, got %+v", logs)
+		}
+		if none := report.Extensions[""]; none.Files != 1 || none.Size != 40 {
+			t.Errorf("Expected 1 file without extension, got %+v", none)
+		}
+	})
+
+	// Expect a missing root to fail
+	t.Run("missing", func(t *testing.T) {
+		_, err := Usage("usage_2")
+		if err == nil {
+			t.Error("Expected an error for a missing root")
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package fs_go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// userDirKind is a kind of per-user application directory.
+type userDirKind int
+
+const (
+	userConfigDir userDirKind = iota
+	userCacheDir
+	userDataDir
+	userLogDir
+)
+
+// UserConfigDir returns the directory for app's configuration and creates it with mode
+// 0700 if needed. It is $XDG_CONFIG_HOME/app or ~/.config/app on Linux and other Unix
+// systems, ~/Library/Application Support/app on macOS, and %AppData%\app on Windows.
+//
+// Example:
+//
+//	dir, err := UserConfigDir("myapp")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = WriteAuto(filepath.Join(dir, "config.json"), config)
+func UserConfigDir(app string) (string, error) {
+	return ensureUserDir("UserConfigDir", userConfigDir, app)
+}
+
+// UserCacheDir returns the directory for app's cached data, which can be deleted at any
+// time, and creates it if needed. It is $XDG_CACHE_HOME/app or ~/.cache/app on Unix,
+// ~/Library/Caches/app on macOS, and %LocalAppData%\app\cache on Windows.
+func UserCacheDir(app string) (string, error) {
+	return ensureUserDir("UserCacheDir", userCacheDir, app)
+}
+
+// UserDataDir returns the directory for app's persistent data and creates it if needed.
+// It is $XDG_DATA_HOME/app or ~/.local/share/app on Unix, ~/Library/Application Support/app
+// on macOS, and %LocalAppData%\app on Windows.
+func UserDataDir(app string) (string, error) {
+	return ensureUserDir("UserDataDir", userDataDir, app)
+}
+
+// UserLogDir returns the directory for app's logs and creates it if needed. It is
+// $XDG_STATE_HOME/app or ~/.local/state/app on Unix, ~/Library/Logs/app on macOS, and
+// %LocalAppData%\app\logs on Windows.
+func UserLogDir(app string) (string, error) {
+	return ensureUserDir("UserLogDir", userLogDir, app)
+}
+
+// ensureUserDir resolves and creates an application directory.
+func ensureUserDir(op string, kind userDirKind, app string) (string, error) {
+	if app == "" {
+		return "", fmt.Errorf("%s failed: app name is empty", op)
+	}
+
+	dir, err := userDir(kind, app, runtime.GOOS)
+	if err != nil {
+		return "", fmt.Errorf("%s failed to resolve directory: %w", op, err)
+	}
+
+	err = EnsureDirWithMode(dir, 0700)
+	if err != nil {
+		return "", fmt.Errorf("%s failed to create directory: %w", op, err)
+	}
+
+	return dir, nil
+}
+
+// userDir returns an application directory following the conventions of the given GOOS.
+func userDir(kind userDirKind, app, goos string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil && goos != "windows" {
+		return "", err
+	}
+
+	switch goos {
+	case "windows":
+		if kind == userConfigDir {
+			return windowsAppDir("AppData", app)
+		}
+		dir, err := windowsAppDir("LocalAppData", app)
+		if err != nil {
+			return "", err
+		}
+		switch kind {
+		case userCacheDir:
+			return filepath.Join(dir, "cache"), nil
+		case userLogDir:
+			return filepath.Join(dir, "logs"), nil
+		default:
+			return dir, nil
+		}
+	case "darwin", "ios":
+		switch kind {
+		case userCacheDir:
+			return filepath.Join(home, "Library", "Caches", app), nil
+		case userLogDir:
+			return filepath.Join(home, "Library", "Logs", app), nil
+		default:
+			return filepath.Join(home, "Library", "Application Support", app), nil
+		}
+	default:
+		var variable, fallback string
+		switch kind {
+		case userConfigDir:
+			variable, fallback = "XDG_CONFIG_HOME", ".config"
+		case userCacheDir:
+			variable, fallback = "XDG_CACHE_HOME", ".cache"
+		case userDataDir:
+			variable, fallback = "XDG_DATA_HOME", filepath.Join(".local", "share")
+		case userLogDir:
+			variable, fallback = "XDG_STATE_HOME", filepath.Join(".local", "state")
+		}
+
+		// The XDG specification requires relative paths to be ignored
+		base := os.Getenv(variable)
+		if !filepath.IsAbs(base) {
+			base = filepath.Join(home, fallback)
+		}
+		return filepath.Join(base, app), nil
+	}
+}
+
+// windowsAppDir returns app's directory inside a Windows known folder given by an
+// environment variable.
+func windowsAppDir(variable, app string) (string, error) {
+	base := os.Getenv(variable)
+	if base == "" {
+		return "", fmt.Errorf("%%%s%% is not set", variable)
+	}
+
+	return filepath.Join(base, app), nil
+}
@@ -0,0 +1,96 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestUserDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("No home directory: %v", err)
+	}
+
+	// Expect XDG variables to be used, and relative ones to be ignored
+	t.Run("xdg", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+		t.Setenv("XDG_CACHE_HOME", "relative")
+		t.Setenv("XDG_DATA_HOME", "")
+
+		cases := map[userDirKind]string{
+			userConfigDir: filepath.Join("/xdg/config", "app"),
+			userCacheDir:  filepath.Join(home, ".cache", "app"),
+			userDataDir:   filepath.Join(home, ".local", "share", "app"),
+		}
+		for kind, expected := range cases {
+			if actual, err := userDir(kind, "app", "linux"); err != nil || actual != expected {
+				t.Errorf("Expected %s, got %s (%v)", expected, actual, err)
+			}
+		}
+	})
+
+	// Expect macOS conventions
+	t.Run("darwin", func(t *testing.T) {
+		cases := map[userDirKind]string{
+			userConfigDir: filepath.Join(home, "Library", "Application Support", "app"),
+			userCacheDir:  filepath.Join(home, "Library", "Caches", "app"),
+			userLogDir:    filepath.Join(home, "Library", "Logs", "app"),
+		}
+		for kind, expected := range cases {
+			if actual, err := userDir(kind, "app", "darwin"); err != nil || actual != expected {
+				t.Errorf("Expected %s, got %s (%v)", expected, actual, err)
+			}
+		}
+	})
+
+	// Expect Windows known folders
+	t.Run("windows", func(t *testing.T) {
+		t.Setenv("AppData", filepath.Join("C", "Roaming"))
+		t.Setenv("LocalAppData", filepath.Join("C", "Local"))
+
+		cases := map[userDirKind]string{
+			userConfigDir: filepath.Join("C", "Roaming", "app"),
+			userCacheDir:  filepath.Join("C", "Local", "app", "cache"),
+			userDataDir:   filepath.Join("C", "Local", "app"),
+		}
+		for kind, expected := range cases {
+			if actual, err := userDir(kind, "app", "windows"); err != nil || actual != expected {
+				t.Errorf("Expected %s, got %s (%v)", expected, actual, err)
+			}
+		}
+	})
+}
+
+func TestUserConfigDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Only XDG directories can be redirected for tests")
+	}
+
+	// Expect the directory to be created with mode 0700
+	t.Run("create", func(t *testing.T) {
+		base, err := filepath.Abs("user_config_dir_1")
+		if err != nil {
+			t.Fatalf("Abs failed: %v", err)
+		}
+		defer os.RemoveAll(base)
+		t.Setenv("XDG_CONFIG_HOME", base)
+
+		dir, err := UserConfigDir("app")
+		if err != nil {
+			t.Fatalf("UserConfigDir failed: %v", err)
+		}
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() || info.Mode().Perm() != 0700 {
+			t.Errorf("Expected directory with mode 0700, got %v (%v)", info, err)
+		}
+	})
+
+	// Expect an empty app name to fail
+	t.Run("empty", func(t *testing.T) {
+		if _, err := UserConfigDir(""); err == nil {
+			t.Error("Expected an error for an empty app name")
+		}
+	})
+}
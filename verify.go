@@ -0,0 +1,252 @@
+package fs_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mismatch is a file whose content no longer matches its expected checksum.
+type Mismatch struct {
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`  // Empty if the file is missing
+	Missing  bool   `json:"missing"` // Whether the file no longer exists
+}
+
+// VerifyOptions configures a Verifier.
+type VerifyOptions struct {
+	// StatePath is a file where progress is persisted, so verification resumes
+	// where it left off after a restart. Progress is kept in memory if empty.
+	StatePath string
+	// Pause is slept between files by Run, to leave the disk idle for other work.
+	Pause time.Duration
+	// OnMismatch is called for every mismatch as soon as it is found.
+	OnMismatch func(Mismatch)
+}
+
+// verifierState is the resumable progress of a Verifier.
+type verifierState struct {
+	Next       int        `json:"next"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+// Verifier slowly re-hashes files and compares them against their expected SHA-256
+// checksums to detect bit rot. It works through the files in small steps, so it can
+// run in the background during idle periods, and its progress can be persisted
+// so long verification passes survive restarts.
+type Verifier struct {
+	checksums map[string]string
+	paths     []string
+	opts      VerifyOptions
+	state     verifierState
+}
+
+// NewVerifier creates a Verifier for a map of paths to hex-encoded SHA-256 checksums.
+// If VerifyOptions.StatePath holds progress from an earlier run, it is resumed.
+//
+// Example:
+//
+//	verifier, err := NewVerifier(checksums, VerifyOptions{
+//	    StatePath: "verify-state.json",
+//	    Pause:     100 * time.Millisecond,
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	// Called by a scheduler whenever the system is idle
+//	mismatches, done, err := verifier.Step(10)
+func NewVerifier(checksums map[string]string, opts VerifyOptions) (*Verifier, error) {
+	paths := make([]string, 0, len(checksums))
+	for path := range checksums {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	v := &Verifier{checksums: checksums, paths: paths, opts: opts}
+	if opts.StatePath == "" {
+		return v, nil
+	}
+
+	err := ReadJson(opts.StatePath, &v.state)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("NewVerifier failed to read state: %w", err)
+	}
+	if v.state.Next < 0 || v.state.Next > len(paths) {
+		v.state = verifierState{}
+	}
+
+	return v, nil
+}
+
+// NewVerifierFromManifest creates a Verifier for the files listed in a manifest written
+// by WriteManifest for root.
+//
+// Example:
+//
+//	verifier, err := NewVerifierFromManifest("photos", "photos.manifest.json", VerifyOptions{})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func NewVerifierFromManifest(root, manifestPath string, opts VerifyOptions) (*Verifier, error) {
+	root, manifestPath = normalizePath(root), normalizePath(manifestPath)
+
+	var manifest Manifest
+	err := ReadJson(manifestPath, &manifest)
+	if err != nil {
+		return nil, pathError("NewVerifierFromManifest", manifestPath, fmt.Errorf("NewVerifierFromManifest failed to read manifest: %w", err))
+	}
+
+	checksums := make(map[string]string, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		checksums[filepath.Join(root, filepath.FromSlash(entry.Path))] = entry.SHA256
+	}
+
+	return NewVerifier(checksums, opts)
+}
+
+// NewVerifierFromXattrs creates a Verifier for the regular files below root that carry
+// a hex-encoded SHA-256 checksum in the extended attribute name. Files without the
+// attribute are skipped.
+//
+// Example:
+//
+//	verifier, err := NewVerifierFromXattrs("archive", "user.sha256", VerifyOptions{})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+func NewVerifierFromXattrs(root, name string, opts VerifyOptions) (*Verifier, error) {
+	root = normalizePath(root)
+
+	paths, err := ReadDirRecWithOptions(root, ReadDirRecOptions{ExcludeSpecial: true})
+	if err != nil {
+		return nil, pathError("NewVerifierFromXattrs", root, fmt.Errorf("NewVerifierFromXattrs failed to list files: %w", err))
+	}
+
+	checksums := make(map[string]string)
+	for _, path := range paths {
+		names, err := ListXattrs(path)
+		if err != nil {
+			return nil, pathError("NewVerifierFromXattrs", path, fmt.Errorf("NewVerifierFromXattrs failed to list attributes: %w", err))
+		}
+		if !slices.Contains(names, name) {
+			continue
+		}
+
+		value, err := GetXattr(path, name)
+		if err != nil {
+			return nil, pathError("NewVerifierFromXattrs", path, fmt.Errorf("NewVerifierFromXattrs failed to read checksum: %w", err))
+		}
+		checksums[path] = strings.TrimSpace(string(value))
+	}
+
+	return NewVerifier(checksums, opts)
+}
+
+// Progress returns the number of files verified in the current pass and the total number of files.
+func (v *Verifier) Progress() (int, int) {
+	return v.state.Next, len(v.paths)
+}
+
+// Mismatches returns the mismatches found so far in the current pass.
+func (v *Verifier) Mismatches() []Mismatch {
+	return v.state.Mismatches
+}
+
+// Step verifies up to n more files and returns the mismatches among them.
+// done is true once the pass is complete, after which the next Step starts a new pass.
+func (v *Verifier) Step(n int) (mismatches []Mismatch, done bool, err error) {
+	if v.state.Next >= len(v.paths) {
+		v.state = verifierState{}
+	}
+
+	for i := 0; i < n && v.state.Next < len(v.paths); i++ {
+		mismatch, ok, err := v.verify(v.paths[v.state.Next])
+		if err != nil {
+			return mismatches, false, fmt.Errorf("Step failed to verify file: %w", err)
+		}
+		if ok {
+			mismatches = append(mismatches, mismatch)
+			v.state.Mismatches = append(v.state.Mismatches, mismatch)
+			if v.opts.OnMismatch != nil {
+				v.opts.OnMismatch(mismatch)
+			}
+		}
+		v.state.Next++
+	}
+
+	err = v.save()
+	if err != nil {
+		return mismatches, false, fmt.Errorf("Step failed to save state: %w", err)
+	}
+
+	return mismatches, v.state.Next >= len(v.paths), nil
+}
+
+// Run verifies the remaining files of the current pass one at a time, sleeping for
+// VerifyOptions.Pause between files, until the pass completes or ctx is done.
+// It returns all mismatches found in the pass. Progress is kept when ctx is cancelled.
+func (v *Verifier) Run(ctx context.Context) ([]Mismatch, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return v.state.Mismatches, err
+		}
+
+		_, done, err := v.Step(1)
+		if err != nil {
+			return v.state.Mismatches, fmt.Errorf("Run failed: %w", err)
+		}
+		if done {
+			return v.state.Mismatches, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return v.state.Mismatches, ctx.Err()
+		case <-time.After(v.opts.Pause):
+		}
+	}
+}
+
+// verify hashes a single file and reports whether it mismatches its expected checksum.
+func (v *Verifier) verify(path string) (Mismatch, bool, error) {
+	expected := v.checksums[path]
+
+	actual, err := hashFile(normalizePath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return Mismatch{Path: path, Expected: expected, Missing: true}, true, nil
+	}
+	if err != nil {
+		return Mismatch{}, false, err
+	}
+	// Hex digits may be uppercase in checksums from manifests or extended attributes
+	if !strings.EqualFold(actual, expected) {
+		return Mismatch{Path: path, Expected: expected, Actual: actual}, true, nil
+	}
+
+	return Mismatch{}, false, nil
+}
+
+// save persists the progress if a state path is configured.
+func (v *Verifier) save() error {
+	if v.opts.StatePath == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(v.state)
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(v.opts.StatePath, content, 0644, false)
+}
@@ -0,0 +1,230 @@
+package fs_go
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// verifyFixture writes files and returns their checksums.
+func verifyFixture(t *testing.T, root string, files map[string]string) map[string]string {
+	t.Helper()
+
+	writeTree(t, root, files)
+	checksums := make(map[string]string)
+	for name := range files {
+		path := filepath.Join(root, name)
+		hash, err := hashFile(path)
+		if err != nil {
+			t.Fatalf("hashFile failed: %v", err)
+		}
+		checksums[path] = hash
+	}
+
+	return checksums
+}
+
+func TestVerifier(t *testing.T) {
+	// Expect corrupted and missing files to be reported
+	t.Run("detect bit rot", func(t *testing.T) {
+		path := "verifier_1"
+		defer os.RemoveAll(path)
+
+		checksums := verifyFixture(t, path, map[string]string{
+			"a.txt": "a",
+			"b.txt": "b",
+			"c.txt": "c",
+		})
+		if err := os.WriteFile(filepath.Join(path, "b.txt"), []byte("rot"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+		if err := os.Remove(filepath.Join(path, "c.txt")); err != nil {
+			t.Fatalf("os.Remove failed: %v", err)
+		}
+
+		var reported []Mismatch
+		verifier, err := NewVerifier(checksums, VerifyOptions{
+			OnMismatch: func(m Mismatch) { reported = append(reported, m) },
+		})
+		if err != nil {
+			t.Fatalf("NewVerifier failed: %v", err)
+		}
+
+		mismatches, err := verifier.Run(context.Background())
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+
+		if len(mismatches) != 2 || len(reported) != 2 {
+			t.Fatalf("Expected 2 mismatches, got %+v", mismatches)
+		}
+
+		if mismatches[0].Path != filepath.Join(path, "b.txt") || mismatches[0].Missing {
+			t.Errorf("Expected b.txt to be corrupted, got %+v", mismatches[0])
+		}
+
+		if mismatches[1].Path != filepath.Join(path, "c.txt") || !mismatches[1].Missing {
+			t.Errorf("Expected c.txt to be missing, got %+v", mismatches[1])
+		}
+	})
+
+	// Expect progress to be resumed from the state file
+	t.Run("resume", func(t *testing.T) {
+		path := "verifier_2"
+		defer os.RemoveAll(path)
+
+		checksums := verifyFixture(t, path, map[string]string{
+			"a.txt": "a",
+			"b.txt": "b",
+			"c.txt": "c",
+		})
+		state := filepath.Join(path, "state.json")
+
+		verifier, err := NewVerifier(checksums, VerifyOptions{StatePath: state})
+		if err != nil {
+			t.Fatalf("NewVerifier failed: %v", err)
+		}
+
+		_, done, err := verifier.Step(2)
+		if err != nil {
+			t.Errorf("Step failed: %v", err)
+		}
+
+		if done {
+			t.Errorf("Expected pass to not be done")
+		}
+
+		resumed, err := NewVerifier(checksums, VerifyOptions{StatePath: state})
+		if err != nil {
+			t.Fatalf("NewVerifier failed: %v", err)
+		}
+
+		verified, total := resumed.Progress()
+		if verified != 2 || total != 3 {
+			t.Errorf("Expected progress 2/3, got %d/%d", verified, total)
+		}
+
+		_, done, err = resumed.Step(2)
+		if err != nil {
+			t.Errorf("Step failed: %v", err)
+		}
+
+		if !done {
+			t.Errorf("Expected pass to be done")
+		}
+	})
+	// Expect checksums to be read from a manifest
+	t.Run("from manifest", func(t *testing.T) {
+		path := "verifier_3"
+		defer os.RemoveAll(path)
+		manifest := "verifier_3.json"
+		defer os.Remove(manifest)
+
+		writeTree(t, path, map[string]string{"a.txt": "a", "nested/b.txt": "b"})
+		if err := WriteManifest(path, manifest); err != nil {
+			t.Fatalf("WriteManifest failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "nested", "b.txt"), []byte("rot"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		verifier, err := NewVerifierFromManifest(path, manifest, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("NewVerifierFromManifest failed: %v", err)
+		}
+
+		mismatches, err := verifier.Run(context.Background())
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+
+		if len(mismatches) != 1 || mismatches[0].Path != filepath.Join(path, "nested", "b.txt") {
+			t.Errorf("Expected nested/b.txt to be corrupted, got %+v", mismatches)
+		}
+	})
+
+	// Expect checksums to be read from extended attributes, skipping files without one
+	t.Run("from xattrs", func(t *testing.T) {
+		path := "verifier_4"
+		defer os.RemoveAll(path)
+
+		checksums := verifyFixture(t, path, map[string]string{"a.txt": "a", "b.txt": "b"})
+		writeTree(t, path, map[string]string{"unchecked.txt": "unchecked"})
+		for file, checksum := range checksums {
+			err := SetXattr(file, "user.sha256", []byte(checksum))
+			if errors.Is(err, errors.ErrUnsupported) {
+				t.Skip("extended attributes are not supported here")
+			}
+			if err != nil {
+				t.Fatalf("SetXattr failed: %v", err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(path, "a.txt"), []byte("rot"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		verifier, err := NewVerifierFromXattrs(path, "user.sha256", VerifyOptions{})
+		if err != nil {
+			t.Fatalf("NewVerifierFromXattrs failed: %v", err)
+		}
+
+		if _, total := verifier.Progress(); total != 2 {
+			t.Errorf("Expected 2 files to verify, got %d", total)
+		}
+
+		mismatches, err := verifier.Run(context.Background())
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+
+		if len(mismatches) != 1 || mismatches[0].Path != filepath.Join(path, "a.txt") {
+			t.Errorf("Expected a.txt to be corrupted, got %+v", mismatches)
+		}
+	})
+	// Expect uppercase hex checksums to match
+	t.Run("uppercase checksums", func(t *testing.T) {
+		path := "verifier_5"
+		defer os.RemoveAll(path)
+
+		checksums := verifyFixture(t, path, map[string]string{"a.txt": "a"})
+		for file, checksum := range checksums {
+			checksums[file] = strings.ToUpper(checksum)
+		}
+
+		verifier, err := NewVerifier(checksums, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("NewVerifier failed: %v", err)
+		}
+
+		mismatches, err := verifier.Run(context.Background())
+		if err != nil || len(mismatches) != 0 {
+			t.Errorf("Expected no mismatches, got %+v (%v)", mismatches, err)
+		}
+	})
+
+	// Expect a cancelled context to stop Run before it hashes anything
+	t.Run("cancelled", func(t *testing.T) {
+		path := "verifier_6"
+		defer os.RemoveAll(path)
+
+		checksums := verifyFixture(t, path, map[string]string{"a.txt": "a", "b.txt": "b"})
+		verifier, err := NewVerifier(checksums, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("NewVerifier failed: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = verifier.Run(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		if verified, _ := verifier.Progress(); verified != 0 {
+			t.Errorf("Expected no files to be verified, got %d", verified)
+		}
+	})
+}
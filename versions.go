@@ -0,0 +1,197 @@
+package fs_go
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// versionsDirName is the name of the directory, next to the versioned file, that stores its versions.
+const versionsDirName = ".versions"
+
+// Version is a snapshot of a file saved by SaveVersion.
+type Version struct {
+	ID   string    // Identifier of the version, sortable by time
+	Time time.Time // When the version was saved
+	Size int64     // Size of the content in bytes
+}
+
+// PrunePolicy decides which versions PruneVersions removes.
+// A version is removed if it violates any of the set limits.
+type PrunePolicy struct {
+	Keep   int           // Number of newest versions to keep, 0 for no limit
+	MaxAge time.Duration // Maximum age of a version, 0 for no limit
+}
+
+// versionsDir returns the directory that stores the versions of path.
+func versionsDir(path string) string {
+	return filepath.Join(filepath.Dir(path), versionsDirName, filepath.Base(path))
+}
+
+// SaveVersion snapshots the current content of a file into the version store
+// kept in a .versions directory next to it.
+//
+// Example:
+//
+//	version, err := SaveVersion("config.json")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	// Later
+//	err = RestoreVersion("config.json", version.ID)
+func SaveVersion(path string) (Version, error) {
+	path = normalizePath(path)
+
+	source, err := os.Open(path)
+	if err != nil {
+		return Version{}, fmt.Errorf("SaveVersion failed to open file: %w", err)
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return Version{}, fmt.Errorf("SaveVersion failed to get file stat: %w", err)
+	}
+	if info.IsDir() {
+		return Version{}, fmt.Errorf("SaveVersion failed: %s is a directory", path)
+	}
+
+	dir := versionsDir(path)
+	now := time.Now()
+	err = checkWritable("SaveVersion", path)
+	if err != nil {
+		return Version{}, err
+	}
+	if dryRunSkip("SaveVersion", path, dir) {
+		return Version{ID: versionID(now.UnixNano()), Time: now, Size: info.Size()}, nil
+	}
+
+	err = EnsureDir(dir)
+	if err != nil {
+		return Version{}, fmt.Errorf("SaveVersion failed to ensure version store: %w", err)
+	}
+
+	// Claim a unique ID, moving forward a nanosecond at a time on collisions
+	nanos := now.UnixNano()
+	var snapshot *os.File
+	for {
+		snapshot, err = os.OpenFile(filepath.Join(dir, versionID(nanos)), os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+		nanos++
+	}
+	if err != nil {
+		return Version{}, fmt.Errorf("SaveVersion failed to create version: %w", err)
+	}
+	defer snapshot.Close()
+
+	size, err := io.Copy(snapshot, source)
+	if err != nil {
+		os.Remove(snapshot.Name())
+		return Version{}, fmt.Errorf("SaveVersion failed to copy content: %w", err)
+	}
+
+	return Version{ID: versionID(nanos), Time: time.Unix(0, nanos), Size: size}, nil
+}
+
+// ListVersions returns the saved versions of a file, oldest first.
+// A file without saved versions has an empty list.
+func ListVersions(path string) ([]Version, error) {
+	path = normalizePath(path)
+
+	entries, err := os.ReadDir(versionsDir(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ListVersions failed to read version store: %w", err)
+	}
+
+	var versions []Version
+	for _, entry := range entries {
+		nanos, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil || entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("ListVersions failed to get version stat: %w", err)
+		}
+
+		versions = append(versions, Version{ID: entry.Name(), Time: time.Unix(0, nanos), Size: info.Size()})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].ID < versions[j].ID })
+
+	return versions, nil
+}
+
+// RestoreVersion atomically replaces the content of a file with a saved version.
+// The current content is not saved; call SaveVersion first to keep it.
+func RestoreVersion(path, id string) error {
+	path = normalizePath(path)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return fmt.Errorf("RestoreVersion failed: invalid version id %q", id)
+	}
+
+	versionPath := filepath.Join(versionsDir(path), id)
+	info, err := os.Stat(versionPath)
+	if err != nil {
+		return fmt.Errorf("RestoreVersion failed to get version stat: %w", err)
+	}
+
+	content, err := ReadBytes(versionPath)
+	if err != nil {
+		return fmt.Errorf("RestoreVersion failed to read version: %w", err)
+	}
+
+	err = writeAtomic(path, content, info.Mode().Perm(), false)
+	if err != nil {
+		return fmt.Errorf("RestoreVersion failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// PruneVersions removes the saved versions of a file that violate the policy
+// and returns the removed versions.
+func PruneVersions(path string, policy PrunePolicy) ([]Version, error) {
+	path = normalizePath(path)
+
+	versions, err := ListVersions(path)
+	if err != nil {
+		return nil, fmt.Errorf("PruneVersions failed to list versions: %w", err)
+	}
+
+	var removed []Version
+	now := time.Now()
+	for i, version := range versions {
+		newer := len(versions) - 1 - i
+		tooMany := policy.Keep > 0 && newer >= policy.Keep
+		tooOld := policy.MaxAge > 0 && now.Sub(version.Time) > policy.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		err = Remove(filepath.Join(versionsDir(path), version.ID))
+		if err != nil {
+			return removed, fmt.Errorf("PruneVersions failed to remove version: %w", err)
+		}
+		removed = append(removed, version)
+	}
+
+	return removed, nil
+}
+
+// versionID formats a version timestamp as a fixed-width, sortable ID.
+func versionID(nanos int64) string {
+	return fmt.Sprintf("%020d", nanos)
+}
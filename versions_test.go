@@ -0,0 +1,164 @@
+package fs_go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveVersion(t *testing.T) {
+	// Expect versions to be saved, listed and restored
+	t.Run("save and restore", func(t *testing.T) {
+		path := "save_version"
+		defer os.RemoveAll(path)
+
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+		file := filepath.Join(path, "config.txt")
+		if err := os.WriteFile(file, []byte("first"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		first, err := SaveVersion(file)
+		if err != nil {
+			t.Fatalf("SaveVersion failed: %v", err)
+		}
+
+		if err := os.WriteFile(file, []byte("second"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		second, err := SaveVersion(file)
+		if err != nil {
+			t.Fatalf("SaveVersion failed: %v", err)
+		}
+
+		versions, err := ListVersions(file)
+		if err != nil {
+			t.Errorf("ListVersions failed: %v", err)
+		}
+
+		if len(versions) != 2 || versions[0].ID != first.ID || versions[1].ID != second.ID {
+			t.Errorf("Expected versions %s and %s, got %+v", first.ID, second.ID, versions)
+		}
+
+		if versions[0].Size != 5 {
+			t.Errorf("Expected first version to have size 5, got %d", versions[0].Size)
+		}
+
+		err = RestoreVersion(file, first.ID)
+		if err != nil {
+			t.Errorf("RestoreVersion failed: %v", err)
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("os.ReadFile failed: %v", err)
+		}
+
+		if string(content) != "first" {
+			t.Errorf("Expected content to be 'first', got '%s'", content)
+		}
+	})
+
+	// Expect a file without versions to have none
+	t.Run("no versions", func(t *testing.T) {
+		versions, err := ListVersions("list_versions_none.txt")
+		if err != nil {
+			t.Errorf("ListVersions failed: %v", err)
+		}
+
+		if len(versions) != 0 {
+			t.Errorf("Expected no versions, got %+v", versions)
+		}
+	})
+}
+
+func TestRestoreVersion(t *testing.T) {
+	// Expect unknown and malformed ids to be rejected
+	t.Run("invalid id", func(t *testing.T) {
+		err := RestoreVersion("restore_version.txt", "../../etc/passwd")
+		if err == nil {
+			t.Errorf("Expected RestoreVersion to fail for a malformed id")
+		}
+
+		err = RestoreVersion("restore_version.txt", "1")
+		if err == nil {
+			t.Errorf("Expected RestoreVersion to fail for an unknown id")
+		}
+	})
+}
+
+func TestPruneVersions(t *testing.T) {
+	// Expect versions beyond the count limit to be removed, oldest first
+	t.Run("keep newest", func(t *testing.T) {
+		path := "prune_versions_1"
+		defer os.RemoveAll(path)
+
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("os.Mkdir failed: %v", err)
+		}
+		file := filepath.Join(path, "file.txt")
+		if err := os.WriteFile(file, []byte("content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		var saved []Version
+		for i := 0; i < 4; i++ {
+			version, err := SaveVersion(file)
+			if err != nil {
+				t.Fatalf("SaveVersion failed: %v", err)
+			}
+			saved = append(saved, version)
+		}
+
+		removed, err := PruneVersions(file, PrunePolicy{Keep: 1})
+		if err != nil {
+			t.Errorf("PruneVersions failed: %v", err)
+		}
+
+		if len(removed) != 3 {
+			t.Errorf("Expected 3 versions to be removed, got %d", len(removed))
+		}
+
+		versions, err := ListVersions(file)
+		if err != nil {
+			t.Errorf("ListVersions failed: %v", err)
+		}
+
+		if len(versions) != 1 || versions[0].ID != saved[3].ID {
+			t.Errorf("Expected only the newest version to be kept, got %+v", versions)
+		}
+	})
+
+	// Expect versions older than the age limit to be removed
+	t.Run("max age", func(t *testing.T) {
+		path := "prune_versions_2"
+		defer os.RemoveAll(path)
+
+		file := filepath.Join(path, "file.txt")
+		store := versionsDir(file)
+		if err := os.MkdirAll(store, 0755); err != nil {
+			t.Fatalf("os.MkdirAll failed: %v", err)
+		}
+
+		old := versionID(time.Now().Add(-48 * time.Hour).UnixNano())
+		recent := versionID(time.Now().UnixNano())
+		for _, id := range []string{old, recent} {
+			if err := os.WriteFile(filepath.Join(store, id), []byte("content"), 0644); err != nil {
+				t.Fatalf("os.WriteFile failed: %v", err)
+			}
+		}
+
+		removed, err := PruneVersions(file, PrunePolicy{MaxAge: 24 * time.Hour})
+		if err != nil {
+			t.Errorf("PruneVersions failed: %v", err)
+		}
+
+		if len(removed) != 1 || removed[0].ID != old {
+			t.Errorf("Expected only the old version to be removed, got %+v", removed)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package fs_go
+
+import "fmt"
+
+// GetXattr returns the value of an extended attribute of a file.
+//
+// Extended attributes are supported on Linux, macOS, FreeBSD and NetBSD. On Linux,
+// attributes set by unprivileged users must be in the "user." namespace, like "user.checksum".
+//
+// Example:
+//
+//	err := SetXattr("archive.tar", "user.sha256", []byte(digest))
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	stored, err := GetXattr("archive.tar", "user.sha256")
+func GetXattr(path, name string) ([]byte, error) {
+	path = normalizePath(path)
+
+	value, err := getXattr(path, name)
+	if err != nil {
+		return nil, fmt.Errorf("GetXattr failed to get attribute %s: %w", name, err)
+	}
+
+	return value, nil
+}
+
+// SetXattr sets an extended attribute of a file, replacing any previous value.
+func SetXattr(path, name string, value []byte) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("SetXattr", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err := setXattr(path, name, value)
+	if err != nil {
+		return fmt.Errorf("SetXattr failed to set attribute %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ListXattrs returns the names of the extended attributes of a file.
+func ListXattrs(path string) ([]string, error) {
+	path = normalizePath(path)
+
+	names, err := listXattrs(path)
+	if err != nil {
+		return nil, fmt.Errorf("ListXattrs failed to list attributes: %w", err)
+	}
+
+	return names, nil
+}
+
+// RemoveXattr removes an extended attribute from a file.
+func RemoveXattr(path, name string) error {
+	path = normalizePath(path)
+
+	if skip, err := writeGuard("RemoveXattr", path, ""); skip {
+		return err
+	}
+	defer invalidateStats(path)
+
+	err := removeXattr(path, name)
+	if err != nil {
+		return fmt.Errorf("RemoveXattr failed to remove attribute %s: %w", name, err)
+	}
+
+	return nil
+}
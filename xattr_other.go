@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !freebsd && !netbsd
+
+package fs_go
+
+import "errors"
+
+func getXattr(path, name string) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func setXattr(path, name string, value []byte) error {
+	return errors.ErrUnsupported
+}
+
+func listXattrs(path string) ([]string, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func removeXattr(path, name string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,56 @@
+package fs_go
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestXattr(t *testing.T) {
+	// Expect attributes to be set, listed, read and removed
+	t.Run("attribute lifecycle", func(t *testing.T) {
+		path := "xattr.txt"
+		defer os.Remove(path)
+
+		if err := os.WriteFile(path, []byte("test content"), 0644); err != nil {
+			t.Fatalf("os.WriteFile failed: %v", err)
+		}
+
+		err := SetXattr(path, "user.origin", []byte("https://example.com"))
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("extended attributes are not supported here")
+		}
+		if err != nil {
+			t.Fatalf("SetXattr failed: %v", err)
+		}
+
+		names, err := ListXattrs(path)
+		if err != nil {
+			t.Errorf("ListXattrs failed: %v", err)
+		}
+
+		if !reflect.DeepEqual(names, []string{"user.origin"}) {
+			t.Errorf("Expected [user.origin], got %v", names)
+		}
+
+		value, err := GetXattr(path, "user.origin")
+		if err != nil {
+			t.Errorf("GetXattr failed: %v", err)
+		}
+
+		if string(value) != "https://example.com" {
+			t.Errorf("Expected value to be 'https://example.com', got '%s'", value)
+		}
+
+		err = RemoveXattr(path, "user.origin")
+		if err != nil {
+			t.Errorf("RemoveXattr failed: %v", err)
+		}
+
+		_, err = GetXattr(path, "user.origin")
+		if err == nil {
+			t.Errorf("Expected GetXattr to fail for a removed attribute")
+		}
+	})
+}
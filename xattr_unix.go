@@ -0,0 +1,67 @@
+//go:build linux || darwin || freebsd || netbsd
+
+package fs_go
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+func getXattr(path, name string) ([]byte, error) {
+	return readXattrBuffer(func(dest []byte) (int, error) {
+		return unix.Getxattr(path, name, dest)
+	})
+}
+
+func setXattr(path, name string, value []byte) error {
+	return unix.Setxattr(path, name, value, 0)
+}
+
+func listXattrs(path string) ([]string, error) {
+	list, err := readXattrBuffer(func(dest []byte) (int, error) {
+		return unix.Listxattr(path, dest)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Names are separated and terminated by null bytes
+	var names []string
+	for _, name := range bytes.Split(list, []byte{0}) {
+		if len(name) > 0 {
+			names = append(names, string(name))
+		}
+	}
+
+	return names, nil
+}
+
+func removeXattr(path, name string) error {
+	return unix.Removexattr(path, name)
+}
+
+// readXattrBuffer queries the size of a value, then reads it, retrying if it grew in between.
+func readXattrBuffer(read func(dest []byte) (int, error)) ([]byte, error) {
+	for {
+		size, err := read(nil)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return []byte{}, nil
+		}
+
+		dest := make([]byte, size)
+		n, err := read(dest)
+		if errors.Is(err, unix.ERANGE) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return dest[:n], nil
+	}
+}